@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"krstenica/internal/dto"
+	"krstenica/internal/service"
+)
+
+// runImport izvrsava komandu
+//
+//	krstenica-api import -file upisi.xlsx [-mapping mapiranje.txt] [-commit] [-skip-duplicates]
+//
+// Bez -commit uvoz je probni: izvestaj se ispisuje, a nista se ne upisuje.
+func runImport(ctx context.Context, svc service.Service, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	file := flags.String("file", "", "XLSX ili CSV datoteka sa krstenicama (prvi red je zaglavlje)")
+	mappingFile := flags.String("mapping", "", "datoteka sa mapiranjem kolona, redovi oblika polje=Naslov kolone")
+	commit := flags.Bool("commit", false, "upisi krstenice; bez ovoga uvoz je probni")
+	skipDuplicates := flags.Bool("skip-duplicates", false, "preskoci duplikate umesto da prekinu uvoz")
+	printMapping := flags.Bool("print-mapping", false, "ispisi podrazumevano mapiranje kolona i izadji")
+	flags.Parse(args)
+
+	if *printMapping {
+		fmt.Print(service.KrstenicaImportMappingTemplate())
+		return nil
+	}
+	if *file == "" {
+		flags.Usage()
+		return errors.New("import: -file is required")
+	}
+
+	content, err := os.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+	req := &dto.KrstenicaImportReq{DryRun: !*commit, SkipDuplicates: *skipDuplicates}
+	if *mappingFile != "" {
+		mapping, err := os.ReadFile(*mappingFile)
+		if err != nil {
+			return err
+		}
+		req.Mapping = string(mapping)
+	}
+
+	report, err := svc.ImportKrstenice(ctx, req, filepath.Base(*file), content)
+	if err != nil {
+		return err
+	}
+	printImportReport(report)
+
+	if *commit && !report.Committed {
+		return errors.New("import: nothing was written, fix the reported rows and run again")
+	}
+	return nil
+}
+
+func printImportReport(report *dto.KrstenicaImportReport) {
+	fields := make([]string, 0, len(report.Columns))
+	for field := range report.Columns {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		fmt.Printf("kolona %-26s <- %q\n", field, report.Columns[field])
+	}
+	for _, issue := range report.Issues {
+		fmt.Printf("red %d\t%s\t%s\t%s\n", issue.Row, issue.Kind, issue.Field, issue.Message)
+	}
+	for _, created := range report.Created {
+		fmt.Printf("red %d\tnovo\t%s\t%s\n", created.Row, created.Entity, created.Name)
+	}
+
+	status := "probni uvoz, nista nije upisano"
+	if report.Committed {
+		status = "upisano"
+	} else if !report.DryRun {
+		status = "nije upisano"
+	}
+	fmt.Printf("%s: redova %d, krstenica %d, preskoceno %d, gresaka %d, duplikata %d, upozorenja %d\n",
+		status, report.Rows, report.Imported, report.Skipped, report.Errors, report.Duplicates, report.Warnings)
+}
@@ -39,6 +39,13 @@ func main() {
 
 	newService := service.NewService(repo, conf)
 
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		if err := runImport(ctx, newService, os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	newHandler := handler.NewHttpHandler(newService, conf, repo)
 	newHandler.Init()
 
@@ -23,12 +23,17 @@ type Config struct {
 	ENV string   `mapstructure:"env"`
 	DB  DBConfig `mapstructure:"db"`
 
-	HTTPPort       string          `mapstructure:"http_port"`
-	JWTSecret      string          `mapstructure:"jwt_secret"`
-	AdminJWTSecret string          `mapstructure:"admin_jwt_secret"`
-	Host           string          `mapstructure:"host"`
-	Migration      MigrationConfig `mapstructure:"migration"`
-	Auth           AuthConfig      `mapstructure:"auth"`
+	HTTPPort       string            `mapstructure:"http_port"`
+	JWTSecret      string            `mapstructure:"jwt_secret"`
+	AdminJWTSecret string            `mapstructure:"admin_jwt_secret"`
+	Host           string            `mapstructure:"host"`
+	Migration      MigrationConfig   `mapstructure:"migration"`
+	Auth           AuthConfig        `mapstructure:"auth"`
+	Verification   VerifyConfig      `mapstructure:"verification"`
+	Signing        SigningConfig     `mapstructure:"signing"`
+	Attachments    AttachmentsConfig `mapstructure:"attachments"`
+	Templates      TemplatesConfig   `mapstructure:"templates"`
+	Trash          TrashConfig       `mapstructure:"trash"`
 }
 
 type AuthConfig struct {
@@ -37,6 +42,41 @@ type AuthConfig struct {
 	SessionSecret string `mapstructure:"session_secret"`
 }
 
+// VerifyConfig podešava QR proveru odštampanih uverenja.
+type VerifyConfig struct {
+	Secret  string `mapstructure:"secret"`
+	BaseURL string `mapstructure:"base_url"`
+}
+
+// SigningConfig podešava digitalni potpis PDF uverenja. Sertifikat i ključ su
+// PEM fajlovi; fajl sertifikata može da sadrži i lanac izdavalaca.
+type SigningConfig struct {
+	CertFile string `mapstructure:"cert_file"`
+	KeyFile  string `mapstructure:"key_file"`
+	Reason   string `mapstructure:"reason"`
+	Location string `mapstructure:"location"`
+}
+
+// AttachmentsConfig podešava čuvanje skeniranih strana knjiga uz upise.
+type AttachmentsConfig struct {
+	Dir       string `mapstructure:"dir"`
+	MaxSizeMB int64  `mapstructure:"max_size_mb"`
+}
+
+// TemplatesConfig podešava čuvanje otpremljenih obrazaca uverenja (XLSX
+// obrasci i pozadinske slike). Fajlovi se ne brišu da bi ponovna štampa
+// mogla da koristi obrazac po kome je uverenje izdato.
+type TemplatesConfig struct {
+	Dir       string `mapstructure:"dir"`
+	MaxSizeMB int64  `mapstructure:"max_size_mb"`
+}
+
+// TrashConfig podešava korpu obrisanih zapisa. Obrisan zapis može trajno da se
+// ukloni tek kad prođe RetentionDays dana od brisanja.
+type TrashConfig struct {
+	RetentionDays int `mapstructure:"retention_days"`
+}
+
 func Load() (*Config, error) {
 	var config Config
 
@@ -63,6 +103,24 @@ func Load() (*Config, error) {
 }
 
 func (c *Config) applyDefaults() {
+	c.Attachments.Dir = strings.TrimSpace(c.Attachments.Dir)
+	if c.Attachments.Dir == "" {
+		c.Attachments.Dir = "data/attachments"
+	}
+	if c.Attachments.MaxSizeMB <= 0 {
+		c.Attachments.MaxSizeMB = 20
+	}
+	c.Templates.Dir = strings.TrimSpace(c.Templates.Dir)
+	if c.Templates.Dir == "" {
+		c.Templates.Dir = "data/templates"
+	}
+	if c.Templates.MaxSizeMB <= 0 {
+		c.Templates.MaxSizeMB = 10
+	}
+	if c.Trash.RetentionDays <= 0 {
+		c.Trash.RetentionDays = 30
+	}
+
 	c.DB.URL = strings.TrimSpace(c.DB.URL)
 	c.DB.LocalURL = strings.TrimSpace(c.DB.LocalURL)
 
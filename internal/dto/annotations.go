@@ -0,0 +1,28 @@
+package dto
+
+import (
+	"time"
+)
+
+type KrstenicaAnnotation struct {
+	ID                int64     `json:"id"`
+	KrstenicaId       int64     `json:"krstenica_id"`
+	Kind              string    `json:"kind"`
+	NoteDate          time.Time `json:"note_date"`
+	Text              string    `json:"text"`
+	CreatedById       *int64    `json:"created_by_id"`
+	CreatedByUsername string    `json:"created_by_username"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+type KrstenicaAnnotationCreateReq struct {
+	Kind     string    `json:"kind" form:"kind"`
+	NoteDate time.Time `json:"note_date" form:"note_date" time_format:"2006-01-02"`
+	Text     string    `json:"text" form:"text"`
+}
+
+type KrstenicaAnnotationUpdateReq struct {
+	Kind     *string    `json:"kind" form:"kind"`
+	NoteDate *time.Time `json:"note_date" form:"note_date" time_format:"2006-01-02"`
+	Text     *string    `json:"text" form:"text"`
+}
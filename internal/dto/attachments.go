@@ -0,0 +1,18 @@
+package dto
+
+import (
+	"time"
+)
+
+type KrstenicaAttachment struct {
+	ID                 int64     `json:"id"`
+	KrstenicaId        int64     `json:"krstenica_id"`
+	FileName           string    `json:"file_name"`
+	ContentType        string    `json:"content_type"`
+	SizeBytes          int64     `json:"size_bytes"`
+	HasThumbnail       bool      `json:"has_thumbnail"`
+	Checksum           string    `json:"checksum"`
+	UploadedById       *int64    `json:"uploaded_by_id"`
+	UploadedByUsername string    `json:"uploaded_by_username"`
+	CreatedAt          time.Time `json:"created_at"`
+}
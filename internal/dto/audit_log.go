@@ -0,0 +1,22 @@
+package dto
+
+import (
+	"time"
+)
+
+type AuditLog struct {
+	ID        int64              `json:"id"`
+	Entity    string             `json:"entity"`
+	EntityId  int64              `json:"entity_id"`
+	Action    string             `json:"action"`
+	UserId    *int64             `json:"user_id"`
+	Username  string             `json:"username"`
+	Changes   []AuditFieldChange `json:"changes"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type AuditFieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
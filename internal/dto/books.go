@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"time"
+)
+
+type Book struct {
+	ID             int64     `json:"id"`
+	Name           string    `json:"name"`
+	TampleId       int64     `json:"tample_id"`
+	TampleName     string    `json:"tample_name"`
+	TampleCity     string    `json:"tample_city"`
+	YearFrom       int64     `json:"year_from"`
+	YearTo         *int64    `json:"year_to"`
+	PageCapacity   int64     `json:"page_capacity"`
+	EntriesPerPage int64     `json:"entries_per_page"`
+	State          string    `json:"state"`
+	EntriesCount   int64     `json:"entries_count"`
+	LastPage       int64     `json:"last_page"`
+	LastNumber     int64     `json:"last_number"`
+	Status         string    `json:"status"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type BookCreateReq struct {
+	Name           string `json:"name" form:"name"`
+	TampleId       int64  `json:"tample_id" form:"tample_id"`
+	YearFrom       int64  `json:"year_from" form:"year_from"`
+	YearTo         *int64 `json:"year_to" form:"year_to"`
+	PageCapacity   int64  `json:"page_capacity" form:"page_capacity"`
+	EntriesPerPage int64  `json:"entries_per_page" form:"entries_per_page"`
+}
+
+type BookUpdateReq struct {
+	Name           *string `json:"name" form:"name"`
+	TampleId       *int64  `json:"tample_id" form:"tample_id"`
+	YearFrom       *int64  `json:"year_from" form:"year_from"`
+	YearTo         *int64  `json:"year_to" form:"year_to"`
+	PageCapacity   *int64  `json:"page_capacity" form:"page_capacity"`
+	EntriesPerPage *int64  `json:"entries_per_page" form:"entries_per_page"`
+	State          *string `json:"state" form:"state"`
+	Status         *string `json:"status" form:"status"`
+}
@@ -0,0 +1,53 @@
+package dto
+
+import "time"
+
+type CertificateLayout struct {
+	ID        int64                   `json:"id"`
+	Kind      string                  `json:"kind"`
+	Name      string                  `json:"name"`
+	IsDefault bool                    `json:"is_default"`
+	CreatedAt time.Time               `json:"created_at"`
+	UpdatedAt time.Time               `json:"updated_at"`
+	Cells     []CertificateLayoutCell `json:"cells"`
+}
+
+// CertificateLayoutCell je jedno polje obrasca; OffsetX i OffsetY su pomeraji
+// teksta u milimetrima (samo PDF), a FitTo je poslednja ćelija u redu do koje
+// se tekst sme raširiti pre smanjivanja fonta.
+type CertificateLayoutCell struct {
+	Cell      string  `json:"cell" form:"cell"`
+	Field     string  `json:"field" form:"field"`
+	Target    string  `json:"target" form:"target"`
+	DateStyle string  `json:"date_style" form:"date_style"`
+	Prefix    string  `json:"prefix" form:"prefix"`
+	Suffix    string  `json:"suffix" form:"suffix"`
+	Bold      bool    `json:"bold" form:"bold"`
+	Wrap      bool    `json:"wrap" form:"wrap"`
+	FitTo     string  `json:"fit_to" form:"fit_to"`
+	OffsetX   float64 `json:"offset_x" form:"offset_x"`
+	OffsetY   float64 `json:"offset_y" form:"offset_y"`
+}
+
+type CertificateLayoutCreateReq struct {
+	Name string `json:"name" form:"name"`
+	// CopyFromId je raspored čije se ćelije preuzimaju; bez njega podrazumevani.
+	CopyFromId *int64 `json:"copy_from_id" form:"copy_from_id"`
+}
+
+type CertificateLayoutUpdateReq struct {
+	Name  string                  `json:"name" form:"name"`
+	Cells []CertificateLayoutCell `json:"cells"`
+}
+
+// CertificateLayoutField je podatak upisa koji se može postaviti u ćeliju.
+type CertificateLayoutField struct {
+	Key    string `json:"key"`
+	Label  string `json:"label"`
+	IsDate bool   `json:"is_date"`
+}
+
+type CertificateLayoutOption struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+}
@@ -0,0 +1,76 @@
+package dto
+
+import (
+	"time"
+)
+
+type CertificateTemplate struct {
+	ID             int64                           `json:"id"`
+	Kind           string                          `json:"kind"`
+	Name           string                          `json:"name"`
+	IsDefault      bool                            `json:"is_default"`
+	CurrentVersion *CertificateTemplateVersion     `json:"current_version"`
+	Versions       []*CertificateTemplateVersion   `json:"versions,omitempty"`
+	Assignments    []CertificateTemplateAssignment `json:"assignments"`
+	CreatedAt      time.Time                       `json:"created_at"`
+	UpdatedAt      time.Time                       `json:"updated_at"`
+}
+
+// CertificateTemplateVersion je jedna verzija obrasca. Putanje fajlova služe
+// samo za štampu i ne šalju se klijentu.
+type CertificateTemplateVersion struct {
+	ID                  int64     `json:"id"`
+	TemplateId          int64     `json:"template_id"`
+	TemplateName        string    `json:"template_name"`
+	Version             int64     `json:"version"`
+	FileName            string    `json:"file_name"`
+	PreviewFileName     string    `json:"preview_file_name"`
+	BackgroundFileName  string    `json:"background_file_name"`
+	BackgroundFullBleed bool      `json:"background_full_bleed"`
+	Checksum            string    `json:"checksum"`
+	Note                string    `json:"note"`
+	UploadedByUsername  string    `json:"uploaded_by_username"`
+	CreatedAt           time.Time `json:"created_at"`
+
+	TemplatePath        string `json:"-"`
+	PreviewTemplatePath string `json:"-"`
+	BackgroundPath      string `json:"-"`
+}
+
+type CertificateTemplateAssignment struct {
+	EparhijaId   *int64 `json:"eparhija_id"`
+	EparhijaName string `json:"eparhija_name"`
+	TampleId     *int64 `json:"tample_id"`
+	TampleName   string `json:"tample_name"`
+}
+
+// CertificateTemplateFile je otpremljeni fajl obrasca.
+type CertificateTemplateFile struct {
+	FileName string
+	Content  []byte
+}
+
+// CertificateTemplateVersionReq opisuje novu verziju obrasca. Fajl koji nije
+// otpremljen preuzima se iz prethodne verzije; pozadina se izostavlja samo uz
+// RemoveBackground.
+type CertificateTemplateVersionReq struct {
+	Note                string
+	Template            *CertificateTemplateFile
+	PreviewTemplate     *CertificateTemplateFile
+	Background          *CertificateTemplateFile
+	BackgroundFullBleed bool
+	RemoveBackground    bool
+}
+
+type CertificateTemplateCreateReq struct {
+	Name string
+	CertificateTemplateVersionReq
+}
+
+// CertificateTemplateUpdateReq menja naziv obrasca i eparhije i hramove za
+// koje je obrazac podrazumevani.
+type CertificateTemplateUpdateReq struct {
+	Name        string  `json:"name" form:"name"`
+	EparhijaIds []int64 `json:"eparhija_ids" form:"eparhija_ids"`
+	TampleIds   []int64 `json:"tample_ids" form:"tample_ids"`
+}
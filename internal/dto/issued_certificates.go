@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"time"
+)
+
+type IssuedCertificate struct {
+	ID              int64     `json:"id"`
+	KrstenicaId     int64     `json:"krstenica_id"`
+	TampleId        *int64    `json:"tample_id"`
+	TampleName      string    `json:"tample_name"`
+	FirstName       string    `json:"first_name"`
+	LastName        string    `json:"last_name"`
+	City            string    `json:"city"`
+	Baptism         time.Time `json:"baptism"`
+	Year            int64     `json:"year"`
+	SerialNumber    int64     `json:"serial_number"`
+	Serial          string    `json:"serial"`
+	Format          string    `json:"format"`
+	TemplateVersion string    `json:"template_version"`
+	// TemplateVersionId je verzija otpremljenog obrasca po kojoj je uverenje
+	// odštampano; nil za ugrađeni obrazac.
+	TemplateVersionId *int64     `json:"template_version_id"`
+	TemplateName      string     `json:"template_name"`
+	TemplateRevision  *int64     `json:"template_revision"`
+	Purpose           string     `json:"purpose"`
+	IssuedById        *int64     `json:"issued_by_id"`
+	IssuedByUsername  string     `json:"issued_by_username"`
+	CreatedAt         time.Time  `json:"created_at"`
+	RevokedAt         *time.Time `json:"revoked_at"`
+	RevokedBy         string     `json:"revoked_by_username"`
+	RevokeReason      string     `json:"revoke_reason"`
+}
+
+type IssuedCertificateCreateReq struct {
+	Format            string `json:"format" form:"format"`
+	TemplateVersion   string `json:"template_version" form:"template_version"`
+	TemplateVersionId *int64 `json:"template_version_id" form:"template_version_id"`
+	Purpose           string `json:"purpose" form:"purpose"`
+}
+
+type IssuedCertificateRevokeReq struct {
+	Reason string `json:"reason" form:"reason"`
+}
+
+// CertificateVerification su podaci prikazani na javnoj stranici provere uverenja.
+type CertificateVerification struct {
+	Serial     string     `json:"serial"`
+	FirstName  string     `json:"first_name"`
+	LastName   string     `json:"last_name"`
+	Baptism    time.Time  `json:"baptism"`
+	TampleName string     `json:"tample_name"`
+	IssuedAt   time.Time  `json:"issued_at"`
+	Status     string     `json:"status"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+}
@@ -0,0 +1,35 @@
+package dto
+
+type KrstenicaImportReq struct {
+	// Mapping ima redove oblika polje=Naslov kolone; prazno znaci podrazumevano mapiranje.
+	Mapping        string `form:"mapping"`
+	DryRun         bool   `form:"dry_run"`
+	SkipDuplicates bool   `form:"skip_duplicates"`
+}
+
+type KrstenicaImportIssue struct {
+	Row     int    `json:"row"`
+	Kind    string `json:"kind"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type KrstenicaImportCreated struct {
+	Row    int    `json:"row"`
+	Entity string `json:"entity"`
+	Name   string `json:"name"`
+}
+
+type KrstenicaImportReport struct {
+	DryRun     bool                     `json:"dry_run"`
+	Committed  bool                     `json:"committed"`
+	Columns    map[string]string        `json:"columns"`
+	Rows       int                      `json:"rows"`
+	Imported   int                      `json:"imported"`
+	Skipped    int                      `json:"skipped"`
+	Errors     int                      `json:"errors"`
+	Duplicates int                      `json:"duplicates"`
+	Warnings   int                      `json:"warnings"`
+	Issues     []KrstenicaImportIssue   `json:"issues"`
+	Created    []KrstenicaImportCreated `json:"created"`
+}
@@ -0,0 +1,20 @@
+package dto
+
+import (
+	"time"
+)
+
+// KrstenicaVersion je jedna sačuvana verzija krštenice. Changes su polja
+// izmenjena u odnosu na prethodnu verziju.
+type KrstenicaVersion struct {
+	ID                  int64              `json:"id"`
+	KrstenicaId         int64              `json:"krstenica_id"`
+	Version             int64              `json:"version"`
+	Current             bool               `json:"current"`
+	RestoredFromVersion *int64             `json:"restored_from_version"`
+	CreatedById         *int64             `json:"created_by_id"`
+	CreatedByUsername   string             `json:"created_by_username"`
+	CreatedAt           time.Time          `json:"created_at"`
+	Changes             []AuditFieldChange `json:"changes"`
+	Krstenica           *Krstenica         `json:"krstenica"`
+}
@@ -7,6 +7,7 @@ import (
 type Krstenica struct {
 	ID                     int64     `json:"id"`
 	Book                   string    `json:"book"`
+	BookId                 *int64    `json:"book_id"`
 	Page                   int64     `json:"page"`
 	CurrentNumber          int64     `json:"current_number"`
 	EparhijaId             *int64    `json:"eparhija_id"`
@@ -14,18 +15,26 @@ type Krstenica struct {
 	TampleId               *int64    `json:"tample_id"`
 	TampleName             string    `json:"tample_name"`
 	TampleCity             string    `json:"tample_city"`
-	ParentId               *int64    `json:"parent_id"`
-	ParentFirstName        string    `json:"parent_first_name"`
-	ParentLastName         string    `json:"parent_last_name"`
-	ParentOccupation       string    `json:"parent_occupation"`
-	ParentCity             string    `json:"parent_city"`
-	ParentReligion         string    `json:"parent_religion"`
+	FatherId               *int64    `json:"father_id"`
+	FatherFirstName        string    `json:"father_first_name"`
+	FatherLastName         string    `json:"father_last_name"`
+	FatherOccupation       string    `json:"father_occupation"`
+	FatherCity             string    `json:"father_city"`
+	FatherReligion         string    `json:"father_religion"`
+	MotherId               *int64    `json:"mother_id"`
+	MotherFirstName        string    `json:"mother_first_name"`
+	MotherLastName         string    `json:"mother_last_name"`
+	MotherOccupation       string    `json:"mother_occupation"`
+	MotherCity             string    `json:"mother_city"`
+	MotherReligion         string    `json:"mother_religion"`
 	GodfatherId            *int64    `json:"godfather_id"`
 	GodfatherFirstName     string    `json:"godfather_first_name"`
 	GodfatherLastName      string    `json:"godfather_last_name"`
 	GodfatherOccupation    string    `json:"godfather_occupation"`
 	GodfatherCity          string    `json:"godfather_city"`
 	GodfatherReligion      string    `json:"godfather_religion"`
+	Godparents             []KrstenicaGodparent `json:"godparents"`
+	Annotations            []KrstenicaAnnotation `json:"annotations,omitempty"`
 	ParohId                *int64    `json:"paroh_id"`
 	ParohFirstName         string    `json:"paroh_first_name"`
 	ParohLastName          string    `json:"paroh_last_name"`
@@ -53,16 +62,31 @@ type Krstenica struct {
 	Comment                string    `json:"comment"`
 	Status                 string    `json:"status"`
 	CreatedAt              time.Time `json:"created_at"`
+	Score                  *float64  `json:"score,omitempty"`
+}
+
+// KrstenicaGodparent je kum naveden na krštenici, po redosledu upisa.
+type KrstenicaGodparent struct {
+	PersonId   int64  `json:"person_id"`
+	Position   int64  `json:"position"`
+	FirstName  string `json:"first_name"`
+	LastName   string `json:"last_name"`
+	Occupation string `json:"occupation"`
+	City       string `json:"city"`
+	Religion   string `json:"religion"`
 }
 
 type KrstenicaCreateReq struct {
 	Book                   string    `json:"book" form:"book"`
+	BookId                 *int64    `json:"book_id" form:"book_id"`
 	Page                   int64     `json:"page" form:"page"`
 	CurrentNumber          int64     `json:"current_number" form:"current_number"`
 	EparhijaId             int64     `json:"eparhija_id" form:"eparhija_id"`
 	TampleId               int64     `json:"tample_id" form:"tample_id"`
-	ParentId               int64     `json:"parent_id" form:"parent_id"`
+	FatherId               *int64    `json:"father_id" form:"father_id"`
+	MotherId               *int64    `json:"mother_id" form:"mother_id"`
 	GodfatherId            int64     `json:"godfather_id" form:"godfather_id"`
+	GodparentIds           []int64   `json:"godparent_ids" form:"godparent_ids"`
 	ParohId                *int64    `json:"paroh_id" form:"paroh_id"`
 	PriestId               int64     `json:"priest_id" form:"priest_id"`
 	FirstName              string    `json:"first_name" form:"first_name"`
@@ -87,12 +111,15 @@ type KrstenicaCreateReq struct {
 
 type KrstenicaUpdateReq struct {
 	Book                   *string    `json:"book" form:"book"`
+	BookId                 *int64     `json:"book_id" form:"book_id"`
 	Page                   *int64     `json:"page" form:"page"`
 	CurrentNumber          *int64     `json:"current_number" form:"current_number"`
 	EparhijaId             *int64     `json:"eparhija_id" form:"eparhija_id"`
 	TampleId               *int64     `json:"tample_id" form:"tample_id"`
-	ParentId               *int64     `json:"parent_id" form:"parent_id"`
+	FatherId               *int64     `json:"father_id" form:"father_id"`
+	MotherId               *int64     `json:"mother_id" form:"mother_id"`
 	GodfatherId            *int64     `json:"godfather_id" form:"godfather_id"`
+	GodparentIds           []int64    `json:"godparent_ids" form:"godparent_ids"`
 	ParohId                *int64     `json:"paroh_id" form:"paroh_id"`
 	PriestId               *int64     `json:"priest_id" form:"priest_id"`
 	FirstName              *string    `json:"first_name" form:"first_name"`
@@ -0,0 +1,34 @@
+package dto
+
+type NumberingCheckReq struct {
+	TampleId int64  `json:"tample_id" form:"tample_id"`
+	BookId   int64  `json:"book_id" form:"book_id"`
+	Book     string `json:"book" form:"book"`
+	Year     int    `json:"year" form:"year"`
+}
+
+type NumberingIssue struct {
+	Kind          string  `json:"kind"`
+	Page          int64   `json:"page"`
+	CurrentNumber int64   `json:"current_number"`
+	KrstenicaIds  []int64 `json:"krstenica_ids"`
+	Message       string  `json:"message"`
+}
+
+type NumberingBookReport struct {
+	TampleId    *int64           `json:"tample_id"`
+	TampleName  string           `json:"tample_name"`
+	Book        string           `json:"book"`
+	BookId      *int64           `json:"book_id"`
+	Year        int              `json:"year"`
+	Entries     int              `json:"entries"`
+	FirstNumber int64            `json:"first_number"`
+	LastNumber  int64            `json:"last_number"`
+	Issues      []NumberingIssue `json:"issues"`
+}
+
+type NumberingReport struct {
+	Year        int                    `json:"year"`
+	Books       []*NumberingBookReport `json:"books"`
+	TotalIssues int                    `json:"total_issues"`
+}
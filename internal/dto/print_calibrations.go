@@ -0,0 +1,57 @@
+package dto
+
+import "time"
+
+// Oblast profila kalibracije: lični profil korisnika, profil radne stanice
+// ili profil koji se bira samo ručno.
+const (
+	PrintCalibrationScopeNone        = ""
+	PrintCalibrationScopeUser        = "user"
+	PrintCalibrationScopeWorkstation = "workstation"
+)
+
+// PrintCalibration ispravlja pomeranje teksta jednog štampača. Tekst se
+// pomera za OffsetX i OffsetY i razmerava sa Scale oko gornjeg levog ugla
+// strane; pomeraji ćelija se dodaju pomerajima iz rasporeda.
+type PrintCalibration struct {
+	ID          int64                  `json:"id"`
+	Name        string                 `json:"name"`
+	OffsetX     float64                `json:"offset_x"`
+	OffsetY     float64                `json:"offset_y"`
+	Scale       float64                `json:"scale"`
+	UserId      *int64                 `json:"user_id"`
+	Username    string                 `json:"username"`
+	Workstation string                 `json:"workstation"`
+	Cells       []PrintCalibrationCell `json:"cells"`
+	CreatedAt   time.Time              `json:"created_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+}
+
+// Scope vraća oblast profila.
+func (c *PrintCalibration) Scope() string {
+	switch {
+	case c.UserId != nil:
+		return PrintCalibrationScopeUser
+	case c.Workstation != "":
+		return PrintCalibrationScopeWorkstation
+	}
+	return PrintCalibrationScopeNone
+}
+
+type PrintCalibrationCell struct {
+	Cell    string  `json:"cell" form:"cell"`
+	OffsetX float64 `json:"offset_x" form:"offset_x"`
+	OffsetY float64 `json:"offset_y" form:"offset_y"`
+}
+
+// PrintCalibrationReq opisuje profil; lični profil se uvek vezuje za
+// korisnika koji ga čuva.
+type PrintCalibrationReq struct {
+	Name        string                 `json:"name" form:"name"`
+	OffsetX     float64                `json:"offset_x" form:"offset_x"`
+	OffsetY     float64                `json:"offset_y" form:"offset_y"`
+	Scale       float64                `json:"scale" form:"scale"`
+	Scope       string                 `json:"scope" form:"scope"`
+	Workstation string                 `json:"workstation" form:"workstation"`
+	Cells       []PrintCalibrationCell `json:"cells"`
+}
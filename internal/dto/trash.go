@@ -0,0 +1,20 @@
+package dto
+
+import (
+	"time"
+)
+
+// TrashItem je obrisan zapis u korpi. PurgeAllowedAt je trenutak od kog zapis
+// sme trajno da se obriše.
+type TrashItem struct {
+	Entity            string     `json:"entity"`
+	ID                int64      `json:"id"`
+	Name              string     `json:"name"`
+	Details           string     `json:"details"`
+	City              string     `json:"city"`
+	DeletedAt         *time.Time `json:"deleted_at"`
+	DeletedById       *int64     `json:"deleted_by_id"`
+	DeletedByUsername string     `json:"deleted_by_username"`
+	PurgeAllowedAt    *time.Time `json:"purge_allowed_at"`
+	CanPurge          bool       `json:"can_purge"`
+}
@@ -0,0 +1,99 @@
+package dto
+
+import (
+	"time"
+)
+
+type Umrlica struct {
+	ID                     int64     `json:"id"`
+	Book                   string    `json:"book"`
+	Page                   int64     `json:"page"`
+	CurrentNumber          int64     `json:"current_number"`
+	EparhijaId             *int64    `json:"eparhija_id"`
+	EparhijaName           string    `json:"eparhija_name"`
+	TampleId               *int64    `json:"tample_id"`
+	TampleName             string    `json:"tample_name"`
+	TampleCity             string    `json:"tample_city"`
+	DeceasedId             *int64    `json:"deceased_id"`
+	DeceasedFirstName      string    `json:"deceased_first_name"`
+	DeceasedLastName       string    `json:"deceased_last_name"`
+	DeceasedOccupation     string    `json:"deceased_occupation"`
+	DeceasedCity           string    `json:"deceased_city"`
+	DeceasedAddress        string    `json:"deceased_address"`
+	DeceasedReligion       string    `json:"deceased_religion"`
+	DeceasedBirthDate      time.Time `json:"deceased_birth_date"`
+	KrstenicaId            *int64    `json:"krstenica_id"`
+	KrstenicaBook          string    `json:"krstenica_book"`
+	KrstenicaPage          int64     `json:"krstenica_page"`
+	KrstenicaCurrentNumber int64     `json:"krstenica_current_number"`
+	KrstenicaFirstName     string    `json:"krstenica_first_name"`
+	KrstenicaLastName      string    `json:"krstenica_last_name"`
+	KrstenicaBirthDate     time.Time `json:"krstenica_birth_date"`
+	KrstenicaBaptism       time.Time `json:"krstenica_baptism"`
+	KrstenicaTampleName    string    `json:"krstenica_tample_name"`
+	PriestId               *int64    `json:"priest_id"`
+	PriestFirstName        string    `json:"priest_first_name"`
+	PriestLastName         string    `json:"priest_last_name"`
+	PriestTitle            string    `json:"priest_title"`
+	DeathDate              time.Time `json:"death_date"`
+	BurialDate             time.Time `json:"burial_date"`
+	City                   string    `json:"city"`
+	Country                string    `json:"country"`
+	Cemetery               string    `json:"cemetery"`
+	CauseOfDeath           string    `json:"cause_of_death"`
+	MaritalStatus          string    `json:"marital_status"`
+	Sacraments             string    `json:"sacraments"`
+	NumberOfCertificate    string    `json:"number_of_certificate"`
+	TownOfCertificate      string    `json:"town_of_certificate"`
+	Certificate            time.Time `json:"certificate"`
+	Comment                string    `json:"comment"`
+	Status                 string    `json:"status"`
+	CreatedAt              time.Time `json:"created_at"`
+}
+
+type UmrlicaCreateReq struct {
+	Book                string    `json:"book" form:"book"`
+	Page                int64     `json:"page" form:"page"`
+	CurrentNumber       int64     `json:"current_number" form:"current_number"`
+	EparhijaId          int64     `json:"eparhija_id" form:"eparhija_id"`
+	TampleId            int64     `json:"tample_id" form:"tample_id"`
+	DeceasedId          int64     `json:"deceased_id" form:"deceased_id"`
+	KrstenicaId         *int64    `json:"krstenica_id" form:"krstenica_id"`
+	PriestId            int64     `json:"priest_id" form:"priest_id"`
+	DeathDate           time.Time `json:"death_date" form:"death_date" time_format:"2006-01-02"`
+	BurialDate          time.Time `json:"burial_date" form:"burial_date" time_format:"2006-01-02"`
+	City                string    `json:"city" form:"city"`
+	Country             string    `json:"country" form:"country"`
+	Cemetery            string    `json:"cemetery" form:"cemetery"`
+	CauseOfDeath        string    `json:"cause_of_death" form:"cause_of_death"`
+	MaritalStatus       string    `json:"marital_status" form:"marital_status"`
+	Sacraments          string    `json:"sacraments" form:"sacraments"`
+	NumberOfCertificate string    `json:"number_of_certificate" form:"number_of_certificate"`
+	TownOfCertificate   string    `json:"town_of_certificate" form:"town_of_certificate"`
+	Certificate         time.Time `json:"certificate" form:"certificate" time_format:"2006-01-02T15:04:05Z07:00"`
+	Comment             string    `json:"comment" form:"comment"`
+}
+
+type UmrlicaUpdateReq struct {
+	Book                *string    `json:"book" form:"book"`
+	Page                *int64     `json:"page" form:"page"`
+	CurrentNumber       *int64     `json:"current_number" form:"current_number"`
+	EparhijaId          *int64     `json:"eparhija_id" form:"eparhija_id"`
+	TampleId            *int64     `json:"tample_id" form:"tample_id"`
+	DeceasedId          *int64     `json:"deceased_id" form:"deceased_id"`
+	KrstenicaId         *int64     `json:"krstenica_id" form:"krstenica_id"`
+	PriestId            *int64     `json:"priest_id" form:"priest_id"`
+	DeathDate           *time.Time `json:"death_date" form:"death_date" time_format:"2006-01-02"`
+	BurialDate          *time.Time `json:"burial_date" form:"burial_date" time_format:"2006-01-02"`
+	City                *string    `json:"city" form:"city"`
+	Country             *string    `json:"country" form:"country"`
+	Cemetery            *string    `json:"cemetery" form:"cemetery"`
+	CauseOfDeath        *string    `json:"cause_of_death" form:"cause_of_death"`
+	MaritalStatus       *string    `json:"marital_status" form:"marital_status"`
+	Sacraments          *string    `json:"sacraments" form:"sacraments"`
+	NumberOfCertificate *string    `json:"number_of_certificate" form:"number_of_certificate"`
+	TownOfCertificate   *string    `json:"town_of_certificate" form:"town_of_certificate"`
+	Certificate         *time.Time `json:"certificate" form:"certificate" time_format:"2006-01-02T15:04:05Z07:00"`
+	Comment             *string    `json:"comment" form:"comment"`
+	Status              *string    `json:"status" form:"status"`
+}
@@ -0,0 +1,102 @@
+package dto
+
+import (
+	"time"
+)
+
+type Vencanica struct {
+	ID                     int64     `json:"id"`
+	Book                   string    `json:"book"`
+	Page                   int64     `json:"page"`
+	CurrentNumber          int64     `json:"current_number"`
+	EparhijaId             *int64    `json:"eparhija_id"`
+	EparhijaName           string    `json:"eparhija_name"`
+	TampleId               *int64    `json:"tample_id"`
+	TampleName             string    `json:"tample_name"`
+	TampleCity             string    `json:"tample_city"`
+	GroomId                *int64    `json:"groom_id"`
+	GroomFirstName         string    `json:"groom_first_name"`
+	GroomLastName          string    `json:"groom_last_name"`
+	GroomOccupation        string    `json:"groom_occupation"`
+	GroomCity              string    `json:"groom_city"`
+	GroomReligion          string    `json:"groom_religion"`
+	GroomBirthDate         time.Time `json:"groom_birth_date"`
+	BrideId                *int64    `json:"bride_id"`
+	BrideFirstName         string    `json:"bride_first_name"`
+	BrideLastName          string    `json:"bride_last_name"`
+	BrideOccupation        string    `json:"bride_occupation"`
+	BrideCity              string    `json:"bride_city"`
+	BrideReligion          string    `json:"bride_religion"`
+	BrideBirthDate         time.Time `json:"bride_birth_date"`
+	WitnessId              *int64    `json:"witness_id"`
+	WitnessFirstName       string    `json:"witness_first_name"`
+	WitnessLastName        string    `json:"witness_last_name"`
+	WitnessCity            string    `json:"witness_city"`
+	SecondWitnessId        *int64    `json:"second_witness_id"`
+	SecondWitnessFirstName string    `json:"second_witness_first_name"`
+	SecondWitnessLastName  string    `json:"second_witness_last_name"`
+	SecondWitnessCity      string    `json:"second_witness_city"`
+	PriestId               *int64    `json:"priest_id"`
+	PriestFirstName        string    `json:"priest_first_name"`
+	PriestLastName         string    `json:"priest_last_name"`
+	PriestTitle            string    `json:"priest_title"`
+	MarriageDate           time.Time `json:"marriage_date"`
+	City                   string    `json:"city"`
+	Country                string    `json:"country"`
+	GroomMarriageOrder     string    `json:"groom_marriage_order"`
+	BrideMarriageOrder     string    `json:"bride_marriage_order"`
+	CivilMarriage          string    `json:"civil_marriage"`
+	NumberOfCertificate    string    `json:"number_of_certificate"`
+	TownOfCertificate      string    `json:"town_of_certificate"`
+	Certificate            time.Time `json:"certificate"`
+	Comment                string    `json:"comment"`
+	Status                 string    `json:"status"`
+	CreatedAt              time.Time `json:"created_at"`
+}
+
+type VencanicaCreateReq struct {
+	Book                string    `json:"book" form:"book"`
+	Page                int64     `json:"page" form:"page"`
+	CurrentNumber       int64     `json:"current_number" form:"current_number"`
+	EparhijaId          int64     `json:"eparhija_id" form:"eparhija_id"`
+	TampleId            int64     `json:"tample_id" form:"tample_id"`
+	GroomId             int64     `json:"groom_id" form:"groom_id"`
+	BrideId             int64     `json:"bride_id" form:"bride_id"`
+	WitnessId           int64     `json:"witness_id" form:"witness_id"`
+	SecondWitnessId     *int64    `json:"second_witness_id" form:"second_witness_id"`
+	PriestId            int64     `json:"priest_id" form:"priest_id"`
+	MarriageDate        time.Time `json:"marriage_date" form:"marriage_date" time_format:"2006-01-02"`
+	City                string    `json:"city" form:"city"`
+	Country             string    `json:"country" form:"country"`
+	GroomMarriageOrder  string    `json:"groom_marriage_order" form:"groom_marriage_order"`
+	BrideMarriageOrder  string    `json:"bride_marriage_order" form:"bride_marriage_order"`
+	CivilMarriage       string    `json:"civil_marriage" form:"civil_marriage"`
+	NumberOfCertificate string    `json:"number_of_certificate" form:"number_of_certificate"`
+	TownOfCertificate   string    `json:"town_of_certificate" form:"town_of_certificate"`
+	Certificate         time.Time `json:"certificate" form:"certificate" time_format:"2006-01-02T15:04:05Z07:00"`
+	Comment             string    `json:"comment" form:"comment"`
+}
+
+type VencanicaUpdateReq struct {
+	Book                *string    `json:"book" form:"book"`
+	Page                *int64     `json:"page" form:"page"`
+	CurrentNumber       *int64     `json:"current_number" form:"current_number"`
+	EparhijaId          *int64     `json:"eparhija_id" form:"eparhija_id"`
+	TampleId            *int64     `json:"tample_id" form:"tample_id"`
+	GroomId             *int64     `json:"groom_id" form:"groom_id"`
+	BrideId             *int64     `json:"bride_id" form:"bride_id"`
+	WitnessId           *int64     `json:"witness_id" form:"witness_id"`
+	SecondWitnessId     *int64     `json:"second_witness_id" form:"second_witness_id"`
+	PriestId            *int64     `json:"priest_id" form:"priest_id"`
+	MarriageDate        *time.Time `json:"marriage_date" form:"marriage_date" time_format:"2006-01-02"`
+	City                *string    `json:"city" form:"city"`
+	Country             *string    `json:"country" form:"country"`
+	GroomMarriageOrder  *string    `json:"groom_marriage_order" form:"groom_marriage_order"`
+	BrideMarriageOrder  *string    `json:"bride_marriage_order" form:"bride_marriage_order"`
+	CivilMarriage       *string    `json:"civil_marriage" form:"civil_marriage"`
+	NumberOfCertificate *string    `json:"number_of_certificate" form:"number_of_certificate"`
+	TownOfCertificate   *string    `json:"town_of_certificate" form:"town_of_certificate"`
+	Certificate         *time.Time `json:"certificate" form:"certificate" time_format:"2006-01-02T15:04:05Z07:00"`
+	Comment             *string    `json:"comment" form:"comment"`
+	Status              *string    `json:"status" form:"status"`
+}
@@ -6,15 +6,57 @@ import (
 )
 
 var (
-	ErrTampleNotFound    = errors.New("tample not found")
-	ErrPriestNotFound    = errors.New("priest not found")
-	ErrEparhijeNotFound  = errors.New("eparhija not found")
-	ErrPersonNotFound    = errors.New("person not found")
-	ErrKrstenicaNotFound = errors.New("krstenica not found")
+	ErrTampleNotFound              = errors.New("tample not found")
+	ErrPriestNotFound              = errors.New("priest not found")
+	ErrEparhijeNotFound            = errors.New("eparhija not found")
+	ErrPersonNotFound              = errors.New("person not found")
+	ErrKrstenicaNotFound           = errors.New("krstenica not found")
+	ErrVencanicaNotFound           = errors.New("vencanica not found")
+	ErrUmrlicaNotFound             = errors.New("umrlica not found")
+	ErrBookNotFound                = errors.New("book not found")
+	ErrAnnotationNotFound          = errors.New("annotation not found")
+	ErrIssuedCertificateNotFound   = errors.New("issued certificate not found")
+	ErrAttachmentNotFound          = errors.New("attachment not found")
+	ErrKrstenicaVersionNotFound    = errors.New("krstenica version not found")
+	ErrTrashItemNotFound           = errors.New("deleted record not found")
+	ErrCertificateLayoutNotFound   = errors.New("certificate layout not found")
+	ErrCertificateTemplateNotFound = errors.New("certificate template not found")
+	ErrPrintCalibrationNotFound    = errors.New("print calibration not found")
+	ErrBookClosed                  = errors.New("књига је затворена за нове уписе")
+	ErrBookFull                    = errors.New("књига је попуњена, отворите нову књигу")
+	ErrBookNumberTaken             = errors.New("у књизи већ постоји упис са истом страном и текућим бројем")
+	ErrPDFSigningNotConfigured     = errors.New("pdf signing is not configured")
+	ErrTrashItemInUse              = errors.New("обрисани запис се и даље користи у другим записима")
+	ErrTrashRetentionNotExpired    = errors.New("рок чувања обрисаног записа још није истекао")
+	ErrImportFormat                = errors.New("увоз подржава само XLSX и CSV датотеке")
+	ErrImportEmpty                 = errors.New("датотека за увоз нема ни један ред са подацима")
+	ErrCertificateLayoutDefault    = errors.New("подразумевани распоред уверења не може бити обрисан")
+	ErrCertificateTemplateDefault  = errors.New("подразумевани образац уверења не може бити обрисан")
+	ErrCityForbidden               = errors.New("немате дозволу за овај град")
+	ErrPrintCalibrationForbidden   = errors.New("лични профил калибрације може да мења само његов власник")
 )
 
-type ValidationError error
+// ValidationError je greška neispravnog zahteva; handler je vraća kao 400.
+type ValidationError struct {
+	message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.message
+}
 
 func GetValidationError(resource, method, message string) error {
-	return fmt.Errorf("%s %s failed with message %s", resource, method, message)
+	return &ValidationError{message: fmt.Sprintf("%s %s failed with message %s", resource, method, message)}
+}
+
+// Greske paginacije kursorom (paging=cursor).
+var (
+	ErrInvalidCursor    = GetValidationError("Paging", "validation", "cursor is invalid")
+	ErrCursorPagingSort = GetValidationError("Paging", "validation", "sort and fuzzy can not be combined with paging=cursor")
+)
+
+// IsValidationError javlja da li je err (ili greška koju obuhvata) ValidationError.
+func IsValidationError(err error) bool {
+	var validationErr *ValidationError
+	return errors.As(err, &validationErr)
 }
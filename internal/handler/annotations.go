@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"krstenica/internal/dto"
+	"krstenica/internal/errorx"
+	"krstenica/internal/model"
+)
+
+var annotationKindLabels = map[string]string{
+	string(model.AnnotationKindMarriage):   "Венчање",
+	string(model.AnnotationKindMonastic):   "Монашење",
+	string(model.AnnotationKindNameChange): "Промена имена",
+	string(model.AnnotationKindDeath):      "Смрт",
+	string(model.AnnotationKindOther):      "Напомена",
+}
+
+var annotationKindOrder = []string{
+	string(model.AnnotationKindMarriage),
+	string(model.AnnotationKindMonastic),
+	string(model.AnnotationKindNameChange),
+	string(model.AnnotationKindDeath),
+	string(model.AnnotationKindOther),
+}
+
+// *************************************************************Naknadne zabeleske*************************************
+func (h *httpHandler) listKrstenicaAnnotations() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		krstenicaID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		annotations, err := h.service.ListKrstenicaAnnotations(ctx.Request.Context(), int64(krstenicaID))
+		if err != nil {
+			ctx.JSON(annotationErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{
+			"data":  annotations,
+			"total": len(annotations),
+		})
+	}
+}
+
+func (h *httpHandler) createKrstenicaAnnotation() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		krstenicaID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		req := &dto.KrstenicaAnnotationCreateReq{}
+		if err := ctx.Bind(req); err != nil {
+			fmt.Println("Error when parsing body", err)
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "error when parsing request data"})
+			return
+		}
+
+		annotation, err := h.service.CreateKrstenicaAnnotation(ctx.Request.Context(), int64(krstenicaID), req)
+		if err != nil {
+			ctx.JSON(annotationErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, annotation)
+	}
+}
+
+func (h *httpHandler) updateKrstenicaAnnotation() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		krstenicaID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		id, err := strconv.Atoi(ctx.Param("annotationId"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		req := &dto.KrstenicaAnnotationUpdateReq{}
+		if err := ctx.Bind(req); err != nil {
+			fmt.Println("Error when parsing body", err)
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "error when parsing request data"})
+			return
+		}
+
+		annotation, err := h.service.UpdateKrstenicaAnnotation(ctx.Request.Context(), int64(krstenicaID), int64(id), req)
+		if err != nil {
+			ctx.JSON(annotationErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, annotation)
+	}
+}
+
+func (h *httpHandler) deleteKrstenicaAnnotation() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		krstenicaID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		id, err := strconv.Atoi(ctx.Param("annotationId"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := h.service.DeleteKrstenicaAnnotation(ctx.Request.Context(), int64(krstenicaID), int64(id)); err != nil {
+			ctx.JSON(annotationErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, nil)
+	}
+}
+
+func annotationErrorStatus(err error) int {
+	if errors.Is(err, errorx.ErrKrstenicaNotFound) || errors.Is(err, errorx.ErrAnnotationNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+func (h *httpHandler) renderKrstenicaAnnotations() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		krstenicaID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			h.renderHTML(ctx, http.StatusBadRequest, "partials/error.html", gin.H{
+				"Message": "Nepostojeci identifikator krstenice",
+			})
+			return
+		}
+
+		annotations, err := h.service.ListKrstenicaAnnotations(ctx.Request.Context(), int64(krstenicaID))
+		if err != nil {
+			h.renderHTML(ctx, annotationErrorStatus(err), "partials/error.html", gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+
+		h.renderHTML(ctx, http.StatusOK, "krstenice/zabeleske.html", gin.H{
+			"KrstenicaID": krstenicaID,
+			"Items":       annotations,
+			"KindLabels":  annotationKindLabels,
+		})
+	}
+}
+
+func (h *httpHandler) renderKrstenicaAnnotationNew() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		krstenicaID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			h.renderHTML(ctx, http.StatusBadRequest, "partials/error.html", gin.H{
+				"Message": "Nepostojeci identifikator krstenice",
+			})
+			return
+		}
+
+		h.renderHTML(ctx, http.StatusOK, "krstenice/zabeleska-new.html", gin.H{
+			"KrstenicaID": krstenicaID,
+			"Kinds":       annotationKindOrder,
+			"KindLabels":  annotationKindLabels,
+		})
+	}
+}
+
+//****************************************************end******Naknadne zabeleske*************************************
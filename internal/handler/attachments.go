@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"krstenica/internal/errorx"
+)
+
+// *************************************************************Prilozi (skenovi)*************************************
+func (h *httpHandler) listKrstenicaAttachments() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		krstenicaID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		attachments, err := h.service.ListKrstenicaAttachments(ctx.Request.Context(), int64(krstenicaID))
+		if err != nil {
+			ctx.JSON(attachmentErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{
+			"data":  attachments,
+			"total": len(attachments),
+		})
+	}
+}
+
+func (h *httpHandler) uploadKrstenicaAttachment() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		krstenicaID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		maxSize := h.service.AttachmentMaxSizeBytes()
+		// mala rezerva za multipart zaglavlja; tačna provera veličine je u servisu
+		ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxSize+1<<20)
+
+		fileHeader, err := ctx.FormFile("file")
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("file can not be larger than %d MB", maxSize>>20)})
+				return
+			}
+			fmt.Println("Error when parsing upload", err)
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+			return
+		}
+		if fileHeader.Size > maxSize {
+			ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("file can not be larger than %d MB", maxSize>>20)})
+			return
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		defer file.Close()
+
+		content, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		attachment, err := h.service.CreateKrstenicaAttachment(ctx.Request.Context(), int64(krstenicaID), fileHeader.Filename, content)
+		if err != nil {
+			ctx.JSON(attachmentErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, attachment)
+	}
+}
+
+// downloadKrstenicaAttachment šalje originalni fajl, ili sličicu uz ?thumbnail=true.
+func (h *httpHandler) downloadKrstenicaAttachment() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		krstenicaID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		id, err := strconv.Atoi(ctx.Param("attachmentId"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		thumbnail := ctx.Query("thumbnail") == "true"
+
+		attachment, file, err := h.service.OpenKrstenicaAttachment(ctx.Request.Context(), int64(krstenicaID), int64(id), thumbnail)
+		if err != nil {
+			ctx.JSON(attachmentErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		defer file.Close()
+
+		info, err := file.Stat()
+		if err != nil {
+			log.Println("Error reading attachment:", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read file"})
+			return
+		}
+
+		contentType := attachment.ContentType
+		disposition := "inline"
+		if thumbnail {
+			contentType = "image/jpeg"
+		} else if ctx.Query("download") == "true" {
+			disposition = "attachment"
+		}
+
+		ctx.DataFromReader(http.StatusOK, info.Size(), contentType, file, map[string]string{
+			"Content-Disposition":    mime.FormatMediaType(disposition, map[string]string{"filename": attachment.FileName}),
+			"Cache-Control":          "private, max-age=3600",
+			"X-Content-Type-Options": "nosniff",
+		})
+	}
+}
+
+func (h *httpHandler) deleteKrstenicaAttachment() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		krstenicaID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		id, err := strconv.Atoi(ctx.Param("attachmentId"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := h.service.DeleteKrstenicaAttachment(ctx.Request.Context(), int64(krstenicaID), int64(id)); err != nil {
+			ctx.JSON(attachmentErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, nil)
+	}
+}
+
+func attachmentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, errorx.ErrKrstenicaNotFound) || errors.Is(err, errorx.ErrAttachmentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, errorx.ErrCityForbidden):
+		return http.StatusForbidden
+	case errorx.IsValidationError(err):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// renderKrstenicaAttachments vraća galeriju priloga koja se učitava u formu za izmenu krštenice.
+func (h *httpHandler) renderKrstenicaAttachments() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		krstenicaID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			h.renderHTML(ctx, http.StatusBadRequest, "partials/error.html", gin.H{
+				"Message": "Nepostojeci identifikator krstenice",
+			})
+			return
+		}
+
+		attachments, err := h.service.ListKrstenicaAttachments(ctx.Request.Context(), int64(krstenicaID))
+		if err != nil {
+			h.renderHTML(ctx, attachmentErrorStatus(err), "partials/error.html", gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+
+		h.renderHTML(ctx, http.StatusOK, "krstenice/prilozi.html", gin.H{
+			"KrstenicaID": krstenicaID,
+			"Items":       attachments,
+			"MaxSizeMB":   h.conf.Attachments.MaxSizeMB,
+		})
+	}
+}
+
+//****************************************************end******Prilozi (skenovi)*************************************
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"krstenica/internal/errorx"
+	"krstenica/pkg"
+)
+
+// *************************************************************Audit log*************************************
+func (h *httpHandler) listAuditLogs() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		cx := ctx.Request.Context()
+
+		filters := pkg.ParseUrlQuery(ctx)
+
+		entries, totalCount, err := h.service.ListAuditLogs(cx, filters)
+		if err != nil {
+			if errorx.IsValidationError(err) {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, listResponse(entries, totalCount, filters))
+	}
+}
+
+//****************************************************end******Audit log*************************************
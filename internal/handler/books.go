@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"krstenica/internal/dto"
+	"krstenica/internal/errorx"
+	"krstenica/pkg"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// *************************************************************Book*************************************
+func (h *httpHandler) createBooks() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		req := &dto.BookCreateReq{}
+
+		if err := ctx.Bind(req); err != nil {
+			fmt.Println("Error when parsing body", err)
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "error when parsing request data"})
+			return
+		}
+
+		cx := ctx.Request.Context()
+
+		book, err := h.service.CreateBook(cx, req)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, book)
+	}
+}
+
+func (h *httpHandler) getBooks() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		cx := ctx.Request.Context()
+
+		book, err := h.service.GetBookByID(cx, int64(id))
+		if err != nil {
+			if err == errorx.ErrBookNotFound {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, book)
+	}
+}
+
+func (h *httpHandler) listBooks() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		cx := ctx.Request.Context()
+
+		filters := pkg.ParseUrlQuery(ctx)
+
+		books, totalCount, err := h.service.ListBooks(cx, filters)
+		if err != nil {
+			if err == errorx.ErrBookNotFound {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			if errorx.IsValidationError(err) {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, listResponse(books, totalCount, filters))
+	}
+}
+
+func (h *httpHandler) updateBooks() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		req := &dto.BookUpdateReq{}
+
+		if err := ctx.Bind(req); err != nil {
+			fmt.Println("Error when parsing body", err)
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "error when parsing request data"})
+			return
+		}
+
+		cx := ctx.Request.Context()
+
+		book, err := h.service.UpdateBook(cx, int64(id), req)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, book)
+	}
+}
+
+func (h *httpHandler) deleteBooks() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		err = h.service.DeleteBook(ctx.Request.Context(), int64(id))
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, nil)
+	}
+}
+
+//****************************************************end******Book*************************************
+
+// isBookConflict prepoznaje greške dodele strane i broja u knjizi.
+func isBookConflict(err error) bool {
+	return errors.Is(err, errorx.ErrBookClosed) ||
+		errors.Is(err, errorx.ErrBookFull) ||
+		errors.Is(err, errorx.ErrBookNumberTaken)
+}
@@ -0,0 +1,279 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+
+	"krstenica/internal/dto"
+	"krstenica/internal/model"
+)
+
+// pdfLongYearShiftMM pomera cetvorocifrenu godinu (pre 2000.) ulevo, preko
+// odstampanog "20" na obrascu.
+const pdfLongYearShiftMM = 8.0
+
+// krstenicaLayoutTextFields racunaju tekstualna polja rasporeda uverenja;
+// kljucevi odgovaraju service.KrstenicaLayoutFields.
+var krstenicaLayoutTextFields = map[string]func(k *dto.Krstenica) string{
+	"book":           func(k *dto.Krstenica) string { return k.Book },
+	"page":           func(k *dto.Krstenica) string { return formatInt(k.Page) },
+	"current_number": func(k *dto.Krstenica) string { return formatInt(k.CurrentNumber) },
+	"eparhija_name":  func(k *dto.Krstenica) string { return k.EparhijaName },
+	"tample_name":    func(k *dto.Krstenica) string { return k.TampleName },
+	"tample_city":    func(k *dto.Krstenica) string { return k.TampleCity },
+	"tample": func(k *dto.Krstenica) string {
+		city := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(k.TampleCity), ","))
+		if city != "" {
+			city += ","
+		}
+		return joinNonEmpty(" ", city, strings.TrimSpace(k.TampleName))
+	},
+	"place_of_birth": func(k *dto.Krstenica) string {
+		place, municipality := krstenicaPlaceOfBirth(k)
+		if place != "" && strings.EqualFold(place, municipality) {
+			return ""
+		}
+		return place
+	},
+	"place_and_municipality_of_birth": func(k *dto.Krstenica) string {
+		place, municipality := krstenicaPlaceOfBirth(k)
+		if place != "" && strings.EqualFold(place, municipality) {
+			return municipality
+		}
+		return ""
+	},
+	"municipality_of_birth": func(k *dto.Krstenica) string {
+		place, municipality := krstenicaPlaceOfBirth(k)
+		if place != "" && strings.EqualFold(place, municipality) {
+			return ""
+		}
+		return municipality
+	},
+	"first_name": func(k *dto.Krstenica) string { return k.FirstName },
+	"gender":     func(k *dto.Krstenica) string { return mapGenderToCyrillic(k.Gender) },
+	"parents": func(k *dto.Krstenica) string {
+		return joinNonEmpty(" и ",
+			formatParentText(k.FatherFirstName, k.FatherLastName, k.FatherOccupation),
+			formatParentText(k.MotherFirstName, k.MotherLastName, k.MotherOccupation))
+	},
+	"parents_city":            func(k *dto.Krstenica) string { return joinDistinct(", ", k.FatherCity, k.MotherCity) },
+	"parents_religion":        func(k *dto.Krstenica) string { return joinDistinct(", ", k.FatherReligion, k.MotherReligion) },
+	"birth_order":             func(k *dto.Krstenica) string { return strings.TrimSpace(k.BirthOrder) },
+	"is_church_married":       func(k *dto.Krstenica) string { return strings.TrimSpace(k.IsChurchMarried) },
+	"is_twin":                 func(k *dto.Krstenica) string { return strings.TrimSpace(k.IsTwin) },
+	"has_physical_disability": func(k *dto.Krstenica) string { return strings.TrimSpace(k.HasPhysicalDisability) },
+	"priest_first_name":       func(k *dto.Krstenica) string { return k.PriestFirstName },
+	"priest_last_name":        func(k *dto.Krstenica) string { return k.PriestLastName },
+	"priest_title":            func(k *dto.Krstenica) string { return strings.TrimSpace(k.PriestTitle) },
+	"priest": func(k *dto.Krstenica) string {
+		name := joinNonEmpty(" ", strings.TrimSpace(k.PriestFirstName), strings.TrimSpace(k.PriestLastName))
+		return joinNonEmpty(", ", name, strings.TrimSpace(k.PriestTitle))
+	},
+	"godparent_first_name": func(k *dto.Krstenica) string {
+		if len(k.Godparents) > 1 {
+			return formatGodparentsText(k.Godparents)
+		}
+		return k.GodfatherFirstName
+	},
+	"godparent_last_name": func(k *dto.Krstenica) string {
+		if len(k.Godparents) > 1 {
+			return ""
+		}
+		return k.GodfatherLastName
+	},
+	"godparent_occupation": func(k *dto.Krstenica) string {
+		if len(k.Godparents) > 1 {
+			return ""
+		}
+		return strings.TrimSpace(k.GodfatherOccupation)
+	},
+	"godparents": func(k *dto.Krstenica) string {
+		// zarez ispred mesta ("из ...") kada poslednji kum nema zanimanje
+		if len(k.Godparents) > 1 {
+			text := formatGodparentsText(k.Godparents)
+			if last := k.Godparents[len(k.Godparents)-1]; strings.TrimSpace(last.Occupation) == "" {
+				text += ","
+			}
+			return text
+		}
+		name := joinNonEmpty(" ", strings.TrimSpace(k.GodfatherFirstName), strings.TrimSpace(k.GodfatherLastName))
+		if name == "" {
+			return ""
+		}
+		if occupation := strings.TrimSpace(k.GodfatherOccupation); occupation != "" {
+			return name + ", " + occupation
+		}
+		return name + ","
+	},
+	"godparents_city": func(k *dto.Krstenica) string {
+		if len(k.Godparents) > 1 {
+			cities := make([]string, len(k.Godparents))
+			for i, g := range k.Godparents {
+				cities[i] = g.City
+			}
+			return joinDistinct(", ", cities...)
+		}
+		return k.GodfatherCity
+	},
+	"godparents_religion": func(k *dto.Krstenica) string {
+		if len(k.Godparents) > 1 {
+			religions := make([]string, len(k.Godparents))
+			for i, g := range k.Godparents {
+				religions[i] = g.Religion
+			}
+			return joinDistinct(", ", religions...)
+		}
+		return k.GodfatherReligion
+	},
+	"anagrafa":              func(k *dto.Krstenica) string { return k.Anagrafa },
+	"remarks":               formatKrstenicaRemarks,
+	"number_of_certificate": func(k *dto.Krstenica) string { return strings.TrimSpace(k.NumberOfCertificate) },
+	"town_of_certificate":   func(k *dto.Krstenica) string { return k.TownOfCertificate },
+}
+
+// krstenicaLayoutDateFields su datumska polja; ispisuju se po stilu celije.
+var krstenicaLayoutDateFields = map[string]func(k *dto.Krstenica) time.Time{
+	"birth_date":  func(k *dto.Krstenica) time.Time { return k.BirthDate },
+	"baptism":     func(k *dto.Krstenica) time.Time { return k.Baptism },
+	"certificate": func(k *dto.Krstenica) time.Time { return k.Certificate },
+}
+
+func krstenicaPlaceOfBirth(k *dto.Krstenica) (string, string) {
+	return strings.TrimSpace(k.PlaceOfBirthday), strings.TrimSpace(k.MunicipalityOfBirthday)
+}
+
+func formatLayoutDate(t time.Time, style string) string {
+	if t.IsZero() {
+		return ""
+	}
+	switch style {
+	case "date_time":
+		return formatDateTimeComma(t)
+	case "day_month":
+		dayMonth, _ := splitDateDayMonthYearSuffix(t)
+		return dayMonth
+	case "year":
+		return formatBaptismYear(t)
+	case "year_short":
+		_, yearSuffix := splitDateDayMonthYearSuffix(t)
+		return yearSuffix
+	default:
+		return formatDateComma(t)
+	}
+}
+
+// krstenicaLayoutValue vraca tekst jedne celije rasporeda; prefiks i sufiks
+// se dodaju samo kada polje ima vrednost.
+func krstenicaLayoutValue(krstenica *dto.Krstenica, cell dto.CertificateLayoutCell) string {
+	var value string
+	if fn, ok := krstenicaLayoutTextFields[cell.Field]; ok {
+		value = fn(krstenica)
+	} else if fn, ok := krstenicaLayoutDateFields[cell.Field]; ok {
+		value = formatLayoutDate(fn(krstenica), cell.DateStyle)
+	}
+	if strings.TrimSpace(value) == "" {
+		return value
+	}
+	return cell.Prefix + strings.TrimSpace(value) + cell.Suffix
+}
+
+// layoutCellsFor vraca celije rasporeda koje vaze za dati izlaz (xlsx ili pdf).
+func layoutCellsFor(layout *dto.CertificateLayout, target string) []dto.CertificateLayoutCell {
+	var cells []dto.CertificateLayoutCell
+	for _, cell := range layout.Cells {
+		if cell.Target == target || cell.Target == model.CertificateLayoutTargetAll || cell.Target == "" {
+			cells = append(cells, cell)
+		}
+	}
+	return cells
+}
+
+// krstenicaExcelCells priprema vrednosti, podebljane, prelomljene celije i
+// opsege za smanjivanje teksta za XLSX uverenje. Opseg se spaja samo ako su
+// ostale celije rasporeda u njemu prazne, da se ne bi prekrio njihov tekst.
+func krstenicaExcelCells(krstenica *dto.Krstenica, layout *dto.CertificateLayout) (map[string]string, []string, []string, map[string]string) {
+	cells := layoutCellsFor(layout, model.CertificateLayoutTargetXLSX)
+	values := make(map[string]string, len(cells))
+	var bold, wrap []string
+	for _, cell := range cells {
+		values[cell.Cell] = krstenicaLayoutValue(krstenica, cell)
+		if cell.Bold {
+			bold = append(bold, cell.Cell)
+		}
+		if cell.Wrap {
+			wrap = append(wrap, cell.Cell)
+		}
+	}
+
+	fitRanges := map[string]string{}
+	for _, cell := range cells {
+		if cell.FitTo == "" || strings.TrimSpace(values[cell.Cell]) == "" {
+			continue
+		}
+		if !excelRangeIsFree(values, cell.Cell, cell.FitTo) {
+			continue
+		}
+		fitRanges[cell.Cell] = cell.FitTo
+	}
+
+	return values, bold, wrap, fitRanges
+}
+
+func excelRangeIsFree(values map[string]string, start, end string) bool {
+	startCol, row, err := excelize.CellNameToCoordinates(start)
+	if err != nil {
+		return false
+	}
+	endCol, _, err := excelize.CellNameToCoordinates(end)
+	if err != nil {
+		return false
+	}
+	for col := startCol + 1; col <= endCol; col++ {
+		name, err := excelize.CoordinatesToCellName(col, row)
+		if err != nil {
+			return false
+		}
+		if strings.TrimSpace(values[name]) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// krstenicaPDFPage priprema vrednosti celija i raspored za stranu krstenice.
+func krstenicaPDFPage(krstenica *dto.Krstenica, layout *dto.CertificateLayout, verifyURL string) (map[string]string, pdfCellSpec) {
+	cells := layoutCellsFor(layout, model.CertificateLayoutTargetPDF)
+	values := make(map[string]string, len(cells))
+	spec := pdfCellSpec{
+		order:       make([]string, 0, len(cells)),
+		offsets:     make(map[string]textOffset, len(cells)),
+		bold:        map[string]bool{},
+		forcedWrap:  map[string]bool{},
+		fitWidth:    map[string]bool{},
+		fontRefCell: "C9",
+		qrCode:      verifyURL,
+	}
+	for _, cell := range cells {
+		value := krstenicaLayoutValue(krstenica, cell)
+		offset := textOffset{dx: cell.OffsetX, dy: cell.OffsetY}
+		if cell.DateStyle == "year" && len(strings.TrimSpace(value)) == 4 {
+			offset.dx -= pdfLongYearShiftMM
+		}
+
+		values[cell.Cell] = value
+		spec.order = append(spec.order, cell.Cell)
+		spec.offsets[cell.Cell] = offset
+		spec.bold[cell.Cell] = cell.Bold
+		spec.forcedWrap[cell.Cell] = cell.Wrap
+		spec.fitWidth[cell.Cell] = cell.FitTo != ""
+	}
+	return values, spec
+}
+
+// formatLayoutOffset ispisuje pomeraj bez suvisnih decimala.
+func formatLayoutOffset(v float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", v), "0"), ".")
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -33,6 +34,28 @@ func (h *httpHandler) addGuiRoutes() {
 	protected.GET("/ui/krstenice/table", h.renderKrsteniceTable())
 	protected.GET("/ui/krstenice/new", h.renderKrsteniceNew())
 	protected.GET("/ui/krstenice/:id/edit", h.renderKrsteniceEdit())
+	protected.GET("/ui/krstenice/:id/zabeleske", h.renderKrstenicaAnnotations())
+	protected.GET("/ui/krstenice/:id/zabeleske/new", h.renderKrstenicaAnnotationNew())
+	protected.GET("/ui/krstenice/:id/prilozi", h.renderKrstenicaAttachments())
+	protected.GET("/ui/krstenice/:id/pregled", h.renderKrstenicaPreviewDialog())
+	protected.GET("/ui/krstenice/:id/istorija", h.renderKrstenicaVersions())
+	protected.GET("/ui/krstenice/:id/istorija/:version", h.renderKrstenicaVersionCompare())
+	protected.GET("/ui/krstenice/picker", h.renderKrstenicePicker())
+	protected.GET("/ui/krstenice/picker/table", h.renderKrstenicePickerTable())
+	protected.GET("/ui/krstenice/picker/select/:id", h.handleKrstenicePickerSelect())
+
+	protected.GET("/ui/izdata-uverenja", h.renderIzdataUverenjaPage())
+	protected.GET("/ui/izdata-uverenja/table", h.renderIzdataUverenjaTable())
+
+	protected.GET("/ui/vencanice", h.renderVencanicePage())
+	protected.GET("/ui/vencanice/table", h.renderVencaniceTable())
+	protected.GET("/ui/vencanice/new", h.renderVencaniceNew())
+	protected.GET("/ui/vencanice/:id/edit", h.renderVencaniceEdit())
+
+	protected.GET("/ui/umrlice", h.renderUmrlicePage())
+	protected.GET("/ui/umrlice/table", h.renderUmrliceTable())
+	protected.GET("/ui/umrlice/new", h.renderUmrliceNew())
+	protected.GET("/ui/umrlice/:id/edit", h.renderUmrliceEdit())
 
 	protected.GET("/ui/eparhije", h.renderEparhijePage())
 	protected.GET("/ui/eparhije/table", h.renderEparhijeTable())
@@ -73,7 +96,23 @@ func (h *httpHandler) addGuiRoutes() {
 	protected.GET("/ui/osobe/picker/table", h.renderOsobePickerTable())
 	protected.GET("/ui/osobe/picker/select/:id", h.handleOsobePickerSelect())
 
+	protected.GET("/ui/kalibracija", h.renderCalibrationsPage())
+	protected.GET("/ui/kalibracija/table", h.renderCalibrationsTable())
+	protected.POST("/ui/kalibracija", h.handleCalibrationCreate())
+	protected.POST("/ui/kalibracija/radna-stanica", h.handleWorkstationSet())
+	protected.GET("/ui/kalibracija/:id", h.renderCalibrationEdit())
+	protected.PUT("/ui/kalibracija/:id", h.handleCalibrationUpdate())
+	protected.DELETE("/ui/kalibracija/:id", h.handleCalibrationDelete())
+	protected.GET("/ui/kalibracija/:id/probna-strana", h.getCalibrationTestPage())
+
 	adminUI := protected.Group("", h.requireUIRole(adminRoleDefault))
+	adminUI.GET("/ui/knjige", h.renderKnjigePage())
+	adminUI.GET("/ui/knjige/table", h.renderKnjigeTable())
+	adminUI.GET("/ui/knjige/new", h.renderKnjigeNew())
+	adminUI.GET("/ui/knjige/:id/edit", h.renderKnjigeEdit())
+	adminUI.GET("/ui/provera-numeracije", h.renderNumberingPage())
+	adminUI.GET("/ui/provera-numeracije/table", h.renderNumberingTable())
+
 	adminUI.GET("/ui/users", h.renderUsersPage())
 	adminUI.GET("/ui/users/table", h.renderUsersTable())
 	adminUI.GET("/ui/users/new", h.renderUsersNew())
@@ -81,6 +120,34 @@ func (h *httpHandler) addGuiRoutes() {
 	adminUI.POST("/ui/users", h.handleUsersCreate())
 	adminUI.PUT("/ui/users/:id", h.handleUsersUpdate())
 	adminUI.DELETE("/ui/users/:id", h.handleUsersDelete())
+
+	adminUI.GET("/ui/audit-log", h.renderAuditLogPage())
+	adminUI.GET("/ui/audit-log/table", h.renderAuditLogTable())
+
+	adminUI.GET("/ui/trash", h.renderTrashPage())
+	adminUI.GET("/ui/trash/table", h.renderTrashTable())
+
+	adminUI.GET("/ui/uvoz-krstenica", h.renderKrsteniceImportPage())
+	adminUI.POST("/ui/uvoz-krstenica", h.handleKrsteniceImport())
+
+	adminUI.GET("/ui/rasporedi-uverenja", h.renderLayoutsPage())
+	adminUI.GET("/ui/rasporedi-uverenja/table", h.renderLayoutsTable())
+	adminUI.POST("/ui/rasporedi-uverenja", h.handleLayoutCreate())
+	adminUI.GET("/ui/rasporedi-uverenja/:id", h.renderLayoutEdit())
+	adminUI.PUT("/ui/rasporedi-uverenja/:id", h.handleLayoutUpdate())
+	adminUI.POST("/ui/rasporedi-uverenja/:id/default", h.handleLayoutSetDefault())
+	adminUI.DELETE("/ui/rasporedi-uverenja/:id", h.handleLayoutDelete())
+
+	adminUI.GET("/ui/obrasci-uverenja", h.renderTemplatesPage())
+	adminUI.GET("/ui/obrasci-uverenja/table", h.renderTemplatesTable())
+	adminUI.POST("/ui/obrasci-uverenja", h.handleTemplateCreate())
+	adminUI.POST("/ui/obrasci-uverenja/ugradjeni", h.handleTemplateClearDefault())
+	adminUI.GET("/ui/obrasci-uverenja/:id", h.renderTemplateDetail())
+	adminUI.PUT("/ui/obrasci-uverenja/:id", h.handleTemplateUpdate())
+	adminUI.POST("/ui/obrasci-uverenja/:id/verzije", h.handleTemplateVersionCreate())
+	adminUI.GET("/ui/obrasci-uverenja/:id/verzije/:versionId/:part", h.downloadTemplateFile())
+	adminUI.POST("/ui/obrasci-uverenja/:id/default", h.handleTemplateSetDefault())
+	adminUI.DELETE("/ui/obrasci-uverenja/:id", h.handleTemplateDelete())
 }
 
 func (h *httpHandler) renderDashboard() gin.HandlerFunc {
@@ -93,10 +160,11 @@ func (h *httpHandler) renderDashboard() gin.HandlerFunc {
 }
 
 type krsteniceTableData struct {
-	Items      []*dto.Krstenica
-	Pagination paginationData
-	Total      int64
-	Filters    map[string]string
+	Items          []*dto.Krstenica
+	Pagination     paginationData
+	Total          int64
+	Filters        map[string]string
+	SigningEnabled bool
 }
 
 type eparhijeTableData struct {
@@ -143,9 +211,15 @@ type paginationData struct {
 
 func (h *httpHandler) renderKrstenicePage() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
+		templates, err := h.service.ListCertificateTemplates(ctx.Request.Context())
+		if err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{"Message": err.Error()})
+			return
+		}
 		h.renderHTML(ctx, http.StatusOK, "krstenice/index.html", gin.H{
 			"Title":           "Krstenice",
 			"ContentTemplate": "krstenice/content",
+			"Templates":       templates,
 		})
 	}
 }
@@ -169,9 +243,10 @@ func (h *httpHandler) renderKrsteniceTable() gin.HandlerFunc {
 		queryValues := cloneValues(ctx.Request.URL.Query())
 
 		data := &krsteniceTableData{
-			Items:   items,
-			Total:   total,
-			Filters: buildFilterMap(queryValues),
+			Items:          items,
+			Total:          total,
+			Filters:        buildFilterMap(queryValues),
+			SigningEnabled: h.conf.Signing.CertFile != "" && h.conf.Signing.KeyFile != "",
 			Pagination: paginationData{
 				Page:       pageNumber,
 				PageSize:   pageSize,
@@ -212,9 +287,18 @@ func (h *httpHandler) renderKrsteniceNew() gin.HandlerFunc {
 			return
 		}
 
+		knjige, err := h.listOpenBooksForForm(cx)
+		if err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+
 		h.renderHTML(ctx, http.StatusOK, "krstenice/new.html", gin.H{
 			"Eparhije": eparhije,
 			"Hramovi":  hramovi,
+			"Knjige":   knjige,
 		})
 	}
 }
@@ -255,14 +339,162 @@ func (h *httpHandler) renderKrsteniceEdit() gin.HandlerFunc {
 			return
 		}
 
+		umrlice, _, err := h.service.ListUmrlice(cx, &pkg.FilterAndSort{
+			Filters: map[pkg.FilterKey][]string{
+				{Property: "krstenica_id", Operator: "eq"}: {strconv.Itoa(id)},
+			},
+		})
+		if err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+
 		h.renderHTML(ctx, http.StatusOK, "krstenice/edit.html", gin.H{
 			"Krstenica": krstenica,
 			"Eparhije":  eparhije,
 			"Hramovi":   hramovi,
+			"Umrlice":   umrlice,
+		})
+	}
+}
+
+func (h *httpHandler) renderKrstenicePicker() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		field := strings.TrimSpace(ctx.Query("field"))
+		if field == "" {
+			field = "krstenica_id"
+		}
+
+		h.renderHTML(ctx, http.StatusOK, "krstenice/picker.html", gin.H{
+			"Field": field,
+		})
+	}
+}
+
+func (h *httpHandler) renderKrstenicePickerTable() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		field := strings.TrimSpace(ctx.Query("field"))
+		if field == "" {
+			field = "krstenica_id"
+		}
+
+		values := cloneValues(ctx.Request.URL.Query())
+		values.Del("field")
+
+		data, err := h.buildKrstenicePickerTable(ctx.Request.Context(), values, ctx.Request.URL.Path)
+		if err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+
+		h.renderHTML(ctx, http.StatusOK, "krstenice/picker-table.html", gin.H{
+			"Field": field,
+			"Data":  data,
 		})
 	}
 }
 
+func (h *httpHandler) buildKrstenicePickerTable(ctx context.Context, values url.Values, basePath string) (*krsteniceTableData, error) {
+	filters := &pkg.FilterAndSort{
+		Filters: map[pkg.FilterKey][]string{},
+		Sort:    []*pkg.SortOptions{},
+		Paging:  &pkg.Paging{},
+	}
+
+	pageNumber := parsePositiveInt(values.Get("page_number"), 1)
+	pageSize := parsePositiveInt(values.Get("page_size"), 10)
+	filters.Paging.PageNumber = strconv.Itoa(pageNumber)
+	filters.Paging.PageSize = strconv.Itoa(pageSize)
+
+	for _, key := range []string{"first_name", "last_name"} {
+		if value := strings.TrimSpace(values.Get(key)); value != "" {
+			filters.Filters[pkg.FilterKey{Property: key, Operator: "icontains"}] = []string{value}
+		}
+	}
+
+	items, total, err := h.service.ListKrstenice(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	queryCopy := cloneValues(values)
+
+	data := &krsteniceTableData{
+		Items:   items,
+		Total:   total,
+		Filters: buildFilterMap(queryCopy),
+		Pagination: paginationData{
+			Page:       pageNumber,
+			PageSize:   pageSize,
+			Total:      total,
+			TotalPages: calculateTotalPages(total, pageSize),
+			HasPrev:    pageNumber > 1,
+			HasNext:    int64(pageNumber*pageSize) < total,
+			PrevPage:   max(pageNumber-1, 1),
+			NextPage:   pageNumber + 1,
+			Query:      queryCopy.Encode(),
+		},
+	}
+
+	data.Pagination.PrevLink = buildPageLink(basePath, queryCopy, data.Pagination.PrevPage, pageSize)
+	data.Pagination.NextLink = buildPageLink(basePath, queryCopy, data.Pagination.NextPage, pageSize)
+
+	return data, nil
+}
+
+func (h *httpHandler) handleKrstenicePickerSelect() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			h.renderHTML(ctx, http.StatusBadRequest, "partials/error.html", gin.H{
+				"Message": "Nepostojeci identifikator krstenice",
+			})
+			return
+		}
+
+		field := strings.TrimSpace(ctx.Query("field"))
+		if field == "" {
+			field = "krstenica_id"
+		}
+
+		krstenica, err := h.service.GetKrstenicaByID(ctx.Request.Context(), int64(id))
+		if err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+
+		label := fmt.Sprintf("%s (књ. %s, стр. %d, бр. %d)",
+			strings.TrimSpace(krstenica.FirstName+" "+krstenica.LastName),
+			strings.TrimSpace(krstenica.Book), krstenica.Page, krstenica.CurrentNumber)
+
+		payload := map[string]interface{}{
+			"person-selected": map[string]interface{}{
+				"field": field,
+				"id":    krstenica.ID,
+				"label": label,
+			},
+			"close-picker": true,
+		}
+
+		bytes, err := json.Marshal(payload)
+		if err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+
+		ctx.Header("HX-Trigger", string(bytes))
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
 func (h *httpHandler) renderEparhijePage() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		h.renderHTML(ctx, http.StatusOK, "eparhije/index.html", gin.H{
@@ -1167,11 +1399,12 @@ func (h *httpHandler) renderOsobePicker() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		field := strings.TrimSpace(ctx.Query("field"))
 		if field == "" {
-			field = "parent_id"
+			field = "father_id"
 		}
 
 		h.renderHTML(ctx, http.StatusOK, "osobe/picker.html", gin.H{
-			"Field": field,
+			"Field":    field,
+			"Multiple": isMultiplePicker(ctx),
 		})
 	}
 }
@@ -1180,11 +1413,12 @@ func (h *httpHandler) renderOsobePickerTable() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		field := strings.TrimSpace(ctx.Query("field"))
 		if field == "" {
-			field = "parent_id"
+			field = "father_id"
 		}
 
 		values := cloneValues(ctx.Request.URL.Query())
 		values.Del("field")
+		values.Del("multiple")
 
 		data, err := h.buildOsobeTable(ctx.Request.Context(), values, ctx.Request.URL.Path)
 		if err != nil {
@@ -1195,12 +1429,18 @@ func (h *httpHandler) renderOsobePickerTable() gin.HandlerFunc {
 		}
 
 		h.renderHTML(ctx, http.StatusOK, "osobe/picker-table.html", gin.H{
-			"Field": field,
-			"Data":  data,
+			"Field":    field,
+			"Multiple": isMultiplePicker(ctx),
+			"Data":     data,
 		})
 	}
 }
 
+func isMultiplePicker(ctx *gin.Context) bool {
+	value := strings.TrimSpace(ctx.Query("multiple"))
+	return value == "1" || strings.EqualFold(value, "true")
+}
+
 func (h *httpHandler) handleOsobePickerSelect() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		id, err := strconv.Atoi(ctx.Param("id"))
@@ -1213,7 +1453,7 @@ func (h *httpHandler) handleOsobePickerSelect() gin.HandlerFunc {
 
 		field := strings.TrimSpace(ctx.Query("field"))
 		if field == "" {
-			field = "parent_id"
+			field = "father_id"
 		}
 
 		person, err := h.service.GetPersonByID(ctx.Request.Context(), int64(id))
@@ -1235,7 +1475,10 @@ func (h *httpHandler) handleOsobePickerSelect() gin.HandlerFunc {
 				"id":    person.ID,
 				"label": label,
 			},
-			"close-picker": true,
+		}
+		// kod izbora više osoba (npr. kumovi) picker ostaje otvoren
+		if !isMultiplePicker(ctx) {
+			payload["close-picker"] = true
 		}
 
 		bytes, err := json.Marshal(payload)
@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"krstenica/internal/dto"
+	"krstenica/internal/model"
+	"krstenica/pkg"
+)
+
+// auditEntityLabels su nazivi entiteta za prikaz i izbor u filteru.
+var auditEntityLabels = []struct {
+	Value string
+	Label string
+}{
+	{model.AuditEntityKrstenica, "Крштеница"},
+	{model.AuditEntityAnnotation, "Забелешка крштенице"},
+	{model.AuditEntityAttachment, "Прилог крштенице"},
+	{model.AuditEntityLayout, "Распоред уверења"},
+	{model.AuditEntityTemplate, "Образац уверења"},
+	{model.AuditEntityCalibration, "Калибрација штампача"},
+	{model.AuditEntityVencanica, "Венчаница"},
+	{model.AuditEntityUmrlica, "Умрлица"},
+	{model.AuditEntityEparhija, "Епархија"},
+	{model.AuditEntityTample, "Храм"},
+	{model.AuditEntityPriest, "Свештеник"},
+	{model.AuditEntityPerson, "Особа"},
+	{model.AuditEntityBook, "Књига"},
+	{model.AuditEntityUser, "Корисник"},
+}
+
+var auditActionLabels = map[string]string{
+	string(model.AuditActionCreate):  "Унос",
+	string(model.AuditActionUpdate):  "Измена",
+	string(model.AuditActionDelete):  "Брисање",
+	string(model.AuditActionRestore): "Враћање из корпе",
+	string(model.AuditActionPurge):   "Трајно брисање",
+}
+
+type auditLogTableData struct {
+	Items      []*dto.AuditLog
+	Pagination paginationData
+	Total      int64
+	Filters    map[string]string
+}
+
+func (h *httpHandler) renderAuditLogPage() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		h.renderHTML(ctx, http.StatusOK, "audit-log/index.html", gin.H{
+			"Title":           "Dnevnik izmena",
+			"ContentTemplate": "audit-log/content",
+			"Entities":        auditEntityLabels,
+		})
+	}
+}
+
+func (h *httpHandler) renderAuditLogTable() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		data, err := h.buildAuditLogTable(ctx.Request.Context(), ctx.Request.URL.Query(), ctx.Request.URL.Path)
+		if err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+
+		h.renderHTML(ctx, http.StatusOK, "audit-log/table.html", data)
+	}
+}
+
+func (h *httpHandler) buildAuditLogTable(ctx context.Context, values url.Values, basePath string) (*auditLogTableData, error) {
+	filters := &pkg.FilterAndSort{
+		Filters: map[pkg.FilterKey][]string{},
+		Sort:    []*pkg.SortOptions{},
+		Paging:  &pkg.Paging{},
+	}
+
+	pageNumber := parsePositiveInt(values.Get("page_number"), 1)
+	pageSize := parsePositiveInt(values.Get("page_size"), 20)
+	filters.Paging.PageNumber = strconv.Itoa(pageNumber)
+	filters.Paging.PageSize = strconv.Itoa(pageSize)
+
+	for key, val := range values {
+		if isPagingKey(key) {
+			continue
+		}
+
+		trimmed := make([]string, 0, len(val))
+		for _, item := range val {
+			if strings.TrimSpace(item) != "" {
+				trimmed = append(trimmed, strings.TrimSpace(item))
+			}
+		}
+		if len(trimmed) == 0 {
+			continue
+		}
+
+		switch key {
+		case "date_from":
+			if day, err := time.Parse("2006-01-02", normalizeDateInputString(trimmed[0])); err == nil {
+				filters.Filters[pkg.FilterKey{Property: "created_at", Operator: "gte"}] = []string{day.Format("2006-01-02")}
+			}
+			continue
+		case "date_to":
+			if day, err := time.Parse("2006-01-02", normalizeDateInputString(trimmed[0])); err == nil {
+				filters.Filters[pkg.FilterKey{Property: "created_at", Operator: "lt"}] = []string{day.AddDate(0, 0, 1).Format("2006-01-02")}
+			}
+			continue
+		case "entity_id":
+			if _, err := strconv.ParseInt(trimmed[0], 10, 64); err != nil {
+				continue
+			}
+		}
+
+		operator := "eq"
+		if key == "username" {
+			operator = "icontains"
+		}
+
+		filters.Filters[pkg.FilterKey{Property: key, Operator: operator}] = trimmed
+	}
+
+	items, total, err := h.service.ListAuditLogs(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	queryCopy := cloneValues(values)
+
+	data := &auditLogTableData{
+		Items:   items,
+		Total:   total,
+		Filters: buildFilterMap(queryCopy),
+		Pagination: paginationData{
+			Page:       pageNumber,
+			PageSize:   pageSize,
+			Total:      total,
+			TotalPages: calculateTotalPages(total, pageSize),
+			HasPrev:    pageNumber > 1,
+			HasNext:    int64(pageNumber*pageSize) < total,
+			PrevPage:   max(pageNumber-1, 1),
+			NextPage:   pageNumber + 1,
+			Query:      queryCopy.Encode(),
+		},
+	}
+
+	data.Pagination.PrevLink = buildPageLink(basePath, queryCopy, data.Pagination.PrevPage, pageSize)
+	data.Pagination.NextLink = buildPageLink(basePath, queryCopy, data.Pagination.NextPage, pageSize)
+
+	return data, nil
+}
+
+func auditEntityLabel(entity string) string {
+	for _, item := range auditEntityLabels {
+		if item.Value == entity {
+			return item.Label
+		}
+	}
+	return entity
+}
+
+func auditActionLabel(action string) string {
+	if label, ok := auditActionLabels[action]; ok {
+		return label
+	}
+	return action
+}
+
+// formatAuditValue prikazuje staru/novu vrednost polja; prazno se prikazuje kao crtica.
+func formatAuditValue(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return "—"
+	case string:
+		if value == "" {
+			return "—"
+		}
+		if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			if t.IsZero() {
+				return "—"
+			}
+			return formatSerbianDateTime(t)
+		}
+		return value
+	case bool:
+		if value {
+			return "да"
+		}
+		return "не"
+	}
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return "?"
+	}
+	return string(payload)
+}
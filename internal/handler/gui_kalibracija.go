@@ -0,0 +1,363 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"krstenica/internal/dto"
+	"krstenica/internal/errorx"
+	"krstenica/pkg"
+)
+
+const (
+	// workstationCookieName cuva naziv radne stanice u pregledacu; po njemu se
+	// bira profil kalibracije stampaca prikljucenog na taj racunar.
+	workstationCookieName   = "krstenica_workstation"
+	workstationCookieMaxAge = 365 * 24 * 60 * 60
+)
+
+// workstationName vraca naziv radne stanice sa koje je stigao zahtev.
+func workstationName(ctx *gin.Context) string {
+	name, err := ctx.Cookie(workstationCookieName)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(name)
+}
+
+type calibrationsTableData struct {
+	Items       []*dto.PrintCalibration
+	Workstation string
+	// Active je profil koji se primenjuje pri stampi sa ovog racunara
+	Active  *dto.PrintCalibration
+	Success string
+	Error   string
+}
+
+type calibrationEditData struct {
+	Calibration *dto.PrintCalibration
+	// LayoutCells su celije podrazumevanog rasporeda, kao predlog za pomeraje
+	LayoutCells []string
+	Templates   []*dto.CertificateTemplate
+	Success     string
+	Error       string
+}
+
+func (h *httpHandler) renderCalibrationsPage() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		h.renderHTML(ctx, http.StatusOK, "kalibracija/index.html", gin.H{
+			"Title":           "Kalibracija stampaca",
+			"ContentTemplate": "kalibracija/content",
+			"Workstation":     workstationName(ctx),
+		})
+	}
+}
+
+func (h *httpHandler) renderCalibrationsTable() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		h.calibrationsTableResponse(ctx, workstationName(ctx), "", "")
+	}
+}
+
+// *************************************************************Kalibracija stampaca*************************************
+func (h *httpHandler) handleWorkstationSet() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		name := strings.TrimSpace(ctx.PostForm("workstation"))
+		if utf8.RuneCountInString(name) > 100 {
+			h.calibrationsTableResponse(ctx, workstationName(ctx), "", "Назив радне станице је предугачак")
+			return
+		}
+		maxAge := workstationCookieMaxAge
+		if name == "" {
+			maxAge = -1
+		}
+		ctx.SetCookie(workstationCookieName, name, maxAge, "/", "", h.isSecureRequest(ctx), true)
+
+		message := "Овај рачунар више нема назив радне станице."
+		if name != "" {
+			message = "Овај рачунар је радна станица '" + name + "'."
+		}
+		h.calibrationsTableResponse(ctx, name, message, "")
+	}
+}
+
+func (h *httpHandler) handleCalibrationCreate() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		req, err := parseCalibrationForm(ctx)
+		if err != nil {
+			h.calibrationsTableResponse(ctx, workstationName(ctx), "", err.Error())
+			return
+		}
+		created, err := h.service.CreatePrintCalibration(ctx.Request.Context(), req)
+		if err != nil {
+			h.calibrationsTableResponse(ctx, workstationName(ctx), "", err.Error())
+			return
+		}
+		ctx.Header("HX-Redirect", fmt.Sprintf("/ui/kalibracija/%d", created.ID))
+		ctx.Status(http.StatusOK)
+	}
+}
+
+func (h *httpHandler) renderCalibrationEdit() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			h.renderHTML(ctx, http.StatusBadRequest, "partials/error.html", gin.H{"Message": "Непознат профил калибрације"})
+			return
+		}
+		calibration, err := h.service.GetPrintCalibrationByID(ctx.Request.Context(), id)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, errorx.ErrPrintCalibrationNotFound) {
+				status = http.StatusNotFound
+			}
+			h.renderHTML(ctx, status, "partials/error.html", gin.H{"Message": err.Error()})
+			return
+		}
+		h.renderHTML(ctx, http.StatusOK, "kalibracija/edit.html", gin.H{
+			"Title":           "Kalibracija stampaca",
+			"ContentTemplate": "kalibracija/edit-content",
+			"Edit":            h.newCalibrationEditData(ctx, calibration, "", ""),
+		})
+	}
+}
+
+func (h *httpHandler) handleCalibrationUpdate() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			h.renderHTML(ctx, http.StatusBadRequest, "partials/error.html", gin.H{"Message": "Непознат профил калибрације"})
+			return
+		}
+
+		req, err := parseCalibrationForm(ctx)
+		if err != nil {
+			h.renderCalibrationForm(ctx, calibrationFromRequest(id, req), "", err.Error())
+			return
+		}
+
+		updated, err := h.service.UpdatePrintCalibration(ctx.Request.Context(), id, req)
+		if err != nil {
+			// neispravan unos ostaje u formi da bi se mogao ispraviti
+			h.renderCalibrationForm(ctx, calibrationFromRequest(id, req), "", err.Error())
+			return
+		}
+		h.renderCalibrationForm(ctx, updated, "Профил калибрације је сачуван.", "")
+	}
+}
+
+func (h *httpHandler) handleCalibrationDelete() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			h.calibrationsTableResponse(ctx, workstationName(ctx), "", "Непознат профил калибрације")
+			return
+		}
+		calibration, err := h.service.GetPrintCalibrationByID(ctx.Request.Context(), id)
+		if err != nil {
+			h.calibrationsTableResponse(ctx, workstationName(ctx), "", err.Error())
+			return
+		}
+		if err := h.service.DeletePrintCalibration(ctx.Request.Context(), id); err != nil {
+			h.calibrationsTableResponse(ctx, workstationName(ctx), "", err.Error())
+			return
+		}
+		h.calibrationsTableResponse(ctx, workstationName(ctx), "Профил '"+calibration.Name+"' је обрисан.", "")
+	}
+}
+
+// getCalibrationTestPage pravi PDF sa mrezom i oznakama celija rasporeda,
+// nacrtanim sa kalibracijom profila. Strana se stampa preko praznog obrasca
+// da bi se izmerilo koliko stampac jos odstupa.
+func (h *httpHandler) getCalibrationTestPage() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		cx := ctx.Request.Context()
+		calibration, err := h.service.GetPrintCalibrationByID(cx, id)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, errorx.ErrPrintCalibrationNotFound) {
+				status = http.StatusNotFound
+			}
+			ctx.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+
+		// obrazac i raspored se biraju istim parametrima kao pri stampi; stampa
+		// iz GUI koristi obrazac za pregled, pa i probna strana
+		filters := pkg.ParseUrlQuery(ctx)
+		filters.Filters[pkg.FilterKey{Property: "calibration", Operator: "eq"}] = []string{"none"}
+		if _, ok := filters.Filters[pkg.FilterKey{Property: "preview", Operator: "eq"}]; !ok {
+			filters.Filters[pkg.FilterKey{Property: "preview", Operator: "eq"}] = []string{"true"}
+		}
+		opts, status, err := h.parseKrstenicaPrintOptions(cx, filters, "")
+		if err != nil {
+			ctx.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		files, err := h.krstenicaTemplateFiles(cx, opts, &dto.Krstenica{})
+		if err != nil {
+			ctx.JSON(templateErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		backgroundImage := ""
+		if ctx.Query("background") == "true" {
+			backgroundImage = files.backgroundImage
+		}
+
+		targetDir, err := os.MkdirTemp("", "krstenica")
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create temp directory"})
+			return
+		}
+		defer os.RemoveAll(targetDir)
+
+		targetFile := filepath.Join(targetDir, "kalibracija.pdf")
+		if err := fillCalibrationTestPagePDF(calibration, opts.layout, files, backgroundImage, targetFile); err != nil {
+			log.Println("Error generating calibration test page:", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to generate PDF file: %v", err)})
+			return
+		}
+		sendGeneratedFile(ctx, targetFile, "application/pdf", "kalibracija.pdf")
+	}
+}
+
+func (h *httpHandler) calibrationsTableResponse(ctx *gin.Context, workstation, successMsg, errorMsg string) {
+	cx := ctx.Request.Context()
+	calibrations, err := h.service.ListPrintCalibrations(cx)
+	if err != nil {
+		h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{"Message": err.Error()})
+		return
+	}
+	active, err := h.service.ResolvePrintCalibration(cx, workstation)
+	if err != nil {
+		h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{"Message": err.Error()})
+		return
+	}
+	h.renderHTML(ctx, http.StatusOK, "kalibracija/table.html", calibrationsTableData{
+		Items:       calibrations,
+		Workstation: workstation,
+		Active:      active,
+		Success:     successMsg,
+		Error:       errorMsg,
+	})
+}
+
+func (h *httpHandler) renderCalibrationForm(ctx *gin.Context, calibration *dto.PrintCalibration, successMsg, errorMsg string) {
+	h.renderHTML(ctx, http.StatusOK, "kalibracija/form.html", h.newCalibrationEditData(ctx, calibration, successMsg, errorMsg))
+}
+
+// newCalibrationEditData dopunjuje formu celijama podrazumevanog rasporeda i
+// obrascima za probnu stranu; greska pri citanju predloga ne sprecava izmenu.
+func (h *httpHandler) newCalibrationEditData(ctx *gin.Context, calibration *dto.PrintCalibration, successMsg, errorMsg string) *calibrationEditData {
+	data := &calibrationEditData{
+		Calibration: calibration,
+		Success:     successMsg,
+		Error:       errorMsg,
+	}
+	cx := ctx.Request.Context()
+	if layout, err := h.service.GetDefaultCertificateLayout(cx); err == nil {
+		seen := map[string]bool{}
+		for _, cell := range layout.Cells {
+			if !seen[cell.Cell] {
+				seen[cell.Cell] = true
+				data.LayoutCells = append(data.LayoutCells, cell.Cell)
+			}
+		}
+	} else {
+		log.Println(err)
+	}
+	if templates, err := h.service.ListCertificateTemplates(cx); err == nil {
+		data.Templates = templates
+	} else {
+		log.Println(err)
+	}
+	return data
+}
+
+func calibrationFromRequest(id int64, req *dto.PrintCalibrationReq) *dto.PrintCalibration {
+	calibration := &dto.PrintCalibration{
+		ID:          id,
+		Name:        req.Name,
+		OffsetX:     req.OffsetX,
+		OffsetY:     req.OffsetY,
+		Scale:       req.Scale,
+		Workstation: req.Workstation,
+		Cells:       req.Cells,
+	}
+	if req.Scope == dto.PrintCalibrationScopeUser {
+		// oznaka licnog profila u formi; vlasnika odredjuje servis
+		calibration.UserId = new(int64)
+	}
+	return calibration
+}
+
+// parseCalibrationForm cita profil iz forme; pomeraji celija su nizovi istog
+// redosleda, a decimalni zarez se prihvata kao tacka.
+func parseCalibrationForm(ctx *gin.Context) (*dto.PrintCalibrationReq, error) {
+	req := &dto.PrintCalibrationReq{
+		Name:        ctx.PostForm("name"),
+		Scope:       strings.TrimSpace(ctx.PostForm("scope")),
+		Workstation: strings.TrimSpace(ctx.PostForm("workstation")),
+	}
+
+	number := func(value string, fallback float64) (float64, error) {
+		value = strings.ReplaceAll(strings.TrimSpace(value), ",", ".")
+		if value == "" {
+			return fallback, nil
+		}
+		return strconv.ParseFloat(value, 64)
+	}
+
+	var err error
+	if req.OffsetX, err = number(ctx.PostForm("offset_x"), 0); err != nil {
+		return req, errors.New("неисправан X помак")
+	}
+	if req.OffsetY, err = number(ctx.PostForm("offset_y"), 0); err != nil {
+		return req, errors.New("неисправан Y помак")
+	}
+	if req.Scale, err = number(ctx.PostForm("scale"), 1); err != nil {
+		return req, errors.New("неисправна размера")
+	}
+
+	offsetsX := ctx.PostFormArray("cell_offset_x")
+	offsetsY := ctx.PostFormArray("cell_offset_y")
+	column := func(values []string, i int) string {
+		if i < len(values) {
+			return values[i]
+		}
+		return ""
+	}
+	var parseErr error
+	for i, ref := range ctx.PostFormArray("cell") {
+		cell := dto.PrintCalibrationCell{Cell: strings.TrimSpace(ref)}
+		if cell.Cell == "" {
+			continue
+		}
+		if cell.OffsetX, err = number(column(offsetsX, i), 0); err != nil && parseErr == nil {
+			parseErr = fmt.Errorf("ред %d: неисправан X помак", i+1)
+		}
+		if cell.OffsetY, err = number(column(offsetsY, i), 0); err != nil && parseErr == nil {
+			parseErr = fmt.Errorf("ред %d: неисправан Y помак", i+1)
+		}
+		req.Cells = append(req.Cells, cell)
+	}
+
+	return req, parseErr
+}
+
+//****************************************************end******Kalibracija stampaca*************************************
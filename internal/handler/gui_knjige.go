@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"krstenica/internal/dto"
+	"krstenica/pkg"
+)
+
+type knjigeTableData struct {
+	Items      []*dto.Book
+	Pagination paginationData
+	Total      int64
+	Filters    map[string]string
+}
+
+func (h *httpHandler) renderKnjigePage() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		h.renderHTML(ctx, http.StatusOK, "knjige/index.html", gin.H{
+			"Title":           "Knjige",
+			"ContentTemplate": "knjige/content",
+		})
+	}
+}
+
+func (h *httpHandler) renderKnjigeTable() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		data, err := h.buildKnjigeTable(ctx.Request.Context(), ctx.Request.URL.Query(), ctx.Request.URL.Path)
+		if err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+
+		h.renderHTML(ctx, http.StatusOK, "knjige/table.html", data)
+	}
+}
+
+func (h *httpHandler) buildKnjigeTable(ctx context.Context, values url.Values, basePath string) (*knjigeTableData, error) {
+	filters := &pkg.FilterAndSort{
+		Filters: map[pkg.FilterKey][]string{},
+		Sort:    []*pkg.SortOptions{},
+		Paging:  &pkg.Paging{},
+	}
+
+	pageNumber := parsePositiveInt(values.Get("page_number"), 1)
+	pageSize := parsePositiveInt(values.Get("page_size"), 10)
+	filters.Paging.PageNumber = strconv.Itoa(pageNumber)
+	filters.Paging.PageSize = strconv.Itoa(pageSize)
+
+	for key, val := range values {
+		if isPagingKey(key) {
+			continue
+		}
+
+		trimmed := make([]string, 0, len(val))
+		for _, item := range val {
+			if strings.TrimSpace(item) != "" {
+				trimmed = append(trimmed, item)
+			}
+		}
+		if len(trimmed) == 0 {
+			continue
+		}
+
+		operator := "eq"
+		switch key {
+		case "name", "tample_name":
+			operator = "icontains"
+		}
+
+		filters.Filters[pkg.FilterKey{Property: key, Operator: operator}] = trimmed
+	}
+
+	items, total, err := h.service.ListBooks(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	queryCopy := cloneValues(values)
+
+	data := &knjigeTableData{
+		Items:   items,
+		Total:   total,
+		Filters: buildFilterMap(queryCopy),
+		Pagination: paginationData{
+			Page:       pageNumber,
+			PageSize:   pageSize,
+			Total:      total,
+			TotalPages: calculateTotalPages(total, pageSize),
+			HasPrev:    pageNumber > 1,
+			HasNext:    int64(pageNumber*pageSize) < total,
+			PrevPage:   max(pageNumber-1, 1),
+			NextPage:   pageNumber + 1,
+			Query:      queryCopy.Encode(),
+		},
+	}
+
+	data.Pagination.PrevLink = buildPageLink(basePath, queryCopy, data.Pagination.PrevPage, pageSize)
+	data.Pagination.NextLink = buildPageLink(basePath, queryCopy, data.Pagination.NextPage, pageSize)
+
+	return data, nil
+}
+
+func (h *httpHandler) renderKnjigeNew() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		hramovi, err := h.listActiveHramoviForForm(ctx.Request.Context())
+		if err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+
+		h.renderHTML(ctx, http.StatusOK, "knjige/new.html", gin.H{
+			"Knjiga":  &dto.Book{PageCapacity: 200, EntriesPerPage: 10},
+			"Hramovi": hramovi,
+		})
+	}
+}
+
+func (h *httpHandler) renderKnjigeEdit() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			h.renderHTML(ctx, http.StatusBadRequest, "partials/error.html", gin.H{
+				"Message": "Nepostojeci identifikator knjige",
+			})
+			return
+		}
+
+		cx := ctx.Request.Context()
+
+		knjiga, err := h.service.GetBookByID(cx, int64(id))
+		if err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+
+		hramovi, err := h.listActiveHramoviForForm(cx)
+		if err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+
+		h.renderHTML(ctx, http.StatusOK, "knjige/edit.html", gin.H{
+			"Knjiga":  knjiga,
+			"Hramovi": hramovi,
+		})
+	}
+}
+
+// listOpenBooksForForm vraća otvorene knjige za izbor pri novom upisu krštenice.
+func (h *httpHandler) listOpenBooksForForm(ctx context.Context) ([]*dto.Book, error) {
+	filters := &pkg.FilterAndSort{
+		Filters: map[pkg.FilterKey][]string{},
+		Sort:    []*pkg.SortOptions{},
+		Paging: &pkg.Paging{
+			All: "yes",
+		},
+	}
+
+	filters.Filters[pkg.FilterKey{Property: "state", Operator: "eq"}] = []string{"open"}
+	filters.Filters[pkg.FilterKey{Property: "status", Operator: "eq"}] = []string{"active"}
+	filters.Sort = append(filters.Sort,
+		&pkg.SortOptions{Property: "tample_name", Direction: "ASC"},
+		&pkg.SortOptions{Property: "year_from", Direction: "DESC"},
+	)
+
+	items, _, err := h.service.ListBooks(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
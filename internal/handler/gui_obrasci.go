@@ -0,0 +1,357 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"krstenica/internal/dto"
+	"krstenica/internal/errorx"
+)
+
+type templatesTableData struct {
+	Items   []*dto.CertificateTemplate
+	Success string
+	Error   string
+}
+
+type templateDetailData struct {
+	Template  *dto.CertificateTemplate
+	Eparhije  []*dto.Eparhije
+	Tamples   []*dto.Tample
+	MaxSizeMB int64
+	Success   string
+	Error     string
+}
+
+// IsAssignedEparhija proverava da li je obrazac dodeljen eparhiji.
+func (d *templateDetailData) IsAssignedEparhija(id int64) bool {
+	for _, a := range d.Template.Assignments {
+		if a.EparhijaId != nil && *a.EparhijaId == id {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAssignedTample proverava da li je obrazac dodeljen hramu.
+func (d *templateDetailData) IsAssignedTample(id int64) bool {
+	for _, a := range d.Template.Assignments {
+		if a.TampleId != nil && *a.TampleId == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *httpHandler) renderTemplatesPage() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		h.renderHTML(ctx, http.StatusOK, "obrasci/index.html", gin.H{
+			"Title":           "Obrasci uverenja",
+			"ContentTemplate": "obrasci/content",
+			"MaxSizeMB":       h.conf.Templates.MaxSizeMB,
+		})
+	}
+}
+
+func (h *httpHandler) renderTemplatesTable() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		h.templatesTableResponse(ctx, "", "")
+	}
+}
+
+// *************************************************************Obrasci uverenja*************************************
+func (h *httpHandler) handleTemplateCreate() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		versionReq, err := h.parseTemplateVersionForm(ctx)
+		if err != nil {
+			h.templatesTableResponse(ctx, "", err.Error())
+			return
+		}
+		created, err := h.service.CreateCertificateTemplate(ctx.Request.Context(), &dto.CertificateTemplateCreateReq{
+			Name:                          ctx.PostForm("name"),
+			CertificateTemplateVersionReq: *versionReq,
+		})
+		if err != nil {
+			h.templatesTableResponse(ctx, "", err.Error())
+			return
+		}
+		ctx.Header("HX-Redirect", fmt.Sprintf("/ui/obrasci-uverenja/%d", created.ID))
+		ctx.Status(http.StatusOK)
+	}
+}
+
+func (h *httpHandler) renderTemplateDetail() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			h.renderHTML(ctx, http.StatusBadRequest, "partials/error.html", gin.H{"Message": "Непознат образац"})
+			return
+		}
+		template, err := h.service.GetCertificateTemplateByID(ctx.Request.Context(), id)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, errorx.ErrCertificateTemplateNotFound) {
+				status = http.StatusNotFound
+			}
+			h.renderHTML(ctx, status, "partials/error.html", gin.H{"Message": err.Error()})
+			return
+		}
+		data, err := h.newTemplateDetailData(ctx, template, "", "")
+		if err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{"Message": err.Error()})
+			return
+		}
+		h.renderHTML(ctx, http.StatusOK, "obrasci/detail.html", gin.H{
+			"Title":           "Obrazac uverenja",
+			"ContentTemplate": "obrasci/detail-content",
+			"Detail":          data,
+		})
+	}
+}
+
+func (h *httpHandler) handleTemplateUpdate() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			h.renderHTML(ctx, http.StatusBadRequest, "partials/error.html", gin.H{"Message": "Непознат образац"})
+			return
+		}
+
+		var req dto.CertificateTemplateUpdateReq
+		if err := ctx.ShouldBind(&req); err != nil {
+			h.renderTemplatePartial(ctx, "obrasci/settings.html", id, "", "Неисправан унос")
+			return
+		}
+		if _, err := h.service.UpdateCertificateTemplate(ctx.Request.Context(), id, &req); err != nil {
+			h.renderTemplatePartial(ctx, "obrasci/settings.html", id, "", err.Error())
+			return
+		}
+		h.renderTemplatePartial(ctx, "obrasci/settings.html", id, "Образац је сачуван.", "")
+	}
+}
+
+func (h *httpHandler) handleTemplateVersionCreate() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			h.renderHTML(ctx, http.StatusBadRequest, "partials/error.html", gin.H{"Message": "Непознат образац"})
+			return
+		}
+
+		req, err := h.parseTemplateVersionForm(ctx)
+		if err != nil {
+			h.renderTemplatePartial(ctx, "obrasci/versions.html", id, "", err.Error())
+			return
+		}
+		template, err := h.service.AddCertificateTemplateVersion(ctx.Request.Context(), id, req)
+		if err != nil {
+			h.renderTemplatePartial(ctx, "obrasci/versions.html", id, "", err.Error())
+			return
+		}
+		h.renderTemplatePartial(ctx, "obrasci/versions.html", id,
+			fmt.Sprintf("Сачувана је верзија %d.", template.CurrentVersion.Version), "")
+	}
+}
+
+func (h *httpHandler) handleTemplateSetDefault() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			h.templatesTableResponse(ctx, "", "Непознат образац")
+			return
+		}
+		template, err := h.service.SetDefaultCertificateTemplate(ctx.Request.Context(), id)
+		if err != nil {
+			h.templatesTableResponse(ctx, "", err.Error())
+			return
+		}
+		h.templatesTableResponse(ctx, "Образац '"+template.Name+"' је сада подразумевани.", "")
+	}
+}
+
+func (h *httpHandler) handleTemplateClearDefault() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if err := h.service.ClearDefaultCertificateTemplate(ctx.Request.Context()); err != nil {
+			h.templatesTableResponse(ctx, "", err.Error())
+			return
+		}
+		h.templatesTableResponse(ctx, "Без додељеног обрасца штампа се по уграђеном обрасцу.", "")
+	}
+}
+
+func (h *httpHandler) handleTemplateDelete() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			h.templatesTableResponse(ctx, "", "Непознат образац")
+			return
+		}
+		template, err := h.service.GetCertificateTemplateByID(ctx.Request.Context(), id)
+		if err != nil {
+			h.templatesTableResponse(ctx, "", err.Error())
+			return
+		}
+		if err := h.service.DeleteCertificateTemplate(ctx.Request.Context(), id); err != nil {
+			h.templatesTableResponse(ctx, "", err.Error())
+			return
+		}
+		h.templatesTableResponse(ctx, "Образац '"+template.Name+"' је обрисан.", "")
+	}
+}
+
+// downloadTemplateFile salje XLSX obrazac, obrazac za pregled ili pozadinu jedne verzije.
+func (h *httpHandler) downloadTemplateFile() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		versionID, err := strconv.ParseInt(ctx.Param("versionId"), 10, 64)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		fileName, contentType, file, err := h.service.OpenCertificateTemplateFile(ctx.Request.Context(), id, versionID, ctx.Param("part"))
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, errorx.ErrCertificateTemplateNotFound) {
+				status = http.StatusNotFound
+			}
+			ctx.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		defer file.Close()
+
+		info, err := file.Stat()
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read file"})
+			return
+		}
+
+		ctx.DataFromReader(http.StatusOK, info.Size(), contentType, file, map[string]string{
+			"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": fileName}),
+			"X-Content-Type-Options": "nosniff",
+		})
+	}
+}
+
+func (h *httpHandler) templatesTableResponse(ctx *gin.Context, successMsg, errorMsg string) {
+	templates, err := h.service.ListCertificateTemplates(ctx.Request.Context())
+	if err != nil {
+		h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{"Message": err.Error()})
+		return
+	}
+	h.renderHTML(ctx, http.StatusOK, "obrasci/table.html", templatesTableData{
+		Items:   templates,
+		Success: successMsg,
+		Error:   errorMsg,
+	})
+}
+
+// renderTemplatePartial ponovo ucitava obrazac i prikazuje deo stranice obrasca sa porukom.
+func (h *httpHandler) renderTemplatePartial(ctx *gin.Context, name string, id int64, successMsg, errorMsg string) {
+	template, err := h.service.GetCertificateTemplateByID(ctx.Request.Context(), id)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, errorx.ErrCertificateTemplateNotFound) {
+			status = http.StatusNotFound
+		}
+		h.renderHTML(ctx, status, "partials/error.html", gin.H{"Message": err.Error()})
+		return
+	}
+	data, err := h.newTemplateDetailData(ctx, template, successMsg, errorMsg)
+	if err != nil {
+		h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{"Message": err.Error()})
+		return
+	}
+	h.renderHTML(ctx, http.StatusOK, name, data)
+}
+
+func (h *httpHandler) newTemplateDetailData(ctx *gin.Context, template *dto.CertificateTemplate, successMsg, errorMsg string) (*templateDetailData, error) {
+	eparhije, err := h.listActiveEparhijeForForm(ctx.Request.Context())
+	if err != nil {
+		return nil, err
+	}
+	tamples, err := h.listActiveHramoviForForm(ctx.Request.Context())
+	if err != nil {
+		return nil, err
+	}
+	return &templateDetailData{
+		Template:  template,
+		Eparhije:  eparhije,
+		Tamples:   tamples,
+		MaxSizeMB: h.conf.Templates.MaxSizeMB,
+		Success:   successMsg,
+		Error:     errorMsg,
+	}, nil
+}
+
+// parseTemplateVersionForm cita fajlove verzije obrasca iz multipart forme;
+// polja bez odabranog fajla ostaju prazna.
+func (h *httpHandler) parseTemplateVersionForm(ctx *gin.Context) (*dto.CertificateTemplateVersionReq, error) {
+	maxSize := h.service.CertificateTemplateMaxSizeBytes()
+	// tri fajla i mala rezerva za multipart zaglavlja; tacna provera velicine je u servisu
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, 3*maxSize+1<<20)
+	if _, err := ctx.MultipartForm(); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, fmt.Errorf("фајлови не смеју бити већи од %d MB", maxSize>>20)
+		}
+		return nil, errors.New("неисправан унос")
+	}
+
+	req := &dto.CertificateTemplateVersionReq{
+		Note:                ctx.PostForm("note"),
+		BackgroundFullBleed: ctx.PostForm("background_full_bleed") == "yes",
+		RemoveBackground:    ctx.PostForm("remove_background") == "yes",
+	}
+	var err error
+	if req.Template, err = readTemplateFormFile(ctx, "template", maxSize); err != nil {
+		return nil, err
+	}
+	if req.PreviewTemplate, err = readTemplateFormFile(ctx, "preview_template", maxSize); err != nil {
+		return nil, err
+	}
+	if req.Background, err = readTemplateFormFile(ctx, "background", maxSize); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func readTemplateFormFile(ctx *gin.Context, name string, maxSize int64) (*dto.CertificateTemplateFile, error) {
+	fileHeader, err := ctx.FormFile(name)
+	if err != nil {
+		if errors.Is(err, http.ErrMissingFile) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if fileHeader.Size == 0 && fileHeader.Filename == "" {
+		return nil, nil
+	}
+	if fileHeader.Size > maxSize {
+		return nil, fmt.Errorf("фајл %s не сме бити већи од %d MB", fileHeader.Filename, maxSize>>20)
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	return &dto.CertificateTemplateFile{FileName: fileHeader.Filename, Content: content}, nil
+}
+
+//****************************************************end******Obrasci uverenja*************************************
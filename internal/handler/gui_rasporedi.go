@@ -0,0 +1,260 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"krstenica/internal/dto"
+	"krstenica/internal/errorx"
+	"krstenica/internal/service"
+)
+
+type layoutsTableData struct {
+	Items   []*dto.CertificateLayout
+	Success string
+	Error   string
+}
+
+type layoutEditData struct {
+	Layout     *dto.CertificateLayout
+	Fields     []dto.CertificateLayoutField
+	Targets    []dto.CertificateLayoutOption
+	DateStyles []dto.CertificateLayoutOption
+	Success    string
+	Error      string
+}
+
+// layoutRowData je jedan red forme rasporeda; Cell je nil za prazan red.
+type layoutRowData struct {
+	Cell       *dto.CertificateLayoutCell
+	Fields     []dto.CertificateLayoutField
+	Targets    []dto.CertificateLayoutOption
+	DateStyles []dto.CertificateLayoutOption
+}
+
+func (d *layoutEditData) Rows() []layoutRowData {
+	rows := make([]layoutRowData, len(d.Layout.Cells))
+	for i := range d.Layout.Cells {
+		rows[i] = d.BlankRow()
+		rows[i].Cell = &d.Layout.Cells[i]
+	}
+	return rows
+}
+
+func (d *layoutEditData) BlankRow() layoutRowData {
+	return layoutRowData{Fields: d.Fields, Targets: d.Targets, DateStyles: d.DateStyles}
+}
+
+func (h *httpHandler) renderLayoutsPage() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		layouts, err := h.service.ListCertificateLayouts(ctx.Request.Context())
+		if err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{"Message": err.Error()})
+			return
+		}
+		h.renderHTML(ctx, http.StatusOK, "rasporedi/index.html", gin.H{
+			"Title":           "Rasporedi uverenja",
+			"ContentTemplate": "rasporedi/content",
+			"Layouts":         layouts,
+		})
+	}
+}
+
+func (h *httpHandler) renderLayoutsTable() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		h.layoutsTableResponse(ctx, "", "")
+	}
+}
+
+// *************************************************************Raspored uverenja*************************************
+func (h *httpHandler) handleLayoutCreate() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req dto.CertificateLayoutCreateReq
+		if err := ctx.ShouldBind(&req); err != nil {
+			h.layoutsTableResponse(ctx, "", "Неисправан унос")
+			return
+		}
+		created, err := h.service.CreateCertificateLayout(ctx.Request.Context(), &req)
+		if err != nil {
+			h.layoutsTableResponse(ctx, "", err.Error())
+			return
+		}
+		ctx.Header("HX-Redirect", fmt.Sprintf("/ui/rasporedi-uverenja/%d", created.ID))
+		ctx.Status(http.StatusOK)
+	}
+}
+
+func (h *httpHandler) renderLayoutEdit() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			h.renderHTML(ctx, http.StatusBadRequest, "partials/error.html", gin.H{"Message": "Непознат распоред"})
+			return
+		}
+		layout, err := h.service.GetCertificateLayoutByID(ctx.Request.Context(), id)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, errorx.ErrCertificateLayoutNotFound) {
+				status = http.StatusNotFound
+			}
+			h.renderHTML(ctx, status, "partials/error.html", gin.H{"Message": err.Error()})
+			return
+		}
+		h.renderHTML(ctx, http.StatusOK, "rasporedi/edit.html", gin.H{
+			"Title":           "Raspored uverenja",
+			"ContentTemplate": "rasporedi/edit-content",
+			"Edit":            newLayoutEditData(layout, "", ""),
+		})
+	}
+}
+
+func (h *httpHandler) handleLayoutUpdate() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			h.renderHTML(ctx, http.StatusBadRequest, "partials/error.html", gin.H{"Message": "Непознат распоред"})
+			return
+		}
+
+		req, err := parseLayoutForm(ctx)
+		if err != nil {
+			h.renderLayoutForm(ctx, &dto.CertificateLayout{ID: id, Name: req.Name, Cells: req.Cells}, "", err.Error())
+			return
+		}
+
+		updated, err := h.service.UpdateCertificateLayout(ctx.Request.Context(), id, req)
+		if err != nil {
+			// neispravan unos ostaje u formi da bi se mogao ispraviti
+			current := &dto.CertificateLayout{ID: id, Name: req.Name, Cells: req.Cells}
+			if existing, getErr := h.service.GetCertificateLayoutByID(ctx.Request.Context(), id); getErr == nil {
+				current.IsDefault = existing.IsDefault
+			}
+			h.renderLayoutForm(ctx, current, "", err.Error())
+			return
+		}
+		h.renderLayoutForm(ctx, updated, "Распоред је сачуван.", "")
+	}
+}
+
+func (h *httpHandler) handleLayoutSetDefault() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			h.layoutsTableResponse(ctx, "", "Непознат распоред")
+			return
+		}
+		layout, err := h.service.SetDefaultCertificateLayout(ctx.Request.Context(), id)
+		if err != nil {
+			h.layoutsTableResponse(ctx, "", err.Error())
+			return
+		}
+		h.layoutsTableResponse(ctx, "Распоред '"+layout.Name+"' се сада користи при штампи.", "")
+	}
+}
+
+func (h *httpHandler) handleLayoutDelete() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			h.layoutsTableResponse(ctx, "", "Непознат распоред")
+			return
+		}
+		layout, err := h.service.GetCertificateLayoutByID(ctx.Request.Context(), id)
+		if err != nil {
+			h.layoutsTableResponse(ctx, "", err.Error())
+			return
+		}
+		if err := h.service.DeleteCertificateLayout(ctx.Request.Context(), id); err != nil {
+			h.layoutsTableResponse(ctx, "", err.Error())
+			return
+		}
+		h.layoutsTableResponse(ctx, "Распоред '"+layout.Name+"' је обрисан.", "")
+	}
+}
+
+func (h *httpHandler) layoutsTableResponse(ctx *gin.Context, successMsg, errorMsg string) {
+	layouts, err := h.service.ListCertificateLayouts(ctx.Request.Context())
+	if err != nil {
+		h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{"Message": err.Error()})
+		return
+	}
+	h.renderHTML(ctx, http.StatusOK, "rasporedi/table.html", layoutsTableData{
+		Items:   layouts,
+		Success: successMsg,
+		Error:   errorMsg,
+	})
+}
+
+func (h *httpHandler) renderLayoutForm(ctx *gin.Context, layout *dto.CertificateLayout, successMsg, errorMsg string) {
+	h.renderHTML(ctx, http.StatusOK, "rasporedi/form.html", newLayoutEditData(layout, successMsg, errorMsg))
+}
+
+func newLayoutEditData(layout *dto.CertificateLayout, successMsg, errorMsg string) *layoutEditData {
+	return &layoutEditData{
+		Layout:     layout,
+		Fields:     service.KrstenicaLayoutFields(),
+		Targets:    service.CertificateLayoutTargets(),
+		DateStyles: service.CertificateLayoutDateStyles(),
+		Success:    successMsg,
+		Error:      errorMsg,
+	}
+}
+
+// parseLayoutForm cita redove celija iz forme; svako polje reda je poseban
+// niz istog redosleda, a bold i wrap dolaze kao skrivena polja 0/1.
+func parseLayoutForm(ctx *gin.Context) (*dto.CertificateLayoutUpdateReq, error) {
+	req := &dto.CertificateLayoutUpdateReq{Name: ctx.PostForm("name")}
+
+	cells := ctx.PostFormArray("cell")
+	// prefiks i sufiks se ne skracaju jer razmak moze biti deo teksta
+	rawColumn := func(name string, i int) string {
+		values := ctx.PostFormArray(name)
+		if i < len(values) {
+			return values[i]
+		}
+		return ""
+	}
+	column := func(name string, i int) string {
+		return strings.TrimSpace(rawColumn(name, i))
+	}
+	offset := func(name string, i int) (float64, error) {
+		value := strings.ReplaceAll(column(name, i), ",", ".")
+		if value == "" {
+			return 0, nil
+		}
+		return strconv.ParseFloat(value, 64)
+	}
+
+	var parseErr error
+	for i := range cells {
+		cell := dto.CertificateLayoutCell{
+			Cell:      column("cell", i),
+			Field:     column("field", i),
+			Target:    column("target", i),
+			DateStyle: column("date_style", i),
+			Prefix:    rawColumn("prefix", i),
+			Suffix:    rawColumn("suffix", i),
+			Bold:      column("bold", i) == "1",
+			Wrap:      column("wrap", i) == "1",
+			FitTo:     column("fit_to", i),
+		}
+		if cell.Cell == "" && cell.Field == "" {
+			continue
+		}
+		var err error
+		if cell.OffsetX, err = offset("offset_x", i); err != nil && parseErr == nil {
+			parseErr = fmt.Errorf("ред %d: неисправан X помак", i+1)
+		}
+		if cell.OffsetY, err = offset("offset_y", i); err != nil && parseErr == nil {
+			parseErr = fmt.Errorf("ред %d: неисправан Y помак", i+1)
+		}
+		req.Cells = append(req.Cells, cell)
+	}
+
+	return req, parseErr
+}
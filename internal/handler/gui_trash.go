@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"krstenica/internal/dto"
+	"krstenica/internal/model"
+	"krstenica/pkg"
+)
+
+// trashEntityLabels su vrste zapisa koje se mogu naći u korpi.
+var trashEntityLabels = []struct {
+	Value string
+	Label string
+}{
+	{model.AuditEntityKrstenica, "Крштеница"},
+	{model.AuditEntityPerson, "Особа"},
+	{model.AuditEntityPriest, "Свештеник"},
+	{model.AuditEntityTample, "Храм"},
+	{model.AuditEntityEparhija, "Епархија"},
+}
+
+type trashTableData struct {
+	Items         []*dto.TrashItem
+	Pagination    paginationData
+	Total         int64
+	Filters       map[string]string
+	RetentionDays int
+}
+
+func (h *httpHandler) renderTrashPage() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		h.renderHTML(ctx, http.StatusOK, "trash/index.html", gin.H{
+			"Title":           "Korpa",
+			"ContentTemplate": "trash/content",
+			"Entities":        trashEntityLabels,
+			"RetentionDays":   h.conf.Trash.RetentionDays,
+		})
+	}
+}
+
+func (h *httpHandler) renderTrashTable() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		data, err := h.buildTrashTable(ctx.Request.Context(), ctx.Request.URL.Query(), ctx.Request.URL.Path)
+		if err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+
+		h.renderHTML(ctx, http.StatusOK, "trash/table.html", data)
+	}
+}
+
+func (h *httpHandler) buildTrashTable(ctx context.Context, values url.Values, basePath string) (*trashTableData, error) {
+	filters := &pkg.FilterAndSort{
+		Filters: map[pkg.FilterKey][]string{},
+		Sort:    []*pkg.SortOptions{},
+		Paging:  &pkg.Paging{},
+	}
+
+	pageNumber := parsePositiveInt(values.Get("page_number"), 1)
+	pageSize := parsePositiveInt(values.Get("page_size"), 20)
+	filters.Paging.PageNumber = strconv.Itoa(pageNumber)
+	filters.Paging.PageSize = strconv.Itoa(pageSize)
+
+	for key, val := range values {
+		if isPagingKey(key) {
+			continue
+		}
+
+		trimmed := make([]string, 0, len(val))
+		for _, item := range val {
+			if strings.TrimSpace(item) != "" {
+				trimmed = append(trimmed, strings.TrimSpace(item))
+			}
+		}
+		if len(trimmed) == 0 {
+			continue
+		}
+
+		// period brisanja: od datuma uključivo, do datuma uključivo (< sledeći dan)
+		switch key {
+		case "date_from":
+			if day, err := time.Parse("2006-01-02", normalizeDateInputString(trimmed[0])); err == nil {
+				filters.Filters[pkg.FilterKey{Property: "deleted_at", Operator: "gte"}] = []string{day.Format("2006-01-02")}
+			}
+			continue
+		case "date_to":
+			if day, err := time.Parse("2006-01-02", normalizeDateInputString(trimmed[0])); err == nil {
+				filters.Filters[pkg.FilterKey{Property: "deleted_at", Operator: "lt"}] = []string{day.AddDate(0, 0, 1).Format("2006-01-02")}
+			}
+			continue
+		}
+
+		operator := "eq"
+		switch key {
+		case "name", "deleted_by_username":
+			operator = "icontains"
+		}
+
+		filters.Filters[pkg.FilterKey{Property: key, Operator: operator}] = trimmed
+	}
+
+	items, total, err := h.service.ListTrash(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	queryCopy := cloneValues(values)
+
+	data := &trashTableData{
+		Items:         items,
+		Total:         total,
+		Filters:       buildFilterMap(queryCopy),
+		RetentionDays: h.conf.Trash.RetentionDays,
+		Pagination: paginationData{
+			Page:       pageNumber,
+			PageSize:   pageSize,
+			Total:      total,
+			TotalPages: calculateTotalPages(total, pageSize),
+			HasPrev:    pageNumber > 1,
+			HasNext:    int64(pageNumber*pageSize) < total,
+			PrevPage:   max(pageNumber-1, 1),
+			NextPage:   pageNumber + 1,
+			Query:      queryCopy.Encode(),
+		},
+	}
+
+	data.Pagination.PrevLink = buildPageLink(basePath, queryCopy, data.Pagination.PrevPage, pageSize)
+	data.Pagination.NextLink = buildPageLink(basePath, queryCopy, data.Pagination.NextPage, pageSize)
+
+	return data, nil
+}
@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"krstenica/internal/dto"
+	"krstenica/pkg"
+)
+
+type umrliceTableData struct {
+	Items      []*dto.Umrlica
+	Pagination paginationData
+	Total      int64
+	Filters    map[string]string
+}
+
+func (h *httpHandler) renderUmrlicePage() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		h.renderHTML(ctx, http.StatusOK, "umrlice/index.html", gin.H{
+			"Title":           "Umrlice",
+			"ContentTemplate": "umrlice/content",
+		})
+	}
+}
+
+func (h *httpHandler) renderUmrliceTable() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		data, err := h.buildUmrliceTable(ctx.Request.Context(), ctx.Request.URL.Query(), ctx.Request.URL.Path)
+		if err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+
+		h.renderHTML(ctx, http.StatusOK, "umrlice/table.html", data)
+	}
+}
+
+func (h *httpHandler) buildUmrliceTable(ctx context.Context, values url.Values, basePath string) (*umrliceTableData, error) {
+	filters := &pkg.FilterAndSort{
+		Filters: map[pkg.FilterKey][]string{},
+		Sort:    []*pkg.SortOptions{},
+		Paging:  &pkg.Paging{},
+	}
+
+	pageNumber := parsePositiveInt(values.Get("page_number"), 1)
+	pageSize := parsePositiveInt(values.Get("page_size"), 10)
+	filters.Paging.PageNumber = strconv.Itoa(pageNumber)
+	filters.Paging.PageSize = strconv.Itoa(pageSize)
+
+	for key, val := range values {
+		if isPagingKey(key) {
+			continue
+		}
+
+		trimmed := make([]string, 0, len(val))
+		for _, item := range val {
+			if strings.TrimSpace(item) != "" {
+				trimmed = append(trimmed, item)
+			}
+		}
+		if len(trimmed) == 0 {
+			continue
+		}
+
+		operator := "eq"
+		switch key {
+		case "deceased_first_name", "deceased_last_name", "book", "cemetery":
+			operator = "icontains"
+		}
+
+		filters.Filters[pkg.FilterKey{Property: key, Operator: operator}] = trimmed
+	}
+
+	items, total, err := h.service.ListUmrlice(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	queryCopy := cloneValues(values)
+
+	data := &umrliceTableData{
+		Items:   items,
+		Total:   total,
+		Filters: buildFilterMap(queryCopy),
+		Pagination: paginationData{
+			Page:       pageNumber,
+			PageSize:   pageSize,
+			Total:      total,
+			TotalPages: calculateTotalPages(total, pageSize),
+			HasPrev:    pageNumber > 1,
+			HasNext:    int64(pageNumber*pageSize) < total,
+			PrevPage:   max(pageNumber-1, 1),
+			NextPage:   pageNumber + 1,
+			Query:      queryCopy.Encode(),
+		},
+	}
+
+	data.Pagination.PrevLink = buildPageLink(basePath, queryCopy, data.Pagination.PrevPage, pageSize)
+	data.Pagination.NextLink = buildPageLink(basePath, queryCopy, data.Pagination.NextPage, pageSize)
+
+	return data, nil
+}
+
+func (h *httpHandler) renderUmrliceNew() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		cx := ctx.Request.Context()
+
+		eparhije, err := h.listActiveEparhijeForForm(cx)
+		if err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+
+		hramovi, err := h.listActiveHramoviForForm(cx)
+		if err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+
+		h.renderHTML(ctx, http.StatusOK, "umrlice/new.html", gin.H{
+			"Umrlica":  &dto.Umrlica{},
+			"Eparhije": eparhije,
+			"Hramovi":  hramovi,
+		})
+	}
+}
+
+func (h *httpHandler) renderUmrliceEdit() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			h.renderHTML(ctx, http.StatusBadRequest, "partials/error.html", gin.H{
+				"Message": "Nepostojeci identifikator umrlice",
+			})
+			return
+		}
+
+		cx := ctx.Request.Context()
+
+		umrlica, err := h.service.GetUmrlicaByID(cx, int64(id))
+		if err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+
+		eparhije, err := h.listActiveEparhijeForForm(cx)
+		if err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+
+		hramovi, err := h.listActiveHramoviForForm(cx)
+		if err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+
+		h.renderHTML(ctx, http.StatusOK, "umrlice/edit.html", gin.H{
+			"Umrlica":  umrlica,
+			"Eparhije": eparhije,
+			"Hramovi":  hramovi,
+		})
+	}
+}
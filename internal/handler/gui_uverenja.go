@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"krstenica/internal/dto"
+	"krstenica/internal/requestctx"
+	"krstenica/pkg"
+)
+
+type izdataUverenjaTableData struct {
+	Items      []*dto.IssuedCertificate
+	Pagination paginationData
+	Total      int64
+	Filters    map[string]string
+	CanRevoke  bool
+}
+
+func (h *httpHandler) renderIzdataUverenjaPage() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		hramovi, err := h.listActiveHramoviForForm(ctx.Request.Context())
+		if err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+
+		h.renderHTML(ctx, http.StatusOK, "izdata-uverenja/index.html", gin.H{
+			"Title":           "Izdata uverenja",
+			"ContentTemplate": "izdata-uverenja/content",
+			"Hramovi":         hramovi,
+		})
+	}
+}
+
+func (h *httpHandler) renderIzdataUverenjaTable() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		data, err := h.buildIzdataUverenjaTable(ctx.Request.Context(), ctx.Request.URL.Query(), ctx.Request.URL.Path)
+		if err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+
+		h.renderHTML(ctx, http.StatusOK, "izdata-uverenja/table.html", data)
+	}
+}
+
+func (h *httpHandler) buildIzdataUverenjaTable(ctx context.Context, values url.Values, basePath string) (*izdataUverenjaTableData, error) {
+	filters := &pkg.FilterAndSort{
+		Filters: map[pkg.FilterKey][]string{},
+		Sort:    []*pkg.SortOptions{},
+		Paging:  &pkg.Paging{},
+	}
+
+	pageNumber := parsePositiveInt(values.Get("page_number"), 1)
+	pageSize := parsePositiveInt(values.Get("page_size"), 20)
+	filters.Paging.PageNumber = strconv.Itoa(pageNumber)
+	filters.Paging.PageSize = strconv.Itoa(pageSize)
+
+	for key, val := range values {
+		if isPagingKey(key) {
+			continue
+		}
+
+		trimmed := make([]string, 0, len(val))
+		for _, item := range val {
+			if strings.TrimSpace(item) != "" {
+				trimmed = append(trimmed, strings.TrimSpace(item))
+			}
+		}
+		if len(trimmed) == 0 {
+			continue
+		}
+
+		// period izdavanja: od datuma uključivo, do datuma uključivo (< sledeći dan)
+		switch key {
+		case "date_from":
+			if day, err := time.Parse("2006-01-02", normalizeDateInputString(trimmed[0])); err == nil {
+				filters.Filters[pkg.FilterKey{Property: "created_at", Operator: "gte"}] = []string{day.Format("2006-01-02")}
+			}
+			continue
+		case "date_to":
+			if day, err := time.Parse("2006-01-02", normalizeDateInputString(trimmed[0])); err == nil {
+				filters.Filters[pkg.FilterKey{Property: "created_at", Operator: "lt"}] = []string{day.AddDate(0, 0, 1).Format("2006-01-02")}
+			}
+			continue
+		}
+
+		operator := "eq"
+		switch key {
+		case "last_name", "purpose", "issued_by_username":
+			operator = "icontains"
+		}
+
+		filters.Filters[pkg.FilterKey{Property: key, Operator: operator}] = trimmed
+	}
+
+	items, total, err := h.service.ListIssuedCertificates(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	queryCopy := cloneValues(values)
+
+	data := &izdataUverenjaTableData{
+		Items:   items,
+		Total:   total,
+		Filters: buildFilterMap(queryCopy),
+		Pagination: paginationData{
+			Page:       pageNumber,
+			PageSize:   pageSize,
+			Total:      total,
+			TotalPages: calculateTotalPages(total, pageSize),
+			HasPrev:    pageNumber > 1,
+			HasNext:    int64(pageNumber*pageSize) < total,
+			PrevPage:   max(pageNumber-1, 1),
+			NextPage:   pageNumber + 1,
+			Query:      queryCopy.Encode(),
+		},
+	}
+
+	if user, ok := requestctx.UserFromContext(ctx); ok {
+		data.CanRevoke = user.IsAdmin()
+	}
+
+	data.Pagination.PrevLink = buildPageLink(basePath, queryCopy, data.Pagination.PrevPage, pageSize)
+	data.Pagination.NextLink = buildPageLink(basePath, queryCopy, data.Pagination.NextPage, pageSize)
+
+	return data, nil
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"krstenica/internal/dto"
+	"krstenica/internal/service"
+)
+
+// krsteniceImportMaxSize je najveca velicina datoteke za uvoz krstenica.
+const krsteniceImportMaxSize = 20 << 20
+
+var krsteniceImportIssueLabels = map[string]string{
+	"error":     "Грешка",
+	"duplicate": "Дупликат",
+	"warning":   "Упозорење",
+}
+
+var krsteniceImportEntityLabels = map[string]string{
+	"person":   "Особа",
+	"priest":   "Свештеник",
+	"tample":   "Храм",
+	"eparhija": "Епархија",
+}
+
+type krsteniceImportReportData struct {
+	Report       *dto.KrstenicaImportReport
+	IssueLabels  map[string]string
+	EntityLabels map[string]string
+}
+
+func (h *httpHandler) renderKrsteniceImportPage() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		h.renderHTML(ctx, http.StatusOK, "uvoz/index.html", gin.H{
+			"Title":           "Uvoz krstenica",
+			"ContentTemplate": "uvoz/content",
+			"Mapping":         service.KrstenicaImportMappingTemplate(),
+			"MaxSizeMB":       krsteniceImportMaxSize >> 20,
+		})
+	}
+}
+
+// *************************************************************Uvoz krstenica*************************************
+func (h *httpHandler) handleKrsteniceImport() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, krsteniceImportMaxSize+1<<20)
+
+		fileHeader, err := ctx.FormFile("file")
+		if err != nil {
+			message := "Одаберите XLSX или CSV датотеку."
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				message = fmt.Sprintf("Датотека не сме бити већа од %d MB.", krsteniceImportMaxSize>>20)
+			}
+			h.renderHTML(ctx, http.StatusBadRequest, "partials/error.html", gin.H{"Message": message})
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			h.renderHTML(ctx, http.StatusBadRequest, "partials/error.html", gin.H{"Message": err.Error()})
+			return
+		}
+		defer file.Close()
+
+		content, err := io.ReadAll(io.LimitReader(file, krsteniceImportMaxSize+1))
+		if err != nil {
+			h.renderHTML(ctx, http.StatusBadRequest, "partials/error.html", gin.H{"Message": err.Error()})
+			return
+		}
+		if len(content) > krsteniceImportMaxSize {
+			h.renderHTML(ctx, http.StatusRequestEntityTooLarge, "partials/error.html", gin.H{
+				"Message": fmt.Sprintf("Датотека не сме бити већа од %d MB.", krsteniceImportMaxSize>>20),
+			})
+			return
+		}
+
+		req := &dto.KrstenicaImportReq{
+			Mapping:        ctx.PostForm("mapping"),
+			DryRun:         ctx.PostForm("commit") != "yes",
+			SkipDuplicates: ctx.PostForm("skip_duplicates") == "yes",
+		}
+		report, err := h.service.ImportKrstenice(ctx.Request.Context(), req, fileHeader.Filename, content)
+		if err != nil {
+			h.renderHTML(ctx, http.StatusBadRequest, "partials/error.html", gin.H{"Message": err.Error()})
+			return
+		}
+
+		h.renderHTML(ctx, http.StatusOK, "uvoz/report.html", &krsteniceImportReportData{
+			Report:       report,
+			IssueLabels:  krsteniceImportIssueLabels,
+			EntityLabels: krsteniceImportEntityLabels,
+		})
+	}
+}
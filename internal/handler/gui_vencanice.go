@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"krstenica/internal/dto"
+	"krstenica/pkg"
+)
+
+type vencaniceTableData struct {
+	Items      []*dto.Vencanica
+	Pagination paginationData
+	Total      int64
+	Filters    map[string]string
+}
+
+func (h *httpHandler) renderVencanicePage() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		h.renderHTML(ctx, http.StatusOK, "vencanice/index.html", gin.H{
+			"Title":           "Vencanice",
+			"ContentTemplate": "vencanice/content",
+		})
+	}
+}
+
+func (h *httpHandler) renderVencaniceTable() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		data, err := h.buildVencaniceTable(ctx.Request.Context(), ctx.Request.URL.Query(), ctx.Request.URL.Path)
+		if err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+
+		h.renderHTML(ctx, http.StatusOK, "vencanice/table.html", data)
+	}
+}
+
+func (h *httpHandler) buildVencaniceTable(ctx context.Context, values url.Values, basePath string) (*vencaniceTableData, error) {
+	filters := &pkg.FilterAndSort{
+		Filters: map[pkg.FilterKey][]string{},
+		Sort:    []*pkg.SortOptions{},
+		Paging:  &pkg.Paging{},
+	}
+
+	pageNumber := parsePositiveInt(values.Get("page_number"), 1)
+	pageSize := parsePositiveInt(values.Get("page_size"), 10)
+	filters.Paging.PageNumber = strconv.Itoa(pageNumber)
+	filters.Paging.PageSize = strconv.Itoa(pageSize)
+
+	for key, val := range values {
+		if isPagingKey(key) {
+			continue
+		}
+
+		trimmed := make([]string, 0, len(val))
+		for _, item := range val {
+			if strings.TrimSpace(item) != "" {
+				trimmed = append(trimmed, item)
+			}
+		}
+		if len(trimmed) == 0 {
+			continue
+		}
+
+		operator := "eq"
+		switch key {
+		case "groom_first_name", "groom_last_name", "bride_first_name", "bride_last_name", "book":
+			operator = "icontains"
+		}
+
+		filters.Filters[pkg.FilterKey{Property: key, Operator: operator}] = trimmed
+	}
+
+	items, total, err := h.service.ListVencanice(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	queryCopy := cloneValues(values)
+
+	data := &vencaniceTableData{
+		Items:   items,
+		Total:   total,
+		Filters: buildFilterMap(queryCopy),
+		Pagination: paginationData{
+			Page:       pageNumber,
+			PageSize:   pageSize,
+			Total:      total,
+			TotalPages: calculateTotalPages(total, pageSize),
+			HasPrev:    pageNumber > 1,
+			HasNext:    int64(pageNumber*pageSize) < total,
+			PrevPage:   max(pageNumber-1, 1),
+			NextPage:   pageNumber + 1,
+			Query:      queryCopy.Encode(),
+		},
+	}
+
+	data.Pagination.PrevLink = buildPageLink(basePath, queryCopy, data.Pagination.PrevPage, pageSize)
+	data.Pagination.NextLink = buildPageLink(basePath, queryCopy, data.Pagination.NextPage, pageSize)
+
+	return data, nil
+}
+
+func (h *httpHandler) renderVencaniceNew() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		cx := ctx.Request.Context()
+
+		eparhije, err := h.listActiveEparhijeForForm(cx)
+		if err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+
+		hramovi, err := h.listActiveHramoviForForm(cx)
+		if err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+
+		h.renderHTML(ctx, http.StatusOK, "vencanice/new.html", gin.H{
+			"Vencanica": &dto.Vencanica{},
+			"Eparhije":  eparhije,
+			"Hramovi":   hramovi,
+		})
+	}
+}
+
+func (h *httpHandler) renderVencaniceEdit() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			h.renderHTML(ctx, http.StatusBadRequest, "partials/error.html", gin.H{
+				"Message": "Nepostojeci identifikator vencanice",
+			})
+			return
+		}
+
+		cx := ctx.Request.Context()
+
+		vencanica, err := h.service.GetVencanicaByID(cx, int64(id))
+		if err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+
+		eparhije, err := h.listActiveEparhijeForForm(cx)
+		if err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+
+		hramovi, err := h.listActiveHramoviForForm(cx)
+		if err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+
+		h.renderHTML(ctx, http.StatusOK, "vencanice/edit.html", gin.H{
+			"Vencanica": vencanica,
+			"Eparhije":  eparhije,
+			"Hramovi":   hramovi,
+		})
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"krstenica/internal/config"
 	"krstenica/internal/repository"
 	"krstenica/internal/service"
+	"krstenica/pkg"
 
 	"github.com/gin-gonic/gin"
 )
@@ -53,11 +54,23 @@ func (h *httpHandler) Init() {
 			}
 			return strconv.FormatInt(*v, 10)
 		},
+		"formatScore": func(v *float64) string {
+			if v == nil {
+				return "-"
+			}
+			return strconv.FormatFloat(*v*100, 'f', 0, 64) + "%"
+		},
+		"formatAuditValue":      formatAuditValue,
+		"auditEntityLabel":      auditEntityLabel,
+		"auditActionLabel":      auditActionLabel,
+		"krstenicaChangeLabels": krstenicaChangeLabels,
+		"formatOffset":          formatLayoutOffset,
 	})
 	templateDir := resolveDir("web/templates")
 	h.mustLoadTemplates(templateDir)
 
 	h.addAuthRoutes()
+	h.addVerificationRoutes()
 	h.addRoutes()
 	h.addGuiRoutes()
 
@@ -172,3 +185,23 @@ func (h *httpHandler) renderHTML(ctx *gin.Context, status int, tmpl string, data
 	}
 	ctx.HTML(status, tmpl, data)
 }
+
+// listResponse vraca telo odgovora liste: ukupan broj kod paginacije po
+// stranama, odnosno next_cursor kod paging=cursor (null na poslednjoj strani).
+func listResponse(data interface{}, total int64, filters *pkg.FilterAndSort) gin.H {
+	if filters.IsCursorPaging() {
+		var nextCursor interface{}
+		if filters.Paging.NextCursor != "" {
+			nextCursor = filters.Paging.NextCursor
+		}
+		return gin.H{
+			"data":        data,
+			"next_cursor": nextCursor,
+		}
+	}
+
+	return gin.H{
+		"data":  data,
+		"total": total,
+	}
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"krstenica/internal/errorx"
+	"krstenica/pkg"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// *************************************************************Izdata uverenja*************************************
+func (h *httpHandler) getIssuedCertificates() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		cx := ctx.Request.Context()
+
+		certificate, err := h.service.GetIssuedCertificateByID(cx, int64(id))
+		if err != nil {
+			if err == errorx.ErrIssuedCertificateNotFound {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, certificate)
+	}
+}
+
+func (h *httpHandler) listIssuedCertificates() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		cx := ctx.Request.Context()
+
+		filters := pkg.ParseUrlQuery(ctx)
+
+		certificates, totalCount, err := h.service.ListIssuedCertificates(cx, filters)
+		if err != nil {
+			if errorx.IsValidationError(err) {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, listResponse(certificates, totalCount, filters))
+	}
+}
+
+//****************************************************end******Izdata uverenja*************************************
@@ -0,0 +1,286 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"krstenica/internal/dto"
+	"krstenica/internal/errorx"
+)
+
+// krstenicaVersionFields su polja koja se porede u istoriji, redom kao u formi.
+var krstenicaVersionFields = []struct {
+	Key   string
+	Label string
+}{
+	{"book", "Књига"},
+	{"page", "Страна"},
+	{"current_number", "Текући број"},
+	{"eparhija_name", "Епархија"},
+	{"tample_name", "Храм"},
+	{"first_name", "Име"},
+	{"last_name", "Презиме"},
+	{"gender", "Пол"},
+	{"birth_date", "Датум рођења"},
+	{"birth_order", "Које дете по реду"},
+	{"place_of_birthday", "Место рођења"},
+	{"municipality_of_birthday", "Општина рођења"},
+	{"city", "Град"},
+	{"country", "Држава"},
+	{"baptism", "Датум крштења"},
+	{"father_first_name", "Име оца"},
+	{"father_last_name", "Презиме оца"},
+	{"mother_first_name", "Име мајке"},
+	{"mother_last_name", "Презиме мајке"},
+	{"godparents", "Кумови"},
+	{"paroh_last_name", "Парох"},
+	{"priest_last_name", "Свештеник"},
+	{"is_church_married", "Црквени брак родитеља"},
+	{"is_twin", "Близанац"},
+	{"has_physical_disability", "Телесна мана"},
+	{"anagrafa", "Анаграфа"},
+	{"number_of_certificate", "Број извода"},
+	{"town_of_certificate", "Место извода"},
+	{"certificate", "Датум извода"},
+	{"comment", "Напомена"},
+}
+
+type krstenicaVersionRow struct {
+	Label   string
+	Old     string
+	Current string
+	Changed bool
+}
+
+// *************************************************************Istorija krstenice*************************************
+func (h *httpHandler) listKrstenicaVersions() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		krstenicaID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		versions, err := h.service.ListKrstenicaVersions(ctx.Request.Context(), int64(krstenicaID))
+		if err != nil {
+			ctx.JSON(krstenicaVersionErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{
+			"data":  versions,
+			"total": len(versions),
+		})
+	}
+}
+
+func (h *httpHandler) getKrstenicaVersion() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		krstenicaID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		version, err := strconv.Atoi(ctx.Param("version"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		res, err := h.service.GetKrstenicaVersion(ctx.Request.Context(), int64(krstenicaID), int64(version))
+		if err != nil {
+			ctx.JSON(krstenicaVersionErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, res)
+	}
+}
+
+func (h *httpHandler) restoreKrstenicaVersion() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		krstenicaID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		version, err := strconv.Atoi(ctx.Param("version"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		krstenica, err := h.service.RestoreKrstenicaVersion(ctx.Request.Context(), int64(krstenicaID), int64(version))
+		if err != nil {
+			ctx.JSON(krstenicaVersionErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, krstenica)
+	}
+}
+
+func krstenicaVersionErrorStatus(err error) int {
+	if errors.Is(err, errorx.ErrKrstenicaNotFound) || errors.Is(err, errorx.ErrKrstenicaVersionNotFound) {
+		return http.StatusNotFound
+	}
+	if isBookConflict(err) {
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// renderKrstenicaVersions vraća spisak verzija koji se učitava u formu za izmenu krštenice.
+func (h *httpHandler) renderKrstenicaVersions() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		krstenicaID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			h.renderHTML(ctx, http.StatusBadRequest, "partials/error.html", gin.H{
+				"Message": "Nepostojeci identifikator krstenice",
+			})
+			return
+		}
+
+		versions, err := h.service.ListKrstenicaVersions(ctx.Request.Context(), int64(krstenicaID))
+		if err != nil {
+			h.renderHTML(ctx, krstenicaVersionErrorStatus(err), "partials/error.html", gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+
+		h.renderHTML(ctx, http.StatusOK, "krstenice/istorija.html", gin.H{
+			"KrstenicaID": krstenicaID,
+			"Items":       versions,
+		})
+	}
+}
+
+// renderKrstenicaVersionCompare prikazuje izabranu verziju uporedo sa trenutnim stanjem.
+func (h *httpHandler) renderKrstenicaVersionCompare() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		krstenicaID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			h.renderHTML(ctx, http.StatusBadRequest, "partials/error.html", gin.H{
+				"Message": "Nepostojeci identifikator krstenice",
+			})
+			return
+		}
+		version, err := strconv.Atoi(ctx.Param("version"))
+		if err != nil {
+			h.renderHTML(ctx, http.StatusBadRequest, "partials/error.html", gin.H{
+				"Message": "Nepostojeca verzija krstenice",
+			})
+			return
+		}
+
+		versions, err := h.service.ListKrstenicaVersions(ctx.Request.Context(), int64(krstenicaID))
+		if err != nil {
+			h.renderHTML(ctx, krstenicaVersionErrorStatus(err), "partials/error.html", gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+
+		var selected *dto.KrstenicaVersion
+		for _, v := range versions {
+			if v.Version == int64(version) {
+				selected = v
+			}
+		}
+		if selected == nil {
+			h.renderHTML(ctx, http.StatusNotFound, "partials/error.html", gin.H{
+				"Message": errorx.ErrKrstenicaVersionNotFound.Error(),
+			})
+			return
+		}
+
+		h.renderHTML(ctx, http.StatusOK, "krstenice/istorija-verzija.html", gin.H{
+			"KrstenicaID": krstenicaID,
+			"Version":     selected,
+			"CurrentNo":   versions[0].Version,
+			"Rows":        makeKrstenicaVersionRows(selected.Krstenica, versions[0].Krstenica),
+		})
+	}
+}
+
+//****************************************************end******Istorija krstenice*************************************
+
+func makeKrstenicaVersionRows(old, current *dto.Krstenica) []krstenicaVersionRow {
+	oldFields := krstenicaVersionValues(old)
+	currentFields := krstenicaVersionValues(current)
+
+	rows := make([]krstenicaVersionRow, 0, len(krstenicaVersionFields))
+	for _, field := range krstenicaVersionFields {
+		row := krstenicaVersionRow{
+			Label:   field.Label,
+			Old:     oldFields[field.Key],
+			Current: currentFields[field.Key],
+		}
+		row.Changed = row.Old != row.Current
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// krstenicaVersionValues vraća vrednosti polja za prikaz, po JSON nazivima iz DTO.
+func krstenicaVersionValues(krstenica *dto.Krstenica) map[string]string {
+	res := map[string]string{}
+	if krstenica == nil {
+		return res
+	}
+
+	payload, err := json.Marshal(krstenica)
+	if err != nil {
+		return res
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return res
+	}
+	for key, value := range fields {
+		res[key] = formatAuditValue(value)
+	}
+
+	names := make([]string, 0, len(krstenica.Godparents))
+	for _, g := range krstenica.Godparents {
+		names = append(names, strings.TrimSpace(g.FirstName+" "+g.LastName))
+	}
+	res["godparents"] = formatAuditValue(strings.Join(names, ", "))
+	res["paroh_last_name"] = formatAuditValue(strings.TrimSpace(krstenica.ParohFirstName + " " + krstenica.ParohLastName))
+	res["priest_last_name"] = formatAuditValue(strings.TrimSpace(krstenica.PriestTitle + " " + krstenica.PriestFirstName + " " + krstenica.PriestLastName))
+	return res
+}
+
+// krstenicaChangeLabels vraća nazive izmenjenih polja za spisak verzija, bez
+// ponavljanja; identifikatori veza se prikazuju preko naziva povezanog polja.
+func krstenicaChangeLabels(changes []dto.AuditFieldChange) []string {
+	aliases := map[string]string{
+		"godfather_": "godparents",
+		"paroh_":     "paroh_last_name",
+		"priest_":    "priest_last_name",
+	}
+
+	seen := map[string]bool{}
+	labels := []string{}
+	for _, field := range krstenicaVersionFields {
+		for _, change := range changes {
+			key := change.Field
+			for prefix, alias := range aliases {
+				if strings.HasPrefix(key, prefix) {
+					key = alias
+				}
+			}
+			if key == field.Key && !seen[key] {
+				seen[key] = true
+				labels = append(labels, field.Label)
+			}
+		}
+	}
+	return labels
+}
@@ -0,0 +1,274 @@
+package handler
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+
+	"krstenica/internal/dto"
+	"krstenica/pkg"
+)
+
+type krsteniceExportColumn struct {
+	Label string
+	Width float64
+	Value func(k *dto.Krstenica) string
+}
+
+// krsteniceExportColumns su kolone izvoza krstenica, redom.
+var krsteniceExportColumns = []krsteniceExportColumn{
+	{Label: "Књига", Width: 10, Value: func(k *dto.Krstenica) string { return k.Book }},
+	{Label: "Страна", Width: 8, Value: func(k *dto.Krstenica) string { return strconv.FormatInt(k.Page, 10) }},
+	{Label: "Текући број", Width: 10, Value: func(k *dto.Krstenica) string { return strconv.FormatInt(k.CurrentNumber, 10) }},
+	{Label: "Име", Width: 16, Value: func(k *dto.Krstenica) string { return k.FirstName }},
+	{Label: "Презиме", Width: 18, Value: func(k *dto.Krstenica) string { return k.LastName }},
+	{Label: "Пол", Width: 8, Value: func(k *dto.Krstenica) string { return k.Gender }},
+	{Label: "Датум рођења", Width: 12, Value: func(k *dto.Krstenica) string { return formatExportDate(k.BirthDate) }},
+	{Label: "Место рођења", Width: 18, Value: func(k *dto.Krstenica) string { return k.PlaceOfBirthday }},
+	{Label: "Општина рођења", Width: 18, Value: func(k *dto.Krstenica) string { return k.MunicipalityOfBirthday }},
+	{Label: "Датум крштења", Width: 12, Value: func(k *dto.Krstenica) string { return formatExportDate(k.Baptism) }},
+	{Label: "Отац", Width: 24, Value: func(k *dto.Krstenica) string { return joinName(k.FatherFirstName, k.FatherLastName) }},
+	{Label: "Мајка", Width: 24, Value: func(k *dto.Krstenica) string { return joinName(k.MotherFirstName, k.MotherLastName) }},
+	{Label: "Кумови", Width: 30, Value: exportGodparents},
+	{Label: "Свештеник", Width: 28, Value: func(k *dto.Krstenica) string {
+		return joinName(k.PriestTitle, k.PriestFirstName, k.PriestLastName)
+	}},
+	{Label: "Парох", Width: 24, Value: func(k *dto.Krstenica) string { return joinName(k.ParohFirstName, k.ParohLastName) }},
+	{Label: "Храм", Width: 28, Value: func(k *dto.Krstenica) string { return k.TampleName }},
+	{Label: "Место храма", Width: 16, Value: func(k *dto.Krstenica) string { return k.TampleCity }},
+	{Label: "Епархија", Width: 24, Value: func(k *dto.Krstenica) string { return k.EparhijaName }},
+	{Label: "Напомена", Width: 40, Value: func(k *dto.Krstenica) string { return k.Comment }},
+}
+
+// krsteniceExportWriter upisuje redove izvoza direktno u odgovor.
+type krsteniceExportWriter interface {
+	WriteRow(values []string) error
+	Flush() error
+	Close() error
+}
+
+// *************************************************************Izvoz krstenica*************************************
+func (h *httpHandler) exportKrstenice() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		format := strings.ToLower(strings.TrimSpace(ctx.DefaultQuery("format", "xlsx")))
+		if format != "xlsx" && format != "csv" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "format must be xlsx or csv"})
+			return
+		}
+
+		filters := pkg.ParseUrlQuery(ctx)
+		delete(filters.Filters, pkg.FilterKey{Property: "format", Operator: "eq"})
+
+		var writer krsteniceExportWriter
+		start := func() error {
+			filename := fmt.Sprintf("krstenice-%s.%s", time.Now().Format("20060102"), format)
+			ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+			ctx.Header("Access-Control-Expose-Headers", "Content-Disposition")
+
+			var err error
+			if format == "csv" {
+				ctx.Header("Content-Type", "text/csv; charset=utf-8")
+				writer, err = newKrsteniceCSVWriter(ctx)
+			} else {
+				ctx.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+				writer, err = newKrsteniceXLSXWriter(ctx)
+			}
+			if err != nil {
+				return err
+			}
+			ctx.Status(http.StatusOK)
+
+			header := make([]string, len(krsteniceExportColumns))
+			for i, column := range krsteniceExportColumns {
+				header[i] = column.Label
+			}
+			return writer.WriteRow(header)
+		}
+
+		cx := ctx.Request.Context()
+		err := h.service.ExportKrstenice(cx, filters, func(items []*dto.Krstenica) error {
+			if writer == nil {
+				if err := start(); err != nil {
+					return err
+				}
+			}
+			for _, item := range items {
+				values := make([]string, len(krsteniceExportColumns))
+				for i, column := range krsteniceExportColumns {
+					values[i] = column.Value(item)
+				}
+				if err := writer.WriteRow(values); err != nil {
+					return err
+				}
+			}
+			return writer.Flush()
+		})
+		if err != nil {
+			if writer == nil {
+				ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			// zaglavlje je vec poslato, izvoz ostaje nepotpun
+			log.Println("Error exporting krstenice:", err)
+			return
+		}
+
+		if writer == nil {
+			if err := start(); err != nil {
+				ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+		}
+		if err := writer.Close(); err != nil {
+			log.Println("Error exporting krstenice:", err)
+		}
+	}
+}
+
+type krsteniceCSVWriter struct {
+	ctx *gin.Context
+	csv *csv.Writer
+}
+
+func newKrsteniceCSVWriter(ctx *gin.Context) (*krsteniceCSVWriter, error) {
+	// BOM da bi Excel prepoznao UTF-8 cirilicu
+	if _, err := ctx.Writer.Write([]byte("\xEF\xBB\xBF")); err != nil {
+		return nil, err
+	}
+
+	return &krsteniceCSVWriter{ctx: ctx, csv: csv.NewWriter(ctx.Writer)}, nil
+}
+
+func (w *krsteniceCSVWriter) WriteRow(values []string) error {
+	return w.csv.Write(values)
+}
+
+func (w *krsteniceCSVWriter) Flush() error {
+	w.csv.Flush()
+	if err := w.csv.Error(); err != nil {
+		return err
+	}
+	w.ctx.Writer.Flush()
+	return nil
+}
+
+func (w *krsteniceCSVWriter) Close() error {
+	return w.Flush()
+}
+
+// krsteniceXLSXWriter koristi excelize StreamWriter, koji redove preko praga
+// drzi u privremenoj datoteci umesto u memoriji.
+type krsteniceXLSXWriter struct {
+	ctx         *gin.Context
+	file        *excelize.File
+	stream      *excelize.StreamWriter
+	headerStyle int
+	row         int
+}
+
+func newKrsteniceXLSXWriter(ctx *gin.Context) (*krsteniceXLSXWriter, error) {
+	xlsxEx := excelize.NewFile()
+
+	sheetName := "Крштенице"
+	if err := xlsxEx.SetSheetName(xlsxEx.GetSheetName(0), sheetName); err != nil {
+		xlsxEx.Close()
+		return nil, err
+	}
+
+	headerStyle, err := xlsxEx.NewStyle(&excelize.Style{
+		Font:   &excelize.Font{Bold: true},
+		Fill:   excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"E6E6E6"}},
+		Border: []excelize.Border{{Type: "bottom", Color: "999999", Style: 1}},
+	})
+	if err != nil {
+		xlsxEx.Close()
+		return nil, err
+	}
+
+	stream, err := xlsxEx.NewStreamWriter(sheetName)
+	if err != nil {
+		xlsxEx.Close()
+		return nil, err
+	}
+	for i, column := range krsteniceExportColumns {
+		if err := stream.SetColWidth(i+1, i+1, column.Width); err != nil {
+			xlsxEx.Close()
+			return nil, err
+		}
+	}
+	if err := stream.SetPanes(&excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"}); err != nil {
+		xlsxEx.Close()
+		return nil, err
+	}
+
+	return &krsteniceXLSXWriter{ctx: ctx, file: xlsxEx, stream: stream, headerStyle: headerStyle, row: 1}, nil
+}
+
+func (w *krsteniceXLSXWriter) WriteRow(values []string) error {
+	cell, err := excelize.CoordinatesToCellName(1, w.row)
+	if err != nil {
+		return err
+	}
+	row := make([]interface{}, len(values))
+	for i, value := range values {
+		row[i] = value
+	}
+
+	var opts []excelize.RowOpts
+	if w.row == 1 {
+		opts = append(opts, excelize.RowOpts{StyleID: w.headerStyle})
+	}
+	w.row++
+
+	return w.stream.SetRow(cell, row, opts...)
+}
+
+// Flush ne radi nista jer se XLSX moze poslati tek kada je ceo list upisan.
+func (w *krsteniceXLSXWriter) Flush() error {
+	return nil
+}
+
+func (w *krsteniceXLSXWriter) Close() error {
+	defer w.file.Close()
+
+	if err := w.stream.Flush(); err != nil {
+		return err
+	}
+	_, err := w.file.WriteTo(w.ctx.Writer)
+	return err
+}
+
+func formatExportDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.In(time.Local).Format("02.01.2006")
+}
+
+func joinName(parts ...string) string {
+	nonEmpty := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return strings.Join(nonEmpty, " ")
+}
+
+func exportGodparents(k *dto.Krstenica) string {
+	if len(k.Godparents) == 0 {
+		return joinName(k.GodfatherFirstName, k.GodfatherLastName)
+	}
+	names := make([]string, len(k.Godparents))
+	for i, g := range k.Godparents {
+		names[i] = joinName(g.FirstName, g.LastName)
+	}
+	return strings.Join(names, ", ")
+}
@@ -15,6 +15,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/boombuler/barcode/qr"
 	"github.com/phpdave11/gofpdf"
 
 	"krstenica/internal/dto"
@@ -30,19 +31,72 @@ const (
 	defaultTextOffsetYMM = -0.9
 	pdfFontScaleFactor   = 4.0 / 3.0
 	pdfFontDefaultKey    = "default"
+	pdfMinFitFontRatio   = 0.55
+	pdfQRCodeSizeMM      = 20.0
+	pdfQRCodeMarginMM    = 8.0
+	pdfFooterFontSizePt  = 7.0
 )
 
-var forcedWrapCells = map[string]bool{
-	"C58": true,
+type textOffset struct {
+	dx float64
+	dy float64
 }
 
-var boldCells = map[string]bool{
-	"F27": true,
+// pdfCalibration ispravlja pomeranje teksta jednog stampaca. Polozaj teksta se
+// razmerava oko gornjeg levog ugla strane i pomera za offsetX i offsetY;
+// pomeraji celija se dodaju pomerajima iz rasporeda. Nil ne menja nista.
+type pdfCalibration struct {
+	offsetX float64
+	offsetY float64
+	scale   float64
+	cells   map[string]textOffset
 }
 
-type textOffset struct {
-	dx float64
-	dy float64
+func newPDFCalibration(calibration *dto.PrintCalibration) *pdfCalibration {
+	if calibration == nil {
+		return nil
+	}
+	res := &pdfCalibration{
+		offsetX: calibration.OffsetX,
+		offsetY: calibration.OffsetY,
+		scale:   calibration.Scale,
+		cells:   make(map[string]textOffset, len(calibration.Cells)),
+	}
+	if res.scale <= 0 {
+		res.scale = 1
+	}
+	for _, cell := range calibration.Cells {
+		res.cells[cell.Cell] = textOffset{dx: cell.OffsetX, dy: cell.OffsetY}
+	}
+	return res
+}
+
+// point vraca polozaj na strani posle kalibracije.
+func (c *pdfCalibration) point(x, y float64) (float64, float64) {
+	if c == nil {
+		return x, y
+	}
+	return x*c.scale + c.offsetX, y*c.scale + c.offsetY
+}
+
+// size razmerava duzinu ili velicinu slova.
+func (c *pdfCalibration) size(v float64) float64 {
+	if c == nil {
+		return v
+	}
+	return v * c.scale
+}
+
+// cellOffset dodaje pomeraj celije iz profila na pomeraj iz rasporeda.
+func (c *pdfCalibration) cellOffset(cell string, offset textOffset) textOffset {
+	if c == nil {
+		return offset
+	}
+	if o, ok := c.cells[cell]; ok {
+		offset.dx += o.dx
+		offset.dy += o.dy
+	}
+	return offset
 }
 
 type pdfFontFamily struct {
@@ -101,50 +155,6 @@ func registerPDFFontFamily(pdf *gofpdf.Fpdf, family pdfFontFamily) error {
 	return nil
 }
 
-var cellOffsets = map[string]textOffset{
-	"C1":  {dx: 0.0, dy: -8.0},
-	"C2":  {dx: 0.0, dy: -7.0},
-	"C3":  {dx: 0.0, dy: -3.5},
-	"F8":  {dx: 12.0, dy: -5.0},
-	"C10": {dx: 0.0, dy: -6.0},
-	"I10": {dx: 10.6, dy: -6.0},
-	"N10": {dx: 2.0, dy: -6.0},
-	"F13": {dx: 12.0, dy: -5.0},
-	"E16": {dx: 12.0, dy: -3.0},
-	"F16": {dx: 12.0, dy: -3.0},
-	"G16": {dx: 12.0, dy: -3.0},
-	"F19": {dx: 12.0, dy: 3.0},
-	"G24": {dx: 12.0, dy: -6.0},
-	"I24": {dx: 12.0, dy: -6.0},
-	"D27": {dx: 12.0, dy: -8.0},
-	"F27": {dx: 12.0, dy: -8.0},
-	"I27": {dx: 0.0, dy: -8.0},
-	"F30": {dx: 12.0, dy: -8.0},
-	"I30": {dx: 0.0, dy: -8.0},
-	"K30": {dx: 0.0, dy: -8.0},
-	"F31": {dx: 12.0, dy: -7.0},
-	"I31": {dx: 0.0, dy: -7.0},
-	"I32": {dx: -10.0, dy: 7.0},
-	// "K32": {dx: 7.0, dy: 3.0},
-	"I36": {dx: -10.0, dy: -3.0},
-	"I38": {dx: -10.0, dy: 3.0},
-	"I41": {dx: 5.0, dy: 1.0},
-	"F43": {dx: 12.0, dy: 9.0},
-	"H43": {dx: 10.0, dy: 9.0},
-	"K43": {dx: 0.0, dy: 9.0},
-	"E48": {dx: 10.0, dy: -2.0},
-	"G48": {dx: 10.0, dy: -2.0},
-	// "K48": {dx: 0.0, dy: -2.0},
-	"E49": {dx: 10.0, dy: 0.0},
-	"G49": {dx: 10.0, dy: 0.0},
-	"E51": {dx: 30.0, dy: 7.0},
-	"C54": {dx: 20.0, dy: 1.0},
-	"B62": {dx: 10.0, dy: -2.0},
-	"B63": {dx: 0.0, dy: 4.0},
-	"C63": {dx: 8.0, dy: 4.0},
-	"B65": {dx: 2.0, dy: 2.0},
-}
-
 type worksheetLayout struct {
 	defaultColWidthMM  float64
 	defaultRowHeightMM float64
@@ -182,133 +192,218 @@ func formatCyrillicIzCity(city string) string {
 	return fmt.Sprintf("из %s", trimmed)
 }
 
-func fillKrstenicaPDFFile(krstenica *dto.Krstenica, templatePath, targetFile, backgroundImage string, fullBleed bool, fontKey string) error {
+func fillKrstenicaPDFFile(krstenica *dto.Krstenica, certificateLayout *dto.CertificateLayout, templatePath, targetFile, backgroundImage string, fullBleed bool, fontKey, verifyURL string, signer *pdfSigner, calibration *pdfCalibration) error {
 	layout, err := loadWorksheetLayout(templatePath)
 	if err != nil {
 		return fmt.Errorf("load worksheet layout: %w", err)
 	}
 
-	values := getKrstenicaCellValues(krstenica)
-	values["G32"] = strings.TrimSpace(krstenica.BirthOrder)
-	priestFirst := strings.TrimSpace(values["F43"])
-	priestLast := strings.TrimSpace(values["H43"])
-	priestTitle := strings.TrimSpace(values["K43"])
-	var priestParts []string
-	if priestFirst != "" {
-		priestParts = append(priestParts, priestFirst)
+	values, spec := krstenicaPDFPage(krstenica, certificateLayout, verifyURL)
+	if signer != nil {
+		spec.footerText = signer.footerText()
 	}
-	if priestLast != "" {
-		priestParts = append(priestParts, priestLast)
+	if err := renderPDFCellValues(values, spec, layout, targetFile, backgroundImage, fullBleed, fontKey, calibration); err != nil {
+		return err
 	}
-	priestText := strings.Join(priestParts, " ")
-	if priestTitle != "" {
-		if priestText != "" {
-			priestText = fmt.Sprintf("%s, %s", priestText, priestTitle)
-		} else {
-			priestText = priestTitle
+	if signer != nil {
+		if err := signer.signFile(targetFile); err != nil {
+			return fmt.Errorf("sign pdf: %w", err)
+		}
+	}
+	return nil
+}
+
+// krstenicaPDFBatchPage je jedna krstenica u zbirnom stampanju.
+type krstenicaPDFBatchPage struct {
+	krstenica *dto.Krstenica
+	files     *krstenicaTemplateFiles
+	verifyURL string
+}
+
+// fillKrstenicaPDFBatchFile pravi jedan PDF sa po jednom stranom za svaku
+// krstenicu, istim rasporedom kao fillKrstenicaPDFFile. Strane mogu imati
+// razlicite obrasce.
+func fillKrstenicaPDFBatchFile(pages []krstenicaPDFBatchPage, certificateLayout *dto.CertificateLayout, targetFile string, fontKey string, signer *pdfSigner, calibration *pdfCalibration) error {
+	layouts := map[string]*worksheetLayout{}
+	for _, page := range pages {
+		if _, ok := layouts[page.files.templateFile]; ok {
+			continue
+		}
+		layout, err := loadWorksheetLayout(page.files.templateFile)
+		if err != nil {
+			return fmt.Errorf("load worksheet layout: %w", err)
 		}
+		layouts[page.files.templateFile] = layout
 	}
-	if priestText != "" {
-		values["F43"] = priestText
-		values["H43"] = ""
-		values["K43"] = ""
+
+	pdf, fontFamily, err := newCertificatePDF(fontKey)
+	if err != nil {
+		return err
 	}
-	parentFirst := strings.TrimSpace(values["F30"])
-	parentLast := strings.TrimSpace(values["I30"])
-	parentOccupation := strings.TrimSpace(values["K30"])
-	var parentParts []string
-	if parentFirst != "" {
-		parentParts = append(parentParts, parentFirst)
+	for _, page := range pages {
+		values, spec := krstenicaPDFPage(page.krstenica, certificateLayout, page.verifyURL)
+		if signer != nil {
+			spec.footerText = signer.footerText()
+		}
+		if err := drawPDFCellValuesPage(pdf, fontFamily, values, spec, layouts[page.files.templateFile], page.files.backgroundImage, page.files.backgroundFullBleed, calibration); err != nil {
+			return err
+		}
 	}
-	if parentLast != "" {
-		parentParts = append(parentParts, parentLast)
+	if err := pdf.OutputFileAndClose(targetFile); err != nil {
+		return fmt.Errorf("write pdf: %w", err)
 	}
-	parentText := strings.Join(parentParts, " ")
-	if parentOccupation != "" {
-		if parentText != "" {
-			parentText = fmt.Sprintf("%s, %s", parentText, parentOccupation)
-		} else {
-			parentText = parentOccupation
+	if signer != nil {
+		if err := signer.signFile(targetFile); err != nil {
+			return fmt.Errorf("sign pdf: %w", err)
 		}
 	}
-	if parentText != "" {
-		values["F30"] = parentText
-		values["I30"] = ""
-		values["K30"] = ""
+	return nil
+}
+
+const (
+	calibrationGridStepMM      = 10.0
+	calibrationGridMajorStepMM = 50.0
+	calibrationMarkSizeMM      = 2.0
+)
+
+// fillCalibrationTestPagePDF pravi probnu stranu za kalibraciju stampaca:
+// milimetarsku mrezu i krstice na pocetku teksta svake celije rasporeda,
+// nacrtane sa kalibracijom profila. Mreza se ne pomera pomerajima celija.
+func fillCalibrationTestPagePDF(calibration *dto.PrintCalibration, certificateLayout *dto.CertificateLayout, files *krstenicaTemplateFiles, backgroundImage, targetFile string) error {
+	layout, err := loadWorksheetLayout(files.templateFile)
+	if err != nil {
+		return fmt.Errorf("load worksheet layout: %w", err)
 	}
-	godfatherFirst := strings.TrimSpace(values["E48"])
-	godfatherLast := strings.TrimSpace(values["G48"])
-	godfatherOccupation := strings.TrimSpace(values["K48"])
-	var godfatherParts []string
-	if godfatherFirst != "" {
-		godfatherParts = append(godfatherParts, godfatherFirst)
+	pdf, fontFamily, err := newCertificatePDF(pdfFontDefaultKey)
+	if err != nil {
+		return err
 	}
-	if godfatherLast != "" {
-		godfatherParts = append(godfatherParts, godfatherLast)
+	pdfCal := newPDFCalibration(calibration)
+
+	pdf.AddPage()
+	if backgroundImage != "" {
+		if _, err := os.Stat(backgroundImage); err == nil {
+			if err := drawBackgroundImage(pdf, layout, backgroundImage, files.backgroundFullBleed); err != nil {
+				return fmt.Errorf("draw background: %w", err)
+			}
+		}
 	}
-	godfatherText := strings.Join(godfatherParts, " ")
-	if godfatherText != "" {
-		if godfatherOccupation != "" {
-			godfatherText = fmt.Sprintf("%s, %s", godfatherText, godfatherOccupation)
+
+	pageW, pageH := pdf.GetPageSize()
+	pdf.SetFont(fontFamily.name, "", 5)
+	pdf.SetTextColor(120, 120, 120)
+	for v := 0.0; v <= math.Max(pageW, pageH); v += calibrationGridStepMM {
+		major := math.Mod(v, calibrationGridMajorStepMM) == 0
+		if major {
+			pdf.SetDrawColor(120, 120, 120)
+			pdf.SetLineWidth(0.3)
 		} else {
-			godfatherText = godfatherText + ","
+			pdf.SetDrawColor(200, 200, 200)
+			pdf.SetLineWidth(0.1)
 		}
-		values["E48"] = godfatherText
-		values["G48"] = ""
-		values["K48"] = ""
-	}
-	templeCity := strings.TrimSpace(values["G24"])
-	templeName := strings.TrimSpace(values["I24"])
-	var templeParts []string
-	if templeCity != "" {
-		cityText := strings.TrimSuffix(templeCity, ",")
-		cityText = strings.TrimSpace(cityText)
-		if cityText != "" {
-			templeParts = append(templeParts, cityText+",")
+		if v <= pageW {
+			x1, y1 := pdfCal.point(v, 0)
+			x2, y2 := pdfCal.point(v, pageH)
+			pdf.Line(x1, y1, x2, y2)
+			if major {
+				pdf.Text(x1+0.5, y1+3, strconv.Itoa(int(v)))
+			}
+		}
+		if v <= pageH {
+			x1, y1 := pdfCal.point(0, v)
+			x2, y2 := pdfCal.point(pageW, v)
+			pdf.Line(x1, y1, x2, y2)
+			if major && v > 0 {
+				pdf.Text(x1+0.5, y1-0.5, strconv.Itoa(int(v)))
+			}
 		}
 	}
-	if templeName != "" {
-		templeParts = append(templeParts, templeName)
-	}
-	if len(templeParts) > 0 {
-		values["G24"] = strings.Join(templeParts, " ")
-		values["I24"] = ""
+
+	_, spec := krstenicaPDFPage(&dto.Krstenica{}, certificateLayout, "")
+	pdf.SetDrawColor(200, 0, 0)
+	pdf.SetTextColor(200, 0, 0)
+	pdf.SetLineWidth(0.2)
+	pdf.SetFont(fontFamily.name, "", 6)
+	for _, cell := range spec.order {
+		rect, ok := layout.cellRect(cell)
+		if !ok {
+			continue
+		}
+		offset := textOffset{dx: defaultTextOffsetXMM, dy: defaultTextOffsetYMM}
+		if o, ok := spec.offsets[cell]; ok {
+			offset = o
+		}
+		x, y := pdfCal.point(layout.cellTextOrigin(rect, pdfCal.cellOffset(cell, offset)))
+		pdf.Line(x-calibrationMarkSizeMM, y, x+calibrationMarkSizeMM, y)
+		pdf.Line(x, y-calibrationMarkSizeMM, x, y+calibrationMarkSizeMM)
+		pdf.Text(x+0.8, y-0.8, cell)
 	}
-	if cityText := formatCyrillicIzCity(values["F31"]); cityText != "" {
-		values["F31"] = cityText
+
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetFont(fontFamily.name, "B", 9)
+	pdf.Text(pdfQRCodeMarginMM, pageH-pdfQRCodeMarginMM-8, fmt.Sprintf("Калибрација: %s", calibration.Name))
+	pdf.SetFont(fontFamily.name, "", 8)
+	pdf.Text(pdfQRCodeMarginMM, pageH-pdfQRCodeMarginMM-4, fmt.Sprintf("X %s mm, Y %s mm, размера %s, помака ћелија: %d",
+		formatLayoutOffset(calibration.OffsetX), formatLayoutOffset(calibration.OffsetY), strconv.FormatFloat(calibration.Scale, 'f', -1, 64), len(calibration.Cells)))
+	pdf.Text(pdfQRCodeMarginMM, pageH-pdfQRCodeMarginMM, "Одштампајте на празан образац и измерите колико крстићи одступају од места за упис.")
+
+	if err := pdf.OutputFileAndClose(targetFile); err != nil {
+		return fmt.Errorf("write pdf: %w", err)
 	}
-	if religion, ok := values["I31"]; ok {
-		religion = strings.TrimSpace(religion)
-		if religion != "" {
-			religion = religion + " "
-		}
-		values["I31"] = religion
+	return nil
+}
+
+// pdfCellSpec describes which worksheet cells are drawn and how.
+type pdfCellSpec struct {
+	order       []string
+	offsets     map[string]textOffset
+	bold        map[string]bool
+	forcedWrap  map[string]bool
+	fitWidth    map[string]bool
+	fontRefCell string
+	// qrCode je sadržaj QR koda u donjem desnom uglu (adresa provere uverenja).
+	qrCode string
+	// footerText se ispisuje u podnožju strane (potpisnik digitalnog potpisa).
+	footerText string
+}
+
+func renderPDFCellValues(values map[string]string, spec pdfCellSpec, layout *worksheetLayout, targetFile, backgroundImage string, fullBleed bool, fontKey string, calibration *pdfCalibration) error {
+	pdf, fontFamily, err := newCertificatePDF(fontKey)
+	if err != nil {
+		return err
 	}
-	if godfatherCity := formatCyrillicIzCity(values["E49"]); godfatherCity != "" {
-		values["E49"] = godfatherCity
+	if err := drawPDFCellValuesPage(pdf, fontFamily, values, spec, layout, backgroundImage, fullBleed, calibration); err != nil {
+		return err
 	}
-	if godfatherReligion, ok := values["G49"]; ok {
-		godfatherReligion = strings.TrimSpace(godfatherReligion)
-		if godfatherReligion != "" {
-			godfatherReligion = godfatherReligion + " "
-		}
-		values["G49"] = godfatherReligion
+
+	if err := pdf.OutputFileAndClose(targetFile); err != nil {
+		return fmt.Errorf("write pdf: %w", err)
 	}
+	return nil
+}
 
+// newCertificatePDF pravi prazan A4 dokument sa registrovanim fontom.
+func newCertificatePDF(fontKey string) (*gofpdf.Fpdf, pdfFontFamily, error) {
 	pdf := gofpdf.New("P", "mm", "A4", "")
 	pdf.SetAutoPageBreak(false, 0)
 	pdf.SetMargins(0, 0, 0)
-	pdf.AddPage()
 	pdf.SetTextColor(0, 0, 0)
 
 	fontFamily, err := selectPDFFontFamily(fontKey)
 	if err != nil {
-		return err
+		return nil, pdfFontFamily{}, err
 	}
-	pdfFontName := fontFamily.name
 	if err := registerPDFFontFamily(pdf, fontFamily); err != nil {
-		return err
+		return nil, pdfFontFamily{}, err
 	}
+	return pdf, fontFamily, nil
+}
+
+// drawPDFCellValuesPage dodaje stranu i na nju crta pozadinu i vrednosti celija.
+// Kalibracija pomera samo tekst, jer pozadina predstavlja vec odstampan obrazac.
+func drawPDFCellValuesPage(pdf *gofpdf.Fpdf, fontFamily pdfFontFamily, values map[string]string, spec pdfCellSpec, layout *worksheetLayout, backgroundImage string, fullBleed bool, calibration *pdfCalibration) error {
+	pdf.AddPage()
+	pdf.SetTextColor(0, 0, 0)
 
 	if backgroundImage != "" {
 		if _, err := os.Stat(backgroundImage); err == nil {
@@ -318,27 +413,51 @@ func fillKrstenicaPDFFile(krstenica *dto.Krstenica, templatePath, targetFile, ba
 		}
 	}
 
-	cellOrder := []string{
-		"C1", "C2", "C3", "F8", "C10", "I10", "N10",
-		"F13", "E16", "F16", "G16", "F19", "G24", "I24",
-		"D27", "F27", "I27", "F30", "I30", "K30", "F31", "I31",
-		"I32", "K32", "I36", "I38", "I41", "F43", "H43", "K43",
-		"E48", "G48", "E49", "G49", "E51", "C54", "B62", "B63", "C63", "B65",
+	for _, run := range layoutPDFCellValues(pdf, fontFamily, values, spec, layout, calibration) {
+		pdf.SetFont(fontFamily.name, run.fontStyle, run.fontSize)
+		pdf.Text(run.x, run.y, run.text)
 	}
 
+	if spec.qrCode != "" {
+		if err := drawQRCode(pdf, spec.qrCode); err != nil {
+			return fmt.Errorf("draw qr code: %w", err)
+		}
+	}
+
+	if spec.footerText != "" {
+		_, pageHeight := pdf.GetPageSize()
+		pdf.SetFont(fontFamily.name, "", pdfFooterFontSizePt*pdfFontSizeScale(fontFamily))
+		pdf.Text(pdfQRCodeMarginMM, pageHeight-pdfQRCodeMarginMM, spec.footerText)
+	}
+
+	return pdf.Error()
+}
+
+// pdfTextRun je jedan red teksta na strani: polozaj osnovne linije u mm i
+// font u tackama. Isti redovi se crtaju u PDF i u PNG pregled.
+type pdfTextRun struct {
+	text      string
+	x         float64
+	y         float64
+	fontStyle string
+	fontSize  float64
+}
+
+// layoutPDFCellValues odredjuje redove teksta za vrednosti celija. pdf sluzi
+// za merenje sirine teksta i ostaje sa poslednjim postavljenim fontom.
+func layoutPDFCellValues(pdf *gofpdf.Fpdf, fontFamily pdfFontFamily, values map[string]string, spec pdfCellSpec, layout *worksheetLayout, calibration *pdfCalibration) []pdfTextRun {
+	pdfFontName := fontFamily.name
 	paddingScaled := pdfCellPaddingMM * layout.scale
 	uniformFontSizePt := defaultFontSizePt
-	if refStyle, ok := layout.cellStyles["C9"]; ok && refStyle.fontSize > 0 {
+	if refStyle, ok := layout.cellStyles[spec.fontRefCell]; ok && refStyle.fontSize > 0 {
 		uniformFontSizePt = refStyle.fontSize
 	}
 	uniformFontSizePt *= pdfFontScaleFactor
+	fontScale := pdfFontSizeScale(fontFamily)
+	cellMargin := pdf.GetCellMargin()
 
-	fontScale := fontFamily.sizeScale
-	if fontScale <= 0 {
-		fontScale = 1.0
-	}
-
-	for _, cell := range cellOrder {
+	var runs []pdfTextRun
+	for _, cell := range spec.order {
 		value, ok := values[cell]
 		if !ok || strings.TrimSpace(value) == "" {
 			continue
@@ -357,24 +476,23 @@ func fillKrstenicaPDFFile(krstenica *dto.Krstenica, templatePath, targetFile, ba
 		}
 
 		fontStyle := ""
-		if boldCells[cell] {
+		if spec.bold[cell] {
 			fontStyle = "B"
 		}
+		fontSizeScaled = calibration.size(fontSizeScaled)
 		pdf.SetFont(pdfFontName, fontStyle, fontSizeScaled)
 
-		wrapText := style.wrapText || forcedWrapCells[cell]
+		wrapText := style.wrapText || spec.forcedWrap[cell]
 		offset := textOffset{dx: defaultTextOffsetXMM, dy: defaultTextOffsetYMM}
-		if o, ok := cellOffsets[cell]; ok {
+		if o, ok := spec.offsets[cell]; ok {
 			offset = o
 		}
-		if cell == "N10" {
-			if trimmed := strings.TrimSpace(value); len(trimmed) == 4 {
-				offset.dx -= 8.0
-			}
-		}
+		offset = calibration.cellOffset(cell, offset)
 		if wrapText {
-			x := layout.leftMarginMM + (rect.x+pdfCellPaddingMM)*layout.scale + offset.dx*layout.scale
-			y := layout.topMarginMM + rect.y*layout.scale + paddingScaled + offset.dy*layout.scale
+			x, y := calibration.point(
+				layout.leftMarginMM+(rect.x+pdfCellPaddingMM)*layout.scale+offset.dx*layout.scale,
+				layout.topMarginMM+rect.y*layout.scale+paddingScaled+offset.dy*layout.scale,
+			)
 			width := rect.width*layout.scale - 2*paddingScaled
 			if width <= 0 {
 				width = rect.width * layout.scale
@@ -384,30 +502,86 @@ func fillKrstenicaPDFFile(krstenica *dto.Krstenica, templatePath, targetFile, ba
 			if lineHeight < minLineHeight {
 				lineHeight = minLineHeight
 			}
-			pdf.SetXY(x, y)
-			pdf.MultiCell(width, lineHeight, value, "", "L", false)
+			width = calibration.size(width)
+			lineHeight = calibration.size(lineHeight)
+			// redovi kao kod MultiCell: osnovna linija na sredini reda
+			for i, line := range pdf.SplitText(value, width) {
+				runs = append(runs, pdfTextRun{
+					text:      line,
+					x:         x + cellMargin,
+					y:         y + float64(i)*lineHeight + 0.5*lineHeight + 0.3*fontSizeScaled*mmPerPoint,
+					fontStyle: fontStyle,
+					fontSize:  fontSizeScaled,
+				})
+			}
 			continue
 		}
 
-		baseline := rect.height*pdfBaselineFactor + pdfCellPaddingMM
-		x := layout.leftMarginMM + (rect.x+pdfCellPaddingMM)*layout.scale + offset.dx*layout.scale
-		y := layout.topMarginMM + (rect.y+baseline)*layout.scale + offset.dy*layout.scale
-		pdf.Text(x, y, value)
+		if spec.fitWidth[cell] {
+			available := calibration.size((layout.contentWidthMM - rect.x - pdfCellPaddingMM - offset.dx) * layout.scale)
+			if width := pdf.GetStringWidth(value); available > 0 && width > available {
+				fontSizeScaled = math.Max(fontSizeScaled*available/width, fontSizeScaled*pdfMinFitFontRatio)
+			}
+		}
+
+		x, y := calibration.point(layout.cellTextOrigin(rect, offset))
+		runs = append(runs, pdfTextRun{text: value, x: x, y: y, fontStyle: fontStyle, fontSize: fontSizeScaled})
 	}
+	return runs
+}
 
-	if err := pdf.OutputFileAndClose(targetFile); err != nil {
-		return fmt.Errorf("write pdf: %w", err)
+func pdfFontSizeScale(fontFamily pdfFontFamily) float64 {
+	if fontFamily.sizeScale <= 0 {
+		return 1.0
 	}
-	return nil
+	return fontFamily.sizeScale
 }
 
-func drawBackgroundImage(pdf *gofpdf.Fpdf, layout *worksheetLayout, imagePath string, fullBleed bool) error {
-	contentW := layout.contentWidthMM * layout.scale
-	contentH := layout.contentHeightMM * layout.scale
+// drawQRCode crta QR kod u donjem desnom uglu strane. Moduli se crtaju kao
+// vektorski kvadrati da bi kod ostao oštar pri štampi i skeniranju.
+func drawQRCode(pdf *gofpdf.Fpdf, content string) error {
+	code, err := qr.Encode(content, qr.M, qr.Auto)
+	if err != nil {
+		return err
+	}
+	bounds := code.Bounds()
+	modules := bounds.Dx()
+	if modules <= 0 {
+		return fmt.Errorf("empty qr code")
+	}
+
+	pageWidth, pageHeight := pdf.GetPageSize()
+	x := pageWidth - pdfQRCodeMarginMM - pdfQRCodeSizeMM
+	y := pageHeight - pdfQRCodeMarginMM - pdfQRCodeSizeMM
+	moduleSize := pdfQRCodeSizeMM / float64(modules)
+
+	pdf.SetFillColor(255, 255, 255)
+	pdf.Rect(x-moduleSize, y-moduleSize, pdfQRCodeSizeMM+2*moduleSize, pdfQRCodeSizeMM+2*moduleSize, "F")
+	pdf.SetFillColor(0, 0, 0)
+	for row := 0; row < modules; row++ {
+		for col := 0; col < modules; col++ {
+			r, _, _, _ := code.At(bounds.Min.X+col, bounds.Min.Y+row).RGBA()
+			if r != 0 {
+				continue
+			}
+			pdf.Rect(x+float64(col)*moduleSize, y+float64(row)*moduleSize, moduleSize, moduleSize, "F")
+		}
+	}
+	return pdf.Error()
+}
 
+func drawBackgroundImage(pdf *gofpdf.Fpdf, layout *worksheetLayout, imagePath string, fullBleed bool) error {
 	pageW, pageH := pdf.GetPageSize()
 	imgType := strings.ToUpper(strings.TrimPrefix(filepath.Ext(imagePath), "."))
+	x, y, w, h := backgroundImageRect(layout, pageW, pageH, imagePath, fullBleed)
+	pdf.ImageOptions(imagePath, x, y, w, h, false, gofpdf.ImageOptions{ImageType: imgType}, 0, "")
+	return nil
+}
 
+// backgroundImageRect vraca polozaj i velicinu pozadine na strani u mm. Pozadina
+// preko cele strane zadrzava odnos stranica i sece visak, a inace pokriva
+// sadrzaj obrasca unutar margina.
+func backgroundImageRect(layout *worksheetLayout, pageW, pageH float64, imagePath string, fullBleed bool) (float64, float64, float64, float64) {
 	if fullBleed {
 		targetW := pageW
 		targetH := pageH
@@ -430,10 +604,11 @@ func drawBackgroundImage(pdf *gofpdf.Fpdf, layout *worksheetLayout, imagePath st
 			}
 		}
 
-		pdf.ImageOptions(imagePath, offsetX, offsetY, targetW, targetH, false, gofpdf.ImageOptions{ImageType: imgType}, 0, "")
-		return nil
+		return offsetX, offsetY, targetW, targetH
 	}
 
+	contentW := layout.contentWidthMM * layout.scale
+	contentH := layout.contentHeightMM * layout.scale
 	if contentW <= 0 {
 		contentW = pageW - layout.leftMarginMM - layout.rightMarginMM
 	}
@@ -447,11 +622,7 @@ func drawBackgroundImage(pdf *gofpdf.Fpdf, layout *worksheetLayout, imagePath st
 		contentH = pageH
 	}
 
-	x := layout.leftMarginMM
-	y := layout.topMarginMM
-
-	pdf.ImageOptions(imagePath, x, y, contentW, contentH, false, gofpdf.ImageOptions{ImageType: imgType}, 0, "")
-	return nil
+	return layout.leftMarginMM, layout.topMarginMM, contentW, contentH
 }
 
 func imageSize(path string) (int, int, error) {
@@ -492,6 +663,14 @@ func (l *worksheetLayout) cellRect(cell string) (cellRect, bool) {
 	return cellRect{x: x, y: y, width: width, height: height}, true
 }
 
+// cellTextOrigin vraca pocetak osnovne linije teksta u celiji (bez prelamanja).
+func (l *worksheetLayout) cellTextOrigin(rect cellRect, offset textOffset) (float64, float64) {
+	baseline := rect.height*pdfBaselineFactor + pdfCellPaddingMM
+	x := l.leftMarginMM + (rect.x+pdfCellPaddingMM)*l.scale + offset.dx*l.scale
+	y := l.topMarginMM + (rect.y+baseline)*l.scale + offset.dy*l.scale
+	return x, y
+}
+
 func (l *worksheetLayout) colWidth(idx int) float64 {
 	if w, ok := l.colWidthsMM[idx]; ok && w > 0 {
 		return w
@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+
+	"krstenica/internal/dto"
+	"krstenica/internal/errorx"
+	"krstenica/pkg"
+)
+
+// pngPreviewDPI je rezolucija pregleda; A4 strana je oko 830x1170 piksela.
+const pngPreviewDPI = 100.0
+
+// *************************************************************Krstenica Preview*************************************
+// getKrstenicaPreview vraca PNG popunjenog uverenja sa istim parametrima kao
+// stampa (obrazac, raspored, font, kalibracija). Pregled ne izdaje uverenje.
+func (h *httpHandler) getKrstenicaPreview() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		cx := ctx.Request.Context()
+		krstenica, err := h.service.GetKrstenicaByID(cx, int64(id))
+		if err != nil {
+			if err == errorx.ErrKrstenicaNotFound {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		filters := pkg.ParseUrlQuery(ctx)
+		opts, status, err := h.parseKrstenicaPrintOptions(cx, filters, workstationName(ctx))
+		if err != nil {
+			ctx.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		files, err := h.krstenicaTemplateFiles(cx, opts, krstenica)
+		if err != nil {
+			ctx.JSON(templateErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		// redni broj uverenja dobija se tek pri izdavanju
+		krstenica.NumberOfCertificate = fmt.Sprintf("___/%d", time.Now().Year())
+
+		var buf bytes.Buffer
+		if err := renderKrstenicaPNG(&buf, krstenica, opts.layout, files, opts.fontKey, opts.calibration, pngPreviewDPI); err != nil {
+			log.Println("Error generating preview:", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to generate preview: %v", err)})
+			return
+		}
+		ctx.Header("Cache-Control", "no-store")
+		ctx.Data(http.StatusOK, "image/png", buf.Bytes())
+	}
+}
+
+// krstenicaPreviewData puni prozor pregleda; izbori obrasca, fonta i
+// kalibracije menjaju sliku bez ponovnog ucitavanja prozora.
+type krstenicaPreviewData struct {
+	Krstenica    *dto.Krstenica
+	Templates    []*dto.CertificateTemplate
+	Calibrations []*dto.PrintCalibration
+	// ActiveCalibration je profil koji se primenjuje bez izbora
+	ActiveCalibration *dto.PrintCalibration
+	SigningEnabled    bool
+}
+
+func (h *httpHandler) renderKrstenicaPreviewDialog() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			h.renderHTML(ctx, http.StatusBadRequest, "partials/error.html", gin.H{
+				"Message": "Nepostojeci identifikator krstenice",
+			})
+			return
+		}
+
+		cx := ctx.Request.Context()
+		krstenica, err := h.service.GetKrstenicaByID(cx, int64(id))
+		if err != nil {
+			status := http.StatusInternalServerError
+			if err == errorx.ErrKrstenicaNotFound {
+				status = http.StatusNotFound
+			}
+			h.renderHTML(ctx, status, "partials/error.html", gin.H{"Message": err.Error()})
+			return
+		}
+
+		data := &krstenicaPreviewData{
+			Krstenica:      krstenica,
+			SigningEnabled: h.conf.Signing.CertFile != "" && h.conf.Signing.KeyFile != "",
+		}
+		if data.Templates, err = h.service.ListCertificateTemplates(cx); err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{"Message": err.Error()})
+			return
+		}
+		if data.Calibrations, err = h.service.ListPrintCalibrations(cx); err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{"Message": err.Error()})
+			return
+		}
+		if data.ActiveCalibration, err = h.service.ResolvePrintCalibration(cx, workstationName(ctx)); err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{"Message": err.Error()})
+			return
+		}
+
+		h.renderHTML(ctx, http.StatusOK, "krstenice/pregled.html", data)
+	}
+}
+
+//****************************************************end******Krstenica Preview*************************************
+
+// renderKrstenicaPNG crta uverenje kao PNG: pozadinu obrasca i redove teksta
+// iz layoutPDFCellValues, kao fillKrstenicaPDFFile. QR kod nastaje tek pri
+// izdavanju, pa se na pregledu oznacava samo njegovo mesto.
+func renderKrstenicaPNG(w io.Writer, krstenica *dto.Krstenica, certificateLayout *dto.CertificateLayout, files *krstenicaTemplateFiles, fontKey string, calibration *pdfCalibration, dpi float64) error {
+	layout, err := loadWorksheetLayout(files.templateFile)
+	if err != nil {
+		return fmt.Errorf("load worksheet layout: %w", err)
+	}
+	// PDF dokument sluzi samo za merenje teksta, istim fontom kao pri stampi
+	pdf, fontFamily, err := newCertificatePDF(fontKey)
+	if err != nil {
+		return err
+	}
+
+	pageW, pageH := pdf.GetPageSize()
+	px := func(mm float64) float64 {
+		return mm * dpi / mmPerInch
+	}
+	img := image.NewRGBA(image.Rect(0, 0, int(math.Round(px(pageW))), int(math.Round(px(pageH)))))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+
+	if files.backgroundImage != "" {
+		if _, err := os.Stat(files.backgroundImage); err == nil {
+			x, y, bw, bh := backgroundImageRect(layout, pageW, pageH, files.backgroundImage, files.backgroundFullBleed)
+			target := image.Rect(int(math.Round(px(x))), int(math.Round(px(y))), int(math.Round(px(x+bw))), int(math.Round(px(y+bh))))
+			if err := drawPNGBackground(img, files.backgroundImage, target); err != nil {
+				return fmt.Errorf("draw background: %w", err)
+			}
+		}
+	}
+
+	values, spec := krstenicaPDFPage(krstenica, certificateLayout, "")
+	faces, err := newPNGFontFaces(fontFamily, dpi)
+	if err != nil {
+		return err
+	}
+	defer faces.close()
+	for _, run := range layoutPDFCellValues(pdf, fontFamily, values, spec, layout, calibration) {
+		face, err := faces.face(run.fontStyle, run.fontSize)
+		if err != nil {
+			return err
+		}
+		drawer := font.Drawer{
+			Dst:  img,
+			Src:  image.Black,
+			Face: face,
+			Dot:  fixed.Point26_6{X: fixed.Int26_6(px(run.x) * 64), Y: fixed.Int26_6(px(run.y) * 64)},
+		}
+		drawer.DrawString(run.text)
+	}
+
+	qrX := pageW - pdfQRCodeMarginMM - pdfQRCodeSizeMM
+	qrY := pageH - pdfQRCodeMarginMM - pdfQRCodeSizeMM
+	qrRect := image.Rect(int(px(qrX)), int(px(qrY)), int(px(qrX+pdfQRCodeSizeMM)), int(px(qrY+pdfQRCodeSizeMM)))
+	draw.Draw(img, qrRect, image.NewUniform(color.Gray{Y: 200}), image.Point{}, draw.Over)
+
+	return png.Encode(w, img)
+}
+
+func drawPNGBackground(dst draw.Image, imagePath string, target image.Rectangle) error {
+	f, err := os.Open(imagePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	src, _, err := image.Decode(f)
+	if err != nil {
+		return err
+	}
+	draw.ApproxBiLinear.Scale(dst, target, src, src.Bounds(), draw.Over, nil)
+	return nil
+}
+
+// pngFontFaces cuva font face po stilu i velicini, jer se vecina redova
+// crta istim slovima.
+type pngFontFaces struct {
+	fonts map[string]*opentype.Font
+	faces map[string]font.Face
+	dpi   float64
+}
+
+func newPNGFontFaces(family pdfFontFamily, dpi float64) (*pngFontFaces, error) {
+	faces := &pngFontFaces{
+		fonts: map[string]*opentype.Font{},
+		faces: map[string]font.Face{},
+		dpi:   dpi,
+	}
+	bold := family.bold
+	if len(bold) == 0 {
+		bold = family.regular
+	}
+	for style, data := range map[string][]byte{"": family.regular, "B": bold} {
+		parsed, err := opentype.Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("parse font %s: %w", family.name, err)
+		}
+		faces.fonts[style] = parsed
+	}
+	return faces, nil
+}
+
+func (f *pngFontFaces) face(style string, sizePt float64) (font.Face, error) {
+	key := style + "/" + strconv.FormatFloat(sizePt, 'f', 2, 64)
+	if face, ok := f.faces[key]; ok {
+		return face, nil
+	}
+	parsed, ok := f.fonts[style]
+	if !ok {
+		parsed = f.fonts[""]
+	}
+	face, err := opentype.NewFace(parsed, &opentype.FaceOptions{Size: sizePt, DPI: f.dpi, Hinting: font.HintingNone})
+	if err != nil {
+		return nil, err
+	}
+	f.faces[key] = face
+	return face, nil
+}
+
+func (f *pngFontFaces) close() {
+	for _, face := range f.faces {
+		face.Close()
+	}
+}
@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"krstenica/internal/dto"
+	"krstenica/internal/errorx"
+	"krstenica/pkg"
+)
+
+// krsteniceBatchPrintLimit je najveci broj krstenica u jednom zbirnom stampanju.
+const krsteniceBatchPrintLimit = 200
+
+// *************************************************************Krstenica Batch Print*************************************
+func (h *httpHandler) getKrstenicePrintBatch() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		cx := ctx.Request.Context()
+		filters := pkg.ParseUrlQuery(ctx)
+
+		if _, ok := filters.Filters[pkg.FilterKey{Property: "format", Operator: "eq"}]; !ok {
+			filters.Filters[pkg.FilterKey{Property: "format", Operator: "eq"}] = []string{"pdf"}
+		}
+		opts, status, err := h.parseKrstenicaPrintOptions(cx, filters, workstationName(ctx))
+		if err != nil {
+			ctx.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		if opts.format != "pdf" && opts.format != "zip" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "format must be pdf or zip"})
+			return
+		}
+		for _, key := range append(krstenicaPrintOptionKeys, "ids") {
+			delete(filters.Filters, pkg.FilterKey{Property: key, Operator: "eq"})
+		}
+
+		ids, err := h.resolveKrsteniceBatchIDs(ctx, filters)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// obrazac se bira za svaku krstenicu posebno (po hramu i eparhiji)
+		pages := make([]krstenicaPDFBatchPage, 0, len(ids))
+		for _, id := range ids {
+			krstenica, err := h.service.GetKrstenicaByID(cx, id)
+			if err != nil {
+				if err == errorx.ErrKrstenicaNotFound {
+					ctx.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("%s: %d", err.Error(), id)})
+					return
+				}
+				ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			files, err := h.krstenicaTemplateFiles(cx, opts, krstenica)
+			if err != nil {
+				ctx.JSON(templateErrorStatus(err), gin.H{"error": err.Error()})
+				return
+			}
+			pages = append(pages, krstenicaPDFBatchPage{krstenica: krstenica, files: files})
+		}
+
+		targetDir, err := os.MkdirTemp("", "krstenica")
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create temp directory"})
+			return
+		}
+		defer os.RemoveAll(targetDir)
+
+		// svaka odstampana krstenica je posebno izdato uverenje; ako zbirni fajl
+		// nije napravljen, sva izdata uverenja se brisu
+		krstenicaIDs := make([]int64, len(pages))
+		issueReqs := make([]*dto.IssuedCertificateCreateReq, len(pages))
+		for i := range pages {
+			krstenicaIDs[i] = pages[i].krstenica.ID
+			issueReqs[i] = opts.issueRequest(pages[i].files)
+			if opts.format == "zip" {
+				issueReqs[i].Format = "xlsx"
+			}
+		}
+
+		var (
+			targetFile  string
+			contentType string
+			renderErr   error
+		)
+		_, err = h.service.IssueKrstenicaCertificates(cx, krstenicaIDs, issueReqs, func(issued []*dto.IssuedCertificate) error {
+			for i := range pages {
+				pages[i].krstenica.NumberOfCertificate = issued[i].Serial
+				pages[i].verifyURL = h.certificateVerificationURL(ctx, issued[i].ID)
+			}
+			if opts.format == "zip" {
+				targetFile = filepath.Join(targetDir, "krstenice.zip")
+				contentType = "application/zip"
+				if renderErr = writeKrsteniceXLSXZip(pages, opts.layout, targetDir, targetFile); renderErr != nil {
+					return fmt.Errorf("failed to generate ZIP file: %w", renderErr)
+				}
+				return nil
+			}
+			targetFile = filepath.Join(targetDir, "krstenice.pdf")
+			contentType = "application/pdf"
+			if renderErr = fillKrstenicaPDFBatchFile(pages, opts.layout, targetFile, opts.fontKey, opts.signer, opts.calibration); renderErr != nil {
+				return fmt.Errorf("failed to generate PDF file: %w", renderErr)
+			}
+			return nil
+		})
+		if err != nil {
+			if renderErr != nil {
+				log.Println("Error generating batch file:", err)
+				ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			log.Println("Error registering issued certificate:", err)
+			ctx.JSON(issueErrorStatus(err), gin.H{"error": fmt.Sprintf("failed to register issued certificate: %v", err)})
+			return
+		}
+		sendGeneratedFile(ctx, targetFile, contentType, filepath.Base(targetFile))
+	}
+}
+
+// resolveKrsteniceBatchIDs vraca krstenice iz parametra ids (redom kako su
+// navedene), a bez njega sve krstenice koje odgovaraju filterima liste.
+func (h *httpHandler) resolveKrsteniceBatchIDs(ctx *gin.Context, filters *pkg.FilterAndSort) ([]int64, error) {
+	var ids []int64
+	seen := map[int64]bool{}
+	for _, part := range strings.Split(ctx.Query("ids"), ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid krstenica id %q", part)
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) == 0 {
+		// jedan vise od granice da bi se prepoznao prevelik izbor
+		filters.Paging = &pkg.Paging{PageNumber: "1", PageSize: strconv.Itoa(krsteniceBatchPrintLimit + 1)}
+		items, _, err := h.service.ListKrstenice(ctx.Request.Context(), filters)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			ids = append(ids, item.ID)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil, errors.New("no krstenice selected for printing")
+	}
+	if len(ids) > krsteniceBatchPrintLimit {
+		return nil, fmt.Errorf("at most %d krstenice can be printed at once", krsteniceBatchPrintLimit)
+	}
+	return ids, nil
+}
+
+// writeKrsteniceXLSXZip pakuje po jednu popunjenu XLSX krstenicu u ZIP arhivu.
+func writeKrsteniceXLSXZip(pages []krstenicaPDFBatchPage, certificateLayout *dto.CertificateLayout, workDir, targetFile string) error {
+	out, err := os.Create(targetFile)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	archive := zip.NewWriter(out)
+	for _, page := range pages {
+		xlsxFile := filepath.Join(workDir, fmt.Sprintf("krstenica-%d.xlsx", page.krstenica.ID))
+		if err := fillKrstenicaExcelFromTemplate(page.krstenica, certificateLayout, page.files, xlsxFile); err != nil {
+			return err
+		}
+		if err := addFileToZip(archive, xlsxFile, filepath.Base(xlsxFile)); err != nil {
+			return err
+		}
+		os.Remove(xlsxFile)
+	}
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+func addFileToZip(archive *zip.Writer, path, name string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	w, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, in)
+	return err
+}
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"krstenica/internal/dto"
@@ -48,26 +50,16 @@ func (h *httpHandler) getKrstenicePrint() gin.HandlerFunc {
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
-		var file string
-		templateDir := resolveDir("doc/template_files")
-		invoiceXlsxTemplateFilePreview := filepath.Join(templateDir, filepath.Base(templateFileRelative))
-		invoiceXlsxTemplateFile := filepath.Join(templateDir, filepath.Base(templateEmptyFileRelative))
-
-		fmt.Println("Template file preview:", invoiceXlsxTemplateFilePreview)
-		fmt.Println("Empty template file:", invoiceXlsxTemplateFile)
-
-		if v, ok := filters.Filters[pkg.FilterKey{Property: "preview", Operator: "eq"}]; ok && len(v) > 0 && v[0] == "true" {
-			file = invoiceXlsxTemplateFilePreview
-		} else {
-			file = invoiceXlsxTemplateFile
-		}
 
-		outputFormat := "xlsx"
-		if v, ok := filters.Filters[pkg.FilterKey{Property: "format", Operator: "eq"}]; ok && len(v) > 0 {
-			outputFormat = strings.ToLower(strings.TrimSpace(v[0]))
+		opts, status, err := h.parseKrstenicaPrintOptions(cx, filters, workstationName(ctx))
+		if err != nil {
+			ctx.JSON(status, gin.H{"error": err.Error()})
+			return
 		}
-		if outputFormat == "" {
-			outputFormat = "xlsx"
+		files, err := h.krstenicaTemplateFiles(cx, opts, krstenica)
+		if err != nil {
+			ctx.JSON(templateErrorStatus(err), gin.H{"error": err.Error()})
+			return
 		}
 
 		targetDir, err := os.MkdirTemp("", "krstenica")
@@ -77,180 +69,389 @@ func (h *httpHandler) getKrstenicePrint() gin.HandlerFunc {
 		}
 		defer os.RemoveAll(targetDir)
 
-		backgroundImage := resolveFile("krstenica_obrada.jpg")
-		backgroundFullBleed := true
-		fontKey := ""
-		if v, ok := filters.Filters[pkg.FilterKey{Property: "template_version", Operator: "eq"}]; ok && len(v) > 0 {
-			version := strings.TrimSpace(strings.ToLower(v[0]))
-			switch version {
-			case "2", "v2", "verzija2", "version2":
-				backgroundImage = ""
-				backgroundFullBleed = false
-			}
-		}
-		if v, ok := filters.Filters[pkg.FilterKey{Property: "font", Operator: "eq"}]; ok && len(v) > 0 {
-			fontKey = strings.TrimSpace(v[0])
-		}
-
 		var (
 			targetFile   string
 			contentType  string
 			downloadName string
+			renderErr    error
 		)
 
-		switch outputFormat {
-		case "pdf":
-			targetFile = filepath.Join(targetDir, "krstenica.pdf")
-			if err := fillKrstenicaPDFFile(krstenica, file, targetFile, backgroundImage, backgroundFullBleed, fontKey); err != nil {
-				log.Println("Error generating PDF file:", err)
-				ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to generate PDF file: %v", err)})
-				return
+		// svako štampanje je izdato uverenje; redni broj se upisuje u polje broja
+		// uverenja, a uverenje se briše ako dokument nije napravljen
+		issued, err := h.service.IssueKrstenicaCertificate(cx, int64(id), opts.issueRequest(files), func(issued *dto.IssuedCertificate) error {
+			krstenica.NumberOfCertificate = issued.Serial
+			switch opts.format {
+			case "pdf":
+				targetFile = filepath.Join(targetDir, "krstenica.pdf")
+				verifyURL := h.certificateVerificationURL(ctx, issued.ID)
+				if renderErr = fillKrstenicaPDFFile(krstenica, opts.layout, files.templateFile, targetFile, files.backgroundImage, files.backgroundFullBleed, opts.fontKey, verifyURL, opts.signer, opts.calibration); renderErr != nil {
+					return fmt.Errorf("failed to generate PDF file: %w", renderErr)
+				}
+				contentType = "application/pdf"
+				downloadName = "krstenica.pdf"
+			default:
+				targetFile = filepath.Join(targetDir, "krstenica.xlsx")
+				if renderErr = fillKrstenicaExcelFromTemplate(krstenica, opts.layout, files, targetFile); renderErr != nil {
+					return fmt.Errorf("failed to generate Excel file: %w", renderErr)
+				}
+				contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+				downloadName = "krstenica.xlsx"
 			}
-			contentType = "application/pdf"
-			downloadName = "krstenica.pdf"
-		default:
-			from, err := os.Open(file)
-			if err != nil {
-				log.Println("Can't open Excel template file:", err)
+			return nil
+		})
+		if err != nil {
+			if renderErr != nil {
+				log.Println("Error generating certificate file:", err)
+				ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 				return
 			}
-			defer from.Close()
+			log.Println("Error registering issued certificate:", err)
+			ctx.JSON(issueErrorStatus(err), gin.H{"error": fmt.Sprintf("failed to register issued certificate: %v", err)})
+			return
+		}
+		ctx.Writer.Header().Set("X-Certificate-Serial", issued.Serial)
 
-			targetFile = filepath.Join(targetDir, "krstenica.xlsx")
-			to, err := os.OpenFile(targetFile, os.O_RDWR|os.O_CREATE, 0666)
-			if err != nil {
-				log.Print(err)
-				return
-			}
-			defer to.Close()
+		sendGeneratedFile(ctx, targetFile, contentType, downloadName)
+	}
+}
 
-			if _, err = io.Copy(to, from); err != nil {
-				log.Print(err)
-				return
-			}
+// issueErrorStatus vraća HTTP status za grešku izdavanja uverenja.
+func issueErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, errorx.ErrKrstenicaNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, errorx.ErrCityForbidden):
+		return http.StatusForbidden
+	case errorx.IsValidationError(err):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
 
-			if err := fillKrstenicaExcelFile(krstenica, targetFile, backgroundImage, backgroundFullBleed); err != nil {
-				log.Println("Error generating Excel file:", err)
-				ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to generate Excel file: %v", err)})
-				return
-			}
+// krstenicaPrintOptions su parametri stampe zajednicki za jednu i vise krstenica.
+type krstenicaPrintOptions struct {
+	format          string
+	preview         bool
+	fontKey         string
+	templateVersion string
+	purpose         string
+	signer          *pdfSigner
+	layout          *dto.CertificateLayout
+	// obrazac: zadati obrazac, zadata verzija (ponovna stampa) ili ugradjeni;
+	// bez ijednog se bira po hramu i eparhiji krstenice
+	templateID        int64
+	templateVersionID int64
+	builtinTemplate   bool
+	plainBackground   bool
+	// calibration je profil stampaca; nil stampa bez kalibracije
+	calibration *pdfCalibration
+}
+
+// krstenicaPrintOptionKeys su parametri stampe koji nisu filteri krstenica.
+var krstenicaPrintOptionKeys = []string{"preview", "format", "template_version", "font", "sign", "purpose", "layout", "template", "template_version_id", "calibration"}
 
-			contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
-			downloadName = "krstenica.xlsx"
+// parseKrstenicaPrintOptions cita parametre stampe; uz gresku vraca i HTTP status.
+// Radna stanica odredjuje profil kalibracije kada profil nije zadat.
+func (h *httpHandler) parseKrstenicaPrintOptions(ctx context.Context, filters *pkg.FilterAndSort, workstation string) (*krstenicaPrintOptions, int, error) {
+	opts := &krstenicaPrintOptions{
+		format:          "xlsx",
+		templateVersion: "1",
+	}
+	if v, ok := filters.Filters[pkg.FilterKey{Property: "preview", Operator: "eq"}]; ok && len(v) > 0 && v[0] == "true" {
+		opts.preview = true
+	}
+	if v, ok := filters.Filters[pkg.FilterKey{Property: "format", Operator: "eq"}]; ok && len(v) > 0 {
+		if format := strings.ToLower(strings.TrimSpace(v[0])); format != "" {
+			opts.format = format
 		}
+	}
+	if v, ok := filters.Filters[pkg.FilterKey{Property: "template_version", Operator: "eq"}]; ok && len(v) > 0 {
+		version := strings.TrimSpace(strings.ToLower(v[0]))
+		switch version {
+		case "2", "v2", "verzija2", "version2":
+			opts.plainBackground = true
+			opts.templateVersion = "2"
+		}
+	}
+	if v, ok := filters.Filters[pkg.FilterKey{Property: "template", Operator: "eq"}]; ok && len(v) > 0 {
+		switch value := strings.TrimSpace(strings.ToLower(v[0])); value {
+		case "", "auto":
+		case "builtin":
+			opts.builtinTemplate = true
+		default:
+			templateID, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || templateID <= 0 {
+				return nil, http.StatusBadRequest, fmt.Errorf("invalid template %q", v[0])
+			}
+			opts.templateID = templateID
+		}
+	}
+	if v, ok := filters.Filters[pkg.FilterKey{Property: "template_version_id", Operator: "eq"}]; ok && len(v) > 0 && strings.TrimSpace(v[0]) != "" {
+		versionID, err := strconv.ParseInt(strings.TrimSpace(v[0]), 10, 64)
+		if err != nil || versionID <= 0 {
+			return nil, http.StatusBadRequest, fmt.Errorf("invalid template_version_id %q", v[0])
+		}
+		opts.templateVersionID = versionID
+	}
+	if v, ok := filters.Filters[pkg.FilterKey{Property: "font", Operator: "eq"}]; ok && len(v) > 0 {
+		opts.fontKey = strings.TrimSpace(v[0])
+	}
+	if v, ok := filters.Filters[pkg.FilterKey{Property: "purpose", Operator: "eq"}]; ok && len(v) > 0 {
+		opts.purpose = v[0]
+	}
 
-		fi, err := os.Stat(targetFile)
+	if v, ok := filters.Filters[pkg.FilterKey{Property: "sign", Operator: "eq"}]; ok && len(v) > 0 && v[0] == "true" {
+		if opts.format != "pdf" {
+			return nil, http.StatusBadRequest, errors.New("only pdf output can be signed")
+		}
+		signer, err := loadPDFSigner(h.conf.Signing)
 		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "file not found"})
-			return
+			log.Println("Error loading pdf signer:", err)
+			status := http.StatusInternalServerError
+			if errors.Is(err, errorx.ErrPDFSigningNotConfigured) {
+				status = http.StatusBadRequest
+			}
+			return nil, status, err
 		}
+		opts.signer = signer
+	}
 
-		size := fi.Size()
+	// raspored polja: zadati parametrom layout ili podrazumevani
+	var err error
+	if v, ok := filters.Filters[pkg.FilterKey{Property: "layout", Operator: "eq"}]; ok && len(v) > 0 && strings.TrimSpace(v[0]) != "" {
+		layoutID, convErr := strconv.ParseInt(strings.TrimSpace(v[0]), 10, 64)
+		if convErr != nil || layoutID <= 0 {
+			return nil, http.StatusBadRequest, fmt.Errorf("invalid layout %q", v[0])
+		}
+		opts.layout, err = h.service.GetCertificateLayoutByID(ctx, layoutID)
+	} else {
+		opts.layout, err = h.service.GetDefaultCertificateLayout(ctx)
+	}
+	if err != nil {
+		if errors.Is(err, errorx.ErrCertificateLayoutNotFound) {
+			return nil, http.StatusBadRequest, err
+		}
+		return nil, http.StatusInternalServerError, err
+	}
 
-		ctx.Writer.Header().Set("Content-Type", contentType)
-		ctx.Writer.Header().Set("Content-Length", fmt.Sprintf("%d", size))
-		ctx.Writer.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", downloadName))
-		ctx.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		ctx.Writer.Header().Add("Access-Control-Expose-Headers", "Content-Disposition")
+	calibration, status, err := h.printCalibration(ctx, filters, workstation)
+	if err != nil {
+		return nil, status, err
+	}
+	opts.calibration = newPDFCalibration(calibration)
 
-		b, err := os.ReadFile(targetFile)
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read file"})
-			return
+	return opts, http.StatusOK, nil
+}
+
+// printCalibration vraca profil kalibracije zadat parametrom calibration (ID
+// ili "none" za stampu bez kalibracije), a bez parametra profil radne stanice
+// ili korisnika.
+func (h *httpHandler) printCalibration(ctx context.Context, filters *pkg.FilterAndSort, workstation string) (*dto.PrintCalibration, int, error) {
+	var (
+		calibration *dto.PrintCalibration
+		err         error
+	)
+	v, ok := filters.Filters[pkg.FilterKey{Property: "calibration", Operator: "eq"}]
+	value := ""
+	if ok && len(v) > 0 {
+		value = strings.TrimSpace(strings.ToLower(v[0]))
+	}
+	switch value {
+	case "", "auto":
+		calibration, err = h.service.ResolvePrintCalibration(ctx, workstation)
+	case "none":
+		return nil, http.StatusOK, nil
+	default:
+		calibrationID, convErr := strconv.ParseInt(value, 10, 64)
+		if convErr != nil || calibrationID <= 0 {
+			return nil, http.StatusBadRequest, fmt.Errorf("invalid calibration %q", v[0])
+		}
+		calibration, err = h.service.GetPrintCalibrationByID(ctx, calibrationID)
+	}
+	if err != nil {
+		if errors.Is(err, errorx.ErrPrintCalibrationNotFound) {
+			return nil, http.StatusBadRequest, err
 		}
+		return nil, http.StatusInternalServerError, err
+	}
+	return calibration, http.StatusOK, nil
+}
+
+// issueRequest opisuje izdato uverenje; svako stampanje je izdato uverenje
+// i pamti verziju obrasca po kojoj je odstampano.
+func (o *krstenicaPrintOptions) issueRequest(files *krstenicaTemplateFiles) *dto.IssuedCertificateCreateReq {
+	return &dto.IssuedCertificateCreateReq{
+		Format:            o.format,
+		TemplateVersion:   o.templateVersion,
+		TemplateVersionId: files.versionID,
+		Purpose:           o.purpose,
+	}
+}
 
-		n, err := ctx.Writer.Write(b)
+// krstenicaTemplateFiles su fajlovi obrasca po kojima se stampa jedna krstenica.
+type krstenicaTemplateFiles struct {
+	templateFile        string
+	backgroundImage     string
+	backgroundFullBleed bool
+	// versionID je verzija otpremljenog obrasca; nil za ugradjeni obrazac
+	versionID *int64
+}
+
+// krstenicaTemplateFiles bira obrazac za krstenicu: otpremljeni obrazac iz
+// registra ili, kada nijedan nije zadat ni dodeljen, obrazac iz doc/template_files.
+func (h *httpHandler) krstenicaTemplateFiles(ctx context.Context, opts *krstenicaPrintOptions, krstenica *dto.Krstenica) (*krstenicaTemplateFiles, error) {
+	var version *dto.CertificateTemplateVersion
+	if !opts.builtinTemplate {
+		var err error
+		version, err = h.service.ResolveKrstenicaTemplate(ctx, krstenica, opts.templateID, opts.templateVersionID)
 		if err != nil {
-			log.Println("Error while writing file to response:", err)
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to send file"})
-			return
+			return nil, err
 		}
+	}
 
-		if int64(n) != size {
-			log.Println("Incomplete file transfer:", n, "bytes written, expected:", size)
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "file transfer incomplete"})
-			return
+	var files *krstenicaTemplateFiles
+	if version != nil {
+		files = &krstenicaTemplateFiles{
+			templateFile:        version.TemplatePath,
+			backgroundImage:     version.BackgroundPath,
+			backgroundFullBleed: version.BackgroundFullBleed,
+			versionID:           &version.ID,
 		}
+		if opts.preview && version.PreviewTemplatePath != "" {
+			files.templateFile = version.PreviewTemplatePath
+		}
+	} else {
+		templateDir := resolveDir("doc/template_files")
+		files = &krstenicaTemplateFiles{
+			templateFile:        filepath.Join(templateDir, filepath.Base(templateEmptyFileRelative)),
+			backgroundImage:     resolveFile("krstenica_obrada.jpg"),
+			backgroundFullBleed: true,
+		}
+		if opts.preview {
+			files.templateFile = filepath.Join(templateDir, filepath.Base(templateFileRelative))
+		}
+	}
 
+	if opts.plainBackground {
+		files.backgroundImage = ""
+		files.backgroundFullBleed = false
 	}
+	return files, nil
 }
 
-func getKrstenicaCellValues(krstenica *dto.Krstenica) map[string]string {
-	values := map[string]string{
-		"C1":  krstenica.Book,
-		"C2":  formatInt(krstenica.Page),
-		"C3":  formatInt(krstenica.CurrentNumber),
-		"F8":  krstenica.EparhijaName,
-		"C10": krstenica.TampleName,
-		"I10": krstenica.TampleCity,
-		"F13": formatDateTimeComma(krstenica.BirthDate),
-		// "I17": krstenica.Country,
-		"F19": formatDateComma(krstenica.Baptism),
-		"G24": krstenica.TampleCity,
-		"I24": krstenica.TampleName,
-		"F27": krstenica.FirstName,
-		"I27": mapGenderToCyrillic(krstenica.Gender),
-		"F30": krstenica.ParentFirstName,
-		"I30": krstenica.ParentLastName,
-		"K30": krstenica.ParentOccupation,
-		"F31": krstenica.ParentCity,
-		"I31": krstenica.ParentReligion,
-		"I32": strings.TrimSpace(krstenica.BirthOrder),
-		"I36": strings.TrimSpace(krstenica.IsChurchMarried),
-		"I38": strings.TrimSpace(krstenica.IsTwin),
-		"I41": strings.TrimSpace(krstenica.HasPhysicalDisability),
-		"F43": krstenica.PriestFirstName,
-		"H43": krstenica.PriestLastName,
-		"K43": strings.TrimSpace(krstenica.PriestTitle),
-		"E48": krstenica.GodfatherFirstName,
-		"G48": krstenica.GodfatherLastName,
-		"E49": krstenica.GodfatherCity,
-		"G49": krstenica.GodfatherReligion,
-		"E51": krstenica.Anagrafa,
-		"C54": krstenica.Comment,
-		"B62": strings.TrimSpace(krstenica.NumberOfCertificate),
-		"B63": "",
-		"C63": "",
-		"B65": krstenica.TownOfCertificate,
-		// "F60": strings.TrimSpace(fmt.Sprintf("%s %s", krstenica.ParohFirstName, krstenica.ParohLastName)),
-		// "C62": krstenica.Status,
-	}
-
-	if values["K48"] == "" {
-		values["K48"] = strings.TrimSpace(krstenica.GodfatherOccupation)
-	}
-
-	placeBirth := strings.TrimSpace(krstenica.PlaceOfBirthday)
-	municipalityBirth := strings.TrimSpace(krstenica.MunicipalityOfBirthday)
-	values["E16"] = ""
-	values["F16"] = ""
-	values["G16"] = ""
+func templateErrorStatus(err error) int {
+	if errors.Is(err, errorx.ErrCertificateTemplateNotFound) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
 
-	switch {
-	case placeBirth != "" && municipalityBirth != "" && strings.EqualFold(placeBirth, municipalityBirth):
-		values["F16"] = municipalityBirth
-	case placeBirth == "" && municipalityBirth != "":
-		values["G16"] = municipalityBirth
-	case municipalityBirth == "" && placeBirth != "":
-		values["E16"] = placeBirth
-	default:
-		values["E16"] = placeBirth
-		values["G16"] = municipalityBirth
+// fillKrstenicaExcelFromTemplate kopira XLSX obrazac u targetFile i popunjava ga.
+func fillKrstenicaExcelFromTemplate(krstenica *dto.Krstenica, certificateLayout *dto.CertificateLayout, files *krstenicaTemplateFiles, targetFile string) error {
+	from, err := os.Open(files.templateFile)
+	if err != nil {
+		return err
 	}
+	defer from.Close()
 
-	if !krstenica.Certificate.IsZero() {
-		dayMonth, yearSuffix := splitDateDayMonthYearSuffix(krstenica.Certificate)
-		values["B63"] = dayMonth
-		values["C63"] = yearSuffix
+	to, err := os.OpenFile(targetFile, os.O_RDWR|os.O_CREATE, 0666)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(to, from); err != nil {
+		to.Close()
+		return err
+	}
+	if err := to.Close(); err != nil {
+		return err
 	}
 
-	if !krstenica.Baptism.IsZero() {
-		values["N10"] = formatBaptismYear(krstenica.Baptism)
-	} else {
-		values["N10"] = ""
+	return fillKrstenicaExcelFile(krstenica, certificateLayout, targetFile, files.backgroundImage, files.backgroundFullBleed)
+}
+
+func sendGeneratedFile(ctx *gin.Context, targetFile, contentType, downloadName string) {
+	fi, err := os.Stat(targetFile)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "file not found"})
+		return
+	}
+
+	size := fi.Size()
+
+	ctx.Writer.Header().Set("Content-Type", contentType)
+	ctx.Writer.Header().Set("Content-Length", fmt.Sprintf("%d", size))
+	ctx.Writer.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", downloadName))
+	ctx.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+	ctx.Writer.Header().Add("Access-Control-Expose-Headers", "Content-Disposition, X-Certificate-Serial")
+
+	b, err := os.ReadFile(targetFile)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read file"})
+		return
 	}
 
-	return values
+	n, err := ctx.Writer.Write(b)
+	if err != nil {
+		log.Println("Error while writing file to response:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to send file"})
+		return
+	}
+
+	if int64(n) != size {
+		log.Println("Incomplete file transfer:", n, "bytes written, expected:", size)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "file transfer incomplete"})
+		return
+	}
+}
+
+// formatParentText spaja ime, prezime i zanimanje roditelja u jedan tekst.
+func formatParentText(firstName, lastName, occupation string) string {
+	name := joinNonEmpty(" ", strings.TrimSpace(firstName), strings.TrimSpace(lastName))
+	return joinNonEmpty(", ", name, strings.TrimSpace(occupation))
+}
+
+// formatGodparentsText navodi sve kumove redom u jednom polju (kao roditelje u F30).
+func formatGodparentsText(godparents []dto.KrstenicaGodparent) string {
+	parts := make([]string, 0, len(godparents))
+	for _, g := range godparents {
+		parts = append(parts, formatParentText(g.FirstName, g.LastName, g.Occupation))
+	}
+	return joinNonEmpty(" и ", parts...)
+}
+
+// formatKrstenicaRemarks dopunjuje napomenu naknadnim zabeleškama (datum, vrsta i tekst).
+func formatKrstenicaRemarks(krstenica *dto.Krstenica) string {
+	parts := []string{strings.TrimSpace(krstenica.Comment)}
+	for _, a := range krstenica.Annotations {
+		label := annotationKindLabels[a.Kind]
+		if label == "" {
+			label = a.Kind
+		}
+		parts = append(parts, joinNonEmpty(" ", formatDate(a.NoteDate), joinNonEmpty(": ", label, strings.TrimSpace(a.Text))))
+	}
+	return joinNonEmpty("; ", parts...)
+}
+
+// joinDistinct spaja neprazne vrednosti bez ponavljanja (npr. isto mesto oba roditelja).
+func joinDistinct(sep string, parts ...string) string {
+	var distinct []string
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		duplicate := false
+		for _, existing := range distinct {
+			if strings.EqualFold(existing, part) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			distinct = append(distinct, part)
+		}
+	}
+	return strings.Join(distinct, sep)
 }
 
 func formatBaptismYear(t time.Time) string {
@@ -261,7 +462,13 @@ func formatBaptismYear(t time.Time) string {
 	return t.Format("06")
 }
 
-func fillKrstenicaExcelFile(krstenica *dto.Krstenica, targetFile string, backgroundImage string, fullBleed bool) error {
+// fillKrstenicaExcelFile popunjava XLSX uverenje po rasporedu uverenja.
+func fillKrstenicaExcelFile(krstenica *dto.Krstenica, certificateLayout *dto.CertificateLayout, targetFile string, backgroundImage string, fullBleed bool) error {
+	values, boldCells, wrapCells, fitRanges := krstenicaExcelCells(krstenica, certificateLayout)
+	return fillExcelCellValues(targetFile, values, boldCells, wrapCells, fitRanges, backgroundImage, fullBleed)
+}
+
+func fillExcelCellValues(targetFile string, values map[string]string, boldCells, wrapCells []string, fitRanges map[string]string, backgroundImage string, fullBleed bool) error {
 
 	// Proveriti da li fajl postoji
 	if _, err := os.Stat(targetFile); os.IsNotExist(err) {
@@ -304,11 +511,24 @@ func fillKrstenicaExcelFile(krstenica *dto.Krstenica, targetFile string, backgro
 		}
 	}
 
-	for cell, value := range getKrstenicaCellValues(krstenica) {
+	for cell, value := range values {
 		set(cell, value)
 	}
 
-	setCellBold(xlsxEx, sheetName, "F27")
+	for _, cell := range boldCells {
+		setCellBold(xlsxEx, sheetName, cell)
+	}
+
+	for _, cell := range wrapCells {
+		setCellWrap(xlsxEx, sheetName, cell)
+	}
+
+	for start, end := range fitRanges {
+		if strings.TrimSpace(values[start]) == "" {
+			continue
+		}
+		setCellShrinkToFit(xlsxEx, sheetName, start, end)
+	}
 
 	// Snimanje fajla
 	if err := xlsxEx.SaveAs(targetFile); err != nil {
@@ -349,6 +569,58 @@ func setCellBold(xlsxEx *excelize.File, sheetName, cell string) {
 	}
 }
 
+func setCellWrap(xlsxEx *excelize.File, sheetName, cell string) {
+	style := &excelize.Style{}
+	if styleID, err := xlsxEx.GetCellStyle(sheetName, cell); err == nil {
+		if existing, err := xlsxEx.GetStyle(styleID); err == nil && existing != nil {
+			style = existing
+		}
+	}
+	if style.Alignment == nil {
+		style.Alignment = &excelize.Alignment{}
+	}
+	if style.Alignment.WrapText {
+		return
+	}
+	style.Alignment.WrapText = true
+
+	wrapStyleID, err := xlsxEx.NewStyle(style)
+	if err != nil {
+		log.Printf("create wrap style for %s failed: %v", cell, err)
+		return
+	}
+	if err := xlsxEx.SetCellStyle(sheetName, cell, cell, wrapStyleID); err != nil {
+		log.Printf("apply wrap style to %s failed: %v", cell, err)
+	}
+}
+
+func setCellShrinkToFit(xlsxEx *excelize.File, sheetName, start, end string) {
+	style := &excelize.Style{}
+	if styleID, err := xlsxEx.GetCellStyle(sheetName, start); err == nil {
+		if existing, err := xlsxEx.GetStyle(styleID); err == nil && existing != nil {
+			style = existing
+		}
+	}
+	if style.Alignment == nil {
+		style.Alignment = &excelize.Alignment{}
+	}
+	style.Alignment.ShrinkToFit = true
+	style.Alignment.WrapText = false
+
+	if err := xlsxEx.MergeCell(sheetName, start, end); err != nil {
+		log.Printf("merge cells %s:%s failed: %v", start, end, err)
+		return
+	}
+	styleID, err := xlsxEx.NewStyle(style)
+	if err != nil {
+		log.Printf("create shrink style for %s failed: %v", start, err)
+		return
+	}
+	if err := xlsxEx.SetCellStyle(sheetName, start, end, styleID); err != nil {
+		log.Printf("apply shrink style to %s failed: %v", start, err)
+	}
+}
+
 func formatDateTime(t time.Time) string {
 	return formatSerbianDateTime(t)
 }
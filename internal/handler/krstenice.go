@@ -26,6 +26,10 @@ func (h *httpHandler) createKrstenice() gin.HandlerFunc {
 
 		krstenica, err := h.service.CreateKrstenica(cx, req)
 		if err != nil {
+			if isBookConflict(err) {
+				ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
@@ -71,14 +75,15 @@ func (h *httpHandler) listKrstenice() gin.HandlerFunc {
 				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 				return
 			}
+			if errorx.IsValidationError(err) {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
 
-		ctx.JSON(http.StatusOK, gin.H{
-			"data":  krstenica,
-			"total": totalCount,
-		})
+		ctx.JSON(http.StatusOK, listResponse(krstenica, totalCount, filters))
 	}
 }
 
@@ -103,6 +108,10 @@ func (h *httpHandler) updateKrstenice() gin.HandlerFunc {
 
 		krstenica, err := h.service.UpdateKrstenica(cx, int64(id), req)
 		if err != nil {
+			if isBookConflict(err) {
+				ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+
+	"krstenica/internal/dto"
+	"krstenica/internal/model"
+)
+
+var numberingIssueLabels = map[string]string{
+	string(model.NumberingIssueGap):       "Прескочен број",
+	string(model.NumberingIssueDuplicate): "Дупли број",
+	string(model.NumberingIssuePageOrder): "Страна унатраг",
+	string(model.NumberingIssueDateOrder): "Датум унатраг",
+}
+
+type numberingReportData struct {
+	Report *dto.NumberingReport
+	Query  string
+	Labels map[string]string
+}
+
+// *************************************************************Provera numeracije*************************************
+func (h *httpHandler) getKrstenicaNumbering() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		req := &dto.NumberingCheckReq{}
+		if err := ctx.ShouldBindQuery(req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "error when parsing request data"})
+			return
+		}
+
+		report, err := h.service.CheckKrstenicaNumbering(ctx.Request.Context(), req)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if strings.EqualFold(strings.TrimSpace(ctx.Query("format")), "xlsx") {
+			sendNumberingReportXLSX(ctx, report)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, report)
+	}
+}
+
+func sendNumberingReportXLSX(ctx *gin.Context, report *dto.NumberingReport) {
+	targetDir, err := os.MkdirTemp("", "provera-numeracije")
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create temp directory"})
+		return
+	}
+	defer os.RemoveAll(targetDir)
+
+	targetFile := filepath.Join(targetDir, "provera-numeracije.xlsx")
+	if err := writeNumberingReportXLSX(targetFile, report); err != nil {
+		log.Println("Error writing numbering report:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to build report: %v", err)})
+		return
+	}
+
+	sendGeneratedFile(ctx, targetFile, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", "provera-numeracije.xlsx")
+}
+
+func writeNumberingReportXLSX(targetFile string, report *dto.NumberingReport) error {
+	xlsxEx := excelize.NewFile()
+	defer xlsxEx.Close()
+
+	sheetName := "Провера"
+	if err := xlsxEx.SetSheetName(xlsxEx.GetSheetName(0), sheetName); err != nil {
+		return err
+	}
+
+	headerStyle, err := xlsxEx.NewStyle(&excelize.Style{
+		Font:   &excelize.Font{Bold: true},
+		Fill:   excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"E6E6E6"}},
+		Border: []excelize.Border{{Type: "bottom", Color: "999999", Style: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	headers := []interface{}{"Храм", "Књига", "Година", "Страна", "Текући број", "Врста", "Опис", "ИД крштеница", "Исправљено"}
+	if err := xlsxEx.SetSheetRow(sheetName, "A1", &headers); err != nil {
+		return err
+	}
+	if err := xlsxEx.SetCellStyle(sheetName, "A1", "I1", headerStyle); err != nil {
+		return err
+	}
+
+	row := 2
+	for _, book := range report.Books {
+		for _, issue := range book.Issues {
+			ids := make([]string, 0, len(issue.KrstenicaIds))
+			for _, id := range issue.KrstenicaIds {
+				ids = append(ids, strconv.FormatInt(id, 10))
+			}
+			values := []interface{}{
+				book.TampleName,
+				book.Book,
+				book.Year,
+				issue.Page,
+				issue.CurrentNumber,
+				numberingIssueLabels[issue.Kind],
+				issue.Message,
+				strings.Join(ids, ", "),
+				"",
+			}
+			cell, err := excelize.CoordinatesToCellName(1, row)
+			if err != nil {
+				return err
+			}
+			if err := xlsxEx.SetSheetRow(sheetName, cell, &values); err != nil {
+				return err
+			}
+			row++
+		}
+	}
+
+	widths := map[string]float64{"A": 28, "B": 14, "C": 8, "D": 8, "E": 12, "F": 16, "G": 70, "H": 16, "I": 12}
+	for col, width := range widths {
+		if err := xlsxEx.SetColWidth(sheetName, col, col, width); err != nil {
+			return err
+		}
+	}
+	if err := xlsxEx.AutoFilter(sheetName, fmt.Sprintf("A1:I%d", max(row-1, 1)), nil); err != nil {
+		return err
+	}
+
+	return xlsxEx.SaveAs(targetFile)
+}
+
+func (h *httpHandler) renderNumberingPage() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		hramovi, err := h.listActiveHramoviForForm(ctx.Request.Context())
+		if err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+
+		h.renderHTML(ctx, http.StatusOK, "knjige/provera.html", gin.H{
+			"Title":           "Provera numeracije",
+			"ContentTemplate": "knjige/provera-content",
+			"Hramovi":         hramovi,
+		})
+	}
+}
+
+func (h *httpHandler) renderNumberingTable() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		req := &dto.NumberingCheckReq{}
+		if err := ctx.ShouldBindQuery(req); err != nil {
+			h.renderHTML(ctx, http.StatusBadRequest, "partials/error.html", gin.H{
+				"Message": "Neispravni parametri provere",
+			})
+			return
+		}
+
+		report, err := h.service.CheckKrstenicaNumbering(ctx.Request.Context(), req)
+		if err != nil {
+			h.renderHTML(ctx, http.StatusInternalServerError, "partials/error.html", gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+
+		query := url.Values{}
+		for key, val := range ctx.Request.URL.Query() {
+			if len(val) > 0 && strings.TrimSpace(val[0]) != "" {
+				query.Set(key, val[0])
+			}
+		}
+		query.Set("format", "xlsx")
+
+		h.renderHTML(ctx, http.StatusOK, "knjige/provera-table.html", &numberingReportData{
+			Report: report,
+			Query:  query.Encode(),
+			Labels: numberingIssueLabels,
+		})
+	}
+}
@@ -0,0 +1,414 @@
+package handler
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"go.mozilla.org/pkcs7"
+
+	"krstenica/internal/config"
+	"krstenica/internal/errorx"
+)
+
+// pdfSignatureReservedBytes je prostor rezervisan za PKCS#7 potpis (sertifikat sa lancem).
+const pdfSignatureReservedBytes = 16384
+
+// errPDFXrefStream: potpis se dodaje kao inkrementalna izmena sa klasičnom
+// xref tabelom, pa PDF čiji se objekti vode u xref stream-u ne može da se
+// potpiše bez oštećenja fajla.
+var errPDFXrefStream = errors.New("pdf cross-reference streams are not supported for signing")
+
+var (
+	pdfTrailerSizeRe  = regexp.MustCompile(`/Size\s+(\d+)`)
+	pdfTrailerRootRe  = regexp.MustCompile(`/Root\s+(\d+)\s+0\s+R`)
+	pdfTrailerInfoRe  = regexp.MustCompile(`/Info\s+(\d+)\s+0\s+R`)
+	pdfStartXrefRe    = regexp.MustCompile(`startxref\s+(\d+)`)
+	pdfPagesRefRe     = regexp.MustCompile(`/Pages\s+(\d+)\s+0\s+R`)
+	pdfKidsFirstRefRe = regexp.MustCompile(`/Kids\s*\[\s*(\d+)\s+0\s+R`)
+)
+
+// pdfSigner potpisuje PDF uverenja sertifikatom parohije (adbe.pkcs7.detached).
+type pdfSigner struct {
+	cert     *x509.Certificate
+	chain    []*x509.Certificate
+	key      crypto.PrivateKey
+	reason   string
+	location string
+}
+
+// loadPDFSigner učitava sertifikat i ključ iz PEM fajlova navedenih u konfiguraciji.
+func loadPDFSigner(conf config.SigningConfig) (*pdfSigner, error) {
+	certFile := strings.TrimSpace(conf.CertFile)
+	keyFile := strings.TrimSpace(conf.KeyFile)
+	if certFile == "" || keyFile == "" {
+		return nil, errorx.ErrPDFSigningNotConfigured
+	}
+
+	certPEM, err := os.ReadFile(resolveFile(certFile))
+	if err != nil {
+		return nil, fmt.Errorf("read signing certificate: %w", err)
+	}
+	var certs []*x509.Certificate
+	for block, rest := pem.Decode(certPEM); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse signing certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("signing certificate file %s has no certificates", certFile)
+	}
+
+	keyPEM, err := os.ReadFile(resolveFile(keyFile))
+	if err != nil {
+		return nil, fmt.Errorf("read signing key: %w", err)
+	}
+	key, err := parsePEMPrivateKey(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("parse signing key: %w", err)
+	}
+
+	return &pdfSigner{
+		cert:     certs[0],
+		chain:    certs[1:],
+		key:      key,
+		reason:   strings.TrimSpace(conf.Reason),
+		location: strings.TrimSpace(conf.Location),
+	}, nil
+}
+
+func parsePEMPrivateKey(data []byte) (crypto.PrivateKey, error) {
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		switch block.Type {
+		case "RSA PRIVATE KEY":
+			return x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "EC PRIVATE KEY":
+			return x509.ParseECPrivateKey(block.Bytes)
+		case "PRIVATE KEY":
+			key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			switch key.(type) {
+			case *rsa.PrivateKey, *ecdsa.PrivateKey:
+				return key, nil
+			case ed25519.PrivateKey:
+				return nil, fmt.Errorf("ed25519 keys are not supported for pdf signatures")
+			}
+			return nil, fmt.Errorf("unsupported private key type %T", key)
+		}
+	}
+	return nil, fmt.Errorf("no private key found")
+}
+
+// subject vraća ime potpisnika (CN i organizacija) za podnožje strane.
+func (s *pdfSigner) subject() string {
+	parts := []string{}
+	if cn := strings.TrimSpace(s.cert.Subject.CommonName); cn != "" {
+		parts = append(parts, cn)
+	}
+	for _, org := range s.cert.Subject.Organization {
+		if org = strings.TrimSpace(org); org != "" && (len(parts) == 0 || parts[0] != org) {
+			parts = append(parts, org)
+		}
+	}
+	if len(parts) == 0 {
+		return s.cert.Subject.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (s *pdfSigner) footerText() string {
+	return "Дигитално потписано: " + s.subject()
+}
+
+// signFile dodaje potpis na postojeći PDF kao inkrementalnu izmenu fajla.
+func (s *pdfSigner) signFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	signed, err := s.sign(data)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, signed, 0666)
+}
+
+func (s *pdfSigner) sign(data []byte) ([]byte, error) {
+	doc, err := parsePDFTrailer(data)
+	if err != nil {
+		return nil, err
+	}
+	catalog, err := doc.objectDict(data, doc.root)
+	if err != nil {
+		return nil, fmt.Errorf("read catalog: %w", err)
+	}
+	if strings.Contains(catalog, "/AcroForm") {
+		return nil, fmt.Errorf("pdf already contains a form")
+	}
+	pageNum, err := doc.firstPage(data, catalog)
+	if err != nil {
+		return nil, err
+	}
+	page, err := doc.objectDict(data, pageNum)
+	if err != nil {
+		return nil, fmt.Errorf("read page: %w", err)
+	}
+
+	sigNum := doc.size
+	widgetNum := doc.size + 1
+
+	var buf bytes.Buffer
+	buf.Write(data)
+	if !bytes.HasSuffix(data, []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	offsets := map[int]int{}
+
+	offsets[sigNum] = buf.Len()
+	fmt.Fprintf(&buf, "%d 0 obj\n<< /Type /Sig /Filter /Adobe.PPKLite /SubFilter /adbe.pkcs7.detached", sigNum)
+	buf.WriteString(" /ByteRange ")
+	byteRangeAt := buf.Len()
+	buf.WriteString(formatByteRange(0, 0, 0, 0))
+	buf.WriteString(" /Contents ")
+	contentsAt := buf.Len()
+	buf.WriteByte('<')
+	buf.Write(bytes.Repeat([]byte("0"), pdfSignatureReservedBytes*2))
+	buf.WriteByte('>')
+	contentsEnd := buf.Len()
+	fmt.Fprintf(&buf, " /M (%s) /Name %s", time.Now().Format("D:20060102150405-07'00'"), pdfTextString(s.subject()))
+	if s.reason != "" {
+		fmt.Fprintf(&buf, " /Reason %s", pdfTextString(s.reason))
+	}
+	if s.location != "" {
+		fmt.Fprintf(&buf, " /Location %s", pdfTextString(s.location))
+	}
+	buf.WriteString(" >>\nendobj\n")
+
+	offsets[widgetNum] = buf.Len()
+	fmt.Fprintf(&buf, "%d 0 obj\n<< /Type /Annot /Subtype /Widget /FT /Sig /Rect [0 0 0 0] /V %d 0 R /T (Potpis) /F 132 /P %d 0 R >>\nendobj\n", widgetNum, sigNum, pageNum)
+
+	offsets[pageNum] = buf.Len()
+	fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", pageNum, addPDFAnnotation(page, widgetNum))
+
+	offsets[doc.root] = buf.Len()
+	acroForm := fmt.Sprintf("/AcroForm << /Fields [%d 0 R] /SigFlags 3 >>", widgetNum)
+	fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", doc.root, insertPDFDictEntry(catalog, acroForm))
+
+	xrefAt := buf.Len()
+	writePDFXref(&buf, offsets)
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root %d 0 R", widgetNum+1, doc.root)
+	if doc.info > 0 {
+		fmt.Fprintf(&buf, " /Info %d 0 R", doc.info)
+	}
+	fmt.Fprintf(&buf, " /Prev %d >>\nstartxref\n%d\n%%%%EOF\n", doc.startXref, xrefAt)
+
+	out := buf.Bytes()
+	byteRange := formatByteRange(0, contentsAt, contentsEnd, len(out)-contentsEnd)
+	copy(out[byteRangeAt:], byteRange)
+
+	signedData, err := pkcs7.NewSignedData(append(append([]byte{}, out[:contentsAt]...), out[contentsEnd:]...))
+	if err != nil {
+		return nil, fmt.Errorf("create signed data: %w", err)
+	}
+	signedData.SetDigestAlgorithm(pkcs7.OIDDigestAlgorithmSHA256)
+	if err := signedData.AddSignerChain(s.cert, s.key, s.chain, pkcs7.SignerInfoConfig{}); err != nil {
+		return nil, fmt.Errorf("add signer: %w", err)
+	}
+	signedData.Detach()
+	signature, err := signedData.Finish()
+	if err != nil {
+		return nil, fmt.Errorf("finish signature: %w", err)
+	}
+	if len(signature) > pdfSignatureReservedBytes {
+		return nil, fmt.Errorf("signature is %d bytes, only %d reserved", len(signature), pdfSignatureReservedBytes)
+	}
+	copy(out[contentsAt+1:], strings.ToUpper(hex.EncodeToString(signature)))
+	return out, nil
+}
+
+// pdfTrailer su podaci iz poslednjeg xref-a i trailer-a potrebni za inkrementalnu izmenu.
+type pdfTrailer struct {
+	size      int
+	root      int
+	info      int
+	startXref int
+	offsets   map[int]int
+}
+
+func parsePDFTrailer(data []byte) (*pdfTrailer, error) {
+	matches := pdfStartXrefRe.FindAllSubmatch(data, -1)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("pdf startxref not found")
+	}
+	startXref, _ := strconv.Atoi(string(matches[len(matches)-1][1]))
+	if startXref <= 0 || startXref >= len(data) {
+		return nil, fmt.Errorf("invalid pdf startxref %d", startXref)
+	}
+
+	section := data[startXref:]
+	if !bytes.HasPrefix(section, []byte("xref")) {
+		return nil, errPDFXrefStream
+	}
+	trailerAt := bytes.Index(section, []byte("trailer"))
+	if trailerAt < 0 {
+		return nil, fmt.Errorf("pdf trailer not found")
+	}
+	trailer := section[trailerAt:]
+	if end := bytes.Index(trailer, []byte("startxref")); end >= 0 {
+		trailer = trailer[:end]
+	}
+	// hibridni fajl: deo objekata je samo u xref stream-u
+	if bytes.Contains(trailer, []byte("/XRefStm")) {
+		return nil, errPDFXrefStream
+	}
+
+	doc := &pdfTrailer{startXref: startXref, offsets: map[int]int{}}
+	if m := pdfTrailerSizeRe.FindSubmatch(trailer); m != nil {
+		doc.size, _ = strconv.Atoi(string(m[1]))
+	}
+	if m := pdfTrailerRootRe.FindSubmatch(trailer); m != nil {
+		doc.root, _ = strconv.Atoi(string(m[1]))
+	}
+	if m := pdfTrailerInfoRe.FindSubmatch(trailer); m != nil {
+		doc.info, _ = strconv.Atoi(string(m[1]))
+	}
+	if doc.size == 0 || doc.root == 0 {
+		return nil, fmt.Errorf("pdf trailer is missing /Size or /Root")
+	}
+
+	lines := strings.Fields(string(section[len("xref"):trailerAt]))
+	for i := 0; i+1 < len(lines); {
+		first, err1 := strconv.Atoi(lines[i])
+		count, err2 := strconv.Atoi(lines[i+1])
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("invalid pdf xref subsection")
+		}
+		i += 2
+		for n := 0; n < count && i+2 < len(lines); n++ {
+			if lines[i+2] == "n" {
+				doc.offsets[first+n], _ = strconv.Atoi(lines[i])
+			}
+			i += 3
+		}
+	}
+	return doc, nil
+}
+
+// objectDict vraća rečnik (<< ... >>) objekta sa datim brojem.
+func (doc *pdfTrailer) objectDict(data []byte, num int) (string, error) {
+	offset, ok := doc.offsets[num]
+	if !ok || offset >= len(data) {
+		return "", fmt.Errorf("object %d not found", num)
+	}
+	start := bytes.Index(data[offset:], []byte("<<"))
+	if start < 0 {
+		return "", fmt.Errorf("object %d has no dictionary", num)
+	}
+	start += offset
+	depth := 0
+	for i := start; i+1 < len(data); i++ {
+		switch {
+		case data[i] == '<' && data[i+1] == '<':
+			depth++
+			i++
+		case data[i] == '>' && data[i+1] == '>':
+			depth--
+			i++
+			if depth == 0 {
+				return string(data[start : i+1]), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("object %d dictionary is not closed", num)
+}
+
+func (doc *pdfTrailer) firstPage(data []byte, catalog string) (int, error) {
+	m := pdfPagesRefRe.FindStringSubmatch(catalog)
+	if m == nil {
+		return 0, fmt.Errorf("pdf catalog has no pages")
+	}
+	pagesNum, _ := strconv.Atoi(m[1])
+	pages, err := doc.objectDict(data, pagesNum)
+	if err != nil {
+		return 0, fmt.Errorf("read pages: %w", err)
+	}
+	m = pdfKidsFirstRefRe.FindStringSubmatch(pages)
+	if m == nil {
+		return 0, fmt.Errorf("pdf page tree has no pages")
+	}
+	return strconv.Atoi(m[1])
+}
+
+func addPDFAnnotation(page string, annotNum int) string {
+	ref := fmt.Sprintf("%d 0 R", annotNum)
+	if idx := strings.Index(page, "/Annots"); idx >= 0 {
+		if open := strings.Index(page[idx:], "["); open >= 0 {
+			at := idx + open + 1
+			return page[:at] + ref + " " + page[at:]
+		}
+	}
+	return insertPDFDictEntry(page, "/Annots ["+ref+"]")
+}
+
+// insertPDFDictEntry dodaje unos pre završnog >> rečnika.
+func insertPDFDictEntry(dict, entry string) string {
+	end := strings.LastIndex(dict, ">>")
+	return dict[:end] + "\n" + entry + "\n" + dict[end:]
+}
+
+func writePDFXref(buf *bytes.Buffer, offsets map[int]int) {
+	nums := make([]int, 0, len(offsets))
+	for num := range offsets {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+
+	buf.WriteString("xref\n")
+	for i := 0; i < len(nums); {
+		j := i
+		for j+1 < len(nums) && nums[j+1] == nums[j]+1 {
+			j++
+		}
+		fmt.Fprintf(buf, "%d %d\n", nums[i], j-i+1)
+		for k := i; k <= j; k++ {
+			fmt.Fprintf(buf, "%010d 00000 n \n", offsets[nums[k]])
+		}
+		i = j + 1
+	}
+}
+
+func formatByteRange(a, b, c, d int) string {
+	return fmt.Sprintf("[%010d %010d %010d %010d]", a, b, c, d)
+}
+
+// pdfTextString kodira tekst kao UTF-16BE heks string da bi ćirilica bila ispravna.
+func pdfTextString(text string) string {
+	var b strings.Builder
+	b.WriteString("<FEFF")
+	for _, r := range utf16.Encode([]rune(text)) {
+		fmt.Fprintf(&b, "%04X", r)
+	}
+	b.WriteString(">")
+	return b.String()
+}
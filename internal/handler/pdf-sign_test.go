@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/phpdave11/gofpdf"
+	"go.mozilla.org/pkcs7"
+)
+
+var testPDFByteRangeRe = regexp.MustCompile(`/ByteRange\s*\[\s*(\d+)\s+(\d+)\s+(\d+)\s+(\d+)\s*\]`)
+
+func newTestPDFSigner(t *testing.T) *pdfSigner {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "Парохија тест", Organization: []string{"Крстеница"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &pdfSigner{cert: cert, key: key, reason: "Уверење", location: "Нови Сад"}
+}
+
+func newTestPDF(t *testing.T, pages int) []byte {
+	t.Helper()
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Arial", "", 12)
+	for i := 0; i < pages; i++ {
+		pdf.AddPage()
+		pdf.Cell(40, 10, fmt.Sprintf("Strana %d", i+1))
+	}
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// verifyTestPDFSignature proverava PKCS#7 potpis nad delovima fajla iz /ByteRange.
+func verifyTestPDFSignature(signed []byte) error {
+	m := testPDFByteRangeRe.FindSubmatch(signed)
+	if m == nil {
+		return fmt.Errorf("byte range not found")
+	}
+	r := make([]int, 4)
+	for i := range r {
+		r[i], _ = strconv.Atoi(string(m[i+1]))
+	}
+	if r[0] != 0 || r[2]+r[3] != len(signed) || r[1] >= r[2] {
+		return fmt.Errorf("byte range %v does not cover the file of %d bytes", r, len(signed))
+	}
+	if signed[r[1]] != '<' || signed[r[2]-1] != '>' {
+		return fmt.Errorf("byte range gap is not the /Contents string")
+	}
+
+	der, err := hex.DecodeString(string(signed[r[1]+1 : r[2]-1]))
+	if err != nil {
+		return err
+	}
+	// /Contents je dopunjen nulama do rezervisane duzine
+	var raw asn1.RawValue
+	if _, err := asn1.Unmarshal(der, &raw); err != nil {
+		return err
+	}
+	p7, err := pkcs7.Parse(raw.FullBytes)
+	if err != nil {
+		return err
+	}
+	p7.Content = append(append([]byte{}, signed[r[0]:r[1]]...), signed[r[2]:r[2]+r[3]]...)
+	return p7.Verify()
+}
+
+func TestPDFSignerSignVerifies(t *testing.T) {
+	signer := newTestPDFSigner(t)
+
+	for _, pages := range []int{1, 3} {
+		t.Run(fmt.Sprintf("%d strana", pages), func(t *testing.T) {
+			data := newTestPDF(t, pages)
+			original, err := parsePDFTrailer(data)
+			if err != nil {
+				t.Fatalf("parse trailer: %v", err)
+			}
+			signed, err := signer.sign(data)
+			if err != nil {
+				t.Fatalf("sign: %v", err)
+			}
+			if !bytes.HasPrefix(signed, data) {
+				t.Fatal("signature must be an incremental update of the original file")
+			}
+			if err := verifyTestPDFSignature(signed); err != nil {
+				t.Fatalf("verify: %v", err)
+			}
+
+			// nova xref tabela mora da pokazuje na objekte koje je potpis dodao
+			doc, err := parsePDFTrailer(signed)
+			if err != nil {
+				t.Fatalf("parse signed trailer: %v", err)
+			}
+			if doc.size != original.size+2 || doc.startXref <= original.startXref {
+				t.Fatalf("signed trailer /Size %d at %d, original /Size %d at %d", doc.size, doc.startXref, original.size, original.startXref)
+			}
+			for num, offset := range doc.offsets {
+				if !bytes.HasPrefix(signed[offset:], []byte(fmt.Sprintf("%d 0 obj", num))) {
+					t.Fatalf("xref offset %d of object %d does not point to the object", offset, num)
+				}
+			}
+			catalog, err := doc.objectDict(signed, doc.root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Contains([]byte(catalog), []byte("/AcroForm")) {
+				t.Fatal("signed catalog has no /AcroForm")
+			}
+		})
+	}
+}
+
+func TestPDFSignerSignDetectsTampering(t *testing.T) {
+	signer := newTestPDFSigner(t)
+	signed, err := signer.sign(newTestPDF(t, 1))
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	// menja se bajt u potpisanom delu pre /Contents
+	signed[len("%PDF-1.")] ^= 0x01
+	if err := verifyTestPDFSignature(signed); err == nil {
+		t.Fatal("verify must fail after the signed content is changed")
+	}
+}
+
+func TestPDFSignerRejectsXrefStreams(t *testing.T) {
+	signer := newTestPDFSigner(t)
+
+	stream := []byte("%PDF-1.5\n" +
+		"1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n" +
+		"3 0 obj\n<< /Type /XRef /Size 4 /Root 1 0 R /W [1 2 1] /Length 0 >>\nstream\n\nendstream\nendobj\n")
+	xrefAt := bytes.Index(stream, []byte("3 0 obj"))
+	stream = append(stream, []byte(fmt.Sprintf("startxref\n%d\n%%%%EOF\n", xrefAt))...)
+
+	hybrid := bytes.Replace(newTestPDF(t, 1), []byte("trailer\n<<"), []byte("trailer\n<<\n/XRefStm 9"), 1)
+	if !bytes.Contains(hybrid, []byte("/XRefStm")) {
+		t.Fatal("test pdf has no trailer")
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"xref stream", stream},
+		{"hibridni fajl", hybrid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signed, err := signer.sign(tt.data)
+			if !errors.Is(err, errPDFXrefStream) {
+				t.Fatalf("sign error = %v, want %v", err, errPDFXrefStream)
+			}
+			if signed != nil {
+				t.Fatal("sign must not return a document on error")
+			}
+		})
+	}
+}
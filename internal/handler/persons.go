@@ -71,14 +71,15 @@ func (h *httpHandler) listPersons() gin.HandlerFunc {
 				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 				return
 			}
+			if errorx.IsValidationError(err) {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
 
-		ctx.JSON(http.StatusOK, gin.H{
-			"data":  person,
-			"total": totalCount,
-		})
+		ctx.JSON(http.StatusOK, listResponse(person, totalCount, filters))
 	}
 }
 
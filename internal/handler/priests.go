@@ -72,14 +72,15 @@ func (h *httpHandler) listPriest() gin.HandlerFunc {
 				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 				return
 			}
+			if errorx.IsValidationError(err) {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
 
-		ctx.JSON(http.StatusOK, gin.H{
-			"data":  priest,
-			"total": totalCount,
-		})
+		ctx.JSON(http.StatusOK, listResponse(priest, totalCount, filters))
 	}
 }
 
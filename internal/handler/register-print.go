@@ -0,0 +1,260 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+
+	"krstenica/pkg"
+)
+
+// registerLayout opisuje jednostavan izvod (naslov + parovi labela/vrednost)
+// za knjige koje nemaju sopstveni XLSX šablon. Šablon se generiše pri svakoj
+// štampi, a PDF se crta preko istog worksheetLayout mehanizma kao krštenica.
+type registerLayout struct {
+	sheetName string
+	title     string
+	rows      []registerRow
+	wrapRows  map[int]bool
+}
+
+type registerRow struct {
+	row   int
+	label string
+}
+
+const (
+	registerTitleCell   = "B2"
+	registerLabelColumn = "B"
+	registerValueColumn = "C"
+)
+
+func registerValueCell(row int) string {
+	return fmt.Sprintf("%s%d", registerValueColumn, row)
+}
+
+func registerLabelCell(row int) string {
+	return fmt.Sprintf("%s%d", registerLabelColumn, row)
+}
+
+func (l registerLayout) staticValues() map[string]string {
+	values := map[string]string{registerTitleCell: l.title}
+	for _, r := range l.rows {
+		values[registerLabelCell(r.row)] = r.label
+	}
+	return values
+}
+
+func (l registerLayout) cellOrder() []string {
+	rows := make([]int, 0, len(l.rows))
+	for _, r := range l.rows {
+		rows = append(rows, r.row)
+	}
+	sort.Ints(rows)
+
+	order := []string{registerTitleCell}
+	for _, row := range rows {
+		order = append(order, registerLabelCell(row), registerValueCell(row))
+	}
+	return order
+}
+
+func (l registerLayout) buildTemplate(targetFile string) error {
+	xlsxEx := excelize.NewFile()
+	defer xlsxEx.Close()
+
+	defaultSheet := xlsxEx.GetSheetName(0)
+	if err := xlsxEx.SetSheetName(defaultSheet, l.sheetName); err != nil {
+		return err
+	}
+	sheet := l.sheetName
+
+	if err := xlsxEx.SetColWidth(sheet, "A", "A", 2); err != nil {
+		return err
+	}
+	if err := xlsxEx.SetColWidth(sheet, registerLabelColumn, registerLabelColumn, 34); err != nil {
+		return err
+	}
+	if err := xlsxEx.SetColWidth(sheet, registerValueColumn, registerValueColumn, 52); err != nil {
+		return err
+	}
+
+	titleStyle, err := xlsxEx.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true, Size: 14}})
+	if err != nil {
+		return err
+	}
+	labelStyle, err := xlsxEx.NewStyle(&excelize.Style{Font: &excelize.Font{Size: 8, Color: "555555"}})
+	if err != nil {
+		return err
+	}
+	valueStyle, err := xlsxEx.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Size: 8},
+		Border:    []excelize.Border{{Type: "bottom", Color: "999999", Style: 1}},
+		Alignment: &excelize.Alignment{Vertical: "bottom"},
+	})
+	if err != nil {
+		return err
+	}
+	wrapStyle, err := xlsxEx.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Size: 8},
+		Border:    []excelize.Border{{Type: "bottom", Color: "999999", Style: 1}},
+		Alignment: &excelize.Alignment{Vertical: "top", WrapText: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := xlsxEx.SetRowHeight(sheet, 2, 28); err != nil {
+		return err
+	}
+	if err := xlsxEx.SetCellValue(sheet, registerTitleCell, l.title); err != nil {
+		return err
+	}
+	if err := xlsxEx.SetCellStyle(sheet, registerTitleCell, registerTitleCell, titleStyle); err != nil {
+		return err
+	}
+
+	for _, r := range l.rows {
+		height := 20.0
+		style := valueStyle
+		if l.wrapRows[r.row] {
+			height = 40.0
+			style = wrapStyle
+		}
+		if err := xlsxEx.SetRowHeight(sheet, r.row, height); err != nil {
+			return err
+		}
+		labelCell := registerLabelCell(r.row)
+		valueCell := registerValueCell(r.row)
+		if err := xlsxEx.SetCellValue(sheet, labelCell, r.label); err != nil {
+			return err
+		}
+		if err := xlsxEx.SetCellStyle(sheet, labelCell, labelCell, labelStyle); err != nil {
+			return err
+		}
+		if err := xlsxEx.SetCellStyle(sheet, valueCell, valueCell, style); err != nil {
+			return err
+		}
+	}
+
+	size := 9
+	if err := xlsxEx.SetPageLayout(sheet, &excelize.PageLayoutOptions{Size: &size}); err != nil {
+		return err
+	}
+	left, right, top, bottom := 0.7, 0.5, 0.75, 0.75
+	if err := xlsxEx.SetPageMargins(sheet, &excelize.PageLayoutMarginsOptions{
+		Left:   &left,
+		Right:  &right,
+		Top:    &top,
+		Bottom: &bottom,
+	}); err != nil {
+		return err
+	}
+
+	return xlsxEx.SaveAs(targetFile)
+}
+
+// writeRegisterDocument generiše šablon u privremenom direktorijumu i vraća
+// popunjen XLSX ili PDF izvod na osnovu "format" i "font" parametara.
+func writeRegisterDocument(ctx *gin.Context, layout registerLayout, values map[string]string, boldCells []string, baseName string) {
+	filters := pkg.ParseUrlQuery(ctx)
+
+	outputFormat := "xlsx"
+	if v, ok := filters.Filters[pkg.FilterKey{Property: "format", Operator: "eq"}]; ok && len(v) > 0 {
+		outputFormat = strings.ToLower(strings.TrimSpace(v[0]))
+	}
+	fontKey := ""
+	if v, ok := filters.Filters[pkg.FilterKey{Property: "font", Operator: "eq"}]; ok && len(v) > 0 {
+		fontKey = strings.TrimSpace(v[0])
+	}
+
+	targetDir, err := os.MkdirTemp("", baseName)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create temp directory"})
+		return
+	}
+	defer os.RemoveAll(targetDir)
+
+	templateFile := filepath.Join(targetDir, baseName+"-template.xlsx")
+	if err := layout.buildTemplate(templateFile); err != nil {
+		log.Println("Error building register template:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to build template: %v", err)})
+		return
+	}
+
+	var (
+		targetFile   string
+		contentType  string
+		downloadName string
+	)
+
+	switch outputFormat {
+	case "pdf":
+		layoutInfo, err := loadWorksheetLayout(templateFile)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to load template layout: %v", err)})
+			return
+		}
+
+		allValues := layout.staticValues()
+		for cell, value := range values {
+			allValues[cell] = value
+		}
+
+		bold := map[string]bool{registerTitleCell: true}
+		for _, cell := range boldCells {
+			bold[cell] = true
+		}
+		forcedWrap := map[string]bool{}
+		for row := range layout.wrapRows {
+			forcedWrap[registerValueCell(row)] = true
+		}
+
+		spec := pdfCellSpec{
+			order:       layout.cellOrder(),
+			offsets:     map[string]textOffset{},
+			bold:        bold,
+			forcedWrap:  forcedWrap,
+			fontRefCell: registerValueCell(layout.rows[0].row),
+		}
+
+		targetFile = filepath.Join(targetDir, baseName+".pdf")
+		if err := renderPDFCellValues(allValues, spec, layoutInfo, targetFile, "", false, fontKey, nil); err != nil {
+			log.Println("Error generating PDF file:", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to generate PDF file: %v", err)})
+			return
+		}
+		contentType = "application/pdf"
+		downloadName = baseName + ".pdf"
+	default:
+		targetFile = templateFile
+		if err := fillExcelCellValues(targetFile, values, boldCells, nil, nil, "", false); err != nil {
+			log.Println("Error generating Excel file:", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to generate Excel file: %v", err)})
+			return
+		}
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+		downloadName = baseName + ".xlsx"
+	}
+
+	sendGeneratedFile(ctx, targetFile, contentType, downloadName)
+}
+
+func formatNumericDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.In(time.Local).Format("02.01.2006.")
+}
+
+func joinNonEmpty(sep string, parts ...string) string {
+	return strings.Join(filterEmpty(parts), sep)
+}
@@ -37,6 +37,10 @@ func (h *httpHandler) addRoutes() {
 	adminRouter.POST(pathWithAction("adminv2", "users"), h.createUser())
 	adminRouter.PUT(pathWithAction("adminv2", "users/:id"), h.updateUser())
 	adminRouter.DELETE(pathWithAction("adminv2", "users/:id"), h.deleteUser())
+	adminRouter.GET(pathWithAction("adminv2", "audit-log"), h.listAuditLogs())
+	adminRouter.GET(pathWithAction("adminv2", "trash"), h.listTrash())
+	adminRouter.POST(pathWithAction("adminv2", "trash/:entity/:id/restore"), h.restoreTrashItem())
+	adminRouter.DELETE(pathWithAction("adminv2", "trash/:entity/:id"), h.purgeTrashItem())
 
 	// krstenice routes available to any authenticated user (service enforces city/role)
 	apiRouter.POST(pathWithAction("adminv2", "krstenice"), h.createKrstenice())
@@ -45,6 +49,45 @@ func (h *httpHandler) addRoutes() {
 	apiRouter.PUT(pathWithAction("adminv2", "krstenice/:id"), h.updateKrstenice())
 	apiRouter.DELETE(pathWithAction("adminv2", "krstenice/:id"), h.deleteKrstenice())
 	apiRouter.GET(pathWithAction("adminv2", "krstenice-print/:id"), h.getKrstenicePrint())
+	apiRouter.GET(pathWithAction("adminv2", "krstenice-print-batch"), h.getKrstenicePrintBatch())
+	apiRouter.GET(pathWithAction("adminv2", "krstenice-print-preview/:id"), h.getKrstenicaPreview())
+	apiRouter.GET(pathWithAction("adminv2", "krstenice-export"), h.exportKrstenice())
+	apiRouter.GET(pathWithAction("adminv2", "krstenice/:id/annotations"), h.listKrstenicaAnnotations())
+	apiRouter.POST(pathWithAction("adminv2", "krstenice/:id/annotations"), h.createKrstenicaAnnotation())
+	apiRouter.PUT(pathWithAction("adminv2", "krstenice/:id/annotations/:annotationId"), h.updateKrstenicaAnnotation())
+	apiRouter.DELETE(pathWithAction("adminv2", "krstenice/:id/annotations/:annotationId"), h.deleteKrstenicaAnnotation())
+	apiRouter.GET(pathWithAction("adminv2", "krstenice/:id/attachments"), h.listKrstenicaAttachments())
+	apiRouter.POST(pathWithAction("adminv2", "krstenice/:id/attachments"), h.uploadKrstenicaAttachment())
+	apiRouter.GET(pathWithAction("adminv2", "krstenice/:id/attachments/:attachmentId"), h.downloadKrstenicaAttachment())
+	apiRouter.DELETE(pathWithAction("adminv2", "krstenice/:id/attachments/:attachmentId"), h.deleteKrstenicaAttachment())
+	apiRouter.GET(pathWithAction("adminv2", "krstenice/:id/versions"), h.listKrstenicaVersions())
+	apiRouter.GET(pathWithAction("adminv2", "krstenice/:id/versions/:version"), h.getKrstenicaVersion())
+	apiRouter.POST(pathWithAction("adminv2", "krstenice/:id/versions/:version/restore"), h.restoreKrstenicaVersion())
+	apiRouter.GET(pathWithAction("adminv2", "issued-certificates/:id"), h.getIssuedCertificates())
+	apiRouter.GET(pathWithAction("adminv2", "issued-certificates"), h.listIssuedCertificates())
+	adminRouter.POST(pathWithAction("adminv2", "issued-certificates/:id/revoke"), h.revokeIssuedCertificate())
+
+	apiRouter.POST(pathWithAction("adminv2", "vencanice"), h.createVencanice())
+	apiRouter.GET(pathWithAction("adminv2", "vencanice/:id"), h.getVencanice())
+	apiRouter.GET(pathWithAction("adminv2", "vencanice"), h.listVencanice())
+	apiRouter.PUT(pathWithAction("adminv2", "vencanice/:id"), h.updateVencanice())
+	apiRouter.DELETE(pathWithAction("adminv2", "vencanice/:id"), h.deleteVencanice())
+	apiRouter.GET(pathWithAction("adminv2", "vencanice-print/:id"), h.getVencanicePrint())
+
+	apiRouter.POST(pathWithAction("adminv2", "umrlice"), h.createUmrlice())
+	apiRouter.GET(pathWithAction("adminv2", "umrlice/:id"), h.getUmrlice())
+	apiRouter.GET(pathWithAction("adminv2", "umrlice"), h.listUmrlice())
+	apiRouter.PUT(pathWithAction("adminv2", "umrlice/:id"), h.updateUmrlice())
+	apiRouter.DELETE(pathWithAction("adminv2", "umrlice/:id"), h.deleteUmrlice())
+	apiRouter.GET(pathWithAction("adminv2", "umrlice-print/:id"), h.getUmrlicePrint())
+
+	// knjige: pregled je dostupan svima (izbor knjige pri upisu), izmene samo adminu
+	apiRouter.GET(pathWithAction("adminv2", "books/:id"), h.getBooks())
+	apiRouter.GET(pathWithAction("adminv2", "books"), h.listBooks())
+	adminRouter.POST(pathWithAction("adminv2", "books"), h.createBooks())
+	adminRouter.PUT(pathWithAction("adminv2", "books/:id"), h.updateBooks())
+	adminRouter.DELETE(pathWithAction("adminv2", "books/:id"), h.deleteBooks())
+	adminRouter.GET(pathWithAction("adminv2", "krstenice-numbering"), h.getKrstenicaNumbering())
 }
 
 func pathWithAction(module string, action string) string {
@@ -67,14 +67,15 @@ func (h *httpHandler) listTample() gin.HandlerFunc {
 
 		tamples, totalCount, err := h.service.ListTamples(cx, filters)
 		if err != nil {
+			if errorx.IsValidationError(err) {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
 
-		ctx.JSON(http.StatusOK, gin.H{
-			"data":  tamples,
-			"total": totalCount,
-		})
+		ctx.JSON(http.StatusOK, listResponse(tamples, totalCount, filters))
 	}
 }
 
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"krstenica/internal/errorx"
+	"krstenica/pkg"
+)
+
+// *************************************************************Korpa*************************************
+func (h *httpHandler) listTrash() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		cx := ctx.Request.Context()
+
+		filters := pkg.ParseUrlQuery(ctx)
+
+		items, totalCount, err := h.service.ListTrash(cx, filters)
+		if err != nil {
+			if errorx.IsValidationError(err) {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, listResponse(items, totalCount, filters))
+	}
+}
+
+func (h *httpHandler) restoreTrashItem() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := h.service.RestoreTrashItem(ctx.Request.Context(), ctx.Param("entity"), int64(id)); err != nil {
+			ctx.JSON(trashErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, nil)
+	}
+}
+
+// purgeTrashItem trajno briše zapis iz korpe.
+func (h *httpHandler) purgeTrashItem() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := h.service.PurgeTrashItem(ctx.Request.Context(), ctx.Param("entity"), int64(id)); err != nil {
+			ctx.JSON(trashErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, nil)
+	}
+}
+
+func trashErrorStatus(err error) int {
+	if errors.Is(err, errorx.ErrTrashItemNotFound) {
+		return http.StatusNotFound
+	}
+	if errors.Is(err, errorx.ErrTrashItemInUse) || errors.Is(err, errorx.ErrTrashRetentionNotExpired) || isBookConflict(err) {
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+//****************************************************end******Korpa*************************************
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"krstenica/internal/dto"
+	"krstenica/internal/errorx"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var umrlicaLayout = registerLayout{
+	sheetName: "umrlica",
+	title:     "ИЗВОД ИЗ МАТИЧНЕ КЊИГЕ УМРЛИХ",
+	rows: []registerRow{
+		{row: 4, label: "Епархија"},
+		{row: 5, label: "Храм"},
+		{row: 6, label: "Књига, страна, текући број"},
+		{row: 8, label: "Име и презиме умрлог"},
+		{row: 9, label: "Занимање и место становања"},
+		{row: 10, label: "Вероисповест"},
+		{row: 11, label: "Датум рођења"},
+		{row: 12, label: "Брачно стање"},
+		{row: 13, label: "Крштен"},
+		{row: 15, label: "Датум смрти"},
+		{row: 16, label: "Место смрти"},
+		{row: 17, label: "Старост"},
+		{row: 18, label: "Узрок смрти"},
+		{row: 19, label: "Свете тајне пред смрт"},
+		{row: 21, label: "Датум сахране"},
+		{row: 22, label: "Место сахране"},
+		{row: 23, label: "Свештеник који је опојао"},
+		{row: 25, label: "Напомена"},
+		{row: 27, label: "Број извода"},
+		{row: 28, label: "Место и датум издавања"},
+	},
+	wrapRows: map[int]bool{13: true, 25: true},
+}
+
+var umrlicaBoldCells = []string{"C8"}
+
+// *************************************************************Umrlica Print*************************************
+func (h *httpHandler) getUmrlicePrint() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		umrlica, err := h.service.GetUmrlicaByID(ctx.Request.Context(), int64(id))
+		if err != nil {
+			if err == errorx.ErrUmrlicaNotFound {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		writeRegisterDocument(ctx, umrlicaLayout, getUmrlicaCellValues(umrlica), umrlicaBoldCells, "umrlica")
+	}
+}
+
+func getUmrlicaCellValues(umrlica *dto.Umrlica) map[string]string {
+	bookLine := joinNonEmpty(", ",
+		prefixIfNotEmpty("књига ", strings.TrimSpace(umrlica.Book)),
+		prefixIfNotEmpty("страна ", formatInt(umrlica.Page)),
+		prefixIfNotEmpty("број ", formatInt(umrlica.CurrentNumber)),
+	)
+
+	birthDate := umrlica.DeceasedBirthDate
+	if birthDate.IsZero() {
+		birthDate = umrlica.KrstenicaBirthDate
+	}
+
+	baptismLine := ""
+	if umrlica.KrstenicaId != nil {
+		baptismLine = joinNonEmpty(", ",
+			formatNumericDate(umrlica.KrstenicaBaptism),
+			umrlica.KrstenicaTampleName,
+			prefixIfNotEmpty("књига ", strings.TrimSpace(umrlica.KrstenicaBook)),
+			prefixIfNotEmpty("страна ", formatInt(umrlica.KrstenicaPage)),
+			prefixIfNotEmpty("број ", formatInt(umrlica.KrstenicaCurrentNumber)),
+		)
+	}
+
+	values := map[string]string{
+		"C4":  umrlica.EparhijaName,
+		"C5":  joinNonEmpty(", ", umrlica.TampleName, umrlica.TampleCity),
+		"C6":  bookLine,
+		"C8":  joinNonEmpty(" ", umrlica.DeceasedFirstName, umrlica.DeceasedLastName),
+		"C9":  joinNonEmpty(", ", umrlica.DeceasedOccupation, joinNonEmpty(" ", umrlica.DeceasedAddress, umrlica.DeceasedCity)),
+		"C10": strings.TrimSpace(umrlica.DeceasedReligion),
+		"C11": formatNumericDate(birthDate),
+		"C12": strings.TrimSpace(umrlica.MaritalStatus),
+		"C13": baptismLine,
+		"C15": formatNumericDate(umrlica.DeathDate),
+		"C16": joinNonEmpty(", ", umrlica.City, umrlica.Country),
+		"C17": formatAgeAtDeath(birthDate, umrlica.DeathDate),
+		"C18": strings.TrimSpace(umrlica.CauseOfDeath),
+		"C19": strings.TrimSpace(umrlica.Sacraments),
+		"C21": formatNumericDate(umrlica.BurialDate),
+		"C22": strings.TrimSpace(umrlica.Cemetery),
+		"C23": joinNonEmpty(", ", joinNonEmpty(" ", umrlica.PriestFirstName, umrlica.PriestLastName), umrlica.PriestTitle),
+		"C25": strings.TrimSpace(umrlica.Comment),
+		"C27": strings.TrimSpace(umrlica.NumberOfCertificate),
+		"C28": joinNonEmpty(", ", umrlica.TownOfCertificate, formatNumericDate(umrlica.Certificate)),
+	}
+
+	return values
+}
+
+// formatAgeAtDeath vraća navršene godine života ("73 год.") ili prazan string
+// ako neki od datuma nedostaje.
+func formatAgeAtDeath(birth, death time.Time) string {
+	if birth.IsZero() || death.IsZero() || death.Before(birth) {
+		return ""
+	}
+	years := death.Year() - birth.Year()
+	if death.Month() < birth.Month() || (death.Month() == birth.Month() && death.Day() < birth.Day()) {
+		years--
+	}
+	return strconv.Itoa(years) + " год."
+}
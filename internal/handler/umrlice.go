@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"fmt"
+	"krstenica/internal/dto"
+	"krstenica/internal/errorx"
+	"krstenica/pkg"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// *************************************************************Umrlica*************************************
+func (h *httpHandler) createUmrlice() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		req := &dto.UmrlicaCreateReq{}
+
+		if err := ctx.Bind(req); err != nil {
+			fmt.Println("Error when parsing body", err)
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "error when parsing request data"})
+			return
+		}
+
+		cx := ctx.Request.Context()
+
+		umrlica, err := h.service.CreateUmrlica(cx, req)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, umrlica)
+	}
+}
+
+func (h *httpHandler) getUmrlice() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		cx := ctx.Request.Context()
+
+		umrlica, err := h.service.GetUmrlicaByID(cx, int64(id))
+		if err != nil {
+			if err == errorx.ErrUmrlicaNotFound {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, umrlica)
+	}
+}
+
+func (h *httpHandler) listUmrlice() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		cx := ctx.Request.Context()
+
+		filters := pkg.ParseUrlQuery(ctx)
+
+		umrlice, totalCount, err := h.service.ListUmrlice(cx, filters)
+		if err != nil {
+			if err == errorx.ErrUmrlicaNotFound {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			if errorx.IsValidationError(err) {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, listResponse(umrlice, totalCount, filters))
+	}
+}
+
+func (h *httpHandler) updateUmrlice() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		req := &dto.UmrlicaUpdateReq{}
+
+		if err := ctx.Bind(req); err != nil {
+			fmt.Println("Error when parsing body", err)
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "error when parsing request data"})
+			return
+		}
+
+		cx := ctx.Request.Context()
+
+		umrlica, err := h.service.UpdateUmrlica(cx, int64(id), req)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, umrlica)
+	}
+}
+
+func (h *httpHandler) deleteUmrlice() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		err = h.service.DeleteUmrlica(ctx.Request.Context(), int64(id))
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, nil)
+	}
+}
+
+//****************************************************end******Umrlica*************************************
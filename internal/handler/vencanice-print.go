@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"fmt"
+	"krstenica/internal/dto"
+	"krstenica/internal/errorx"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var vencanicaLayout = registerLayout{
+	sheetName: "vencanica",
+	title:     "ИЗВОД ИЗ МАТИЧНЕ КЊИГЕ ВЕНЧАНИХ",
+	rows: []registerRow{
+		{row: 4, label: "Епархија"},
+		{row: 5, label: "Храм"},
+		{row: 6, label: "Књига, страна, текући број"},
+		{row: 7, label: "Датум венчања"},
+		{row: 8, label: "Место венчања"},
+		{row: 10, label: "Младожења"},
+		{row: 11, label: "Занимање и место становања"},
+		{row: 12, label: "Вероисповест"},
+		{row: 13, label: "Датум рођења"},
+		{row: 14, label: "Који брак по реду"},
+		{row: 16, label: "Невеста"},
+		{row: 17, label: "Занимање и место становања"},
+		{row: 18, label: "Вероисповест"},
+		{row: 19, label: "Датум рођења"},
+		{row: 20, label: "Који брак по реду"},
+		{row: 22, label: "Кум"},
+		{row: 23, label: "Стари сват / други сведок"},
+		{row: 24, label: "Свештеник који је венчао"},
+		{row: 25, label: "Грађански брак"},
+		{row: 27, label: "Напомена"},
+		{row: 29, label: "Број извода"},
+		{row: 30, label: "Место и датум издавања"},
+	},
+	wrapRows: map[int]bool{27: true},
+}
+
+var vencanicaBoldCells = []string{"C10", "C16"}
+
+// *************************************************************Vencanica Print*************************************
+func (h *httpHandler) getVencanicePrint() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		vencanica, err := h.service.GetVencanicaByID(ctx.Request.Context(), int64(id))
+		if err != nil {
+			if err == errorx.ErrVencanicaNotFound {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		writeRegisterDocument(ctx, vencanicaLayout, getVencanicaCellValues(vencanica), vencanicaBoldCells, "vencanica")
+	}
+}
+
+func getVencanicaCellValues(vencanica *dto.Vencanica) map[string]string {
+	bookLine := joinNonEmpty(", ",
+		prefixIfNotEmpty("књига ", strings.TrimSpace(vencanica.Book)),
+		prefixIfNotEmpty("страна ", formatInt(vencanica.Page)),
+		prefixIfNotEmpty("број ", formatInt(vencanica.CurrentNumber)),
+	)
+
+	values := map[string]string{
+		"C4":  vencanica.EparhijaName,
+		"C5":  joinNonEmpty(", ", vencanica.TampleName, vencanica.TampleCity),
+		"C6":  bookLine,
+		"C7":  formatNumericDate(vencanica.MarriageDate),
+		"C8":  joinNonEmpty(", ", vencanica.City, vencanica.Country),
+		"C10": joinNonEmpty(" ", vencanica.GroomFirstName, vencanica.GroomLastName),
+		"C11": joinNonEmpty(", ", vencanica.GroomOccupation, formatCyrillicIzCity(vencanica.GroomCity)),
+		"C12": strings.TrimSpace(vencanica.GroomReligion),
+		"C13": formatNumericDate(vencanica.GroomBirthDate),
+		"C14": strings.TrimSpace(vencanica.GroomMarriageOrder),
+		"C16": joinNonEmpty(" ", vencanica.BrideFirstName, vencanica.BrideLastName),
+		"C17": joinNonEmpty(", ", vencanica.BrideOccupation, formatCyrillicIzCity(vencanica.BrideCity)),
+		"C18": strings.TrimSpace(vencanica.BrideReligion),
+		"C19": formatNumericDate(vencanica.BrideBirthDate),
+		"C20": strings.TrimSpace(vencanica.BrideMarriageOrder),
+		"C22": joinNonEmpty(", ", joinNonEmpty(" ", vencanica.WitnessFirstName, vencanica.WitnessLastName), formatCyrillicIzCity(vencanica.WitnessCity)),
+		"C23": joinNonEmpty(", ", joinNonEmpty(" ", vencanica.SecondWitnessFirstName, vencanica.SecondWitnessLastName), formatCyrillicIzCity(vencanica.SecondWitnessCity)),
+		"C24": joinNonEmpty(", ", joinNonEmpty(" ", vencanica.PriestFirstName, vencanica.PriestLastName), vencanica.PriestTitle),
+		"C25": strings.TrimSpace(vencanica.CivilMarriage),
+		"C27": strings.TrimSpace(vencanica.Comment),
+		"C29": strings.TrimSpace(vencanica.NumberOfCertificate),
+		"C30": joinNonEmpty(", ", vencanica.TownOfCertificate, formatNumericDate(vencanica.Certificate)),
+	}
+
+	return values
+}
+
+func prefixIfNotEmpty(prefix, value string) string {
+	if strings.TrimSpace(value) == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s%s", prefix, value)
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"fmt"
+	"krstenica/internal/dto"
+	"krstenica/internal/errorx"
+	"krstenica/pkg"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// *************************************************************Vencanica*************************************
+func (h *httpHandler) createVencanice() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		req := &dto.VencanicaCreateReq{}
+
+		if err := ctx.Bind(req); err != nil {
+			fmt.Println("Error when parsing body", err)
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "error when parsing request data"})
+			return
+		}
+
+		cx := ctx.Request.Context()
+
+		vencanica, err := h.service.CreateVencanica(cx, req)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, vencanica)
+	}
+}
+
+func (h *httpHandler) getVencanice() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		cx := ctx.Request.Context()
+
+		vencanica, err := h.service.GetVencanicaByID(cx, int64(id))
+		if err != nil {
+			if err == errorx.ErrVencanicaNotFound {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, vencanica)
+	}
+}
+
+func (h *httpHandler) listVencanice() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		cx := ctx.Request.Context()
+
+		filters := pkg.ParseUrlQuery(ctx)
+
+		vencanice, totalCount, err := h.service.ListVencanice(cx, filters)
+		if err != nil {
+			if err == errorx.ErrVencanicaNotFound {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			if errorx.IsValidationError(err) {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, listResponse(vencanice, totalCount, filters))
+	}
+}
+
+func (h *httpHandler) updateVencanice() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		req := &dto.VencanicaUpdateReq{}
+
+		if err := ctx.Bind(req); err != nil {
+			fmt.Println("Error when parsing body", err)
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "error when parsing request data"})
+			return
+		}
+
+		cx := ctx.Request.Context()
+
+		vencanica, err := h.service.UpdateVencanica(cx, int64(id), req)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, vencanica)
+	}
+}
+
+func (h *httpHandler) deleteVencanice() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		err = h.service.DeleteVencanica(ctx.Request.Context(), int64(id))
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, nil)
+	}
+}
+
+//****************************************************end******Vencanica*************************************
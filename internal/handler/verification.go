@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"krstenica/internal/dto"
+	"krstenica/internal/errorx"
+	"krstenica/internal/model"
+)
+
+// verificationSignatureSize skraćuje HMAC da QR kod ostane mali i čitljiv.
+const verificationSignatureSize = 16
+
+var verificationStatusLabels = map[string]string{
+	string(model.VerificationStatusValid):      "Уверење је важеће",
+	string(model.VerificationStatusRevoked):    "Уверење је поништено",
+	string(model.VerificationStatusSuperseded): "Уверење је замењено новијим",
+}
+
+func (h *httpHandler) addVerificationRoutes() {
+	if h.verificationSecret() == "" {
+		log.Println("verification.secret is not set; certificates are printed without QR verification")
+	}
+	h.router.GET("/verify/:token", h.renderCertificateVerification())
+}
+
+// *************************************************************Provera uverenja*************************************
+func (h *httpHandler) renderCertificateVerification() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := h.parseVerificationToken(ctx.Param("token"))
+		if err != nil {
+			h.renderHTML(ctx, http.StatusNotFound, "verify/index.html", gin.H{
+				"Title": "Провера уверења",
+			})
+			return
+		}
+
+		verification, err := h.service.VerifyIssuedCertificate(ctx.Request.Context(), id)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, errorx.ErrIssuedCertificateNotFound) {
+				status = http.StatusNotFound
+			}
+			h.renderHTML(ctx, status, "verify/index.html", gin.H{
+				"Title": "Провера уверења",
+			})
+			return
+		}
+
+		h.renderHTML(ctx, http.StatusOK, "verify/index.html", gin.H{
+			"Title":        "Провера уверења",
+			"Verification": verification,
+			"StatusLabel":  verificationStatusLabels[verification.Status],
+		})
+	}
+}
+
+func (h *httpHandler) revokeIssuedCertificate() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		req := &dto.IssuedCertificateRevokeReq{}
+		if err := ctx.Bind(req); err != nil {
+			fmt.Println("Error when parsing body", err)
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "error when parsing request data"})
+			return
+		}
+		// dugme u GUI-ju šalje razlog kroz hx-prompt
+		if strings.TrimSpace(req.Reason) == "" {
+			req.Reason = ctx.GetHeader("HX-Prompt")
+		}
+
+		certificate, err := h.service.RevokeIssuedCertificate(ctx.Request.Context(), int64(id), req)
+		if err != nil {
+			if err == errorx.ErrIssuedCertificateNotFound {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, certificate)
+	}
+}
+
+// certificateVerificationURL vraća javnu adresu provere koja se upisuje u QR kod.
+// Bez podešene tajne vraća prazan string, pa se uverenje štampa bez QR koda.
+func (h *httpHandler) certificateVerificationURL(ctx *gin.Context, issuedID int64) string {
+	if h.verificationSecret() == "" {
+		return ""
+	}
+	base := strings.TrimRight(strings.TrimSpace(h.conf.Verification.BaseURL), "/")
+	if base == "" {
+		scheme := "http"
+		if ctx.Request.TLS != nil || strings.EqualFold(ctx.GetHeader("X-Forwarded-Proto"), "https") {
+			scheme = "https"
+		}
+		base = scheme + "://" + ctx.Request.Host
+	}
+	return base + "/verify/" + h.createVerificationToken(issuedID)
+}
+
+func (h *httpHandler) createVerificationToken(issuedID int64) string {
+	payload := strconv.FormatInt(issuedID, 10)
+	return payload + "." + h.signVerificationPayload(payload)
+}
+
+func (h *httpHandler) parseVerificationToken(token string) (int64, error) {
+	payload, signature, ok := strings.Cut(strings.TrimSpace(token), ".")
+	if !ok {
+		return 0, errors.New("invalid verification token")
+	}
+	if h.verificationSecret() == "" {
+		return 0, errors.New("verification secret is not configured")
+	}
+	if !hmac.Equal([]byte(h.signVerificationPayload(payload)), []byte(signature)) {
+		return 0, errors.New("invalid verification signature")
+	}
+	return strconv.ParseInt(payload, 10, 64)
+}
+
+// verificationSecret je ključ za potpis adresa provere; prazan string znači
+// da provera uverenja nije podešena. Namerno se ne deli sa ključem sesije.
+func (h *httpHandler) verificationSecret() string {
+	return strings.TrimSpace(h.conf.Verification.Secret)
+}
+
+func (h *httpHandler) signVerificationPayload(payload string) string {
+	mac := hmac.New(sha256.New, []byte(h.verificationSecret()))
+	mac.Write([]byte("issued-certificate|" + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:verificationSignatureSize])
+}
+
+//****************************************************end******Provera uverenja*************************************
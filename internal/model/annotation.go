@@ -0,0 +1,50 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type AnnotationKind string
+
+const (
+	AnnotationKindMarriage   AnnotationKind = "marriage"
+	AnnotationKindMonastic   AnnotationKind = "monastic"
+	AnnotationKindNameChange AnnotationKind = "name_change"
+	AnnotationKindDeath      AnnotationKind = "death"
+	AnnotationKindOther      AnnotationKind = "other"
+)
+
+// IsValid proverava da li je vrsta naknadne zabeleške podržana.
+func (k AnnotationKind) IsValid() bool {
+	switch k {
+	case AnnotationKindMarriage, AnnotationKindMonastic, AnnotationKindNameChange, AnnotationKindDeath, AnnotationKindOther:
+		return true
+	}
+	return false
+}
+
+type AnnotationStatus string
+
+const (
+	AnnotationStatusActive  AnnotationStatus = "active"
+	AnnotationStatusDeleted AnnotationStatus = "deleted"
+)
+
+// KrstenicaAnnotation je naknadna zabeleška na upisu krštenja (venčanje,
+// monašenje, promena imena, smrt).
+type KrstenicaAnnotation struct {
+	ID                int64            `gorm:"column:id"`
+	KrstenicaId       int64            `gorm:"column:krstenica_id"`
+	Kind              AnnotationKind   `gorm:"column:kind"`
+	NoteDate          time.Time        `gorm:"column:note_date"`
+	Text              string           `gorm:"column:text"`
+	CreatedById       sql.NullInt64    `gorm:"column:created_by_id"`
+	CreatedByUsername string           `gorm:"column:created_by_username"`
+	Status            AnnotationStatus `gorm:"column:status"`
+	CreatedAt         time.Time        `gorm:"column:created_at"`
+}
+
+func (KrstenicaAnnotation) TableName() string {
+	return "krstenica_annotations"
+}
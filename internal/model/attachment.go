@@ -0,0 +1,34 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type AttachmentStatus string
+
+const (
+	AttachmentStatusActive  AttachmentStatus = "active"
+	AttachmentStatusDeleted AttachmentStatus = "deleted"
+)
+
+// KrstenicaAttachment je skenirana strana originalne knjige sačuvana uz upis.
+// Sam fajl je u direktorijumu priloga, a StorageKey je putanja relativna na njega.
+type KrstenicaAttachment struct {
+	ID                 int64            `gorm:"column:id"`
+	KrstenicaId        int64            `gorm:"column:krstenica_id"`
+	FileName           string           `gorm:"column:file_name"`
+	ContentType        string           `gorm:"column:content_type"`
+	SizeBytes          int64            `gorm:"column:size_bytes"`
+	StorageKey         string           `gorm:"column:storage_key"`
+	ThumbnailKey       string           `gorm:"column:thumbnail_key"`
+	Checksum           string           `gorm:"column:checksum"`
+	UploadedById       sql.NullInt64    `gorm:"column:uploaded_by_id"`
+	UploadedByUsername string           `gorm:"column:uploaded_by_username"`
+	Status             AttachmentStatus `gorm:"column:status"`
+	CreatedAt          time.Time        `gorm:"column:created_at"`
+}
+
+func (KrstenicaAttachment) TableName() string {
+	return "krstenica_attachments"
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type AuditAction string
+
+const (
+	AuditActionCreate AuditAction = "create"
+	AuditActionUpdate AuditAction = "update"
+	AuditActionDelete AuditAction = "delete"
+	// AuditActionRestore i AuditActionPurge beleže vraćanje iz korpe i trajno brisanje.
+	AuditActionRestore AuditAction = "restore"
+	AuditActionPurge   AuditAction = "purge"
+)
+
+// Entiteti čije se izmene beleže u audit_log.
+const (
+	AuditEntityTample      = "tample"
+	AuditEntityPriest      = "priest"
+	AuditEntityEparhija    = "eparhija"
+	AuditEntityPerson      = "person"
+	AuditEntityKrstenica   = "krstenica"
+	AuditEntityVencanica   = "vencanica"
+	AuditEntityUmrlica     = "umrlica"
+	AuditEntityBook        = "book"
+	AuditEntityUser        = "user"
+	AuditEntityAnnotation  = "krstenica_annotation"
+	AuditEntityAttachment  = "krstenica_attachment"
+	AuditEntityLayout      = "certificate_layout"
+	AuditEntityTemplate    = "certificate_template"
+	AuditEntityCalibration = "print_calibration"
+)
+
+// AuditChange je stara i nova vrednost jednog polja.
+type AuditChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// AuditLog je jedna izmena entiteta; Changes je JSON objekat polje -> AuditChange.
+type AuditLog struct {
+	ID        int64         `gorm:"column:id"`
+	Entity    string        `gorm:"column:entity"`
+	EntityId  int64         `gorm:"column:entity_id"`
+	Action    AuditAction   `gorm:"column:action"`
+	UserId    sql.NullInt64 `gorm:"column:user_id"`
+	Username  string        `gorm:"column:username"`
+	Changes   string        `gorm:"column:changes"`
+	CreatedAt time.Time     `gorm:"column:created_at"`
+}
+
+func (AuditLog) TableName() string {
+	return "audit_log"
+}
@@ -0,0 +1,68 @@
+package model
+
+import "database/sql"
+
+type BookStatus string
+
+const (
+	BookStatusActive   BookStatus = "active"
+	BookStatusDeleted  BookStatus = "deleted"
+	BookStatusInactive BookStatus = "inactive"
+)
+
+type BookState string
+
+const (
+	BookStateOpen   BookState = "open"
+	BookStateClosed BookState = "closed"
+)
+
+type Book struct {
+	ID             int64         `gorm:"column:id"`
+	Name           string        `gorm:"column:name"`
+	TampleId       int64         `gorm:"column:tample_id"`
+	TampleName     string        `gorm:"column:tample_name"`
+	TampleCity     string        `gorm:"column:tample_city"`
+	YearFrom       int64         `gorm:"column:year_from"`
+	YearTo         sql.NullInt64 `gorm:"column:year_to"`
+	PageCapacity   int64         `gorm:"column:page_capacity"`
+	EntriesPerPage int64         `gorm:"column:entries_per_page"`
+	State          BookState     `gorm:"column:state"`
+	EntriesCount   int64         `gorm:"column:entries_count"`
+	LastPage       sql.NullInt64 `gorm:"column:last_page"`
+	LastNumber     sql.NullInt64 `gorm:"column:last_number"`
+	Status         BookStatus    `gorm:"column:status"`
+	CreatedAt      sql.NullTime  `gorm:"column:created_at"`
+}
+
+func (Book) TableName() string {
+	return "books"
+}
+
+type BookPost struct {
+	ID             int64         `gorm:"column:id"`
+	Name           string        `gorm:"column:name"`
+	TampleId       int64         `gorm:"column:tample_id"`
+	YearFrom       int64         `gorm:"column:year_from"`
+	YearTo         sql.NullInt64 `gorm:"column:year_to"`
+	PageCapacity   int64         `gorm:"column:page_capacity"`
+	EntriesPerPage int64         `gorm:"column:entries_per_page"`
+	State          BookState     `gorm:"column:state"`
+	Status         BookStatus    `gorm:"column:status"`
+	CreatedAt      sql.NullTime  `gorm:"column:created_at"`
+}
+
+func (BookPost) TableName() string {
+	return "books"
+}
+
+// CoversYear proverava da li godina upisa pripada opsegu knjige.
+func (b *Book) CoversYear(year int) bool {
+	if int64(year) < b.YearFrom {
+		return false
+	}
+	if b.YearTo.Valid && int64(year) > b.YearTo.Int64 {
+		return false
+	}
+	return true
+}
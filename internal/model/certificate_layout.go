@@ -0,0 +1,60 @@
+package model
+
+import "time"
+
+type CertificateLayoutStatus string
+
+const (
+	CertificateLayoutStatusActive  CertificateLayoutStatus = "active"
+	CertificateLayoutStatusDeleted CertificateLayoutStatus = "deleted"
+)
+
+// CertificateLayoutKindKrstenica je raspored polja na uverenju o krštenju.
+const CertificateLayoutKindKrstenica = "krstenica"
+
+// Cilj ćelije rasporeda: oba izlaza ili samo XLSX, odnosno samo PDF.
+const (
+	CertificateLayoutTargetAll  = "all"
+	CertificateLayoutTargetXLSX = "xlsx"
+	CertificateLayoutTargetPDF  = "pdf"
+)
+
+// CertificateLayout je sačuvano mapiranje polja upisa na ćelije obrasca
+// uverenja; podrazumevani raspored se koristi pri štampi.
+type CertificateLayout struct {
+	ID        int64                   `gorm:"column:id"`
+	Kind      string                  `gorm:"column:kind"`
+	Name      string                  `gorm:"column:name"`
+	IsDefault bool                    `gorm:"column:is_default"`
+	Status    CertificateLayoutStatus `gorm:"column:status"`
+	CreatedAt time.Time               `gorm:"column:created_at"`
+	UpdatedAt time.Time               `gorm:"column:updated_at"`
+	Cells     []CertificateLayoutCell `gorm:"-"`
+}
+
+func (CertificateLayout) TableName() string {
+	return "certificate_layouts"
+}
+
+// CertificateLayoutCell je jedno polje na obrascu: u koju ćeliju se upisuje
+// i kako se formatira. Pomeraji su u milimetrima i važe samo za PDF.
+type CertificateLayoutCell struct {
+	ID        int64   `gorm:"column:id"`
+	LayoutId  int64   `gorm:"column:layout_id"`
+	Position  int64   `gorm:"column:position"`
+	Cell      string  `gorm:"column:cell"`
+	Field     string  `gorm:"column:field"`
+	Target    string  `gorm:"column:target"`
+	DateStyle string  `gorm:"column:date_style"`
+	Prefix    string  `gorm:"column:prefix"`
+	Suffix    string  `gorm:"column:suffix"`
+	Bold      bool    `gorm:"column:bold"`
+	Wrap      bool    `gorm:"column:wrap"`
+	FitTo     string  `gorm:"column:fit_to"`
+	OffsetX   float64 `gorm:"column:offset_x"`
+	OffsetY   float64 `gorm:"column:offset_y"`
+}
+
+func (CertificateLayoutCell) TableName() string {
+	return "certificate_layout_cells"
+}
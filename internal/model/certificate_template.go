@@ -0,0 +1,73 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type CertificateTemplateStatus string
+
+const (
+	CertificateTemplateStatusActive  CertificateTemplateStatus = "active"
+	CertificateTemplateStatusDeleted CertificateTemplateStatus = "deleted"
+)
+
+// CertificateTemplateKindKrstenica je obrazac uverenja o krštenju.
+const CertificateTemplateKindKrstenica = "krstenica"
+
+// CertificateTemplate je otpremljeni obrazac uverenja (npr. obrazac jedne
+// eparhije); sami fajlovi su u njegovim verzijama.
+type CertificateTemplate struct {
+	ID        int64                     `gorm:"column:id"`
+	Kind      string                    `gorm:"column:kind"`
+	Name      string                    `gorm:"column:name"`
+	IsDefault bool                      `gorm:"column:is_default"`
+	Status    CertificateTemplateStatus `gorm:"column:status"`
+	CreatedAt time.Time                 `gorm:"column:created_at"`
+	UpdatedAt time.Time                 `gorm:"column:updated_at"`
+}
+
+func (CertificateTemplate) TableName() string {
+	return "certificate_templates"
+}
+
+// CertificateTemplateVersion je jedna nepromenljiva verzija obrasca: XLSX
+// obrazac, opcioni obrazac za pregled i opciona pozadinska slika. Ključevi su
+// putanje relativne na direktorijum obrazaca.
+type CertificateTemplateVersion struct {
+	ID                   int64         `gorm:"column:id"`
+	TemplateId           int64         `gorm:"column:template_id"`
+	Version              int64         `gorm:"column:version"`
+	FileName             string        `gorm:"column:file_name"`
+	StorageKey           string        `gorm:"column:storage_key"`
+	PreviewFileName      string        `gorm:"column:preview_file_name"`
+	PreviewStorageKey    string        `gorm:"column:preview_storage_key"`
+	BackgroundFileName   string        `gorm:"column:background_file_name"`
+	BackgroundStorageKey string        `gorm:"column:background_storage_key"`
+	BackgroundFullBleed  bool          `gorm:"column:background_full_bleed"`
+	Checksum             string        `gorm:"column:checksum"`
+	Note                 string        `gorm:"column:note"`
+	UploadedById         sql.NullInt64 `gorm:"column:uploaded_by_id"`
+	UploadedByUsername   string        `gorm:"column:uploaded_by_username"`
+	CreatedAt            time.Time     `gorm:"column:created_at"`
+}
+
+func (CertificateTemplateVersion) TableName() string {
+	return "certificate_template_versions"
+}
+
+// CertificateTemplateAssignment čini obrazac podrazumevanim za jednu eparhiju
+// ili jedan hram.
+type CertificateTemplateAssignment struct {
+	ID           int64         `gorm:"column:id"`
+	TemplateId   int64         `gorm:"column:template_id"`
+	EparhijaId   sql.NullInt64 `gorm:"column:eparhija_id"`
+	EparhijaName string        `gorm:"column:eparhija_name;->"`
+	TampleId     sql.NullInt64 `gorm:"column:tample_id"`
+	TampleName   string        `gorm:"column:tample_name;->"`
+	CreatedAt    time.Time     `gorm:"column:created_at"`
+}
+
+func (CertificateTemplateAssignment) TableName() string {
+	return "certificate_template_assignments"
+}
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// IssuedCertificate je zapis o jednom izdatom uverenju (odštampanoj krštenici)
+// sa rednim brojem koji se svake godine broji od 1.
+type IssuedCertificate struct {
+	ID              int64         `gorm:"column:id"`
+	KrstenicaId     int64         `gorm:"column:krstenica_id"`
+	TampleId        sql.NullInt64 `gorm:"column:tample_id"`
+	TampleName      string        `gorm:"column:tample_name"`
+	FirstName       string        `gorm:"column:first_name"`
+	LastName        string        `gorm:"column:last_name"`
+	City            string        `gorm:"column:city"`
+	Baptism         sql.NullTime  `gorm:"column:baptism"`
+	KrstenicaStatus string        `gorm:"column:krstenica_status"`
+	Year            int64         `gorm:"column:year"`
+	SerialNumber    int64         `gorm:"column:serial_number"`
+	Format          string        `gorm:"column:format"`
+	TemplateVersion string        `gorm:"column:template_version"`
+	// TemplateVersionId je verzija otpremljenog obrasca; prazno za ugrađeni obrazac.
+	TemplateVersionId sql.NullInt64 `gorm:"column:template_version_id"`
+	TemplateName      string        `gorm:"column:template_name"`
+	TemplateRevision  sql.NullInt64 `gorm:"column:template_revision"`
+	Purpose           string        `gorm:"column:purpose"`
+	IssuedById        sql.NullInt64 `gorm:"column:issued_by_id"`
+	IssuedByUsername  string        `gorm:"column:issued_by_username"`
+	CreatedAt         time.Time     `gorm:"column:created_at"`
+	RevokedAt         sql.NullTime  `gorm:"column:revoked_at"`
+	RevokedBy         string        `gorm:"column:revoked_by_username"`
+	RevokeReason      string        `gorm:"column:revoke_reason"`
+}
+
+func (IssuedCertificate) TableName() string {
+	return "issued_certificates"
+}
+
+type IssuedCertificatePost struct {
+	ID                int64         `gorm:"column:id"`
+	KrstenicaId       int64         `gorm:"column:krstenica_id"`
+	TampleId          sql.NullInt64 `gorm:"column:tample_id"`
+	Year              int64         `gorm:"column:year"`
+	SerialNumber      int64         `gorm:"column:serial_number"`
+	Format            string        `gorm:"column:format"`
+	TemplateVersion   string        `gorm:"column:template_version"`
+	TemplateVersionId sql.NullInt64 `gorm:"column:template_version_id"`
+	Purpose           string        `gorm:"column:purpose"`
+	IssuedById        sql.NullInt64 `gorm:"column:issued_by_id"`
+	IssuedByUsername  string        `gorm:"column:issued_by_username"`
+	CreatedAt         time.Time     `gorm:"column:created_at"`
+}
+
+func (IssuedCertificatePost) TableName() string {
+	return "issued_certificates"
+}
+
+type VerificationStatus string
+
+const (
+	VerificationStatusValid      VerificationStatus = "valid"
+	VerificationStatusRevoked    VerificationStatus = "revoked"
+	VerificationStatusSuperseded VerificationStatus = "superseded"
+)
@@ -13,40 +13,48 @@ const (
 )
 
 type Krstenica struct {
-	ID                  int64         `gorm:"column:id"`
-	Book                string        `gorm:"column:book"`
-	Page                int64         `gorm:"column:page"`
-	CurrentNumber       int64         `gorm:"column:current_number"`
-	EparhijaId          sql.NullInt64 `gorm:"column:eparhija_id"`
-	EparhijaName        string        `gorm:"column:eparhija_name"`
-	TampleId            sql.NullInt64 `gorm:"column:tample_id"`
-	TampleName          string        `gorm:"column:tample_name"`
-	TampleCity          string        `gorm:"column:tample_city"`
-	ParentId            sql.NullInt64 `gorm:"column:parent_id"`
-	ParentFirstName     string        `gorm:"column:parent_first_name"`
-	ParentLastName      string        `gorm:"column:parent_last_name"`
-	ParentOccupation    string        `gorm:"column:parent_occupation"`
-	ParentCity          string        `gorm:"column:parent_city"`
-	ParentReligion      string        `gorm:"column:parent_religion"`
-	GodfatherId         sql.NullInt64 `gorm:"column:godfather_id"`
-	GodfatherFirstName  string        `gorm:"column:godfather_first_name"`
-	GodfatherLastName   string        `gorm:"column:godfather_last_name"`
-	GodfatherOccupation string        `gorm:"column:godfather_occupation"`
-	GodfatherCity       string        `gorm:"column:godfather_city"`
-	GodfatherReligion   string        `gorm:"column:godfather_religion"`
-	ParohId             sql.NullInt64 `gorm:"column:paroh_id"`
-	ParohFirstName      string        `gorm:"column:paroh_first_name"`
-	ParohLastName       string        `gorm:"column:paroh_last_name"`
-	PriestId            sql.NullInt64 `gorm:"column:priest_id"`
-	PriestFirstName     string        `gorm:"column:priest_first_name"`
-	PriestLastName      string        `gorm:"column:priest_last_name"`
-	PriestTitle         string        `gorm:"column:priest_title"`
-	FirstName           string        `gorm:"column:first_name"`
-	LastName            string        `gorm:"column:last_name"`
-	Gender              string        `gorm:"column:gender"`
-	City                string        `gorm:"column:city"`
-	Country             string        `gorm:"column:country"`
-	BirthDate           sql.NullTime  `gorm:"column:birth_date"`
+	ID                  int64                `gorm:"column:id"`
+	BookId              sql.NullInt64        `gorm:"column:book_id"`
+	Book                string               `gorm:"column:book"`
+	Page                int64                `gorm:"column:page"`
+	CurrentNumber       int64                `gorm:"column:current_number"`
+	EparhijaId          sql.NullInt64        `gorm:"column:eparhija_id"`
+	EparhijaName        string               `gorm:"column:eparhija_name"`
+	TampleId            sql.NullInt64        `gorm:"column:tample_id"`
+	TampleName          string               `gorm:"column:tample_name"`
+	TampleCity          string               `gorm:"column:tample_city"`
+	FatherId            sql.NullInt64        `gorm:"column:father_id"`
+	FatherFirstName     string               `gorm:"column:father_first_name"`
+	FatherLastName      string               `gorm:"column:father_last_name"`
+	FatherOccupation    string               `gorm:"column:father_occupation"`
+	FatherCity          string               `gorm:"column:father_city"`
+	FatherReligion      string               `gorm:"column:father_religion"`
+	MotherId            sql.NullInt64        `gorm:"column:mother_id"`
+	MotherFirstName     string               `gorm:"column:mother_first_name"`
+	MotherLastName      string               `gorm:"column:mother_last_name"`
+	MotherOccupation    string               `gorm:"column:mother_occupation"`
+	MotherCity          string               `gorm:"column:mother_city"`
+	MotherReligion      string               `gorm:"column:mother_religion"`
+	GodfatherId         sql.NullInt64        `gorm:"column:godfather_id"`
+	GodfatherFirstName  string               `gorm:"column:godfather_first_name"`
+	GodfatherLastName   string               `gorm:"column:godfather_last_name"`
+	GodfatherOccupation string               `gorm:"column:godfather_occupation"`
+	GodfatherCity       string               `gorm:"column:godfather_city"`
+	GodfatherReligion   string               `gorm:"column:godfather_religion"`
+	Godparents          []KrstenicaGodparent `gorm:"-"`
+	ParohId             sql.NullInt64        `gorm:"column:paroh_id"`
+	ParohFirstName      string               `gorm:"column:paroh_first_name"`
+	ParohLastName       string               `gorm:"column:paroh_last_name"`
+	PriestId            sql.NullInt64        `gorm:"column:priest_id"`
+	PriestFirstName     string               `gorm:"column:priest_first_name"`
+	PriestLastName      string               `gorm:"column:priest_last_name"`
+	PriestTitle         string               `gorm:"column:priest_title"`
+	FirstName           string               `gorm:"column:first_name"`
+	LastName            string               `gorm:"column:last_name"`
+	Gender              string               `gorm:"column:gender"`
+	City                string               `gorm:"column:city"`
+	Country             string               `gorm:"column:country"`
+	BirthDate           sql.NullTime         `gorm:"column:birth_date"`
 	// BirthDate              JSONDate     `gorm:"column:birth_date" json:"birth_date"`
 	BirthOrder             string       `gorm:"column:birth_order"`
 	PlaceOfBirthday        string       `gorm:"column:place_of_birthday"`
@@ -62,6 +70,8 @@ type Krstenica struct {
 	Comment                string       `gorm:"column:comment"`
 	Status                 string       `gorm:"column:status"`
 	CreatedAt              sql.NullTime `gorm:"column:created_at"`
+	// Score je slicnost imena kod fuzzy pretrage.
+	Score sql.NullFloat64 `gorm:"column:score"`
 }
 
 func (Krstenica) TableName() string {
@@ -70,6 +80,7 @@ func (Krstenica) TableName() string {
 
 type KrstenicaPost struct {
 	ID            int64  `gorm:"column:id"`
+	BookId        *int64 `gorm:"column:book_id"`
 	Book          string `gorm:"column:book"`
 	Page          int64  `gorm:"column:page"`
 	CurrentNumber int64  `gorm:"column:current_number"`
@@ -78,13 +89,11 @@ type KrstenicaPost struct {
 	TampleId int64 `gorm:"column:tample_id"`
 	//TampleName             string       `gorm:"column:tample_name"`
 	//TampleCity             string       `gorm:"column:tample_city"`
-	ParentId int64 `gorm:"column:parent_id"`
-	//ParentFirstName        string       `gorm:"column:parent_first_name"`
-	//ParentLastName         string       `gorm:"column:parent_last_name"`
-	//ParentOccupation       string       `gorm:"column:parent_occupation"`
-	//ParentCity             string       `gorm:"column:parent_city"`
-	//ParentReligion         string       `gorm:"column:parent_religion"`
-	GodfatherId int64 `gorm:"column:godfather_id"`
+	FatherId    *int64 `gorm:"column:father_id"`
+	MotherId    *int64 `gorm:"column:mother_id"`
+	GodfatherId int64  `gorm:"column:godfather_id"`
+	// GodparentIds su svi kumovi redom; prvi se čuva i u godfather_id
+	GodparentIds []int64 `gorm:"-"`
 	//GodfatherFirstName     string       `gorm:"column:godfather_first_name"`
 	//GodfatherLastName      string       `gorm:"column:godfather_last_name"`
 	//GodfatherOccupation    string       `gorm:"column:godfather_occupation"`
@@ -122,3 +131,20 @@ type KrstenicaPost struct {
 func (KrstenicaPost) TableName() string {
 	return "krstenice"
 }
+
+// KrstenicaGodparent je jedan kum krštenice; Position određuje redosled
+// navođenja (1 je kum iz kolone godfather_id).
+type KrstenicaGodparent struct {
+	KrstenicaId int64  `gorm:"column:krstenica_id"`
+	PersonId    int64  `gorm:"column:person_id"`
+	Position    int64  `gorm:"column:position"`
+	FirstName   string `gorm:"column:first_name"`
+	LastName    string `gorm:"column:last_name"`
+	Occupation  string `gorm:"column:occupation"`
+	City        string `gorm:"column:city"`
+	Religion    string `gorm:"column:religion"`
+}
+
+func (KrstenicaGodparent) TableName() string {
+	return "krstenica_godparents"
+}
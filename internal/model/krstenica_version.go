@@ -0,0 +1,23 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// KrstenicaVersion je snimak reda krštenice (sa kumovima) posle jedne izmene.
+// Snapshot je model.Krstenica serijalizovan u JSON.
+type KrstenicaVersion struct {
+	ID                  int64         `gorm:"column:id"`
+	KrstenicaId         int64         `gorm:"column:krstenica_id"`
+	Version             int64         `gorm:"column:version"`
+	Snapshot            string        `gorm:"column:snapshot"`
+	RestoredFromVersion sql.NullInt64 `gorm:"column:restored_from_version"`
+	CreatedById         sql.NullInt64 `gorm:"column:created_by_id"`
+	CreatedByUsername   string        `gorm:"column:created_by_username"`
+	CreatedAt           time.Time     `gorm:"column:created_at"`
+}
+
+func (KrstenicaVersion) TableName() string {
+	return "krstenica_versions"
+}
@@ -0,0 +1,26 @@
+package model
+
+import "database/sql"
+
+type NumberingIssueKind string
+
+const (
+	NumberingIssueGap       NumberingIssueKind = "gap"
+	NumberingIssueDuplicate NumberingIssueKind = "duplicate"
+	NumberingIssuePageOrder NumberingIssueKind = "page_order"
+	NumberingIssueDateOrder NumberingIssueKind = "date_order"
+)
+
+// KrstenicaNumberingEntry je upis krštenice sa kolonama koje koristi provera
+// numeracije. Year je godina krštenja, a ako nije uneta godina upisa.
+type KrstenicaNumberingEntry struct {
+	ID            int64         `gorm:"column:id"`
+	TampleId      sql.NullInt64 `gorm:"column:tample_id"`
+	TampleName    string        `gorm:"column:tample_name"`
+	BookId        sql.NullInt64 `gorm:"column:book_id"`
+	Book          string        `gorm:"column:book"`
+	Page          int64         `gorm:"column:page"`
+	CurrentNumber int64         `gorm:"column:current_number"`
+	Baptism       sql.NullTime  `gorm:"column:baptism"`
+	Year          int           `gorm:"column:year"`
+}
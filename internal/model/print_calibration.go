@@ -0,0 +1,48 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type PrintCalibrationStatus string
+
+const (
+	PrintCalibrationStatusActive  PrintCalibrationStatus = "active"
+	PrintCalibrationStatusDeleted PrintCalibrationStatus = "deleted"
+)
+
+// PrintCalibration je profil kalibracije jednog štampača: pomeraj i razmera
+// celog teksta i dodatni pomeraji pojedinih ćelija, u milimetrima. Profil
+// pripada korisniku (UserId) ili radnoj stanici (Workstation), ili nikome.
+type PrintCalibration struct {
+	ID          int64                  `gorm:"column:id"`
+	Name        string                 `gorm:"column:name"`
+	OffsetX     float64                `gorm:"column:offset_x"`
+	OffsetY     float64                `gorm:"column:offset_y"`
+	Scale       float64                `gorm:"column:scale"`
+	UserId      sql.NullInt64          `gorm:"column:user_id"`
+	Username    string                 `gorm:"column:username;->"`
+	Workstation string                 `gorm:"column:workstation"`
+	Status      PrintCalibrationStatus `gorm:"column:status"`
+	CreatedAt   time.Time              `gorm:"column:created_at"`
+	UpdatedAt   time.Time              `gorm:"column:updated_at"`
+	Cells       []PrintCalibrationCell `gorm:"-"`
+}
+
+func (PrintCalibration) TableName() string {
+	return "print_calibrations"
+}
+
+// PrintCalibrationCell je dodatni pomeraj jedne ćelije obrasca.
+type PrintCalibrationCell struct {
+	ID            int64   `gorm:"column:id"`
+	CalibrationId int64   `gorm:"column:calibration_id"`
+	Cell          string  `gorm:"column:cell"`
+	OffsetX       float64 `gorm:"column:offset_x"`
+	OffsetY       float64 `gorm:"column:offset_y"`
+}
+
+func (PrintCalibrationCell) TableName() string {
+	return "print_calibration_cells"
+}
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"database/sql"
+)
+
+// TrashItem je obrisan zapis (status 'deleted') prikazan u korpi. Entity je
+// jedna od AuditEntity vrednosti, a Details dodatni opis (za krštenicu
+// knjiga/strana/broj).
+type TrashItem struct {
+	Entity            string        `gorm:"column:entity"`
+	ID                int64         `gorm:"column:id"`
+	Name              string        `gorm:"column:name"`
+	Details           string        `gorm:"column:details"`
+	City              string        `gorm:"column:city"`
+	DeletedAt         sql.NullTime  `gorm:"column:deleted_at"`
+	DeletedById       sql.NullInt64 `gorm:"column:deleted_by_id"`
+	DeletedByUsername string        `gorm:"column:deleted_by_username"`
+}
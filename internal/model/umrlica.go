@@ -0,0 +1,94 @@
+package model
+
+import (
+	"database/sql"
+)
+
+type UmrlicaStatus string
+
+const (
+	UmrlicaStatusActive   UmrlicaStatus = "active"
+	UmrlicaStatusDeleted  UmrlicaStatus = "deleted"
+	UmrlicaStatusInactive UmrlicaStatus = "inactive"
+)
+
+type Umrlica struct {
+	ID                     int64         `gorm:"column:id"`
+	Book                   string        `gorm:"column:book"`
+	Page                   int64         `gorm:"column:page"`
+	CurrentNumber          int64         `gorm:"column:current_number"`
+	EparhijaId             sql.NullInt64 `gorm:"column:eparhija_id"`
+	EparhijaName           string        `gorm:"column:eparhija_name"`
+	TampleId               sql.NullInt64 `gorm:"column:tample_id"`
+	TampleName             string        `gorm:"column:tample_name"`
+	TampleCity             string        `gorm:"column:tample_city"`
+	DeceasedId             sql.NullInt64 `gorm:"column:deceased_id"`
+	DeceasedFirstName      string        `gorm:"column:deceased_first_name"`
+	DeceasedLastName       string        `gorm:"column:deceased_last_name"`
+	DeceasedOccupation     string        `gorm:"column:deceased_occupation"`
+	DeceasedCity           string        `gorm:"column:deceased_city"`
+	DeceasedAddress        string        `gorm:"column:deceased_address"`
+	DeceasedReligion       string        `gorm:"column:deceased_religion"`
+	DeceasedBirthDate      sql.NullTime  `gorm:"column:deceased_birth_date"`
+	KrstenicaId            sql.NullInt64 `gorm:"column:krstenica_id"`
+	KrstenicaBook          string        `gorm:"column:krstenica_book"`
+	KrstenicaPage          sql.NullInt64 `gorm:"column:krstenica_page"`
+	KrstenicaCurrentNumber sql.NullInt64 `gorm:"column:krstenica_current_number"`
+	KrstenicaFirstName     string        `gorm:"column:krstenica_first_name"`
+	KrstenicaLastName      string        `gorm:"column:krstenica_last_name"`
+	KrstenicaBirthDate     sql.NullTime  `gorm:"column:krstenica_birth_date"`
+	KrstenicaBaptism       sql.NullTime  `gorm:"column:krstenica_baptism"`
+	KrstenicaTampleName    string        `gorm:"column:krstenica_tample_name"`
+	PriestId               sql.NullInt64 `gorm:"column:priest_id"`
+	PriestFirstName        string        `gorm:"column:priest_first_name"`
+	PriestLastName         string        `gorm:"column:priest_last_name"`
+	PriestTitle            string        `gorm:"column:priest_title"`
+	DeathDate              sql.NullTime  `gorm:"column:death_date"`
+	BurialDate             sql.NullTime  `gorm:"column:burial_date"`
+	City                   string        `gorm:"column:city"`
+	Country                string        `gorm:"column:country"`
+	Cemetery               string        `gorm:"column:cemetery"`
+	CauseOfDeath           string        `gorm:"column:cause_of_death"`
+	MaritalStatus          string        `gorm:"column:marital_status"`
+	Sacraments             string        `gorm:"column:sacraments"`
+	NumberOfCertificate    string        `gorm:"column:number_of_certificate"`
+	TownOfCertificate      string        `gorm:"column:town_of_certificate"`
+	Certificate            sql.NullTime  `gorm:"column:certificate"`
+	Comment                string        `gorm:"column:comment"`
+	Status                 string        `gorm:"column:status"`
+	CreatedAt              sql.NullTime  `gorm:"column:created_at"`
+}
+
+func (Umrlica) TableName() string {
+	return "umrlice"
+}
+
+type UmrlicaPost struct {
+	ID                  int64        `gorm:"column:id"`
+	Book                string       `gorm:"column:book"`
+	Page                int64        `gorm:"column:page"`
+	CurrentNumber       int64        `gorm:"column:current_number"`
+	EparhijaId          int64        `gorm:"column:eparhija_id"`
+	TampleId            int64        `gorm:"column:tample_id"`
+	DeceasedId          int64        `gorm:"column:deceased_id"`
+	KrstenicaId         *int64       `gorm:"column:krstenica_id"`
+	PriestId            int64        `gorm:"column:priest_id"`
+	DeathDate           sql.NullTime `gorm:"column:death_date"`
+	BurialDate          sql.NullTime `gorm:"column:burial_date"`
+	City                string       `gorm:"column:city"`
+	Country             string       `gorm:"column:country"`
+	Cemetery            string       `gorm:"column:cemetery"`
+	CauseOfDeath        string       `gorm:"column:cause_of_death"`
+	MaritalStatus       string       `gorm:"column:marital_status"`
+	Sacraments          string       `gorm:"column:sacraments"`
+	NumberOfCertificate string       `gorm:"column:number_of_certificate"`
+	TownOfCertificate   string       `gorm:"column:town_of_certificate"`
+	Certificate         sql.NullTime `gorm:"column:certificate"`
+	Comment             string       `gorm:"column:comment"`
+	Status              string       `gorm:"column:status"`
+	CreatedAt           sql.NullTime `gorm:"column:created_at"`
+}
+
+func (UmrlicaPost) TableName() string {
+	return "umrlice"
+}
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"database/sql"
+)
+
+type VencanicaStatus string
+
+const (
+	VencanicaStatusActive   VencanicaStatus = "active"
+	VencanicaStatusDeleted  VencanicaStatus = "deleted"
+	VencanicaStatusInactive VencanicaStatus = "inactive"
+)
+
+type Vencanica struct {
+	ID                     int64         `gorm:"column:id"`
+	Book                   string        `gorm:"column:book"`
+	Page                   int64         `gorm:"column:page"`
+	CurrentNumber          int64         `gorm:"column:current_number"`
+	EparhijaId             sql.NullInt64 `gorm:"column:eparhija_id"`
+	EparhijaName           string        `gorm:"column:eparhija_name"`
+	TampleId               sql.NullInt64 `gorm:"column:tample_id"`
+	TampleName             string        `gorm:"column:tample_name"`
+	TampleCity             string        `gorm:"column:tample_city"`
+	GroomId                sql.NullInt64 `gorm:"column:groom_id"`
+	GroomFirstName         string        `gorm:"column:groom_first_name"`
+	GroomLastName          string        `gorm:"column:groom_last_name"`
+	GroomOccupation        string        `gorm:"column:groom_occupation"`
+	GroomCity              string        `gorm:"column:groom_city"`
+	GroomReligion          string        `gorm:"column:groom_religion"`
+	GroomBirthDate         sql.NullTime  `gorm:"column:groom_birth_date"`
+	BrideId                sql.NullInt64 `gorm:"column:bride_id"`
+	BrideFirstName         string        `gorm:"column:bride_first_name"`
+	BrideLastName          string        `gorm:"column:bride_last_name"`
+	BrideOccupation        string        `gorm:"column:bride_occupation"`
+	BrideCity              string        `gorm:"column:bride_city"`
+	BrideReligion          string        `gorm:"column:bride_religion"`
+	BrideBirthDate         sql.NullTime  `gorm:"column:bride_birth_date"`
+	WitnessId              sql.NullInt64 `gorm:"column:witness_id"`
+	WitnessFirstName       string        `gorm:"column:witness_first_name"`
+	WitnessLastName        string        `gorm:"column:witness_last_name"`
+	WitnessCity            string        `gorm:"column:witness_city"`
+	SecondWitnessId        sql.NullInt64 `gorm:"column:second_witness_id"`
+	SecondWitnessFirstName string        `gorm:"column:second_witness_first_name"`
+	SecondWitnessLastName  string        `gorm:"column:second_witness_last_name"`
+	SecondWitnessCity      string        `gorm:"column:second_witness_city"`
+	PriestId               sql.NullInt64 `gorm:"column:priest_id"`
+	PriestFirstName        string        `gorm:"column:priest_first_name"`
+	PriestLastName         string        `gorm:"column:priest_last_name"`
+	PriestTitle            string        `gorm:"column:priest_title"`
+	MarriageDate           sql.NullTime  `gorm:"column:marriage_date"`
+	City                   string        `gorm:"column:city"`
+	Country                string        `gorm:"column:country"`
+	GroomMarriageOrder     string        `gorm:"column:groom_marriage_order"`
+	BrideMarriageOrder     string        `gorm:"column:bride_marriage_order"`
+	CivilMarriage          string        `gorm:"column:civil_marriage"`
+	NumberOfCertificate    string        `gorm:"column:number_of_certificate"`
+	TownOfCertificate      string        `gorm:"column:town_of_certificate"`
+	Certificate            sql.NullTime  `gorm:"column:certificate"`
+	Comment                string        `gorm:"column:comment"`
+	Status                 string        `gorm:"column:status"`
+	CreatedAt              sql.NullTime  `gorm:"column:created_at"`
+}
+
+func (Vencanica) TableName() string {
+	return "vencanice"
+}
+
+type VencanicaPost struct {
+	ID                  int64        `gorm:"column:id"`
+	Book                string       `gorm:"column:book"`
+	Page                int64        `gorm:"column:page"`
+	CurrentNumber       int64        `gorm:"column:current_number"`
+	EparhijaId          int64        `gorm:"column:eparhija_id"`
+	TampleId            int64        `gorm:"column:tample_id"`
+	GroomId             int64        `gorm:"column:groom_id"`
+	BrideId             int64        `gorm:"column:bride_id"`
+	WitnessId           int64        `gorm:"column:witness_id"`
+	SecondWitnessId     *int64       `gorm:"column:second_witness_id"`
+	PriestId            int64        `gorm:"column:priest_id"`
+	MarriageDate        sql.NullTime `gorm:"column:marriage_date"`
+	City                string       `gorm:"column:city"`
+	Country             string       `gorm:"column:country"`
+	GroomMarriageOrder  string       `gorm:"column:groom_marriage_order"`
+	BrideMarriageOrder  string       `gorm:"column:bride_marriage_order"`
+	CivilMarriage       string       `gorm:"column:civil_marriage"`
+	NumberOfCertificate string       `gorm:"column:number_of_certificate"`
+	TownOfCertificate   string       `gorm:"column:town_of_certificate"`
+	Certificate         sql.NullTime `gorm:"column:certificate"`
+	Comment             string       `gorm:"column:comment"`
+	Status              string       `gorm:"column:status"`
+	CreatedAt           sql.NullTime `gorm:"column:created_at"`
+}
+
+func (VencanicaPost) TableName() string {
+	return "vencanice"
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"krstenica/internal/errorx"
+	"krstenica/internal/model"
+
+	"gorm.io/gorm"
+)
+
+func (r *repo) GetKrstenicaAnnotationByID(ctx context.Context, id int64) (*model.KrstenicaAnnotation, error) {
+	var annotation model.KrstenicaAnnotation
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND status != ?", id, model.AnnotationStatusDeleted).
+		First(&annotation).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errorx.ErrAnnotationNotFound
+		}
+		return nil, err
+	}
+
+	return &annotation, nil
+}
+
+// ListKrstenicaAnnotations vraća zabeleške krštenice hronološki.
+func (r *repo) ListKrstenicaAnnotations(ctx context.Context, krstenicaID int64) ([]model.KrstenicaAnnotation, error) {
+	var annotations []model.KrstenicaAnnotation
+	err := r.db.WithContext(ctx).
+		Where("krstenica_id = ? AND status != ?", krstenicaID, model.AnnotationStatusDeleted).
+		Order("note_date ASC, id ASC").
+		Find(&annotations).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return annotations, nil
+}
+
+func (r *repo) CreateKrstenicaAnnotation(ctx context.Context, annotation *model.KrstenicaAnnotation) (*model.KrstenicaAnnotation, error) {
+	if err := r.db.WithContext(ctx).Create(annotation).Error; err != nil {
+		return nil, err
+	}
+
+	return r.GetKrstenicaAnnotationByID(ctx, annotation.ID)
+}
+
+func (r *repo) UpdateKrstenicaAnnotation(ctx context.Context, id int64, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).
+		Table("krstenica_annotations").
+		Where("id = ?", id).
+		Updates(updates).Error
+}
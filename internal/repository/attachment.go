@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"krstenica/internal/errorx"
+	"krstenica/internal/model"
+
+	"gorm.io/gorm"
+)
+
+func (r *repo) GetKrstenicaAttachmentByID(ctx context.Context, id int64) (*model.KrstenicaAttachment, error) {
+	var attachment model.KrstenicaAttachment
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND status != ?", id, model.AttachmentStatusDeleted).
+		First(&attachment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errorx.ErrAttachmentNotFound
+		}
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+// ListKrstenicaAttachments vraća priloge krštenice redom kojim su dodati.
+func (r *repo) ListKrstenicaAttachments(ctx context.Context, krstenicaID int64) ([]model.KrstenicaAttachment, error) {
+	var attachments []model.KrstenicaAttachment
+	err := r.db.WithContext(ctx).
+		Where("krstenica_id = ? AND status != ?", krstenicaID, model.AttachmentStatusDeleted).
+		Order("created_at ASC, id ASC").
+		Find(&attachments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+func (r *repo) CreateKrstenicaAttachment(ctx context.Context, attachment *model.KrstenicaAttachment) (*model.KrstenicaAttachment, error) {
+	if err := r.db.WithContext(ctx).Create(attachment).Error; err != nil {
+		return nil, err
+	}
+
+	return r.GetKrstenicaAttachmentByID(ctx, attachment.ID)
+}
+
+func (r *repo) UpdateKrstenicaAttachment(ctx context.Context, id int64, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).
+		Table("krstenica_attachments").
+		Where("id = ?", id).
+		Updates(updates).Error
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"krstenica/internal/model"
+	"krstenica/pkg"
+	"strconv"
+	"strings"
+)
+
+func (r *repo) CreateAuditLog(ctx context.Context, entry *model.AuditLog) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+func (r *repo) ListAuditLogs(ctx context.Context, filterAndSort *pkg.FilterAndSort) ([]model.AuditLog, int64, error) {
+	var entries []model.AuditLog
+
+	where, whereParams, err := pkg.FilterToSQL(filterAndSort.Filters, validateAuditLogFilterAttr)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	orderBy, err := pkg.SortSQL(filterAndSort.Sort, transformAuditLogSortAttribute)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if orderBy != "" {
+		if !strings.Contains(orderBy, "id") {
+			orderBy += ", id DESC"
+		}
+	} else {
+		orderBy = "id DESC"
+	}
+
+	query := r.db.WithContext(ctx).Table("audit_log").
+		Where(where, whereParams...).
+		Order(orderBy)
+
+	query, err = applyPagination(query, filterAndSort, "id")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = query.Find(&entries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if filterAndSort.IsCursorPaging() {
+		entries = entries[:keysetPageSize(filterAndSort, len(entries), func(i int) []string {
+			return []string{strconv.FormatInt(entries[i].ID, 10)}
+		})]
+		return entries, 0, nil
+	}
+
+	var totalCount int64
+	err = r.db.WithContext(ctx).Table("audit_log").
+		Where(where, whereParams...).
+		Count(&totalCount).
+		Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return entries, totalCount, nil
+}
+
+var allowedAtributesInAuditLogFilters = []string{
+	"id", "entity", "entity_id", "action", "user_id", "username", "created_at",
+}
+
+var allowedAtributesInAuditLogSort = allowedAtributesInAuditLogFilters
+
+func transformAuditLogSortAttribute(p string) (string, error) {
+	if !pkg.InList(p, allowedAtributesInAuditLogSort) {
+		return "", fmt.Errorf("UNSUPPORTED_SORT_PROPERTY")
+	}
+
+	return p, nil
+}
+
+func validateAuditLogFilterAttr(p string, v []string) (string, error) {
+	if !pkg.InList(p, allowedAtributesInAuditLogFilters) {
+		return "", fmt.Errorf("UNSUPPORTED_FILTER_PROPERTY")
+	}
+
+	return p, nil
+}
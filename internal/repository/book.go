@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"krstenica/internal/errorx"
+	"krstenica/internal/model"
+	"krstenica/pkg"
+	"log"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const bookSelect = `t.*, tm.name as tample_name,
+		tm.city as tample_city,
+		(SELECT COUNT(*) FROM krstenice k WHERE k.book_id = t.id AND k.status != 'deleted') as entries_count,
+		(SELECT MAX(k.page) FROM krstenice k WHERE k.book_id = t.id) as last_page,
+		(SELECT MAX(k.current_number) FROM krstenice k WHERE k.book_id = t.id) as last_number`
+
+func withBookJoins(db *gorm.DB) *gorm.DB {
+	return db.Joins("LEFT JOIN tamples as tm on tm.id = t.tample_id")
+}
+
+func (r *repo) GetBookByID(ctx context.Context, id int64) (*model.Book, error) {
+	var book model.Book
+	if id <= 0 {
+		return nil, errors.New("invalid ID provided")
+	}
+
+	err := withBookJoins(r.db.WithContext(ctx).Table("books AS t")).
+		Where("t.id = ?", id).
+		Select(bookSelect).
+		First(&book).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errorx.ErrBookNotFound
+		}
+		return nil, err
+	}
+
+	return &book, nil
+}
+
+func (r *repo) ListBooks(ctx context.Context, filterAndSort *pkg.FilterAndSort) ([]model.Book, int64, error) {
+	var books []model.Book
+
+	where, whereParams, err := pkg.FilterToSQL(filterAndSort.Filters, validateBookFilterAttr)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if where == "" {
+		where += "t.status != 'deleted' "
+	} else {
+		where += " AND t.status != 'deleted' "
+	}
+
+	orderBy, err := pkg.SortSQL(filterAndSort.Sort, transformBookSortAttribute)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if orderBy != "" {
+		if !strings.Contains(orderBy, "t.id") {
+			orderBy += ", t.id DESC"
+		}
+	} else {
+		orderBy = "t.id DESC"
+	}
+
+	query := withBookJoins(r.db.WithContext(ctx).Table("books AS t")).
+		Where(where, whereParams...).
+		Select(bookSelect).
+		Order(orderBy)
+
+	query, err = applyPagination(query, filterAndSort, "t.id")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = query.Find(&books).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if filterAndSort.IsCursorPaging() {
+		books = books[:keysetPageSize(filterAndSort, len(books), func(i int) []string {
+			return []string{strconv.FormatInt(books[i].ID, 10)}
+		})]
+		return books, 0, nil
+	}
+
+	var totalCount int64
+	err = withBookJoins(r.db.WithContext(ctx).Table("books AS t")).
+		Where(where, whereParams...).
+		Count(&totalCount).
+		Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return books, totalCount, nil
+}
+
+var bookJoinedAttributes = map[string]string{
+	"tample_name": "tm.name",
+	"tample_city": "tm.city",
+}
+
+var allowedAtributesInBookFilters = []string{
+	"id", "name", "tample_id", "tample_name", "tample_city", "year_from", "year_to",
+	"page_capacity", "entries_per_page", "state", "status", "created_at",
+}
+
+var allowedAtributesInBookSort = allowedAtributesInBookFilters
+
+func transformBookSortAttribute(p string) (string, error) {
+	if !pkg.InList(p, allowedAtributesInBookSort) {
+		return "", fmt.Errorf("UNSUPPORTED_SORT_PROPERTY")
+	}
+	if column, ok := bookJoinedAttributes[p]; ok {
+		return column, nil
+	}
+
+	return "t." + p, nil
+}
+
+func validateBookFilterAttr(p string, v []string) (string, error) {
+	if !pkg.InList(p, allowedAtributesInBookFilters) {
+		return "", fmt.Errorf("UNSUPPORTED_FILTER_PROPERTY")
+	}
+	if column, ok := bookJoinedAttributes[p]; ok {
+		return column, nil
+	}
+
+	return "t." + p, nil
+}
+
+func (r *repo) CreateBook(ctx context.Context, bookPost *model.BookPost) (*model.Book, error) {
+	err := r.db.WithContext(ctx).Create(bookPost).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetBookByID(ctx, bookPost.ID)
+}
+
+func (r *repo) UpdateBook(ctx context.Context, id int64, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).
+		Table("books").
+		Where("id = ? ", id).
+		Updates(updates).Error
+}
+
+// CreateKrstenicaInBook dodeljuje sledeću stranu i tekući broj u knjizi i
+// upisuje krštenicu u istoj transakciji. Red knjige se zaključava (FOR UPDATE)
+// tako da dva istovremena upisa ne mogu dobiti isti broj.
+func (r *repo) CreateKrstenicaInBook(ctx context.Context, krstenicaPost *model.KrstenicaPost) (*model.Krstenica, error) {
+	if krstenicaPost.BookId == nil {
+		return nil, errors.New("book is required for automatic numbering")
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var book model.BookPost
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND status != 'deleted'", *krstenicaPost.BookId).
+			First(&book).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errorx.ErrBookNotFound
+			}
+			return err
+		}
+		if book.State != model.BookStateOpen {
+			return errorx.ErrBookClosed
+		}
+
+		var last struct {
+			Page          int64
+			CurrentNumber int64
+		}
+		err = tx.Table("krstenice").
+			Select("page, current_number").
+			Where("book_id = ?", book.ID).
+			Order("current_number DESC, page DESC").
+			Limit(1).
+			Scan(&last).Error
+		if err != nil {
+			return err
+		}
+
+		var onLastPage int64
+		if last.CurrentNumber > 0 {
+			err = tx.Table("krstenice").
+				Where("book_id = ? AND page = ?", book.ID, last.Page).
+				Count(&onLastPage).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		page, number := nextBookSlot(last.Page, last.CurrentNumber, onLastPage, book.EntriesPerPage)
+		if page > book.PageCapacity {
+			return errorx.ErrBookFull
+		}
+
+		krstenicaPost.Book = book.Name
+		krstenicaPost.Page = page
+		krstenicaPost.CurrentNumber = number
+
+		if err := tx.Create(krstenicaPost).Error; err != nil {
+			return err
+		}
+		return saveKrstenicaGodparents(tx, krstenicaPost.ID, krstenicaPost.GodparentIds)
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, errorx.ErrBookNumberTaken
+		}
+		return nil, err
+	}
+
+	krstenica, err := r.GetKrstenicaByID(ctx, krstenicaPost.ID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return krstenica, nil
+}
+
+func nextBookSlot(lastPage, lastNumber, onLastPage, entriesPerPage int64) (int64, int64) {
+	if lastNumber <= 0 {
+		return 1, 1
+	}
+	if lastPage <= 0 {
+		lastPage = 1
+	}
+	if entriesPerPage > 0 && onLastPage >= entriesPerPage {
+		return lastPage + 1, lastNumber + 1
+	}
+	return lastPage, lastNumber + 1
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"krstenica/internal/errorx"
+	"krstenica/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// ListCertificateLayouts vraća rasporede uverenja jedne vrste, bez ćelija.
+func (r *repo) ListCertificateLayouts(ctx context.Context, kind string) ([]model.CertificateLayout, error) {
+	var layouts []model.CertificateLayout
+	err := r.db.WithContext(ctx).
+		Where("kind = ? AND status != ?", kind, model.CertificateLayoutStatusDeleted).
+		Order("is_default DESC, name ASC, id ASC").
+		Find(&layouts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return layouts, nil
+}
+
+func (r *repo) GetCertificateLayoutByID(ctx context.Context, id int64) (*model.CertificateLayout, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid ID provided")
+	}
+
+	return r.getCertificateLayout(ctx, r.db.WithContext(ctx).Where("id = ?", id))
+}
+
+func (r *repo) GetDefaultCertificateLayout(ctx context.Context, kind string) (*model.CertificateLayout, error) {
+	return r.getCertificateLayout(ctx, r.db.WithContext(ctx).Where("kind = ? AND is_default", kind))
+}
+
+func (r *repo) getCertificateLayout(ctx context.Context, query *gorm.DB) (*model.CertificateLayout, error) {
+	var layout model.CertificateLayout
+	err := query.
+		Where("status != ?", model.CertificateLayoutStatusDeleted).
+		First(&layout).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errorx.ErrCertificateLayoutNotFound
+		}
+		return nil, err
+	}
+
+	err = r.db.WithContext(ctx).
+		Where("layout_id = ?", layout.ID).
+		Order("position ASC, id ASC").
+		Find(&layout.Cells).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &layout, nil
+}
+
+// CreateCertificateLayout upisuje raspored zajedno sa njegovim ćelijama.
+func (r *repo) CreateCertificateLayout(ctx context.Context, layout *model.CertificateLayout) (*model.CertificateLayout, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(layout).Error; err != nil {
+			return err
+		}
+		return (&repo{db: tx}).ReplaceCertificateLayoutCells(ctx, layout.ID, layout.Cells)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetCertificateLayoutByID(ctx, layout.ID)
+}
+
+func (r *repo) UpdateCertificateLayout(ctx context.Context, id int64, updates map[string]interface{}) error {
+	updates["updated_at"] = time.Now()
+	return r.db.WithContext(ctx).
+		Table("certificate_layouts").
+		Where("id = ?", id).
+		Updates(updates).Error
+}
+
+// ReplaceCertificateLayoutCells menja sve ćelije rasporeda novim spiskom.
+func (r *repo) ReplaceCertificateLayoutCells(ctx context.Context, id int64, cells []model.CertificateLayoutCell) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("layout_id = ?", id).Delete(&model.CertificateLayoutCell{}).Error; err != nil {
+			return err
+		}
+		if len(cells) == 0 {
+			return nil
+		}
+		rows := make([]model.CertificateLayoutCell, len(cells))
+		for i, cell := range cells {
+			cell.ID = 0
+			cell.LayoutId = id
+			cell.Position = int64(i + 1)
+			rows[i] = cell
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+// SetDefaultCertificateLayout označava raspored kao podrazumevani za svoju vrstu.
+func (r *repo) SetDefaultCertificateLayout(ctx context.Context, kind string, id int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Table("certificate_layouts").
+			Where("kind = ? AND is_default AND id != ?", kind, id).
+			Update("is_default", false).Error
+		if err != nil {
+			return err
+		}
+		return tx.Table("certificate_layouts").
+			Where("id = ?", id).
+			Updates(map[string]interface{}{"is_default": true, "updated_at": time.Now()}).Error
+	})
+}
@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"krstenica/internal/errorx"
+	"krstenica/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// ListCertificateTemplates vraća obrasce uverenja jedne vrste.
+func (r *repo) ListCertificateTemplates(ctx context.Context, kind string) ([]model.CertificateTemplate, error) {
+	var templates []model.CertificateTemplate
+	err := r.db.WithContext(ctx).
+		Where("kind = ? AND status != ?", kind, model.CertificateTemplateStatusDeleted).
+		Order("is_default DESC, name ASC, id ASC").
+		Find(&templates).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+func (r *repo) GetCertificateTemplateByID(ctx context.Context, id int64) (*model.CertificateTemplate, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid ID provided")
+	}
+
+	return r.getCertificateTemplate(r.db.WithContext(ctx).Where("id = ?", id))
+}
+
+func (r *repo) GetDefaultCertificateTemplate(ctx context.Context, kind string) (*model.CertificateTemplate, error) {
+	return r.getCertificateTemplate(r.db.WithContext(ctx).Where("kind = ? AND is_default", kind))
+}
+
+// GetAssignedCertificateTemplate vraća obrazac dodeljen hramu, a ako ga hram
+// nema, obrazac dodeljen eparhiji.
+func (r *repo) GetAssignedCertificateTemplate(ctx context.Context, kind string, tampleID, eparhijaID int64) (*model.CertificateTemplate, error) {
+	for _, assignment := range []struct {
+		column string
+		id     int64
+	}{{"tample_id", tampleID}, {"eparhija_id", eparhijaID}} {
+		if assignment.id <= 0 {
+			continue
+		}
+		template, err := r.getCertificateTemplate(r.db.WithContext(ctx).
+			Where("kind = ?", kind).
+			Where("id IN (?)", r.db.Table("certificate_template_assignments").
+				Select("template_id").
+				Where(assignment.column+" = ?", assignment.id)))
+		if err == nil {
+			return template, nil
+		}
+		if !errors.Is(err, errorx.ErrCertificateTemplateNotFound) {
+			return nil, err
+		}
+	}
+
+	return nil, errorx.ErrCertificateTemplateNotFound
+}
+
+func (r *repo) getCertificateTemplate(query *gorm.DB) (*model.CertificateTemplate, error) {
+	var template model.CertificateTemplate
+	err := query.
+		Where("status != ?", model.CertificateTemplateStatusDeleted).
+		First(&template).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errorx.ErrCertificateTemplateNotFound
+		}
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+func (r *repo) CreateCertificateTemplate(ctx context.Context, template *model.CertificateTemplate) (*model.CertificateTemplate, error) {
+	if err := r.db.WithContext(ctx).Create(template).Error; err != nil {
+		return nil, err
+	}
+
+	return r.GetCertificateTemplateByID(ctx, template.ID)
+}
+
+func (r *repo) UpdateCertificateTemplate(ctx context.Context, id int64, updates map[string]interface{}) error {
+	updates["updated_at"] = time.Now()
+	return r.db.WithContext(ctx).
+		Table("certificate_templates").
+		Where("id = ?", id).
+		Updates(updates).Error
+}
+
+// SetDefaultCertificateTemplate označava obrazac kao podrazumevani za svoju vrstu.
+func (r *repo) SetDefaultCertificateTemplate(ctx context.Context, kind string, id int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Table("certificate_templates").
+			Where("kind = ? AND is_default AND id != ?", kind, id).
+			Update("is_default", false).Error
+		if err != nil {
+			return err
+		}
+		return tx.Table("certificate_templates").
+			Where("id = ?", id).
+			Updates(map[string]interface{}{"is_default": true, "updated_at": time.Now()}).Error
+	})
+}
+
+// GetCertificateTemplateVersionByID vraća verziju obrasca i kada je obrazac
+// obrisan, da bi se ranije izdato uverenje moglo ponovo odštampati.
+func (r *repo) GetCertificateTemplateVersionByID(ctx context.Context, id int64) (*model.CertificateTemplateVersion, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid ID provided")
+	}
+
+	var version model.CertificateTemplateVersion
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&version).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errorx.ErrCertificateTemplateNotFound
+		}
+		return nil, err
+	}
+
+	return &version, nil
+}
+
+// GetLatestCertificateTemplateVersion vraća tekuću (poslednju) verziju obrasca.
+func (r *repo) GetLatestCertificateTemplateVersion(ctx context.Context, templateID int64) (*model.CertificateTemplateVersion, error) {
+	var version model.CertificateTemplateVersion
+	err := r.db.WithContext(ctx).
+		Where("template_id = ?", templateID).
+		Order("version DESC").
+		First(&version).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errorx.ErrCertificateTemplateNotFound
+		}
+		return nil, err
+	}
+
+	return &version, nil
+}
+
+// ListCertificateTemplateVersions vraća sve verzije obrasca, od najnovije.
+func (r *repo) ListCertificateTemplateVersions(ctx context.Context, templateID int64) ([]model.CertificateTemplateVersion, error) {
+	var versions []model.CertificateTemplateVersion
+	err := r.db.WithContext(ctx).
+		Where("template_id = ?", templateID).
+		Order("version DESC").
+		Find(&versions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// CreateCertificateTemplateVersion dodeljuje sledeći broj verzije obrasca i
+// upisuje verziju. Red obrasca se zaključava da dve istovremene verzije ne bi
+// dobile isti broj.
+func (r *repo) CreateCertificateTemplateVersion(ctx context.Context, version *model.CertificateTemplateVersion) (*model.CertificateTemplateVersion, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT id FROM certificate_templates WHERE id = ? FOR UPDATE", version.TemplateId).Error; err != nil {
+			return err
+		}
+
+		var last int64
+		err := tx.Table("certificate_template_versions").
+			Select("COALESCE(MAX(version), 0)").
+			Where("template_id = ?", version.TemplateId).
+			Scan(&last).Error
+		if err != nil {
+			return err
+		}
+
+		version.Version = last + 1
+		if err := tx.Create(version).Error; err != nil {
+			return err
+		}
+		return tx.Table("certificate_templates").
+			Where("id = ?", version.TemplateId).
+			Update("updated_at", time.Now()).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetCertificateTemplateVersionByID(ctx, version.ID)
+}
+
+// ListCertificateTemplateAssignments vraća eparhije i hramove kojima je obrazac dodeljen.
+func (r *repo) ListCertificateTemplateAssignments(ctx context.Context, templateID int64) ([]model.CertificateTemplateAssignment, error) {
+	var assignments []model.CertificateTemplateAssignment
+	err := r.db.WithContext(ctx).
+		Table("certificate_template_assignments AS a").
+		Joins("LEFT JOIN eparhije AS e ON e.id = a.eparhija_id").
+		Joins("LEFT JOIN tamples AS tm ON tm.id = a.tample_id").
+		Select("a.*, COALESCE(e.name, '') AS eparhija_name, COALESCE(tm.name, '') AS tample_name").
+		Where("a.template_id = ?", templateID).
+		Order("e.name ASC, tm.name ASC, a.id ASC").
+		Find(&assignments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return assignments, nil
+}
+
+// ReplaceCertificateTemplateAssignments dodeljuje obrazac zadatim eparhijama i
+// hramovima. Eparhija ili hram imaju najviše jedan obrazac, pa se njihove
+// ranije dodele drugim obrascima uklanjaju.
+func (r *repo) ReplaceCertificateTemplateAssignments(ctx context.Context, templateID int64, eparhijaIDs, tampleIDs []int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("template_id = ?", templateID)
+		if len(eparhijaIDs) > 0 {
+			query = query.Or("eparhija_id IN ?", eparhijaIDs)
+		}
+		if len(tampleIDs) > 0 {
+			query = query.Or("tample_id IN ?", tampleIDs)
+		}
+		if err := query.Delete(&model.CertificateTemplateAssignment{}).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		var rows []model.CertificateTemplateAssignment
+		for _, id := range eparhijaIDs {
+			rows = append(rows, model.CertificateTemplateAssignment{TemplateId: templateID, EparhijaId: sql.NullInt64{Valid: true, Int64: id}, CreatedAt: now})
+		}
+		for _, id := range tampleIDs {
+			rows = append(rows, model.CertificateTemplateAssignment{TemplateId: templateID, TampleId: sql.NullInt64{Valid: true, Int64: id}, CreatedAt: now})
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+func (r *repo) DeleteCertificateTemplateAssignments(ctx context.Context, templateID int64) error {
+	return r.db.WithContext(ctx).
+		Where("template_id = ?", templateID).
+		Delete(&model.CertificateTemplateAssignment{}).Error
+}
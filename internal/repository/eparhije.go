@@ -6,6 +6,7 @@ import (
 	"krstenica/internal/errorx"
 	"krstenica/internal/model"
 	"krstenica/pkg"
+	"strconv"
 	"strings"
 
 	"gorm.io/gorm"
@@ -59,7 +60,10 @@ func (r *repo) ListEparhije(ctx context.Context, filterAndSort *pkg.FilterAndSor
 		Where(where, whereParams...).
 		Order(orderBy)
 
-	query = applyPagination(query, filterAndSort)
+	query, err = applyPagination(query, filterAndSort, "t.id")
+	if err != nil {
+		return nil, 0, err
+	}
 
 	err = query.Find(&eparhija).Error
 	if err != nil {
@@ -69,6 +73,13 @@ func (r *repo) ListEparhije(ctx context.Context, filterAndSort *pkg.FilterAndSor
 		return nil, 0, err
 	}
 
+	if filterAndSort.IsCursorPaging() {
+		eparhija = eparhija[:keysetPageSize(filterAndSort, len(eparhija), func(i int) []string {
+			return []string{strconv.FormatInt(eparhija[i].ID, 10)}
+		})]
+		return eparhija, 0, nil
+	}
+
 	var totalCount int64
 
 	//totalCount
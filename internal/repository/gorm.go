@@ -2,6 +2,7 @@ package repository
 
 import (
 	"krstenica/internal/config"
+	"krstenica/pkg"
 	"log"
 	"os"
 	"regexp"
@@ -28,7 +29,8 @@ func InitORM(dbConf config.DBConfig) (*gorm.DB, error) {
 		},
 	)
 	db, err := gorm.Open(postgres.Open(dbConf.URL), &gorm.Config{
-		Logger: newLogger,
+		Logger:         newLogger,
+		TranslateError: true,
 	})
 	if err != nil {
 		return nil, err
@@ -64,3 +66,52 @@ func Underscore(in string) string {
 func convertToUnderscore(s string) string {
 	return string(s[0]) + "_" + string(s[1])
 }
+
+// foldSearchFilters normalizuje vrednosti filtera po imenima kako bi se
+// poredile sa search_ kolonama nezavisno od pisma i dijakritika.
+func foldSearchFilters(filters map[pkg.FilterKey][]string, searchColumns map[string]string) map[pkg.FilterKey][]string {
+	folded := make(map[pkg.FilterKey][]string, len(filters))
+	for key, values := range filters {
+		if key.Operator == pkg.FilterExpressionOperator {
+			folded[key] = foldSearchExpressions(values, searchColumns)
+			continue
+		}
+		if _, ok := searchColumns[Underscore(key.Property)]; ok {
+			normalized := make([]string, len(values))
+			for i, value := range values {
+				normalized[i] = pkg.FoldSearchText(value)
+			}
+			values = normalized
+		}
+		folded[key] = values
+	}
+
+	return folded
+}
+
+// foldSearchExpressions normalizuje vrednosti uslova po imenima unutar izraza
+// filtera. Neispravan izraz ostaje nepromenjen da bi FilterToSQL vratio gresku.
+func foldSearchExpressions(values []string, searchColumns map[string]string) []string {
+	folded := make([]string, len(values))
+	for i, value := range values {
+		expr, err := pkg.ParseFilterExpression(value)
+		if err != nil {
+			folded[i] = value
+			continue
+		}
+		expr.Walk(func(e *pkg.FilterExpression) {
+			if e.Kind != "" {
+				return
+			}
+			if _, ok := searchColumns[Underscore(e.Key.Property)]; !ok {
+				return
+			}
+			for j, v := range e.Values {
+				e.Values[j] = pkg.FoldSearchText(v)
+			}
+		})
+		folded[i] = expr.String()
+	}
+
+	return folded
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"krstenica/internal/errorx"
+	"krstenica/internal/model"
+	"krstenica/pkg"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// issuedCertificateLockKey je prvi ključ advisory lock-a za dodelu rednog broja
+// uverenja; drugi ključ je godina.
+const issuedCertificateLockKey = 8008
+
+const issuedCertificateSelect = `t.*, tm.name as tample_name,
+		k.first_name as first_name,
+		k.last_name as last_name,
+		k.city as city,
+		k.baptism as baptism,
+		k.status as krstenica_status,
+		ct.name as template_name,
+		tv.version as template_revision`
+
+func withIssuedCertificateJoins(db *gorm.DB) *gorm.DB {
+	return db.Joins("LEFT JOIN krstenice as k on k.id = t.krstenica_id").
+		Joins("LEFT JOIN tamples as tm on tm.id = t.tample_id").
+		Joins("LEFT JOIN certificate_template_versions as tv on tv.id = t.template_version_id").
+		Joins("LEFT JOIN certificate_templates as ct on ct.id = tv.template_id")
+}
+
+func (r *repo) GetIssuedCertificateByID(ctx context.Context, id int64) (*model.IssuedCertificate, error) {
+	var certificate model.IssuedCertificate
+	if id <= 0 {
+		return nil, errors.New("invalid ID provided")
+	}
+
+	err := withIssuedCertificateJoins(r.db.WithContext(ctx).Table("issued_certificates AS t")).
+		Where("t.id = ?", id).
+		Select(issuedCertificateSelect).
+		First(&certificate).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errorx.ErrIssuedCertificateNotFound
+		}
+		return nil, err
+	}
+
+	return &certificate, nil
+}
+
+func (r *repo) ListIssuedCertificates(ctx context.Context, filterAndSort *pkg.FilterAndSort) ([]model.IssuedCertificate, int64, error) {
+	var certificates []model.IssuedCertificate
+
+	where, whereParams, err := pkg.FilterToSQL(filterAndSort.Filters, validateIssuedCertificateFilterAttr)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	orderBy, err := pkg.SortSQL(filterAndSort.Sort, transformIssuedCertificateSortAttribute)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if orderBy != "" {
+		if !strings.Contains(orderBy, "t.id") {
+			orderBy += ", t.id DESC"
+		}
+	} else {
+		orderBy = "t.id DESC"
+	}
+
+	query := withIssuedCertificateJoins(r.db.WithContext(ctx).Table("issued_certificates AS t")).
+		Where(where, whereParams...).
+		Select(issuedCertificateSelect).
+		Order(orderBy)
+
+	query, err = applyPagination(query, filterAndSort, "t.id")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = query.Find(&certificates).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if filterAndSort.IsCursorPaging() {
+		certificates = certificates[:keysetPageSize(filterAndSort, len(certificates), func(i int) []string {
+			return []string{strconv.FormatInt(certificates[i].ID, 10)}
+		})]
+		return certificates, 0, nil
+	}
+
+	var totalCount int64
+	err = withIssuedCertificateJoins(r.db.WithContext(ctx).Table("issued_certificates AS t")).
+		Where(where, whereParams...).
+		Count(&totalCount).
+		Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return certificates, totalCount, nil
+}
+
+var issuedCertificateJoinedAttributes = map[string]string{
+	"tample_name": "tm.name",
+	"first_name":  "k.first_name",
+	"last_name":   "k.last_name",
+	"city":        "k.city",
+}
+
+var allowedAtributesInIssuedCertificateFilters = []string{
+	"id", "krstenica_id", "tample_id", "tample_name", "first_name", "last_name", "city",
+	"year", "serial_number", "format", "template_version", "template_version_id", "purpose", "issued_by_id",
+	"issued_by_username", "created_at", "revoked_at",
+}
+
+var allowedAtributesInIssuedCertificateSort = allowedAtributesInIssuedCertificateFilters
+
+func transformIssuedCertificateSortAttribute(p string) (string, error) {
+	if !pkg.InList(p, allowedAtributesInIssuedCertificateSort) {
+		return "", fmt.Errorf("UNSUPPORTED_SORT_PROPERTY")
+	}
+	if column, ok := issuedCertificateJoinedAttributes[p]; ok {
+		return column, nil
+	}
+
+	return "t." + p, nil
+}
+
+func validateIssuedCertificateFilterAttr(p string, v []string) (string, error) {
+	if !pkg.InList(p, allowedAtributesInIssuedCertificateFilters) {
+		return "", fmt.Errorf("UNSUPPORTED_FILTER_PROPERTY")
+	}
+	if column, ok := issuedCertificateJoinedAttributes[p]; ok {
+		return column, nil
+	}
+
+	return "t." + p, nil
+}
+
+// CreateIssuedCertificate dodeljuje sledeći redni broj u godini izdavanja i
+// upisuje uverenje u istoj transakciji. Advisory lock po godini sprečava da
+// dva istovremena izdavanja dobiju isti broj.
+func (r *repo) CreateIssuedCertificate(ctx context.Context, certificate *model.IssuedCertificatePost) (*model.IssuedCertificate, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?, ?)", issuedCertificateLockKey, certificate.Year).Error; err != nil {
+			return err
+		}
+
+		var last int64
+		err := tx.Table("issued_certificates").
+			Select("COALESCE(MAX(serial_number), 0)").
+			Where("year = ?", certificate.Year).
+			Scan(&last).Error
+		if err != nil {
+			return err
+		}
+
+		certificate.SerialNumber = last + 1
+		return tx.Create(certificate).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetIssuedCertificateByID(ctx, certificate.ID)
+}
+
+func (r *repo) UpdateIssuedCertificate(ctx context.Context, id int64, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).
+		Table("issued_certificates").
+		Where("id = ?", id).
+		Updates(updates).Error
+}
+
+// DeleteIssuedCertificates briše uverenja čiji dokument nije napravljen.
+func (r *repo) DeleteIssuedCertificates(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Table("issued_certificates").
+		Where("id IN ?", ids).
+		Delete(nil).Error
+}
+
+// HasNewerIssuedCertificate proverava da li je za istu krštenicu kasnije
+// izdato uverenje koje nije poništeno.
+func (r *repo) HasNewerIssuedCertificate(ctx context.Context, krstenicaID, id int64) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Table("issued_certificates").
+		Where("krstenica_id = ? AND id > ? AND revoked_at IS NULL", krstenicaID, id).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
@@ -8,6 +8,7 @@ import (
 	"krstenica/internal/model"
 	"krstenica/pkg"
 	"log"
+	"strconv"
 	"strings"
 
 	"gorm.io/gorm"
@@ -21,7 +22,8 @@ func (r *repo) GetKrstenicaByID(ctx context.Context, id int64) (*model.Krstenica
 
 	eparhijaJoin := "LEFT JOIN eparhije as ep on ep.id = t.eparhija_id AND ep.status != 'deleted'"
 	tampleJoin := "LEFT JOIN tamples as tm on tm.id = t.tample_id AND tm.status != 'deleted'"
-	parentJoin := "LEFT JOIN persons as par on par.id = t.parent_id AND par.status != 'deleted'"
+	fatherJoin := "LEFT JOIN persons as oc on oc.id = t.father_id AND oc.status != 'deleted'"
+	motherJoin := "LEFT JOIN persons as maj on maj.id = t.mother_id AND maj.status != 'deleted'"
 	godFatherJoin := "LEFT JOIN persons as fat on fat.id = t.godfather_id AND fat.status != 'deleted'"
 	parohJoin := "LEFT JOIN persons as pa on pa.id = t.paroh_id AND pa.status != 'deleted'"
 	priestJoin := "LEFT JOIN priests as pr on pr.id = t.priest_id AND pr.status != 'deleted'"
@@ -32,18 +34,24 @@ func (r *repo) GetKrstenicaByID(ctx context.Context, id int64) (*model.Krstenica
 		Where("t.id = ?", id).
 		Joins(eparhijaJoin).
 		Joins(tampleJoin).
-		Joins(parentJoin).
+		Joins(fatherJoin).
+		Joins(motherJoin).
 		Joins(godFatherJoin).
 		Joins(parohJoin).
 		Joins(priestJoin).
 		Select(`t.*, ep.name as eparhija_name,
 		tm.name as tample_name,
 		tm.city as tample_city, 
-		par.first_name as parent_first_name, 
-		par.last_name as parent_last_name, 
-		par.occupation as parent_occupation, 
-		par.city as parent_city, 
-		par.religion as parent_religion,
+		oc.first_name as father_first_name,
+		oc.last_name as father_last_name,
+		oc.occupation as father_occupation,
+		oc.city as father_city,
+		oc.religion as father_religion,
+		maj.first_name as mother_first_name,
+		maj.last_name as mother_last_name,
+		maj.occupation as mother_occupation,
+		maj.city as mother_city,
+		maj.religion as mother_religion,
 		fat.first_name as godfather_first_name, 
 		fat.last_name as godfather_last_name, 
 		fat.occupation as godfather_occupation, 
@@ -62,6 +70,12 @@ func (r *repo) GetKrstenicaByID(ctx context.Context, id int64) (*model.Krstenica
 		return nil, err
 	}
 
+	godparents, err := loadKrstenicaGodparents(r.db.WithContext(ctx), []int64{krstenica.ID})
+	if err != nil {
+		return nil, err
+	}
+	krstenica.Godparents = godparents[krstenica.ID]
+
 	return &krstenica, nil
 }
 
@@ -69,11 +83,103 @@ func (r *repo) ListKrstenice(ctx context.Context, filterAndSort *pkg.FilterAndSo
 
 	var krstenica []model.Krstenica
 
-	where, whereParams, err := pkg.FilterToSQL(filterAndSort.Filters, validateKrstenicaFilterAttr)
+	query, countQuery, err := r.krsteniceQuery(ctx, filterAndSort)
 	if err != nil {
 		return nil, 0, err
 	}
 
+	query, err = applyPagination(query, filterAndSort, "t.id")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = query.Find(&krstenica).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, 0, errorx.ErrKrstenicaNotFound
+		}
+		return nil, 0, err
+	}
+
+	var totalCount int64
+
+	if filterAndSort.IsCursorPaging() {
+		krstenica = krstenica[:keysetPageSize(filterAndSort, len(krstenica), func(i int) []string {
+			return []string{strconv.FormatInt(krstenica[i].ID, 10)}
+		})]
+	} else {
+		//totalCount
+		err = countQuery.Count(&totalCount).Error
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if err := attachKrsteniceGodparents(r.db.WithContext(ctx), krstenica); err != nil {
+		return nil, 0, err
+	}
+
+	return krstenica, totalCount, nil
+}
+
+const krsteniceExportBatchSize = 500
+
+// ExportKrstenice prolazi kroz sve krstenice po filterima i sortu i predaje ih
+// funkciji fn u serijama, bez ucitavanja celog rezultata u memoriju.
+func (r *repo) ExportKrstenice(ctx context.Context, filterAndSort *pkg.FilterAndSort, fn func([]model.Krstenica) error) error {
+	query, _, err := r.krsteniceQuery(ctx, filterAndSort)
+	if err != nil {
+		return err
+	}
+
+	rows, err := query.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	batch := make([]model.Krstenica, 0, krsteniceExportBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := attachKrsteniceGodparents(r.db.WithContext(ctx), batch); err != nil {
+			return err
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	for rows.Next() {
+		var krstenica model.Krstenica
+		if err := r.db.ScanRows(rows, &krstenica); err != nil {
+			return err
+		}
+		batch = append(batch, krstenica)
+		if len(batch) == krsteniceExportBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	return flush()
+}
+
+// krsteniceQuery sklapa upit liste krstenica sa spojenim imenima, filterima i
+// sortom, kao i odgovarajuci upit za ukupan broj.
+func (r *repo) krsteniceQuery(ctx context.Context, filterAndSort *pkg.FilterAndSort) (*gorm.DB, *gorm.DB, error) {
+	where, whereParams, err := pkg.FilterToSQL(foldSearchFilters(filterAndSort.Filters, krstenicaSearchColumns), validateKrstenicaFilterAttr)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	if where == "" {
 		where += "t.status != 'deleted' "
 	} else {
@@ -82,7 +188,7 @@ func (r *repo) ListKrstenice(ctx context.Context, filterAndSort *pkg.FilterAndSo
 
 	orderBy, err := pkg.SortSQL(filterAndSort.Sort, transformKrstenicaSortAttribute)
 	if err != nil {
-		return nil, 0, err
+		return nil, nil, err
 	}
 
 	if orderBy != "" {
@@ -92,29 +198,34 @@ func (r *repo) ListKrstenice(ctx context.Context, filterAndSort *pkg.FilterAndSo
 	} else {
 		orderBy = "t.id DESC"
 	}
-	eparhijaJoin := "LEFT JOIN eparhije as ep on ep.id = t.eparhija_id AND ep.status != 'deleted'"
-	tampleJoin := "LEFT JOIN tamples as tm on tm.id = t.tample_id AND tm.status != 'deleted'"
-	parentJoin := "LEFT JOIN persons as par on par.id = t.parent_id AND par.status != 'deleted'"
-	godFatherJoin := "LEFT JOIN persons as fat on fat.id = t.godfather_id AND fat.status != 'deleted'"
-	parohJoin := "LEFT JOIN persons as pa on pa.id = t.paroh_id AND pa.status != 'deleted'"
-	priestJoin := "LEFT JOIN priests as pr on pr.id = t.priest_id AND pr.status != 'deleted'"
-	query := r.db.WithContext(ctx).
-		Table("krstenice AS t").
-		Joins(eparhijaJoin).
-		Joins(tampleJoin).
-		Joins(parentJoin).
-		Joins(godFatherJoin).
-		Joins(parohJoin).
-		Joins(priestJoin).
+
+	// fuzzy pretraga filtrira po slicnosti punog imena i rangira po njoj
+	scoreSelect := "NULL AS score"
+	selectParams := []interface{}{}
+	rankedOrderBy := orderBy
+	if fuzzy := pkg.FoldSearchText(filterAndSort.Fuzzy); fuzzy != "" {
+		where += " AND ? <% t.search_full_name"
+		whereParams = append(whereParams, fuzzy)
+		scoreSelect = "word_similarity(?, t.search_full_name) AS score"
+		selectParams = append(selectParams, fuzzy)
+		rankedOrderBy = "score DESC, " + orderBy
+	}
+
+	query := withKrsteniceJoins(r.db.WithContext(ctx).Table("krstenice AS t")).
 		Where(where, whereParams...).
 		Select(`t.*, ep.name as eparhija_name,
 		tm.name as tample_name,
 		tm.city as tample_city, 
-		par.first_name as parent_first_name, 
-		par.last_name as parent_last_name, 
-		par.occupation as parent_occupation, 
-		par.city as parent_city, 
-		par.religion as parent_religion,
+		oc.first_name as father_first_name,
+		oc.last_name as father_last_name,
+		oc.occupation as father_occupation,
+		oc.city as father_city,
+		oc.religion as father_religion,
+		maj.first_name as mother_first_name,
+		maj.last_name as mother_last_name,
+		maj.occupation as mother_occupation,
+		maj.city as mother_city,
+		maj.religion as mother_religion,
 		fat.first_name as godfather_first_name, 
 		fat.last_name as godfather_last_name, 
 		fat.occupation as godfather_occupation, 
@@ -124,47 +235,94 @@ func (r *repo) ListKrstenice(ctx context.Context, filterAndSort *pkg.FilterAndSo
 		pa.last_name as paroh_last_name,
 		pr.first_name as priest_first_name,
 		pr.last_name as priest_last_name,
-		pr.title as priest_title`).
+		pr.title as priest_title, `+scoreSelect, selectParams...).
+		Order(rankedOrderBy)
+
+	countQuery := withKrsteniceJoins(r.db.Table("krstenice AS t")).
+		Where(where, whereParams...).
 		Order(orderBy)
 
-	query = applyPagination(query, filterAndSort)
+	return query, countQuery, nil
+}
 
-	err = query.Find(&krstenica).Error
+// numberingYearSQL je godina po kojoj se vodi numeracija: godina krštenja, a
+// ako nije uneta godina upisa.
+const numberingYearSQL = "COALESCE(EXTRACT(YEAR FROM COALESCE(t.baptism, t.created_at))::int, 0)"
+
+// ListKrsteniceNumbering vraca upise za proveru numeracije poredjane po hramu,
+// knjizi, godini i tekucem broju. Citaju se samo kolone koje provera koristi.
+func (r *repo) ListKrsteniceNumbering(ctx context.Context, filterAndSort *pkg.FilterAndSort, year int) ([]model.KrstenicaNumberingEntry, error) {
+	where, whereParams, err := pkg.FilterToSQL(filterAndSort.Filters, validateKrstenicaFilterAttr)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, 0, errorx.ErrKrstenicaNotFound
-		}
-		return nil, 0, err
+		return nil, err
 	}
 
-	var totalCount int64
+	if where == "" {
+		where += "t.status != 'deleted' "
+	} else {
+		where += " AND t.status != 'deleted' "
+	}
+	if year > 0 {
+		where += " AND " + numberingYearSQL + " = ?"
+		whereParams = append(whereParams, year)
+	}
 
-	//totalCount
-	err = r.db.Table("krstenice AS t").
-		Joins(eparhijaJoin).
-		Joins(tampleJoin).
-		Joins(parentJoin).
-		Joins(godFatherJoin).
-		Joins(parohJoin).
-		Joins(priestJoin).
+	entries := []model.KrstenicaNumberingEntry{}
+	err = withKrsteniceJoins(r.db.WithContext(ctx).Table("krstenice AS t")).
 		Where(where, whereParams...).
-		Order(orderBy).
-		Count(&totalCount).
-		Error
+		Select(`t.id, t.tample_id, tm.name as tample_name, t.book_id, t.book, t.page,
+		t.current_number, t.baptism, ` + numberingYearSQL + ` as year`).
+		Order("t.tample_id, t.book, year, t.current_number, t.page, t.id").
+		Scan(&entries).Error
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	return krstenica, totalCount, nil
+	return entries, nil
+}
+
+func withKrsteniceJoins(db *gorm.DB) *gorm.DB {
+	return db.
+		Joins("LEFT JOIN eparhije as ep on ep.id = t.eparhija_id AND ep.status != 'deleted'").
+		Joins("LEFT JOIN tamples as tm on tm.id = t.tample_id AND tm.status != 'deleted'").
+		Joins("LEFT JOIN persons as oc on oc.id = t.father_id AND oc.status != 'deleted'").
+		Joins("LEFT JOIN persons as maj on maj.id = t.mother_id AND maj.status != 'deleted'").
+		Joins("LEFT JOIN persons as fat on fat.id = t.godfather_id AND fat.status != 'deleted'").
+		Joins("LEFT JOIN persons as pa on pa.id = t.paroh_id AND pa.status != 'deleted'").
+		Joins("LEFT JOIN priests as pr on pr.id = t.priest_id AND pr.status != 'deleted'")
+}
+
+// attachKrsteniceGodparents ucitava kumove za sve krstenice iz liste jednim upitom.
+func attachKrsteniceGodparents(db *gorm.DB, krstenica []model.Krstenica) error {
+	ids := make([]int64, len(krstenica))
+	for i := range krstenica {
+		ids[i] = krstenica[i].ID
+	}
+	godparents, err := loadKrstenicaGodparents(db, ids)
+	if err != nil {
+		return err
+	}
+	for i := range krstenica {
+		krstenica[i].Godparents = godparents[krstenica[i].ID]
+	}
+
+	return nil
 }
 
 var allowedAtributesInKrstenicaFilters = []string{
-	"id", "book", "page", "current_number", "eparhija_name", "tample_name", "tample_city",
-	"parent_first_name",
-	"parent_last_name",
-	"parent_occupation",
-	"parent_city",
-	"parent_religion",
+	"id", "book_id", "book", "page", "current_number", "tample_id", "eparhija_name", "tample_name", "tample_city",
+	"father_id",
+	"father_first_name",
+	"father_last_name",
+	"father_occupation",
+	"father_city",
+	"father_religion",
+	"mother_id",
+	"mother_first_name",
+	"mother_last_name",
+	"mother_occupation",
+	"mother_city",
+	"mother_religion",
 	"godfather_first_name",
 	"godfather_last_name",
 	"godfather_occupation",
@@ -176,13 +334,36 @@ var allowedAtributesInKrstenicaFilters = []string{
 	"comment", "status", "created_at",
 }
 
+// krstenicaSearchColumns mapira filtere po imenima na normalizovane kolone.
+var krstenicaSearchColumns = map[string]string{
+	"first_name":           "t.search_first_name",
+	"last_name":            "t.search_last_name",
+	"father_first_name":    "oc.search_first_name",
+	"father_last_name":     "oc.search_last_name",
+	"mother_first_name":    "maj.search_first_name",
+	"mother_last_name":     "maj.search_last_name",
+	"godfather_first_name": "fat.search_first_name",
+	"godfather_last_name":  "fat.search_last_name",
+	"paroh_first_name":     "pa.search_first_name",
+	"paroh_last_name":      "pa.search_last_name",
+	"priest_first_name":    "pr.search_first_name",
+	"priest_last_name":     "pr.search_last_name",
+}
+
 var allowedAtributesInKrstenicaSort = []string{
-	"id", "book", "page", "current_number", "eparhija_name", "tample_name", "tample_city",
-	"parent_first_name",
-	"parent_last_name",
-	"parent_occupation",
-	"parent_city",
-	"parent_religion",
+	"id", "book_id", "book", "page", "current_number", "tample_id", "eparhija_name", "tample_name", "tample_city",
+	"father_id",
+	"father_first_name",
+	"father_last_name",
+	"father_occupation",
+	"father_city",
+	"father_religion",
+	"mother_id",
+	"mother_first_name",
+	"mother_last_name",
+	"mother_occupation",
+	"mother_city",
+	"mother_religion",
 	"godfather_first_name",
 	"godfather_last_name",
 	"godfather_occupation",
@@ -208,20 +389,35 @@ func transformKrstenicaSortAttribute(p string) (string, error) {
 	if p == "tample_city" {
 		return "tm.city", nil
 	}
-	if p == "parent_first_name" {
-		return "par.first_name", nil
+	if p == "father_first_name" {
+		return "oc.first_name", nil
+	}
+	if p == "father_last_name" {
+		return "oc.last_name", nil
+	}
+	if p == "father_occupation" {
+		return "oc.occupation", nil
 	}
-	if p == "parent_last_name" {
-		return "par.last_name", nil
+	if p == "father_city" {
+		return "oc.city", nil
 	}
-	if p == "parent_occupation" {
-		return "par.occupation", nil
+	if p == "father_religion" {
+		return "oc.religion", nil
 	}
-	if p == "parent_city" {
-		return "par.city", nil
+	if p == "mother_first_name" {
+		return "maj.first_name", nil
 	}
-	if p == "parent_religion" {
-		return "par.religion", nil
+	if p == "mother_last_name" {
+		return "maj.last_name", nil
+	}
+	if p == "mother_occupation" {
+		return "maj.occupation", nil
+	}
+	if p == "mother_city" {
+		return "maj.city", nil
+	}
+	if p == "mother_religion" {
+		return "maj.religion", nil
 	}
 	if p == "godfather_first_name" {
 		return "fat.first_name", nil
@@ -262,6 +458,9 @@ func validateKrstenicaFilterAttr(p string, v []string) (string, error) {
 		return "", fmt.Errorf("UNSUPPORTED_FILTER_PROPERTY")
 	}
 	p = Underscore(p)
+	if column, ok := krstenicaSearchColumns[p]; ok {
+		return column, nil
+	}
 	if p == "eparhija_name" {
 		return "ep.name", nil
 	}
@@ -271,20 +470,35 @@ func validateKrstenicaFilterAttr(p string, v []string) (string, error) {
 	if p == "tample_city" {
 		return "tm.city", nil
 	}
-	if p == "parent_first_name" {
-		return "par.first_name", nil
+	if p == "father_first_name" {
+		return "oc.first_name", nil
+	}
+	if p == "father_last_name" {
+		return "oc.last_name", nil
 	}
-	if p == "parent_last_name" {
-		return "par.last_name", nil
+	if p == "father_occupation" {
+		return "oc.occupation", nil
 	}
-	if p == "parent_occupation" {
-		return "par.occupation", nil
+	if p == "father_city" {
+		return "oc.city", nil
 	}
-	if p == "parent_city" {
-		return "par.city", nil
+	if p == "father_religion" {
+		return "oc.religion", nil
 	}
-	if p == "parent_religion" {
-		return "par.religion", nil
+	if p == "mother_first_name" {
+		return "maj.first_name", nil
+	}
+	if p == "mother_last_name" {
+		return "maj.last_name", nil
+	}
+	if p == "mother_occupation" {
+		return "maj.occupation", nil
+	}
+	if p == "mother_city" {
+		return "maj.city", nil
+	}
+	if p == "mother_religion" {
+		return "maj.religion", nil
 	}
 	if p == "godfather_first_name" {
 		return "fat.first_name", nil
@@ -321,8 +535,16 @@ func validateKrstenicaFilterAttr(p string, v []string) (string, error) {
 }
 
 func (r *repo) CreateKrstenica(ctx context.Context, krstenicaPost *model.KrstenicaPost) (*model.Krstenica, error) {
-	err := r.db.WithContext(ctx).Create(krstenicaPost).Error
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(krstenicaPost).Error; err != nil {
+			return err
+		}
+		return saveKrstenicaGodparents(tx, krstenicaPost.ID, krstenicaPost.GodparentIds)
+	})
 	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, errorx.ErrBookNumberTaken
+		}
 		return nil, err
 	}
 
@@ -335,14 +557,84 @@ func (r *repo) CreateKrstenica(ctx context.Context, krstenicaPost *model.Krsteni
 	return krstenica, nil
 }
 
+// UpdateKrstenica menja kolone krštenice. Ključ "godparent_ids" ([]int64) nije
+// kolona: kumovi se tada zamenjuju novim spiskom u istoj transakciji.
 func (r *repo) UpdateKrstenica(ctx context.Context, id int64, updates map[string]interface{}) error {
-	err := r.db.WithContext(ctx).
-		Table("krstenice").
-		Where("id = ? ", id).
-		Updates(updates).Error
+	godparentIds, replaceGodparents := updates["godparent_ids"].([]int64)
+	delete(updates, "godparent_ids")
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(updates) > 0 {
+			err := tx.Table("krstenice").
+				Where("id = ? ", id).
+				Updates(updates).Error
+			if err != nil {
+				return err
+			}
+		}
+		if replaceGodparents {
+			return saveKrstenicaGodparents(tx, id, godparentIds)
+		}
+		return nil
+	})
 	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return errorx.ErrBookNumberTaken
+		}
 		return err
 	}
 
 	return nil
 }
+
+func loadKrstenicaGodparents(db *gorm.DB, krstenicaIDs []int64) (map[int64][]model.KrstenicaGodparent, error) {
+	res := map[int64][]model.KrstenicaGodparent{}
+	if len(krstenicaIDs) == 0 {
+		return res, nil
+	}
+
+	var godparents []model.KrstenicaGodparent
+	err := db.Table("krstenica_godparents AS kg").
+		Joins("JOIN persons AS p ON p.id = kg.person_id").
+		Where("kg.krstenica_id IN ?", krstenicaIDs).
+		Select(`kg.krstenica_id, kg.person_id, kg.position,
+		p.first_name, p.last_name, p.occupation, p.city, p.religion`).
+		Order("kg.krstenica_id, kg.position").
+		Scan(&godparents).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, g := range godparents {
+		res[g.KrstenicaId] = append(res[g.KrstenicaId], g)
+	}
+	return res, nil
+}
+
+// saveKrstenicaGodparents zamenjuje kumove krštenice datim spiskom (redosled se
+// čuva) i prvog kuma upisuje u krstenice.godfather_id.
+func saveKrstenicaGodparents(tx *gorm.DB, krstenicaID int64, personIDs []int64) error {
+	if len(personIDs) == 0 {
+		return nil
+	}
+
+	if err := tx.Where("krstenica_id = ?", krstenicaID).Delete(&model.KrstenicaGodparent{}).Error; err != nil {
+		return err
+	}
+
+	rows := make([]map[string]interface{}, len(personIDs))
+	for i, personID := range personIDs {
+		rows[i] = map[string]interface{}{
+			"krstenica_id": krstenicaID,
+			"person_id":    personID,
+			"position":     i + 1,
+		}
+	}
+	if err := tx.Table("krstenica_godparents").Create(rows).Error; err != nil {
+		return err
+	}
+
+	return tx.Table("krstenice").
+		Where("id = ?", krstenicaID).
+		Update("godfather_id", personIDs[0]).Error
+}
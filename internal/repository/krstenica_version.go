@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"krstenica/internal/errorx"
+	"krstenica/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// ListKrstenicaVersions vraća verzije krštenice od najnovije ka najstarijoj.
+func (r *repo) ListKrstenicaVersions(ctx context.Context, krstenicaID int64) ([]model.KrstenicaVersion, error) {
+	var versions []model.KrstenicaVersion
+	err := r.db.WithContext(ctx).
+		Where("krstenica_id = ?", krstenicaID).
+		Order("version DESC").
+		Find(&versions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+func (r *repo) GetKrstenicaVersion(ctx context.Context, krstenicaID, version int64) (*model.KrstenicaVersion, error) {
+	var res model.KrstenicaVersion
+	err := r.db.WithContext(ctx).
+		Where("krstenica_id = ? AND version = ?", krstenicaID, version).
+		First(&res).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errorx.ErrKrstenicaVersionNotFound
+		}
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// CreateKrstenicaVersion upisuje novu verziju sa sledećim brojem. Ako
+// krštenica još nema nijednu verziju (upisana pre uvođenja istorije), prvo se
+// kao verzija 1 upisuje baseline, stanje pre izmene. Red krštenice se
+// zaključava da dve istovremene izmene ne dobiju isti broj.
+func (r *repo) CreateKrstenicaVersion(ctx context.Context, krstenicaID int64, baseline, version *model.KrstenicaVersion) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT id FROM krstenice WHERE id = ? FOR UPDATE", krstenicaID).Error; err != nil {
+			return err
+		}
+
+		var last int64
+		err := tx.Model(&model.KrstenicaVersion{}).
+			Where("krstenica_id = ?", krstenicaID).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&last).Error
+		if err != nil {
+			return err
+		}
+
+		versions := []*model.KrstenicaVersion{version}
+		if last == 0 && baseline != nil {
+			versions = []*model.KrstenicaVersion{baseline, version}
+		}
+		for _, v := range versions {
+			last++
+			v.KrstenicaId = krstenicaID
+			v.Version = last
+			if err := tx.Create(v).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"krstenica/internal/errorx"
 	"krstenica/internal/model"
 	"krstenica/pkg"
+	"strconv"
 	"strings"
 
 	"gorm.io/gorm"
@@ -31,7 +32,7 @@ func (r *repo) ListPersons(ctx context.Context, filterAndSort *pkg.FilterAndSort
 
 	var person []model.Person
 
-	where, whereParams, err := pkg.FilterToSQL(filterAndSort.Filters, validatePersonFilterAttr)
+	where, whereParams, err := pkg.FilterToSQL(foldSearchFilters(filterAndSort.Filters, personSearchColumns), validatePersonFilterAttr)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -59,7 +60,10 @@ func (r *repo) ListPersons(ctx context.Context, filterAndSort *pkg.FilterAndSort
 		Where(where, whereParams...).
 		Order(orderBy)
 
-	query = applyPagination(query, filterAndSort)
+	query, err = applyPagination(query, filterAndSort, "t.id")
+	if err != nil {
+		return nil, 0, err
+	}
 
 	err = query.Find(&person).Error
 	if err != nil {
@@ -69,6 +73,13 @@ func (r *repo) ListPersons(ctx context.Context, filterAndSort *pkg.FilterAndSort
 		return nil, 0, err
 	}
 
+	if filterAndSort.IsCursorPaging() {
+		person = person[:keysetPageSize(filterAndSort, len(person), func(i int) []string {
+			return []string{strconv.FormatInt(person[i].ID, 10)}
+		})]
+		return person, 0, nil
+	}
+
 	var totalCount int64
 
 	//totalCount
@@ -89,6 +100,12 @@ var allowedAtributesInPersonFilters = []string{
 	"id", "first_name", "last_name", "brief_name", "occupation", "religion", "address", "country", "role", "status", "city", "birth_date", "created_at",
 }
 
+// personSearchColumns mapira filtere po imenu na normalizovane kolone.
+var personSearchColumns = map[string]string{
+	"first_name": "t.search_first_name",
+	"last_name":  "t.search_last_name",
+}
+
 var allowedAtributesInPersonSort = []string{
 	"id", "first_name", "last_name", "brief_name", "occupation", "religion", "address", "country", "role", "status", "city", "birth_date", "created_at",
 }
@@ -105,6 +122,9 @@ func validatePersonFilterAttr(p string, v []string) (string, error) {
 	if !pkg.InList(p, allowedAtributesInPersonFilters) {
 		return "", fmt.Errorf("UNSUPPORTED_FILTER_PROPERTY")
 	}
+	if column, ok := personSearchColumns[p]; ok {
+		return column, nil
+	}
 
 	return "t." + p, nil
 }
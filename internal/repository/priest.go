@@ -6,6 +6,7 @@ import (
 	"krstenica/internal/errorx"
 	"krstenica/internal/model"
 	"krstenica/pkg"
+	"strconv"
 	"strings"
 
 	"gorm.io/gorm"
@@ -31,7 +32,7 @@ func (r *repo) ListPriests(ctx context.Context, filterAndSort *pkg.FilterAndSort
 
 	var priest []model.Priest
 
-	where, whereParams, err := pkg.FilterToSQL(filterAndSort.Filters, validatePriestFilterAttr)
+	where, whereParams, err := pkg.FilterToSQL(foldSearchFilters(filterAndSort.Filters, priestSearchColumns), validatePriestFilterAttr)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -60,7 +61,10 @@ func (r *repo) ListPriests(ctx context.Context, filterAndSort *pkg.FilterAndSort
 		Where(where, whereParams...).
 		Order(orderBy)
 
-	query = applyPagination(query, filterAndSort)
+	query, err = applyPagination(query, filterAndSort, "t.id")
+	if err != nil {
+		return nil, 0, err
+	}
 
 	err = query.Find(&priest).Error
 	if err != nil {
@@ -70,6 +74,13 @@ func (r *repo) ListPriests(ctx context.Context, filterAndSort *pkg.FilterAndSort
 		return nil, 0, err
 	}
 
+	if filterAndSort.IsCursorPaging() {
+		priest = priest[:keysetPageSize(filterAndSort, len(priest), func(i int) []string {
+			return []string{strconv.FormatInt(priest[i].ID, 10)}
+		})]
+		return priest, 0, nil
+	}
+
 	var totalCount int64
 
 	//totalCount
@@ -90,6 +101,12 @@ var allowedAtributesInPriestFilters = []string{
 	"id", "first_name", "last_name", "city", "title", "status", "created_at",
 }
 
+// priestSearchColumns mapira filtere po imenu na normalizovane kolone.
+var priestSearchColumns = map[string]string{
+	"first_name": "t.search_first_name",
+	"last_name":  "t.search_last_name",
+}
+
 var allowedAtributesInPriestSort = []string{
 	"id", "first_name", "last_name", "city", "title", "status", "created_at",
 }
@@ -106,6 +123,9 @@ func validatePriestFilterAttr(p string, v []string) (string, error) {
 	if !pkg.InList(p, allowedAtributesInPriestFilters) {
 		return "", fmt.Errorf("UNSUPPORTED_FILTER_PROPERTY")
 	}
+	if column, ok := priestSearchColumns[p]; ok {
+		return column, nil
+	}
 
 	return "t." + p, nil
 }
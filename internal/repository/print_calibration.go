@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"krstenica/internal/errorx"
+	"krstenica/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// ListPrintCalibrations vraća profile kalibracije, bez ćelija.
+func (r *repo) ListPrintCalibrations(ctx context.Context) ([]model.PrintCalibration, error) {
+	var calibrations []model.PrintCalibration
+	err := r.printCalibrationQuery(ctx).
+		Order("c.name ASC, c.id ASC").
+		Find(&calibrations).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return calibrations, nil
+}
+
+func (r *repo) GetPrintCalibrationByID(ctx context.Context, id int64) (*model.PrintCalibration, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid ID provided")
+	}
+
+	return r.getPrintCalibration(ctx, r.printCalibrationQuery(ctx).Where("c.id = ?", id))
+}
+
+// GetUserPrintCalibration vraća lični profil korisnika.
+func (r *repo) GetUserPrintCalibration(ctx context.Context, userID int64) (*model.PrintCalibration, error) {
+	return r.getPrintCalibration(ctx, r.printCalibrationQuery(ctx).Where("c.user_id = ?", userID))
+}
+
+// GetWorkstationPrintCalibration vraća profil radne stanice.
+func (r *repo) GetWorkstationPrintCalibration(ctx context.Context, workstation string) (*model.PrintCalibration, error) {
+	return r.getPrintCalibration(ctx, r.printCalibrationQuery(ctx).Where("c.workstation = ? AND c.workstation != ''", workstation))
+}
+
+func (r *repo) printCalibrationQuery(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Table("print_calibrations AS c").
+		Joins("LEFT JOIN app_users AS u ON u.id = c.user_id").
+		Select("c.*, COALESCE(u.username, '') AS username").
+		Where("c.status != ?", model.PrintCalibrationStatusDeleted)
+}
+
+func (r *repo) getPrintCalibration(ctx context.Context, query *gorm.DB) (*model.PrintCalibration, error) {
+	var calibration model.PrintCalibration
+	err := query.First(&calibration).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errorx.ErrPrintCalibrationNotFound
+		}
+		return nil, err
+	}
+
+	err = r.db.WithContext(ctx).
+		Where("calibration_id = ?", calibration.ID).
+		Order("cell ASC").
+		Find(&calibration.Cells).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &calibration, nil
+}
+
+// CreatePrintCalibration upisuje profil zajedno sa pomerajima ćelija.
+func (r *repo) CreatePrintCalibration(ctx context.Context, calibration *model.PrintCalibration) (*model.PrintCalibration, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(calibration).Error; err != nil {
+			return err
+		}
+		return (&repo{db: tx}).ReplacePrintCalibrationCells(ctx, calibration.ID, calibration.Cells)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetPrintCalibrationByID(ctx, calibration.ID)
+}
+
+func (r *repo) UpdatePrintCalibration(ctx context.Context, id int64, updates map[string]interface{}) error {
+	updates["updated_at"] = time.Now()
+	return r.db.WithContext(ctx).
+		Table("print_calibrations").
+		Where("id = ?", id).
+		Updates(updates).Error
+}
+
+// ReplacePrintCalibrationCells menja sve pomeraje ćelija profila novim spiskom.
+func (r *repo) ReplacePrintCalibrationCells(ctx context.Context, id int64, cells []model.PrintCalibrationCell) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("calibration_id = ?", id).Delete(&model.PrintCalibrationCell{}).Error; err != nil {
+			return err
+		}
+		if len(cells) == 0 {
+			return nil
+		}
+		rows := make([]model.PrintCalibrationCell, len(cells))
+		for i, cell := range cells {
+			cell.ID = 0
+			cell.CalibrationId = id
+			rows[i] = cell
+		}
+		return tx.Create(&rows).Error
+	})
+}
@@ -5,10 +5,12 @@ import (
 	"strconv"
 	"strings"
 
+	"krstenica/internal/errorx"
 	"krstenica/internal/model"
 	"krstenica/pkg"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Repo interface {
@@ -36,6 +38,85 @@ type Repo interface {
 	CreateKrstenica(ctx context.Context, krstenica *model.KrstenicaPost) (*model.Krstenica, error)
 	UpdateKrstenica(ctx context.Context, id int64, updates map[string]interface{}) error
 	ListKrstenice(ctx context.Context, filterAndSort *pkg.FilterAndSort) ([]model.Krstenica, int64, error)
+	ListKrsteniceNumbering(ctx context.Context, filterAndSort *pkg.FilterAndSort, year int) ([]model.KrstenicaNumberingEntry, error)
+	ExportKrstenice(ctx context.Context, filterAndSort *pkg.FilterAndSort, fn func([]model.Krstenica) error) error
+
+	GetVencanicaByID(ctx context.Context, id int64) (*model.Vencanica, error)
+	CreateVencanica(ctx context.Context, vencanica *model.VencanicaPost) (*model.Vencanica, error)
+	UpdateVencanica(ctx context.Context, id int64, updates map[string]interface{}) error
+	ListVencanice(ctx context.Context, filterAndSort *pkg.FilterAndSort) ([]model.Vencanica, int64, error)
+
+	GetUmrlicaByID(ctx context.Context, id int64) (*model.Umrlica, error)
+	CreateUmrlica(ctx context.Context, umrlica *model.UmrlicaPost) (*model.Umrlica, error)
+	UpdateUmrlica(ctx context.Context, id int64, updates map[string]interface{}) error
+	ListUmrlice(ctx context.Context, filterAndSort *pkg.FilterAndSort) ([]model.Umrlica, int64, error)
+
+	GetBookByID(ctx context.Context, id int64) (*model.Book, error)
+	CreateBook(ctx context.Context, book *model.BookPost) (*model.Book, error)
+	UpdateBook(ctx context.Context, id int64, updates map[string]interface{}) error
+	ListBooks(ctx context.Context, filterAndSort *pkg.FilterAndSort) ([]model.Book, int64, error)
+	CreateKrstenicaInBook(ctx context.Context, krstenica *model.KrstenicaPost) (*model.Krstenica, error)
+
+	GetKrstenicaAnnotationByID(ctx context.Context, id int64) (*model.KrstenicaAnnotation, error)
+	ListKrstenicaAnnotations(ctx context.Context, krstenicaID int64) ([]model.KrstenicaAnnotation, error)
+	CreateKrstenicaAnnotation(ctx context.Context, annotation *model.KrstenicaAnnotation) (*model.KrstenicaAnnotation, error)
+	UpdateKrstenicaAnnotation(ctx context.Context, id int64, updates map[string]interface{}) error
+
+	GetKrstenicaAttachmentByID(ctx context.Context, id int64) (*model.KrstenicaAttachment, error)
+	ListKrstenicaAttachments(ctx context.Context, krstenicaID int64) ([]model.KrstenicaAttachment, error)
+	CreateKrstenicaAttachment(ctx context.Context, attachment *model.KrstenicaAttachment) (*model.KrstenicaAttachment, error)
+	UpdateKrstenicaAttachment(ctx context.Context, id int64, updates map[string]interface{}) error
+
+	ListKrstenicaVersions(ctx context.Context, krstenicaID int64) ([]model.KrstenicaVersion, error)
+	GetKrstenicaVersion(ctx context.Context, krstenicaID, version int64) (*model.KrstenicaVersion, error)
+	CreateKrstenicaVersion(ctx context.Context, krstenicaID int64, baseline, version *model.KrstenicaVersion) error
+
+	ListTrash(ctx context.Context, filterAndSort *pkg.FilterAndSort) ([]model.TrashItem, int64, error)
+	GetTrashItem(ctx context.Context, entity string, id int64) (*model.TrashItem, error)
+	RestoreTrashItem(ctx context.Context, entity string, id int64) error
+	CountTrashReferences(ctx context.Context, entity string, id int64) (int64, int64, error)
+	PurgeTrashItem(ctx context.Context, entity string, id int64) error
+
+	CreateAuditLog(ctx context.Context, entry *model.AuditLog) error
+	ListAuditLogs(ctx context.Context, filterAndSort *pkg.FilterAndSort) ([]model.AuditLog, int64, error)
+
+	GetIssuedCertificateByID(ctx context.Context, id int64) (*model.IssuedCertificate, error)
+	ListIssuedCertificates(ctx context.Context, filterAndSort *pkg.FilterAndSort) ([]model.IssuedCertificate, int64, error)
+	CreateIssuedCertificate(ctx context.Context, certificate *model.IssuedCertificatePost) (*model.IssuedCertificate, error)
+	UpdateIssuedCertificate(ctx context.Context, id int64, updates map[string]interface{}) error
+	DeleteIssuedCertificates(ctx context.Context, ids []int64) error
+	HasNewerIssuedCertificate(ctx context.Context, krstenicaID, id int64) (bool, error)
+
+	ListCertificateLayouts(ctx context.Context, kind string) ([]model.CertificateLayout, error)
+	GetCertificateLayoutByID(ctx context.Context, id int64) (*model.CertificateLayout, error)
+	GetDefaultCertificateLayout(ctx context.Context, kind string) (*model.CertificateLayout, error)
+	CreateCertificateLayout(ctx context.Context, layout *model.CertificateLayout) (*model.CertificateLayout, error)
+	UpdateCertificateLayout(ctx context.Context, id int64, updates map[string]interface{}) error
+	ReplaceCertificateLayoutCells(ctx context.Context, id int64, cells []model.CertificateLayoutCell) error
+	SetDefaultCertificateLayout(ctx context.Context, kind string, id int64) error
+
+	ListCertificateTemplates(ctx context.Context, kind string) ([]model.CertificateTemplate, error)
+	GetCertificateTemplateByID(ctx context.Context, id int64) (*model.CertificateTemplate, error)
+	GetDefaultCertificateTemplate(ctx context.Context, kind string) (*model.CertificateTemplate, error)
+	GetAssignedCertificateTemplate(ctx context.Context, kind string, tampleID, eparhijaID int64) (*model.CertificateTemplate, error)
+	CreateCertificateTemplate(ctx context.Context, template *model.CertificateTemplate) (*model.CertificateTemplate, error)
+	UpdateCertificateTemplate(ctx context.Context, id int64, updates map[string]interface{}) error
+	SetDefaultCertificateTemplate(ctx context.Context, kind string, id int64) error
+	GetCertificateTemplateVersionByID(ctx context.Context, id int64) (*model.CertificateTemplateVersion, error)
+	GetLatestCertificateTemplateVersion(ctx context.Context, templateID int64) (*model.CertificateTemplateVersion, error)
+	ListCertificateTemplateVersions(ctx context.Context, templateID int64) ([]model.CertificateTemplateVersion, error)
+	CreateCertificateTemplateVersion(ctx context.Context, version *model.CertificateTemplateVersion) (*model.CertificateTemplateVersion, error)
+	ListCertificateTemplateAssignments(ctx context.Context, templateID int64) ([]model.CertificateTemplateAssignment, error)
+	ReplaceCertificateTemplateAssignments(ctx context.Context, templateID int64, eparhijaIDs, tampleIDs []int64) error
+	DeleteCertificateTemplateAssignments(ctx context.Context, templateID int64) error
+
+	ListPrintCalibrations(ctx context.Context) ([]model.PrintCalibration, error)
+	GetPrintCalibrationByID(ctx context.Context, id int64) (*model.PrintCalibration, error)
+	GetUserPrintCalibration(ctx context.Context, userID int64) (*model.PrintCalibration, error)
+	GetWorkstationPrintCalibration(ctx context.Context, workstation string) (*model.PrintCalibration, error)
+	CreatePrintCalibration(ctx context.Context, calibration *model.PrintCalibration) (*model.PrintCalibration, error)
+	UpdatePrintCalibration(ctx context.Context, id int64, updates map[string]interface{}) error
+	ReplacePrintCalibrationCells(ctx context.Context, id int64, cells []model.PrintCalibrationCell) error
 
 	GetUserByUsername(ctx context.Context, username string) (*model.User, error)
 	CreateUser(ctx context.Context, user *model.User) (*model.User, error)
@@ -44,6 +125,8 @@ type Repo interface {
 	GetUserByID(ctx context.Context, id int64) (*model.User, error)
 	UpdateUser(ctx context.Context, id int64, updates map[string]interface{}) error
 	DeleteUser(ctx context.Context, id int64) error
+
+	Transaction(ctx context.Context, fn func(txRepo Repo) error) error
 }
 
 type repo struct {
@@ -54,6 +137,14 @@ func NewRepository(db *gorm.DB) Repo {
 	return &repo{db: db}
 }
 
+// Transaction izvrsava fn nad repozitorijumom vezanim za jednu transakciju;
+// greska iz fn ponistava sve izmene. Ugnjezdeni poziv koristi savepoint.
+func (r *repo) Transaction(ctx context.Context, fn func(txRepo Repo) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&repo{db: tx})
+	})
+}
+
 func (r *repo) GetUserByUsername(ctx context.Context, username string) (*model.User, error) {
 	var user model.User
 	if err := r.db.WithContext(ctx).Where("username = ?", username).First(&user).Error; err != nil {
@@ -107,22 +198,27 @@ func Paginate(db *gorm.DB, dest interface{}, limit int) *gorm.DB {
 
 const defaultPageSize = 10
 
-func applyPagination(db *gorm.DB, fas *pkg.FilterAndSort) *gorm.DB {
+// applyPagination primenjuje LIMIT/OFFSET ili, kod paging=cursor, keyset
+// paginaciju po kolonama keyColumns (opadajuce).
+func applyPagination(db *gorm.DB, fas *pkg.FilterAndSort, keyColumns ...string) (*gorm.DB, error) {
 	if fas == nil || fas.Paging == nil {
-		return db
+		return db, nil
+	}
+	if fas.IsCursorPaging() {
+		return applyKeysetPagination(db, fas, keyColumns)
 	}
 
 	paging := fas.Paging
 	if strings.EqualFold(strings.TrimSpace(paging.All), "yes") {
-		return db
+		return db, nil
 	}
 	if strings.EqualFold(strings.TrimSpace(paging.Paging), "no") {
-		return db
+		return db, nil
 	}
 
 	pageSize := parsePositiveInt(paging.PageSize, defaultPageSize)
 	if pageSize <= 0 {
-		return db
+		return db, nil
 	}
 
 	pageNumber := parsePositiveInt(paging.PageNumber, 1)
@@ -135,7 +231,53 @@ func applyPagination(db *gorm.DB, fas *pkg.FilterAndSort) *gorm.DB {
 		offset = 0
 	}
 
-	return db.Limit(pageSize).Offset(offset)
+	return db.Limit(pageSize).Offset(offset), nil
+}
+
+// applyKeysetPagination redja po kljucu (sort i fuzzy nisu dozvoljeni),
+// nastavlja iza kursora i ucitava jedan red vise da bi se znalo da li postoji
+// sledeca strana.
+func applyKeysetPagination(db *gorm.DB, fas *pkg.FilterAndSort, keyColumns []string) (*gorm.DB, error) {
+	// kursor pamti samo kljuc poslednjeg reda, pa bi se zadati redosled i
+	// rangiranje po slicnosti izgubili
+	if len(fas.Sort) > 0 || strings.TrimSpace(fas.Fuzzy) != "" {
+		return nil, errorx.ErrCursorPagingSort
+	}
+	if fas.Paging.Cursor != "" {
+		values, err := pkg.DecodeCursor(fas.Paging.Cursor)
+		if err != nil || len(values) != len(keyColumns) {
+			return nil, errorx.ErrInvalidCursor
+		}
+		params := make([]interface{}, len(values))
+		for i, value := range values {
+			params[i] = value
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", len(values)), ",")
+		db = db.Where("("+strings.Join(keyColumns, ", ")+") < ("+placeholders+")", params...)
+	}
+
+	for i, column := range keyColumns {
+		db = db.Order(clause.OrderByColumn{
+			Column:  clause.Column{Name: column, Raw: true},
+			Desc:    true,
+			Reorder: i == 0,
+		})
+	}
+
+	return db.Limit(parsePositiveInt(fas.Paging.PageSize, defaultPageSize) + 1), nil
+}
+
+// keysetPageSize vraca broj redova strane kod paging=cursor i postavlja
+// NextCursor od kljuca poslednjeg reda kada postoji sledeca strana.
+func keysetPageSize(fas *pkg.FilterAndSort, count int, key func(i int) []string) int {
+	pageSize := parsePositiveInt(fas.Paging.PageSize, defaultPageSize)
+	fas.Paging.NextCursor = ""
+	if count <= pageSize {
+		return count
+	}
+	fas.Paging.NextCursor = pkg.EncodeCursor(key(pageSize - 1))
+
+	return pageSize
 }
 
 func parsePositiveInt(raw string, fallback int) int {
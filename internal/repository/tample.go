@@ -6,6 +6,7 @@ import (
 	"krstenica/internal/errorx"
 	"krstenica/internal/model"
 	"krstenica/pkg"
+	"strconv"
 	"strings"
 
 	"gorm.io/gorm"
@@ -81,13 +82,23 @@ func (r *repo) ListTamples(ctx context.Context, filterAndSort *pkg.FilterAndSort
 		Where(where, whereParams...).
 		Order(orderBy)
 
-	query = applyPagination(query, filterAndSort)
+	query, err = applyPagination(query, filterAndSort, "t.id")
+	if err != nil {
+		return nil, 0, err
+	}
 
 	err = query.Find(&tample).Error
 	if err != nil {
 		return nil, 0, err
 	}
 
+	if filterAndSort.IsCursorPaging() {
+		tample = tample[:keysetPageSize(filterAndSort, len(tample), func(i int) []string {
+			return []string{strconv.FormatInt(tample[i].ID, 10)}
+		})]
+		return tample, 0, nil
+	}
+
 	var totalCount int64
 
 	//totalCount
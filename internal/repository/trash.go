@@ -0,0 +1,269 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"krstenica/internal/errorx"
+	"krstenica/internal/model"
+	"krstenica/pkg"
+
+	"gorm.io/gorm"
+)
+
+// trashTables su tabele čiji se obrisani zapisi prikazuju u korpi.
+var trashTables = map[string]string{
+	model.AuditEntityKrstenica: "krstenice",
+	model.AuditEntityPerson:    "persons",
+	model.AuditEntityPriest:    "priests",
+	model.AuditEntityTample:    "tamples",
+	model.AuditEntityEparhija:  "eparhije",
+}
+
+// trashColumns su izrazi za naziv i opis zapisa u korpi, po entitetu.
+var trashColumns = map[string][2]string{
+	model.AuditEntityKrstenica: {"CONCAT_WS(' ', first_name, last_name)", "CONCAT_WS('/', book, page, current_number)"},
+	model.AuditEntityPerson:    {"CONCAT_WS(' ', first_name, last_name)", "''"},
+	model.AuditEntityPriest:    {"CONCAT_WS(' ', title, first_name, last_name)", "''"},
+	model.AuditEntityTample:    {"COALESCE(name, '')", "''"},
+	model.AuditEntityEparhija:  {"COALESCE(name, '')", "''"},
+}
+
+// trashReference je kolona koja pokazuje na zapis iz korpe. StatusColumn je
+// prazan za tabele bez statusa (izdata uverenja), čiji se redovi uvek računaju kao živi.
+type trashReference struct {
+	Table        string
+	Column       string
+	StatusColumn string
+}
+
+var trashReferences = map[string][]trashReference{
+	model.AuditEntityKrstenica: {
+		{"issued_certificates", "krstenica_id", ""},
+		{"umrlice", "krstenica_id", "status"},
+	},
+	model.AuditEntityPerson: {
+		{"krstenice", "father_id", "status"},
+		{"krstenice", "mother_id", "status"},
+		{"krstenice", "godfather_id", "status"},
+		{"krstenice", "paroh_id", "status"},
+		{"krstenica_godparents kg JOIN krstenice k ON k.id = kg.krstenica_id", "kg.person_id", "k.status"},
+		{"vencanice", "groom_id", "status"},
+		{"vencanice", "bride_id", "status"},
+		{"vencanice", "witness_id", "status"},
+		{"vencanice", "second_witness_id", "status"},
+		{"umrlice", "deceased_id", "status"},
+	},
+	model.AuditEntityPriest: {
+		{"krstenice", "priest_id", "status"},
+		{"vencanice", "priest_id", "status"},
+		{"umrlice", "priest_id", "status"},
+	},
+	model.AuditEntityTample: {
+		{"krstenice", "tample_id", "status"},
+		{"vencanice", "tample_id", "status"},
+		{"umrlice", "tample_id", "status"},
+		{"books", "tample_id", "status"},
+		{"issued_certificates", "tample_id", ""},
+	},
+	model.AuditEntityEparhija: {
+		{"krstenice", "eparhija_id", "status"},
+		{"vencanice", "eparhija_id", "status"},
+		{"umrlice", "eparhija_id", "status"},
+	},
+}
+
+func trashSelect(entity string) string {
+	columns := trashColumns[entity]
+	return fmt.Sprintf(`SELECT '%s' AS entity, id, %s AS name, %s AS details, COALESCE(city, '') AS city,
+		deleted_at, deleted_by_id, deleted_by_username
+		FROM %s WHERE status = 'deleted'`, entity, columns[0], columns[1], trashTables[entity])
+}
+
+// trashQuery spaja obrisane zapise svih entiteta u jednu tabelu "trash".
+func trashQuery() string {
+	entities := make([]string, 0, len(trashTables))
+	for entity := range trashTables {
+		entities = append(entities, entity)
+	}
+	// stabilan redosled radi lakšeg čitanja upita u logu
+	sort.Strings(entities)
+
+	selects := make([]string, 0, len(entities))
+	for _, entity := range entities {
+		selects = append(selects, trashSelect(entity))
+	}
+	return "(" + strings.Join(selects, " UNION ALL ") + ") AS trash"
+}
+
+func (r *repo) ListTrash(ctx context.Context, filterAndSort *pkg.FilterAndSort) ([]model.TrashItem, int64, error) {
+	var items []model.TrashItem
+
+	where, whereParams, err := pkg.FilterToSQL(filterAndSort.Filters, validateTrashFilterAttr)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	orderBy, err := pkg.SortSQL(filterAndSort.Sort, transformTrashSortAttribute)
+	if err != nil {
+		return nil, 0, err
+	}
+	if orderBy == "" {
+		orderBy = "deleted_at DESC NULLS LAST"
+	}
+	orderBy += ", entity, id DESC"
+
+	query := r.db.WithContext(ctx).Table(trashQuery()).
+		Where(where, whereParams...).
+		Order(orderBy)
+
+	query, err = applyPagination(query, filterAndSort, "entity", "id")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = query.Find(&items).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if filterAndSort.IsCursorPaging() {
+		items = items[:keysetPageSize(filterAndSort, len(items), func(i int) []string {
+			return []string{items[i].Entity, strconv.FormatInt(items[i].ID, 10)}
+		})]
+		return items, 0, nil
+	}
+
+	var totalCount int64
+	err = r.db.WithContext(ctx).Table(trashQuery()).
+		Where(where, whereParams...).
+		Count(&totalCount).
+		Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return items, totalCount, nil
+}
+
+func (r *repo) GetTrashItem(ctx context.Context, entity string, id int64) (*model.TrashItem, error) {
+	if _, ok := trashTables[entity]; !ok {
+		return nil, errorx.ErrTrashItemNotFound
+	}
+
+	var item model.TrashItem
+	err := r.db.WithContext(ctx).
+		Table("("+trashSelect(entity)+") AS trash").
+		Where("id = ?", id).
+		Take(&item).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errorx.ErrTrashItemNotFound
+		}
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// RestoreTrashItem vraća obrisan zapis u aktivne.
+func (r *repo) RestoreTrashItem(ctx context.Context, entity string, id int64) error {
+	table, ok := trashTables[entity]
+	if !ok {
+		return errorx.ErrTrashItemNotFound
+	}
+
+	res := r.db.WithContext(ctx).
+		Table(table).
+		Where("id = ? AND status = 'deleted'", id).
+		Updates(map[string]interface{}{
+			"status":              "active",
+			"deleted_at":          nil,
+			"deleted_by_id":       nil,
+			"deleted_by_username": "",
+		})
+	if res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrDuplicatedKey) {
+			return errorx.ErrBookNumberTaken
+		}
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errorx.ErrTrashItemNotFound
+	}
+	return nil
+}
+
+// CountTrashReferences broji redove koji pokazuju na zapis, posebno žive i
+// obrisane.
+func (r *repo) CountTrashReferences(ctx context.Context, entity string, id int64) (int64, int64, error) {
+	var live, deleted int64
+	for _, ref := range trashReferences[entity] {
+		var counts struct {
+			Live    int64 `gorm:"column:live"`
+			Deleted int64 `gorm:"column:deleted"`
+		}
+		deletedExpr := "0"
+		if ref.StatusColumn != "" {
+			deletedExpr = fmt.Sprintf("COUNT(*) FILTER (WHERE %s = 'deleted')", ref.StatusColumn)
+		}
+		err := r.db.WithContext(ctx).
+			Table(ref.Table).
+			Select(fmt.Sprintf("COUNT(*) - %s AS live, %s AS deleted", deletedExpr, deletedExpr)).
+			Where(ref.Column+" = ?", id).
+			Scan(&counts).Error
+		if err != nil {
+			return 0, 0, err
+		}
+		live += counts.Live
+		deleted += counts.Deleted
+	}
+	return live, deleted, nil
+}
+
+// PurgeTrashItem trajno briše obrisan zapis. Zavisni redovi krštenice
+// (kumovi, zabeleške, prilozi, verzije) brišu se kaskadno u bazi.
+func (r *repo) PurgeTrashItem(ctx context.Context, entity string, id int64) error {
+	table, ok := trashTables[entity]
+	if !ok {
+		return errorx.ErrTrashItemNotFound
+	}
+
+	res := r.db.WithContext(ctx).Exec(fmt.Sprintf("DELETE FROM %s WHERE id = ? AND status = 'deleted'", table), id)
+	if res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrForeignKeyViolated) {
+			return errorx.ErrTrashItemInUse
+		}
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errorx.ErrTrashItemNotFound
+	}
+	return nil
+}
+
+var allowedAtributesInTrashFilters = []string{
+	"entity", "id", "name", "city", "deleted_at", "deleted_by_username",
+}
+
+var allowedAtributesInTrashSort = allowedAtributesInTrashFilters
+
+func transformTrashSortAttribute(p string) (string, error) {
+	if !pkg.InList(p, allowedAtributesInTrashSort) {
+		return "", fmt.Errorf("UNSUPPORTED_SORT_PROPERTY")
+	}
+
+	return p, nil
+}
+
+func validateTrashFilterAttr(p string, v []string) (string, error) {
+	if !pkg.InList(p, allowedAtributesInTrashFilters) {
+		return "", fmt.Errorf("UNSUPPORTED_FILTER_PROPERTY")
+	}
+
+	return p, nil
+}
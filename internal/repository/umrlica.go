@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"krstenica/internal/errorx"
+	"krstenica/internal/model"
+	"krstenica/pkg"
+	"log"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var umrlicaJoins = []string{
+	"LEFT JOIN eparhije as ep on ep.id = t.eparhija_id AND ep.status != 'deleted'",
+	"LEFT JOIN tamples as tm on tm.id = t.tample_id AND tm.status != 'deleted'",
+	"LEFT JOIN persons as de on de.id = t.deceased_id AND de.status != 'deleted'",
+	"LEFT JOIN krstenice as kr on kr.id = t.krstenica_id AND kr.status != 'deleted'",
+	"LEFT JOIN tamples as ktm on ktm.id = kr.tample_id AND ktm.status != 'deleted'",
+	"LEFT JOIN priests as pr on pr.id = t.priest_id AND pr.status != 'deleted'",
+}
+
+const umrlicaSelect = `t.*, ep.name as eparhija_name,
+		tm.name as tample_name,
+		tm.city as tample_city,
+		de.first_name as deceased_first_name,
+		de.last_name as deceased_last_name,
+		de.occupation as deceased_occupation,
+		de.city as deceased_city,
+		de.address as deceased_address,
+		de.religion as deceased_religion,
+		de.birth_date as deceased_birth_date,
+		kr.book as krstenica_book,
+		kr.page as krstenica_page,
+		kr.current_number as krstenica_current_number,
+		kr.first_name as krstenica_first_name,
+		kr.last_name as krstenica_last_name,
+		kr.birth_date as krstenica_birth_date,
+		kr.baptism as krstenica_baptism,
+		ktm.name as krstenica_tample_name,
+		pr.first_name as priest_first_name,
+		pr.last_name as priest_last_name,
+		pr.title as priest_title`
+
+func withUmrlicaJoins(db *gorm.DB) *gorm.DB {
+	for _, join := range umrlicaJoins {
+		db = db.Joins(join)
+	}
+	return db
+}
+
+func (r *repo) GetUmrlicaByID(ctx context.Context, id int64) (*model.Umrlica, error) {
+	var umrlica model.Umrlica
+	if id <= 0 {
+		return nil, errors.New("invalid ID provided")
+	}
+
+	err := withUmrlicaJoins(r.db.WithContext(ctx).Table("umrlice AS t")).
+		Where("t.id = ?", id).
+		Select(umrlicaSelect).
+		First(&umrlica).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errorx.ErrUmrlicaNotFound
+		}
+		return nil, err
+	}
+
+	return &umrlica, nil
+}
+
+func (r *repo) ListUmrlice(ctx context.Context, filterAndSort *pkg.FilterAndSort) ([]model.Umrlica, int64, error) {
+	var umrlice []model.Umrlica
+
+	where, whereParams, err := pkg.FilterToSQL(filterAndSort.Filters, validateUmrlicaFilterAttr)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if where == "" {
+		where += "t.status != 'deleted' "
+	} else {
+		where += " AND t.status != 'deleted' "
+	}
+
+	orderBy, err := pkg.SortSQL(filterAndSort.Sort, transformUmrlicaSortAttribute)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if orderBy != "" {
+		if !strings.Contains(orderBy, "t.id") {
+			orderBy += ", t.id DESC"
+		}
+	} else {
+		orderBy = "t.id DESC"
+	}
+
+	query := withUmrlicaJoins(r.db.WithContext(ctx).Table("umrlice AS t")).
+		Where(where, whereParams...).
+		Select(umrlicaSelect).
+		Order(orderBy)
+
+	query, err = applyPagination(query, filterAndSort, "t.id")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = query.Find(&umrlice).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if filterAndSort.IsCursorPaging() {
+		umrlice = umrlice[:keysetPageSize(filterAndSort, len(umrlice), func(i int) []string {
+			return []string{strconv.FormatInt(umrlice[i].ID, 10)}
+		})]
+		return umrlice, 0, nil
+	}
+
+	var totalCount int64
+	err = withUmrlicaJoins(r.db.WithContext(ctx).Table("umrlice AS t")).
+		Where(where, whereParams...).
+		Count(&totalCount).
+		Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return umrlice, totalCount, nil
+}
+
+var umrlicaJoinedAttributes = map[string]string{
+	"eparhija_name":            "ep.name",
+	"tample_name":              "tm.name",
+	"tample_city":              "tm.city",
+	"deceased_first_name":      "de.first_name",
+	"deceased_last_name":       "de.last_name",
+	"deceased_occupation":      "de.occupation",
+	"deceased_city":            "de.city",
+	"deceased_religion":        "de.religion",
+	"deceased_birth_date":      "de.birth_date",
+	"krstenica_book":           "kr.book",
+	"krstenica_page":           "kr.page",
+	"krstenica_current_number": "kr.current_number",
+	"krstenica_first_name":     "kr.first_name",
+	"krstenica_last_name":      "kr.last_name",
+	"priest_first_name":        "pr.first_name",
+	"priest_last_name":         "pr.last_name",
+	"priest_title":             "pr.title",
+}
+
+var allowedAtributesInUmrlicaFilters = []string{
+	"id", "book", "page", "current_number", "eparhija_id", "tample_id", "deceased_id", "krstenica_id", "priest_id",
+	"eparhija_name", "tample_name", "tample_city",
+	"deceased_first_name", "deceased_last_name", "deceased_occupation", "deceased_city", "deceased_religion", "deceased_birth_date",
+	"krstenica_book", "krstenica_page", "krstenica_current_number", "krstenica_first_name", "krstenica_last_name",
+	"priest_first_name", "priest_last_name", "priest_title",
+	"death_date", "burial_date", "city", "country", "cemetery", "cause_of_death", "marital_status", "sacraments",
+	"number_of_certificate", "town_of_certificate", "certificate", "comment", "status", "created_at",
+}
+
+var allowedAtributesInUmrlicaSort = allowedAtributesInUmrlicaFilters
+
+func transformUmrlicaSortAttribute(p string) (string, error) {
+	if !pkg.InList(p, allowedAtributesInUmrlicaSort) {
+		return "", fmt.Errorf("UNSUPPORTED_SORT_PROPERTY")
+	}
+	p = Underscore(p)
+	if column, ok := umrlicaJoinedAttributes[p]; ok {
+		return column, nil
+	}
+
+	return "t." + p, nil
+}
+
+func validateUmrlicaFilterAttr(p string, v []string) (string, error) {
+	if !pkg.InList(p, allowedAtributesInUmrlicaFilters) {
+		return "", fmt.Errorf("UNSUPPORTED_FILTER_PROPERTY")
+	}
+	p = Underscore(p)
+	if column, ok := umrlicaJoinedAttributes[p]; ok {
+		return column, nil
+	}
+
+	return "t." + p, nil
+}
+
+func (r *repo) CreateUmrlica(ctx context.Context, umrlicaPost *model.UmrlicaPost) (*model.Umrlica, error) {
+	err := r.db.WithContext(ctx).Create(umrlicaPost).Error
+	if err != nil {
+		return nil, err
+	}
+
+	umrlica, err := r.GetUmrlicaByID(ctx, umrlicaPost.ID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return umrlica, nil
+}
+
+func (r *repo) UpdateUmrlica(ctx context.Context, id int64, updates map[string]interface{}) error {
+	err := r.db.WithContext(ctx).
+		Table("umrlice").
+		Where("id = ? ", id).
+		Updates(updates).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
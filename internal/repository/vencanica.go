@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"krstenica/internal/errorx"
+	"krstenica/internal/model"
+	"krstenica/pkg"
+	"log"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var vencanicaJoins = []string{
+	"LEFT JOIN eparhije as ep on ep.id = t.eparhija_id AND ep.status != 'deleted'",
+	"LEFT JOIN tamples as tm on tm.id = t.tample_id AND tm.status != 'deleted'",
+	"LEFT JOIN persons as gr on gr.id = t.groom_id AND gr.status != 'deleted'",
+	"LEFT JOIN persons as br on br.id = t.bride_id AND br.status != 'deleted'",
+	"LEFT JOIN persons as wi on wi.id = t.witness_id AND wi.status != 'deleted'",
+	"LEFT JOIN persons as sw on sw.id = t.second_witness_id AND sw.status != 'deleted'",
+	"LEFT JOIN priests as pr on pr.id = t.priest_id AND pr.status != 'deleted'",
+}
+
+const vencanicaSelect = `t.*, ep.name as eparhija_name,
+		tm.name as tample_name,
+		tm.city as tample_city,
+		gr.first_name as groom_first_name,
+		gr.last_name as groom_last_name,
+		gr.occupation as groom_occupation,
+		gr.city as groom_city,
+		gr.religion as groom_religion,
+		gr.birth_date as groom_birth_date,
+		br.first_name as bride_first_name,
+		br.last_name as bride_last_name,
+		br.occupation as bride_occupation,
+		br.city as bride_city,
+		br.religion as bride_religion,
+		br.birth_date as bride_birth_date,
+		wi.first_name as witness_first_name,
+		wi.last_name as witness_last_name,
+		wi.city as witness_city,
+		sw.first_name as second_witness_first_name,
+		sw.last_name as second_witness_last_name,
+		sw.city as second_witness_city,
+		pr.first_name as priest_first_name,
+		pr.last_name as priest_last_name,
+		pr.title as priest_title`
+
+func withVencanicaJoins(db *gorm.DB) *gorm.DB {
+	for _, join := range vencanicaJoins {
+		db = db.Joins(join)
+	}
+	return db
+}
+
+func (r *repo) GetVencanicaByID(ctx context.Context, id int64) (*model.Vencanica, error) {
+	var vencanica model.Vencanica
+	if id <= 0 {
+		return nil, errors.New("invalid ID provided")
+	}
+
+	err := withVencanicaJoins(r.db.WithContext(ctx).Table("vencanice AS t")).
+		Where("t.id = ?", id).
+		Select(vencanicaSelect).
+		First(&vencanica).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errorx.ErrVencanicaNotFound
+		}
+		return nil, err
+	}
+
+	return &vencanica, nil
+}
+
+func (r *repo) ListVencanice(ctx context.Context, filterAndSort *pkg.FilterAndSort) ([]model.Vencanica, int64, error) {
+	var vencanice []model.Vencanica
+
+	where, whereParams, err := pkg.FilterToSQL(filterAndSort.Filters, validateVencanicaFilterAttr)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if where == "" {
+		where += "t.status != 'deleted' "
+	} else {
+		where += " AND t.status != 'deleted' "
+	}
+
+	orderBy, err := pkg.SortSQL(filterAndSort.Sort, transformVencanicaSortAttribute)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if orderBy != "" {
+		if !strings.Contains(orderBy, "t.id") {
+			orderBy += ", t.id DESC"
+		}
+	} else {
+		orderBy = "t.id DESC"
+	}
+
+	query := withVencanicaJoins(r.db.WithContext(ctx).Table("vencanice AS t")).
+		Where(where, whereParams...).
+		Select(vencanicaSelect).
+		Order(orderBy)
+
+	query, err = applyPagination(query, filterAndSort, "t.id")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = query.Find(&vencanice).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if filterAndSort.IsCursorPaging() {
+		vencanice = vencanice[:keysetPageSize(filterAndSort, len(vencanice), func(i int) []string {
+			return []string{strconv.FormatInt(vencanice[i].ID, 10)}
+		})]
+		return vencanice, 0, nil
+	}
+
+	var totalCount int64
+	err = withVencanicaJoins(r.db.WithContext(ctx).Table("vencanice AS t")).
+		Where(where, whereParams...).
+		Count(&totalCount).
+		Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return vencanice, totalCount, nil
+}
+
+var vencanicaJoinedAttributes = map[string]string{
+	"eparhija_name":             "ep.name",
+	"tample_name":               "tm.name",
+	"tample_city":               "tm.city",
+	"groom_first_name":          "gr.first_name",
+	"groom_last_name":           "gr.last_name",
+	"groom_occupation":          "gr.occupation",
+	"groom_city":                "gr.city",
+	"groom_religion":            "gr.religion",
+	"bride_first_name":          "br.first_name",
+	"bride_last_name":           "br.last_name",
+	"bride_occupation":          "br.occupation",
+	"bride_city":                "br.city",
+	"bride_religion":            "br.religion",
+	"witness_first_name":        "wi.first_name",
+	"witness_last_name":         "wi.last_name",
+	"second_witness_first_name": "sw.first_name",
+	"second_witness_last_name":  "sw.last_name",
+	"priest_first_name":         "pr.first_name",
+	"priest_last_name":          "pr.last_name",
+	"priest_title":              "pr.title",
+}
+
+var allowedAtributesInVencanicaFilters = []string{
+	"id", "book", "page", "current_number", "eparhija_id", "tample_id", "groom_id", "bride_id", "witness_id", "second_witness_id", "priest_id",
+	"eparhija_name", "tample_name", "tample_city",
+	"groom_first_name", "groom_last_name", "groom_occupation", "groom_city", "groom_religion",
+	"bride_first_name", "bride_last_name", "bride_occupation", "bride_city", "bride_religion",
+	"witness_first_name", "witness_last_name", "second_witness_first_name", "second_witness_last_name",
+	"priest_first_name", "priest_last_name", "priest_title",
+	"marriage_date", "city", "country", "groom_marriage_order", "bride_marriage_order", "civil_marriage",
+	"number_of_certificate", "town_of_certificate", "certificate", "comment", "status", "created_at",
+}
+
+var allowedAtributesInVencanicaSort = allowedAtributesInVencanicaFilters
+
+func transformVencanicaSortAttribute(p string) (string, error) {
+	if !pkg.InList(p, allowedAtributesInVencanicaSort) {
+		return "", fmt.Errorf("UNSUPPORTED_SORT_PROPERTY")
+	}
+	p = Underscore(p)
+	if column, ok := vencanicaJoinedAttributes[p]; ok {
+		return column, nil
+	}
+
+	return "t." + p, nil
+}
+
+func validateVencanicaFilterAttr(p string, v []string) (string, error) {
+	if !pkg.InList(p, allowedAtributesInVencanicaFilters) {
+		return "", fmt.Errorf("UNSUPPORTED_FILTER_PROPERTY")
+	}
+	p = Underscore(p)
+	if column, ok := vencanicaJoinedAttributes[p]; ok {
+		return column, nil
+	}
+
+	return "t." + p, nil
+}
+
+func (r *repo) CreateVencanica(ctx context.Context, vencanicaPost *model.VencanicaPost) (*model.Vencanica, error) {
+	err := r.db.WithContext(ctx).Create(vencanicaPost).Error
+	if err != nil {
+		return nil, err
+	}
+
+	vencanica, err := r.GetVencanicaByID(ctx, vencanicaPost.ID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return vencanica, nil
+}
+
+func (r *repo) UpdateVencanica(ctx context.Context, id int64, updates map[string]interface{}) error {
+	err := r.db.WithContext(ctx).
+		Table("vencanice").
+		Where("id = ? ", id).
+		Updates(updates).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"krstenica/internal/dto"
+	"krstenica/internal/errorx"
+	"krstenica/internal/model"
+	"krstenica/internal/repository"
+	"krstenica/internal/requestctx"
+)
+
+func (s *service) ListKrstenicaAnnotations(ctx context.Context, krstenicaID int64) ([]*dto.KrstenicaAnnotation, error) {
+	if _, err := s.getPermittedKrstenica(ctx, krstenicaID); err != nil {
+		return nil, err
+	}
+
+	annotations, err := s.repo.ListKrstenicaAnnotations(ctx, krstenicaID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	res := make([]*dto.KrstenicaAnnotation, len(annotations))
+	for i := range annotations {
+		res[i] = makeKrstenicaAnnotationResponse(&annotations[i])
+	}
+	return res, nil
+}
+
+func (s *service) CreateKrstenicaAnnotation(ctx context.Context, krstenicaID int64, req *dto.KrstenicaAnnotationCreateReq) (*dto.KrstenicaAnnotation, error) {
+	if _, err := s.getPermittedKrstenica(ctx, krstenicaID); err != nil {
+		return nil, err
+	}
+	if err := validateKrstenicaAnnotationCreateRequest(req); err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	annotation := &model.KrstenicaAnnotation{
+		KrstenicaId: krstenicaID,
+		Kind:        model.AnnotationKind(req.Kind),
+		NoteDate:    req.NoteDate,
+		Text:        req.Text,
+		Status:      model.AnnotationStatusActive,
+		CreatedAt:   time.Now(),
+	}
+	if user, ok := requestctx.UserFromContext(ctx); ok {
+		if user.ID > 0 {
+			annotation.CreatedById = sql.NullInt64{Valid: true, Int64: user.ID}
+		}
+		annotation.CreatedByUsername = user.Username
+	}
+
+	var res *dto.KrstenicaAnnotation
+	err := s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		newAnnotation, err := txRepo.CreateKrstenicaAnnotation(ctx, annotation)
+		if err != nil {
+			return err
+		}
+		res = makeKrstenicaAnnotationResponse(newAnnotation)
+		return s.recordAudit(ctx, txRepo, model.AuditEntityAnnotation, res.ID, model.AuditActionCreate, nil, res)
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *service) UpdateKrstenicaAnnotation(ctx context.Context, krstenicaID, id int64, req *dto.KrstenicaAnnotationUpdateReq) (*dto.KrstenicaAnnotation, error) {
+	current, err := s.getAnnotationOfKrstenica(ctx, krstenicaID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates, err := validateKrstenicaAnnotationUpdateRequest(req)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	var res *dto.KrstenicaAnnotation
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if len(updates) > 0 {
+			if err := txRepo.UpdateKrstenicaAnnotation(ctx, id, updates); err != nil {
+				return err
+			}
+		}
+		annotation, err := txRepo.GetKrstenicaAnnotationByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		res = makeKrstenicaAnnotationResponse(annotation)
+		return s.recordAudit(ctx, txRepo, model.AuditEntityAnnotation, id, model.AuditActionUpdate, makeKrstenicaAnnotationResponse(current), res)
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *service) DeleteKrstenicaAnnotation(ctx context.Context, krstenicaID, id int64) error {
+	current, err := s.getAnnotationOfKrstenica(ctx, krstenicaID, id)
+	if err != nil {
+		return err
+	}
+
+	updates := map[string]interface{}{}
+	updates["status"] = model.AnnotationStatusDeleted
+
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if err := txRepo.UpdateKrstenicaAnnotation(ctx, id, updates); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, txRepo, model.AuditEntityAnnotation, id, model.AuditActionDelete, makeKrstenicaAnnotationResponse(current), nil)
+	})
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	return nil
+}
+
+// getPermittedKrstenica učitava krštenicu i proverava da korisnik sme da
+// radi sa upisima njenog grada.
+func (s *service) getPermittedKrstenica(ctx context.Context, krstenicaID int64) (*model.Krstenica, error) {
+	krstenica, err := s.repo.GetKrstenicaByID(ctx, krstenicaID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if err := enforceCityPermission(ctx, krstenica.City); err != nil {
+		return nil, err
+	}
+	return krstenica, nil
+}
+
+func (s *service) getAnnotationOfKrstenica(ctx context.Context, krstenicaID, id int64) (*model.KrstenicaAnnotation, error) {
+	if _, err := s.getPermittedKrstenica(ctx, krstenicaID); err != nil {
+		return nil, err
+	}
+
+	annotation, err := s.repo.GetKrstenicaAnnotationByID(ctx, id)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if annotation.KrstenicaId != krstenicaID {
+		return nil, errorx.ErrAnnotationNotFound
+	}
+	return annotation, nil
+}
+
+func makeKrstenicaAnnotationResponse(annotation *model.KrstenicaAnnotation) *dto.KrstenicaAnnotation {
+	return &dto.KrstenicaAnnotation{
+		ID:                annotation.ID,
+		KrstenicaId:       annotation.KrstenicaId,
+		Kind:              string(annotation.Kind),
+		NoteDate:          annotation.NoteDate,
+		Text:              annotation.Text,
+		CreatedById:       int64Ptr(annotation.CreatedById),
+		CreatedByUsername: annotation.CreatedByUsername,
+		CreatedAt:         annotation.CreatedAt,
+	}
+}
+
+func validateKrstenicaAnnotationCreateRequest(req *dto.KrstenicaAnnotationCreateReq) error {
+	req.Kind = strings.TrimSpace(req.Kind)
+	if !model.AnnotationKind(req.Kind).IsValid() {
+		return errorx.GetValidationError("Annotation", "validation", "Kind must be one of marriage, monastic, name_change, death, other")
+	}
+	if req.NoteDate.IsZero() {
+		return errorx.GetValidationError("Annotation", "validation", "Date of annotation is required")
+	}
+	req.Text = strings.TrimSpace(req.Text)
+	if utf8.RuneCountInString(req.Text) > 1000 {
+		return errorx.GetValidationError("Annotation", "validation", "Text of annotation can not be longer than 1000 characters")
+	}
+
+	return nil
+}
+
+func validateKrstenicaAnnotationUpdateRequest(req *dto.KrstenicaAnnotationUpdateReq) (map[string]interface{}, error) {
+	updates := map[string]interface{}{}
+
+	if req.Kind != nil {
+		kind := model.AnnotationKind(strings.TrimSpace(*req.Kind))
+		if !kind.IsValid() {
+			return nil, errorx.GetValidationError("Annotation", "validation", "Kind must be one of marriage, monastic, name_change, death, other")
+		}
+		updates["kind"] = kind
+	}
+	if req.NoteDate != nil {
+		if req.NoteDate.IsZero() {
+			return nil, errorx.GetValidationError("Annotation", "validation", "Date of annotation is required")
+		}
+		updates["note_date"] = *req.NoteDate
+	}
+	if req.Text != nil {
+		text := strings.TrimSpace(*req.Text)
+		if utf8.RuneCountInString(text) > 1000 {
+			return nil, errorx.GetValidationError("Annotation", "validation", "Text of annotation can not be longer than 1000 characters")
+		}
+		updates["text"] = text
+	}
+
+	return updates, nil
+}
@@ -0,0 +1,322 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/jpeg"
+	_ "image/png"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/tiff"
+
+	"krstenica/internal/dto"
+	"krstenica/internal/errorx"
+	"krstenica/internal/model"
+	"krstenica/internal/repository"
+	"krstenica/internal/requestctx"
+)
+
+const (
+	attachmentThumbnailSize = 320
+	// attachmentMaxThumbnailPixels štiti memoriju od ogromnih skenova; oni ostaju bez sličice.
+	attachmentMaxThumbnailPixels = 80_000_000
+)
+
+// attachmentExtensions su dozvoljeni tipovi skenova i ekstenzija pod kojom se čuvaju.
+var attachmentExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/tiff":      ".tif",
+	"application/pdf": ".pdf",
+}
+
+func (s *service) ListKrstenicaAttachments(ctx context.Context, krstenicaID int64) ([]*dto.KrstenicaAttachment, error) {
+	if _, err := s.getPermittedKrstenica(ctx, krstenicaID); err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.repo.ListKrstenicaAttachments(ctx, krstenicaID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	res := make([]*dto.KrstenicaAttachment, len(attachments))
+	for i := range attachments {
+		res[i] = makeKrstenicaAttachmentResponse(&attachments[i])
+	}
+	return res, nil
+}
+
+func (s *service) CreateKrstenicaAttachment(ctx context.Context, krstenicaID int64, fileName string, content []byte) (*dto.KrstenicaAttachment, error) {
+	krstenica, err := s.getPermittedKrstenica(ctx, krstenicaID)
+	if err != nil {
+		return nil, err
+	}
+	// obrisana krštenica je u korpi i ne prima nove priloge
+	if krstenica.Status == string(model.KrstenicaStatusDeleted) {
+		return nil, errorx.ErrKrstenicaNotFound
+	}
+
+	fileName, contentType, err := s.validateKrstenicaAttachment(fileName, content)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	var thumbnail []byte
+	if strings.HasPrefix(contentType, "image/") {
+		thumbnail, err = makeAttachmentThumbnail(content)
+		if err != nil {
+			log.Println(err)
+			return nil, errorx.GetValidationError("Attachment", "validation", "Image can not be read")
+		}
+	}
+
+	name, err := randomAttachmentName()
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	dir := filepath.Join("krstenice", strconv.FormatInt(krstenicaID, 10))
+	storageKey := filepath.ToSlash(filepath.Join(dir, name+attachmentExtensions[contentType]))
+	if err := s.writeAttachmentFile(storageKey, content); err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	thumbnailKey := ""
+	if len(thumbnail) > 0 {
+		thumbnailKey = filepath.ToSlash(filepath.Join(dir, name+"_thumb.jpg"))
+		if err := s.writeAttachmentFile(thumbnailKey, thumbnail); err != nil {
+			log.Println(err)
+			return nil, err
+		}
+	}
+
+	checksum := sha256.Sum256(content)
+	attachment := &model.KrstenicaAttachment{
+		KrstenicaId:  krstenicaID,
+		FileName:     fileName,
+		ContentType:  contentType,
+		SizeBytes:    int64(len(content)),
+		StorageKey:   storageKey,
+		ThumbnailKey: thumbnailKey,
+		Checksum:     hex.EncodeToString(checksum[:]),
+		Status:       model.AttachmentStatusActive,
+		CreatedAt:    time.Now(),
+	}
+	if user, ok := requestctx.UserFromContext(ctx); ok {
+		if user.ID > 0 {
+			attachment.UploadedById = sql.NullInt64{Valid: true, Int64: user.ID}
+		}
+		attachment.UploadedByUsername = user.Username
+	}
+
+	var res *dto.KrstenicaAttachment
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		newAttachment, err := txRepo.CreateKrstenicaAttachment(ctx, attachment)
+		if err != nil {
+			return err
+		}
+		res = makeKrstenicaAttachmentResponse(newAttachment)
+		return s.recordAudit(ctx, txRepo, model.AuditEntityAttachment, res.ID, model.AuditActionCreate, nil, res)
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// OpenKrstenicaAttachment otvara fajl priloga (ili njegovu sličicu) za slanje klijentu.
+func (s *service) OpenKrstenicaAttachment(ctx context.Context, krstenicaID, id int64, thumbnail bool) (*dto.KrstenicaAttachment, *os.File, error) {
+	attachment, err := s.getAttachmentOfKrstenica(ctx, krstenicaID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key := attachment.StorageKey
+	if thumbnail {
+		if attachment.ThumbnailKey == "" {
+			return nil, nil, errorx.ErrAttachmentNotFound
+		}
+		key = attachment.ThumbnailKey
+	}
+
+	file, err := os.Open(s.attachmentPath(key))
+	if err != nil {
+		log.Println(err)
+		if os.IsNotExist(err) {
+			return nil, nil, errorx.ErrAttachmentNotFound
+		}
+		return nil, nil, err
+	}
+
+	return makeKrstenicaAttachmentResponse(attachment), file, nil
+}
+
+func (s *service) DeleteKrstenicaAttachment(ctx context.Context, krstenicaID, id int64) error {
+	current, err := s.getAttachmentOfKrstenica(ctx, krstenicaID, id)
+	if err != nil {
+		return err
+	}
+
+	updates := map[string]interface{}{}
+	updates["status"] = model.AttachmentStatusDeleted
+
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if err := txRepo.UpdateKrstenicaAttachment(ctx, id, updates); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, txRepo, model.AuditEntityAttachment, id, model.AuditActionDelete, makeKrstenicaAttachmentResponse(current), nil)
+	})
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) getAttachmentOfKrstenica(ctx context.Context, krstenicaID, id int64) (*model.KrstenicaAttachment, error) {
+	if _, err := s.getPermittedKrstenica(ctx, krstenicaID); err != nil {
+		return nil, err
+	}
+
+	attachment, err := s.repo.GetKrstenicaAttachmentByID(ctx, id)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if attachment.KrstenicaId != krstenicaID {
+		return nil, errorx.ErrAttachmentNotFound
+	}
+	return attachment, nil
+}
+
+// AttachmentMaxSizeBytes je najveća dozvoljena veličina jednog priloga.
+func (s *service) AttachmentMaxSizeBytes() int64 {
+	return s.conf.Attachments.MaxSizeMB * 1024 * 1024
+}
+
+func (s *service) validateKrstenicaAttachment(fileName string, content []byte) (string, string, error) {
+	if len(content) == 0 {
+		return "", "", errorx.GetValidationError("Attachment", "validation", "File is empty")
+	}
+	if int64(len(content)) > s.AttachmentMaxSizeBytes() {
+		return "", "", errorx.GetValidationError("Attachment", "validation", fmt.Sprintf("File can not be larger than %d MB", s.conf.Attachments.MaxSizeMB))
+	}
+
+	contentType := detectAttachmentContentType(content)
+	if _, ok := attachmentExtensions[contentType]; !ok {
+		return "", "", errorx.GetValidationError("Attachment", "validation", "Only JPEG, PNG, TIFF and PDF files are allowed")
+	}
+
+	fileName = strings.TrimSpace(filepath.Base(strings.ReplaceAll(fileName, "\\", "/")))
+	if fileName == "" || fileName == "." || fileName == "/" {
+		fileName = "sken" + attachmentExtensions[contentType]
+	}
+	if utf8.RuneCountInString(fileName) > 255 {
+		return "", "", errorx.GetValidationError("Attachment", "validation", "File name can not be longer than 255 characters")
+	}
+
+	return fileName, contentType, nil
+}
+
+// detectAttachmentContentType određuje tip po sadržaju fajla, a ne po imenu koje šalje klijent.
+func detectAttachmentContentType(content []byte) string {
+	if bytes.HasPrefix(content, []byte("II*\x00")) || bytes.HasPrefix(content, []byte("MM\x00*")) {
+		return "image/tiff"
+	}
+	contentType := http.DetectContentType(content)
+	if idx := strings.Index(contentType, ";"); idx >= 0 {
+		contentType = contentType[:idx]
+	}
+	return contentType
+}
+
+// makeAttachmentThumbnail pravi JPEG sličicu za galeriju; prevelike slike ostaju bez sličice.
+func makeAttachmentThumbnail(content []byte) ([]byte, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, fmt.Errorf("invalid image size %dx%d", config.Width, config.Height)
+	}
+	if config.Width*config.Height > attachmentMaxThumbnailPixels {
+		return nil, nil
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+
+	width, height := config.Width, config.Height
+	if width > height {
+		height = max(1, height*attachmentThumbnailSize/width)
+		width = attachmentThumbnailSize
+	} else {
+		width = max(1, width*attachmentThumbnailSize/height)
+		height = attachmentThumbnailSize
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Src, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func randomAttachmentName() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (s *service) attachmentPath(key string) string {
+	return filepath.Join(s.conf.Attachments.Dir, filepath.FromSlash(key))
+}
+
+func (s *service) writeAttachmentFile(key string, content []byte) error {
+	path := s.attachmentPath(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0640)
+}
+
+func makeKrstenicaAttachmentResponse(attachment *model.KrstenicaAttachment) *dto.KrstenicaAttachment {
+	return &dto.KrstenicaAttachment{
+		ID:                 attachment.ID,
+		KrstenicaId:        attachment.KrstenicaId,
+		FileName:           attachment.FileName,
+		ContentType:        attachment.ContentType,
+		SizeBytes:          attachment.SizeBytes,
+		HasThumbnail:       attachment.ThumbnailKey != "",
+		Checksum:           attachment.Checksum,
+		UploadedById:       int64Ptr(attachment.UploadedById),
+		UploadedByUsername: attachment.UploadedByUsername,
+		CreatedAt:          attachment.CreatedAt,
+	}
+}
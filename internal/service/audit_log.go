@@ -0,0 +1,171 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"reflect"
+	"sort"
+	"time"
+
+	"krstenica/internal/dto"
+	"krstenica/internal/model"
+	"krstenica/internal/repository"
+	"krstenica/internal/requestctx"
+	"krstenica/pkg"
+)
+
+// auditPasswordMask zamenjuje lozinku u zapisu; beleži se samo da je promenjena.
+const auditPasswordMask = "********"
+
+// recordAudit upisuje izmenu entiteta u audit_log kroz repo transakcije u kojoj
+// je izmena sačuvana. before je stanje pre izmene (nil kod kreiranja), after
+// stanje posle (nil kod brisanja). Greška upisa poništava i samu izmenu.
+func (s *service) recordAudit(ctx context.Context, repo repository.Repo, entity string, entityID int64, action model.AuditAction, before, after interface{}) error {
+	changes := diffAuditFields(auditFields(before), auditFields(after))
+	if len(changes) == 0 && action == model.AuditActionUpdate {
+		return nil
+	}
+
+	payload, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	entry := &model.AuditLog{
+		Entity:    entity,
+		EntityId:  entityID,
+		Action:    action,
+		Changes:   string(payload),
+		CreatedAt: time.Now(),
+	}
+	if user, ok := requestctx.UserFromContext(ctx); ok {
+		if user.ID > 0 {
+			entry.UserId = sql.NullInt64{Valid: true, Int64: user.ID}
+		}
+		entry.Username = user.Username
+	}
+
+	return repo.CreateAuditLog(ctx, entry)
+}
+
+func (s *service) ListAuditLogs(ctx context.Context, filterAndSort *pkg.FilterAndSort) ([]*dto.AuditLog, int64, error) {
+	entries, totalCount, err := s.repo.ListAuditLogs(ctx, filterAndSort)
+	if err != nil {
+		log.Println(err)
+		return nil, 0, err
+	}
+
+	res := make([]*dto.AuditLog, len(entries))
+	for i := range entries {
+		res[i] = makeAuditLogResponse(&entries[i])
+	}
+	return res, totalCount, nil
+}
+
+// auditFields pretvara DTO (ili gotovu mapu) u mapu polja po JSON imenima.
+func auditFields(v interface{}) map[string]interface{} {
+	if v == nil {
+		return nil
+	}
+	if fields, ok := v.(map[string]interface{}); ok {
+		return fields
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return nil
+	}
+
+	payload, err := json.Marshal(v)
+	if err != nil {
+		log.Println("audit log:", err)
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	fields := map[string]interface{}{}
+	if err := decoder.Decode(&fields); err != nil {
+		log.Println("audit log:", err)
+		return nil
+	}
+	return fields
+}
+
+func diffAuditFields(before, after map[string]interface{}) map[string]model.AuditChange {
+	changes := map[string]model.AuditChange{}
+	for field, oldValue := range before {
+		newValue, ok := after[field]
+		if after != nil && !ok {
+			continue
+		}
+		if reflect.DeepEqual(oldValue, newValue) || (isEmptyAuditValue(oldValue) && isEmptyAuditValue(newValue)) {
+			continue
+		}
+		changes[field] = model.AuditChange{Old: oldValue, New: newValue}
+	}
+	for field, newValue := range after {
+		if _, ok := before[field]; ok {
+			continue
+		}
+		if isEmptyAuditValue(newValue) {
+			continue
+		}
+		changes[field] = model.AuditChange{New: newValue}
+	}
+	return changes
+}
+
+func isEmptyAuditValue(v interface{}) bool {
+	switch value := v.(type) {
+	case nil:
+		return true
+	case string:
+		return value == "" || value == "0001-01-01T00:00:00Z"
+	case []interface{}:
+		return len(value) == 0
+	case map[string]interface{}:
+		return len(value) == 0
+	}
+	return false
+}
+
+func makeAuditLogResponse(entry *model.AuditLog) *dto.AuditLog {
+	res := &dto.AuditLog{
+		ID:        entry.ID,
+		Entity:    entry.Entity,
+		EntityId:  entry.EntityId,
+		Action:    string(entry.Action),
+		UserId:    int64Ptr(entry.UserId),
+		Username:  entry.Username,
+		Changes:   []dto.AuditFieldChange{},
+		CreatedAt: entry.CreatedAt,
+	}
+
+	changes := map[string]model.AuditChange{}
+	if err := json.Unmarshal([]byte(entry.Changes), &changes); err != nil {
+		log.Println("audit log:", err)
+		return res
+	}
+	res.Changes = makeAuditFieldChanges(changes)
+	return res
+}
+
+// makeAuditFieldChanges vraća izmene polja sortirane po nazivu polja.
+func makeAuditFieldChanges(changes map[string]model.AuditChange) []dto.AuditFieldChange {
+	fields := make([]string, 0, len(changes))
+	for field := range changes {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	res := make([]dto.AuditFieldChange, 0, len(fields))
+	for _, field := range fields {
+		res = append(res, dto.AuditFieldChange{
+			Field: field,
+			Old:   changes[field].Old,
+			New:   changes[field].New,
+		})
+	}
+	return res
+}
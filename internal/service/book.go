@@ -0,0 +1,426 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"krstenica/internal/dto"
+	"krstenica/internal/errorx"
+	"krstenica/internal/model"
+	"krstenica/internal/repository"
+	"krstenica/pkg"
+)
+
+func (s *service) DeleteBook(ctx context.Context, id int64) error {
+	current, err := s.repo.GetBookByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	updates := map[string]interface{}{}
+	updates["status"] = model.BookStatusDeleted
+
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if err := txRepo.UpdateBook(ctx, id, updates); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, txRepo, model.AuditEntityBook, id, model.AuditActionDelete, makeBookResponse(current), nil)
+	})
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) UpdateBook(ctx context.Context, id int64, bookReq *dto.BookUpdateReq) (*dto.Book, error) {
+	current, err := s.repo.GetBookByID(ctx, id)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	updates, err := validateBookUpdateRequest(current, bookReq)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if tampleID, ok := updates["tample_id"].(int64); ok && tampleID != current.TampleId {
+		if current.EntriesCount > 0 {
+			return nil, errorx.GetValidationError("Book", "validation", "Tample can not be changed for a book that already has entries")
+		}
+		if _, err := s.repo.GetTampleByID(ctx, tampleID); err != nil {
+			return nil, err
+		}
+	}
+
+	var res *dto.Book
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if err := txRepo.UpdateBook(ctx, id, updates); err != nil {
+			return err
+		}
+		book, err := txRepo.GetBookByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		res = makeBookResponse(book)
+		return s.recordAudit(ctx, txRepo, model.AuditEntityBook, id, model.AuditActionUpdate, makeBookResponse(current), res)
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *service) CreateBook(ctx context.Context, bookReq *dto.BookCreateReq) (*dto.Book, error) {
+	err := validateBookCreateRequest(bookReq)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if _, err := s.repo.GetTampleByID(ctx, bookReq.TampleId); err != nil {
+		return nil, err
+	}
+
+	book := &model.BookPost{
+		Name:           bookReq.Name,
+		TampleId:       bookReq.TampleId,
+		YearFrom:       bookReq.YearFrom,
+		PageCapacity:   bookReq.PageCapacity,
+		EntriesPerPage: bookReq.EntriesPerPage,
+		State:          model.BookStateOpen,
+		Status:         model.BookStatusActive,
+		CreatedAt:      sql.NullTime{Valid: true, Time: time.Now()},
+	}
+	if bookReq.YearTo != nil {
+		book.YearTo = sql.NullInt64{Valid: true, Int64: *bookReq.YearTo}
+	}
+
+	var res *dto.Book
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		newBook, err := txRepo.CreateBook(ctx, book)
+		if err != nil {
+			return err
+		}
+		res = makeBookResponse(newBook)
+		return s.recordAudit(ctx, txRepo, model.AuditEntityBook, res.ID, model.AuditActionCreate, nil, res)
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *service) GetBookByID(ctx context.Context, id int64) (*dto.Book, error) {
+	book, err := s.repo.GetBookByID(ctx, id)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return makeBookResponse(book), nil
+}
+
+func (s *service) ListBooks(ctx context.Context, filterAndSort *pkg.FilterAndSort) ([]*dto.Book, int64, error) {
+	books, totalCount, err := s.repo.ListBooks(ctx, filterAndSort)
+	if err != nil {
+		log.Println(err)
+		return nil, 0, err
+	}
+
+	res := make([]*dto.Book, len(books))
+	for i := range books {
+		res[i] = makeBookResponse(&books[i])
+	}
+	return res, totalCount, nil
+}
+
+// resolveKrstenicaBook pronalazi knjigu za novu krštenicu (po ID-u ili po
+// nazivu u okviru hrama) i proverava da upis pripada njenom hramu i opsegu godina.
+func (s *service) resolveKrstenicaBook(ctx context.Context, krstenicaReq *dto.KrstenicaCreateReq) (*model.Book, error) {
+	if krstenicaReq.BookId != nil && *krstenicaReq.BookId <= 0 {
+		krstenicaReq.BookId = nil
+	}
+
+	var book *model.Book
+	if krstenicaReq.BookId != nil {
+		found, err := s.repo.GetBookByID(ctx, *krstenicaReq.BookId)
+		if err != nil {
+			if errors.Is(err, errorx.ErrBookNotFound) {
+				return nil, errorx.GetValidationError("Krstenica", "validation", "Selected book does not exist")
+			}
+			return nil, err
+		}
+		if found.Status == model.BookStatusDeleted {
+			return nil, errorx.GetValidationError("Krstenica", "validation", "Selected book does not exist")
+		}
+		book = found
+	} else {
+		name := strings.TrimSpace(krstenicaReq.Book)
+		if name == "" || krstenicaReq.TampleId <= 0 {
+			return nil, nil
+		}
+		books, _, err := s.repo.ListBooks(ctx, &pkg.FilterAndSort{
+			Filters: map[pkg.FilterKey][]string{
+				{Property: "tample_id", Operator: "eq"}: {strconv.FormatInt(krstenicaReq.TampleId, 10)},
+				{Property: "name", Operator: "eq"}:      {name},
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(books) == 0 {
+			return nil, nil
+		}
+		book = &books[0]
+	}
+
+	if krstenicaReq.TampleId <= 0 {
+		krstenicaReq.TampleId = book.TampleId
+	} else if krstenicaReq.TampleId != book.TampleId {
+		return nil, errorx.GetValidationError("Krstenica", "validation", "Selected book does not belong to the selected tample")
+	}
+
+	year := krstenicaReq.Baptism.Year()
+	if krstenicaReq.Baptism.IsZero() {
+		year = time.Now().Year()
+	}
+	if !book.CoversYear(year) {
+		return nil, errorx.GetValidationError("Krstenica", "validation", "Baptism year is outside of the year range of the selected book")
+	}
+
+	// rucno zadata strana i broj prolaze iste provere kao automatsko numerisanje
+	if book.State != model.BookStateOpen {
+		return nil, errorx.ErrBookClosed
+	}
+	if (krstenicaReq.Page > 0) != (krstenicaReq.CurrentNumber > 0) {
+		return nil, errorx.GetValidationError("Krstenica", "validation", "Page and current number must be set together or both left empty")
+	}
+	if krstenicaReq.Page > book.PageCapacity {
+		return nil, errorx.GetValidationError("Krstenica", "validation", fmt.Sprintf("Page %d exceeds the book capacity of %d pages", krstenicaReq.Page, book.PageCapacity))
+	}
+
+	krstenicaReq.Book = book.Name
+	return book, nil
+}
+
+// resolveKrstenicaBookUpdate proverava novo mesto upisa (knjiga, strana, broj,
+// hram, godina krštenja) istim pravilima kao pri unosu i usklađuje naziv
+// knjige, knjigu i hram u izmenama.
+func (s *service) resolveKrstenicaBookUpdate(ctx context.Context, current *model.Krstenica, krstenicaReq *dto.KrstenicaUpdateReq, updates map[string]interface{}) error {
+	placement := &dto.KrstenicaCreateReq{
+		Book:          current.Book,
+		Page:          current.Page,
+		CurrentNumber: current.CurrentNumber,
+		TampleId:      current.TampleId.Int64,
+		Baptism:       current.Baptism.Time,
+	}
+	if current.BookId.Valid {
+		bookID := current.BookId.Int64
+		placement.BookId = &bookID
+	}
+	if krstenicaReq.Book != nil {
+		placement.Book = *krstenicaReq.Book
+		// novi naziv bez knjige po ID-u premešta upis u knjigu tog naziva
+		if krstenicaReq.BookId == nil && strings.TrimSpace(*krstenicaReq.Book) != strings.TrimSpace(current.Book) {
+			placement.BookId = nil
+		}
+	}
+	if krstenicaReq.BookId != nil {
+		placement.BookId = krstenicaReq.BookId
+	}
+	if krstenicaReq.Page != nil {
+		placement.Page = *krstenicaReq.Page
+	}
+	if krstenicaReq.CurrentNumber != nil {
+		placement.CurrentNumber = *krstenicaReq.CurrentNumber
+	}
+	if krstenicaReq.TampleId != nil {
+		placement.TampleId = *krstenicaReq.TampleId
+	}
+	if krstenicaReq.Baptism != nil {
+		placement.Baptism = *krstenicaReq.Baptism
+	}
+
+	book, err := s.resolveKrstenicaBook(ctx, placement)
+	if err != nil {
+		return err
+	}
+	if book == nil {
+		if strings.TrimSpace(placement.Book) == "" || placement.Page <= 0 || placement.CurrentNumber <= 0 {
+			return errorx.GetValidationError("Krstenica", "validation", "Book, page and current number are required when no registry book is selected")
+		}
+		updates["book_id"] = nil
+		return nil
+	}
+
+	updates["book_id"] = book.ID
+	updates["book"] = book.Name
+	updates["tample_id"] = book.TampleId
+	return nil
+}
+
+// krstenicaPlacementChanged javlja da izmena menja knjigu, stranu, broj, hram
+// ili godinu krštenja upisa.
+func krstenicaPlacementChanged(current *model.Krstenica, krstenicaReq *dto.KrstenicaUpdateReq) bool {
+	if krstenicaReq.Book != nil && strings.TrimSpace(*krstenicaReq.Book) != strings.TrimSpace(current.Book) {
+		return true
+	}
+	if krstenicaReq.BookId != nil {
+		if *krstenicaReq.BookId <= 0 {
+			if current.BookId.Valid {
+				return true
+			}
+		} else if !current.BookId.Valid || *krstenicaReq.BookId != current.BookId.Int64 {
+			return true
+		}
+	}
+	if krstenicaReq.Page != nil && *krstenicaReq.Page != current.Page {
+		return true
+	}
+	if krstenicaReq.CurrentNumber != nil && *krstenicaReq.CurrentNumber != current.CurrentNumber {
+		return true
+	}
+	if krstenicaReq.TampleId != nil && *krstenicaReq.TampleId != current.TampleId.Int64 {
+		return true
+	}
+	return krstenicaReq.Baptism != nil && krstenicaReq.Baptism.Year() != current.Baptism.Time.Year()
+}
+
+func makeBookResponse(book *model.Book) *dto.Book {
+	return &dto.Book{
+		ID:             book.ID,
+		Name:           book.Name,
+		TampleId:       book.TampleId,
+		TampleName:     book.TampleName,
+		TampleCity:     book.TampleCity,
+		YearFrom:       book.YearFrom,
+		YearTo:         int64Ptr(book.YearTo),
+		PageCapacity:   book.PageCapacity,
+		EntriesPerPage: book.EntriesPerPage,
+		State:          string(book.State),
+		EntriesCount:   book.EntriesCount,
+		LastPage:       book.LastPage.Int64,
+		LastNumber:     book.LastNumber.Int64,
+		Status:         string(book.Status),
+		CreatedAt:      book.CreatedAt.Time,
+	}
+}
+
+func validateBookCreateRequest(bookReq *dto.BookCreateReq) error {
+	bookReq.Name = strings.TrimSpace(bookReq.Name)
+	if bookReq.Name == "" {
+		return errorx.GetValidationError("Book", "validation", "Name of book is required")
+	}
+	if len(bookReq.Name) > 100 {
+		return errorx.GetValidationError("Book", "validation", "Name of book can not be longer than 100 characters")
+	}
+	if bookReq.TampleId <= 0 {
+		return errorx.GetValidationError("Book", "validation", "Tample is required")
+	}
+	if bookReq.YearFrom <= 0 {
+		return errorx.GetValidationError("Book", "validation", "Year from is required")
+	}
+	if bookReq.YearTo != nil && *bookReq.YearTo <= 0 {
+		bookReq.YearTo = nil
+	}
+	if bookReq.YearTo != nil && *bookReq.YearTo < bookReq.YearFrom {
+		return errorx.GetValidationError("Book", "validation", "Year to can not be before year from")
+	}
+	if bookReq.PageCapacity == 0 {
+		bookReq.PageCapacity = 200
+	}
+	if bookReq.EntriesPerPage == 0 {
+		bookReq.EntriesPerPage = 10
+	}
+	if bookReq.PageCapacity < 0 {
+		return errorx.GetValidationError("Book", "validation", "Page capacity must be greater than zero")
+	}
+	if bookReq.EntriesPerPage < 0 {
+		return errorx.GetValidationError("Book", "validation", "Entries per page must be greater than zero")
+	}
+
+	return nil
+}
+
+func validateBookUpdateRequest(current *model.Book, bookReq *dto.BookUpdateReq) (map[string]interface{}, error) {
+	updates := map[string]interface{}{}
+
+	if bookReq.Name != nil {
+		name := strings.TrimSpace(*bookReq.Name)
+		if name == "" {
+			return nil, errorx.GetValidationError("Book", "validation", "Name of book is required")
+		}
+		if len(name) > 100 {
+			return nil, errorx.GetValidationError("Book", "validation", "Name of book can not be longer than 100 characters")
+		}
+		updates["name"] = name
+	}
+	if bookReq.TampleId != nil {
+		if *bookReq.TampleId <= 0 {
+			return nil, errorx.GetValidationError("Book", "validation", "Tample is required")
+		}
+		updates["tample_id"] = *bookReq.TampleId
+	}
+
+	yearFrom := current.YearFrom
+	if bookReq.YearFrom != nil {
+		if *bookReq.YearFrom <= 0 {
+			return nil, errorx.GetValidationError("Book", "validation", "Year from is required")
+		}
+		yearFrom = *bookReq.YearFrom
+		updates["year_from"] = yearFrom
+	}
+	yearTo := current.YearTo
+	if bookReq.YearTo != nil {
+		if *bookReq.YearTo <= 0 {
+			yearTo = sql.NullInt64{}
+			updates["year_to"] = nil
+		} else {
+			yearTo = sql.NullInt64{Valid: true, Int64: *bookReq.YearTo}
+			updates["year_to"] = *bookReq.YearTo
+		}
+	}
+	if yearTo.Valid && yearTo.Int64 < yearFrom {
+		return nil, errorx.GetValidationError("Book", "validation", "Year to can not be before year from")
+	}
+
+	if bookReq.PageCapacity != nil {
+		if *bookReq.PageCapacity <= 0 {
+			return nil, errorx.GetValidationError("Book", "validation", "Page capacity must be greater than zero")
+		}
+		if current.LastPage.Valid && *bookReq.PageCapacity < current.LastPage.Int64 {
+			return nil, errorx.GetValidationError("Book", "validation", "Page capacity can not be smaller than the last used page")
+		}
+		updates["page_capacity"] = *bookReq.PageCapacity
+	}
+	if bookReq.EntriesPerPage != nil {
+		if *bookReq.EntriesPerPage <= 0 {
+			return nil, errorx.GetValidationError("Book", "validation", "Entries per page must be greater than zero")
+		}
+		updates["entries_per_page"] = *bookReq.EntriesPerPage
+	}
+	if bookReq.State != nil {
+		state := model.BookState(strings.TrimSpace(*bookReq.State))
+		if state != model.BookStateOpen && state != model.BookStateClosed {
+			return nil, errorx.GetValidationError("Book", "validation", "State must be open or closed")
+		}
+		updates["state"] = state
+	}
+	if bookReq.Status != nil {
+		updates["status"] = *bookReq.Status
+	}
+
+	return updates, nil
+}
@@ -0,0 +1,411 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/xuri/excelize/v2"
+
+	"krstenica/internal/dto"
+	"krstenica/internal/errorx"
+	"krstenica/internal/model"
+	"krstenica/internal/repository"
+)
+
+// certificateLayoutMaxOffsetMM ogranicava pomeraj teksta na obrascu.
+const certificateLayoutMaxOffsetMM = 100.0
+
+// krstenicaLayoutFields su podaci krstenice koji se mogu postaviti na obrazac.
+// Kljucevi se popunjavaju u handler-u koji pravi XLSX i PDF uverenje.
+var krstenicaLayoutFields = []dto.CertificateLayoutField{
+	{Key: "book", Label: "Књига"},
+	{Key: "page", Label: "Страна"},
+	{Key: "current_number", Label: "Текући број"},
+	{Key: "eparhija_name", Label: "Епархија"},
+	{Key: "tample_name", Label: "Храм"},
+	{Key: "tample_city", Label: "Место храма"},
+	{Key: "tample", Label: "Место и храм"},
+	{Key: "birth_date", Label: "Датум рођења", IsDate: true},
+	{Key: "baptism", Label: "Датум крштења", IsDate: true},
+	{Key: "place_of_birth", Label: "Место рођења (без општине)"},
+	{Key: "place_and_municipality_of_birth", Label: "Место рођења (исто као општина)"},
+	{Key: "municipality_of_birth", Label: "Општина рођења"},
+	{Key: "first_name", Label: "Име детета"},
+	{Key: "gender", Label: "Пол"},
+	{Key: "parents", Label: "Родитељи"},
+	{Key: "parents_city", Label: "Место родитеља"},
+	{Key: "parents_religion", Label: "Вера родитеља"},
+	{Key: "birth_order", Label: "Које дете по реду"},
+	{Key: "is_church_married", Label: "Црквени брак родитеља"},
+	{Key: "is_twin", Label: "Близанац"},
+	{Key: "has_physical_disability", Label: "Телесна мана"},
+	{Key: "priest_first_name", Label: "Име свештеника"},
+	{Key: "priest_last_name", Label: "Презиме свештеника"},
+	{Key: "priest_title", Label: "Звање свештеника"},
+	{Key: "priest", Label: "Свештеник (име, презиме, звање)"},
+	{Key: "godparent_first_name", Label: "Име кума (или сви кумови)"},
+	{Key: "godparent_last_name", Label: "Презиме кума"},
+	{Key: "godparent_occupation", Label: "Занимање кума"},
+	{Key: "godparents", Label: "Кумови (име, презиме, занимање)"},
+	{Key: "godparents_city", Label: "Место кумова"},
+	{Key: "godparents_religion", Label: "Вера кумова"},
+	{Key: "anagrafa", Label: "Анаграфа"},
+	{Key: "remarks", Label: "Напомена и накнадне забелешке"},
+	{Key: "number_of_certificate", Label: "Број уверења"},
+	{Key: "certificate", Label: "Датум уверења", IsDate: true},
+	{Key: "town_of_certificate", Label: "Место издавања"},
+}
+
+var certificateLayoutTargets = []dto.CertificateLayoutOption{
+	{Key: model.CertificateLayoutTargetAll, Label: "XLSX и PDF"},
+	{Key: model.CertificateLayoutTargetXLSX, Label: "Само XLSX"},
+	{Key: model.CertificateLayoutTargetPDF, Label: "Само PDF"},
+}
+
+// certificateLayoutDateStyles su nacini ispisa datuma; prazan stil je "date".
+var certificateLayoutDateStyles = []dto.CertificateLayoutOption{
+	{Key: "date", Label: "Датум (2024 јануар 1)"},
+	{Key: "date_time", Label: "Датум и време (2024 јануар 1 у 10:30 часова)"},
+	{Key: "day_month", Label: "Дан и месец (01.01.)"},
+	{Key: "year", Label: "Година (24, пре 2000. цела)"},
+	{Key: "year_short", Label: "Година (24)"},
+}
+
+// KrstenicaLayoutFields vraca podatke krstenice dostupne za raspored uverenja.
+func KrstenicaLayoutFields() []dto.CertificateLayoutField {
+	return krstenicaLayoutFields
+}
+
+// CertificateLayoutTargets vraca izlaze na koje se celija rasporeda odnosi.
+func CertificateLayoutTargets() []dto.CertificateLayoutOption {
+	return certificateLayoutTargets
+}
+
+// CertificateLayoutDateStyles vraca podrzane stilove ispisa datuma.
+func CertificateLayoutDateStyles() []dto.CertificateLayoutOption {
+	return certificateLayoutDateStyles
+}
+
+func (s *service) ListCertificateLayouts(ctx context.Context) ([]*dto.CertificateLayout, error) {
+	layouts, err := s.repo.ListCertificateLayouts(ctx, model.CertificateLayoutKindKrstenica)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	res := make([]*dto.CertificateLayout, len(layouts))
+	for i := range layouts {
+		res[i] = makeCertificateLayoutResponse(&layouts[i])
+	}
+	return res, nil
+}
+
+func (s *service) GetCertificateLayoutByID(ctx context.Context, id int64) (*dto.CertificateLayout, error) {
+	layout, err := s.repo.GetCertificateLayoutByID(ctx, id)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return makeCertificateLayoutResponse(layout), nil
+}
+
+// GetDefaultCertificateLayout vraca raspored koji se koristi pri stampi krstenice.
+func (s *service) GetDefaultCertificateLayout(ctx context.Context) (*dto.CertificateLayout, error) {
+	layout, err := s.repo.GetDefaultCertificateLayout(ctx, model.CertificateLayoutKindKrstenica)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return makeCertificateLayoutResponse(layout), nil
+}
+
+// CreateCertificateLayout pravi novi raspored kao kopiju postojeceg
+// (podrazumevano tekuceg podrazumevanog rasporeda).
+func (s *service) CreateCertificateLayout(ctx context.Context, req *dto.CertificateLayoutCreateReq) (*dto.CertificateLayout, error) {
+	name, err := validateCertificateLayoutName(req.Name)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	var source *model.CertificateLayout
+	if req.CopyFromId != nil && *req.CopyFromId > 0 {
+		source, err = s.repo.GetCertificateLayoutByID(ctx, *req.CopyFromId)
+	} else {
+		source, err = s.repo.GetDefaultCertificateLayout(ctx, model.CertificateLayoutKindKrstenica)
+	}
+	if err != nil && !errors.Is(err, errorx.ErrCertificateLayoutNotFound) {
+		log.Println(err)
+		return nil, err
+	}
+
+	now := time.Now()
+	layout := &model.CertificateLayout{
+		Kind:      model.CertificateLayoutKindKrstenica,
+		Name:      name,
+		Status:    model.CertificateLayoutStatusActive,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if source != nil {
+		layout.Cells = source.Cells
+	} else {
+		// prvi raspored postaje podrazumevani
+		layout.IsDefault = true
+	}
+
+	var res *dto.CertificateLayout
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		created, err := txRepo.CreateCertificateLayout(ctx, layout)
+		if err != nil {
+			return err
+		}
+		res = makeCertificateLayoutResponse(created)
+		return s.recordAudit(ctx, txRepo, model.AuditEntityLayout, res.ID, model.AuditActionCreate, nil, res)
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// UpdateCertificateLayout menja naziv i sve celije rasporeda.
+func (s *service) UpdateCertificateLayout(ctx context.Context, id int64, req *dto.CertificateLayoutUpdateReq) (*dto.CertificateLayout, error) {
+	current, err := s.repo.GetCertificateLayoutByID(ctx, id)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	name, err := validateCertificateLayoutName(req.Name)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	cells, err := validateCertificateLayoutCells(req.Cells)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	var res *dto.CertificateLayout
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if err := txRepo.UpdateCertificateLayout(ctx, id, map[string]interface{}{"name": name}); err != nil {
+			return err
+		}
+		if err := txRepo.ReplaceCertificateLayoutCells(ctx, id, cells); err != nil {
+			return err
+		}
+		updated, err := txRepo.GetCertificateLayoutByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		res = makeCertificateLayoutResponse(updated)
+		return s.recordAudit(ctx, txRepo, model.AuditEntityLayout, id, model.AuditActionUpdate, makeCertificateLayoutResponse(current), res)
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// SetDefaultCertificateLayout odredjuje raspored koji se koristi pri stampi.
+func (s *service) SetDefaultCertificateLayout(ctx context.Context, id int64) (*dto.CertificateLayout, error) {
+	current, err := s.repo.GetCertificateLayoutByID(ctx, id)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if current.IsDefault {
+		return makeCertificateLayoutResponse(current), nil
+	}
+
+	var res *dto.CertificateLayout
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if err := txRepo.SetDefaultCertificateLayout(ctx, current.Kind, id); err != nil {
+			return err
+		}
+		updated, err := txRepo.GetCertificateLayoutByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		res = makeCertificateLayoutResponse(updated)
+		return s.recordAudit(ctx, txRepo, model.AuditEntityLayout, id, model.AuditActionUpdate, makeCertificateLayoutResponse(current), res)
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *service) DeleteCertificateLayout(ctx context.Context, id int64) error {
+	current, err := s.repo.GetCertificateLayoutByID(ctx, id)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	if current.IsDefault {
+		return errorx.ErrCertificateLayoutDefault
+	}
+
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if err := txRepo.UpdateCertificateLayout(ctx, id, map[string]interface{}{"status": model.CertificateLayoutStatusDeleted}); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, txRepo, model.AuditEntityLayout, id, model.AuditActionDelete, makeCertificateLayoutResponse(current), nil)
+	})
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	return nil
+}
+
+func validateCertificateLayoutName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errorx.GetValidationError("CertificateLayout", "validation", "name is required")
+	}
+	if utf8.RuneCountInString(name) > 255 {
+		return "", errorx.GetValidationError("CertificateLayout", "validation", "name is too long")
+	}
+	return name, nil
+}
+
+// validateCertificateLayoutCells proverava celije i vraca ih spremne za upis.
+// Ista celija sme biti zadata samo jednom za svaki izlaz (XLSX i PDF).
+func validateCertificateLayoutCells(cells []dto.CertificateLayoutCell) ([]model.CertificateLayoutCell, error) {
+	fields := map[string]dto.CertificateLayoutField{}
+	for _, field := range krstenicaLayoutFields {
+		fields[field.Key] = field
+	}
+	dateStyles := map[string]bool{}
+	for _, style := range certificateLayoutDateStyles {
+		dateStyles[style.Key] = true
+	}
+
+	fail := func(i int, message string) error {
+		return errorx.GetValidationError("CertificateLayout", "validation", fmt.Sprintf("row %d: %s", i+1, message))
+	}
+
+	used := map[string]bool{}
+	res := make([]model.CertificateLayoutCell, 0, len(cells))
+	for i, cell := range cells {
+		ref := strings.ToUpper(strings.TrimSpace(cell.Cell))
+		col, row, err := excelize.CellNameToCoordinates(ref)
+		if err != nil {
+			return nil, fail(i, fmt.Sprintf("invalid cell %q", cell.Cell))
+		}
+
+		field, ok := fields[strings.TrimSpace(cell.Field)]
+		if !ok {
+			return nil, fail(i, fmt.Sprintf("unknown field %q", cell.Field))
+		}
+
+		target := strings.TrimSpace(cell.Target)
+		if target == "" {
+			target = model.CertificateLayoutTargetAll
+		}
+		var outputs []string
+		switch target {
+		case model.CertificateLayoutTargetAll:
+			outputs = []string{model.CertificateLayoutTargetXLSX, model.CertificateLayoutTargetPDF}
+		case model.CertificateLayoutTargetXLSX, model.CertificateLayoutTargetPDF:
+			outputs = []string{target}
+		default:
+			return nil, fail(i, fmt.Sprintf("invalid target %q", cell.Target))
+		}
+		for _, output := range outputs {
+			key := output + ":" + ref
+			if used[key] {
+				return nil, fail(i, fmt.Sprintf("cell %s is already used for %s", ref, output))
+			}
+			used[key] = true
+		}
+
+		dateStyle := strings.TrimSpace(cell.DateStyle)
+		if dateStyle != "" {
+			if !field.IsDate {
+				return nil, fail(i, fmt.Sprintf("field %s is not a date", field.Key))
+			}
+			if !dateStyles[dateStyle] {
+				return nil, fail(i, fmt.Sprintf("invalid date style %q", cell.DateStyle))
+			}
+		}
+
+		fitTo := strings.ToUpper(strings.TrimSpace(cell.FitTo))
+		if fitTo != "" {
+			fitCol, fitRow, err := excelize.CellNameToCoordinates(fitTo)
+			if err != nil || fitRow != row || fitCol <= col {
+				return nil, fail(i, fmt.Sprintf("fit cell %q must be to the right of %s in the same row", cell.FitTo, ref))
+			}
+		}
+
+		if utf8.RuneCountInString(cell.Prefix) > 50 || utf8.RuneCountInString(cell.Suffix) > 50 {
+			return nil, fail(i, "prefix and suffix can have at most 50 characters")
+		}
+		if math.IsNaN(cell.OffsetX) || math.IsNaN(cell.OffsetY) ||
+			math.Abs(cell.OffsetX) > certificateLayoutMaxOffsetMM || math.Abs(cell.OffsetY) > certificateLayoutMaxOffsetMM {
+			return nil, fail(i, fmt.Sprintf("offsets must be between -%.0f and %.0f mm", certificateLayoutMaxOffsetMM, certificateLayoutMaxOffsetMM))
+		}
+
+		res = append(res, model.CertificateLayoutCell{
+			Cell:      ref,
+			Field:     field.Key,
+			Target:    target,
+			DateStyle: dateStyle,
+			Prefix:    cell.Prefix,
+			Suffix:    cell.Suffix,
+			Bold:      cell.Bold,
+			Wrap:      cell.Wrap,
+			FitTo:     fitTo,
+			OffsetX:   cell.OffsetX,
+			OffsetY:   cell.OffsetY,
+		})
+	}
+
+	return res, nil
+}
+
+func makeCertificateLayoutResponse(layout *model.CertificateLayout) *dto.CertificateLayout {
+	res := &dto.CertificateLayout{
+		ID:        layout.ID,
+		Kind:      layout.Kind,
+		Name:      layout.Name,
+		IsDefault: layout.IsDefault,
+		CreatedAt: layout.CreatedAt,
+		UpdatedAt: layout.UpdatedAt,
+		Cells:     make([]dto.CertificateLayoutCell, len(layout.Cells)),
+	}
+	for i, cell := range layout.Cells {
+		res.Cells[i] = dto.CertificateLayoutCell{
+			Cell:      cell.Cell,
+			Field:     cell.Field,
+			Target:    cell.Target,
+			DateStyle: cell.DateStyle,
+			Prefix:    cell.Prefix,
+			Suffix:    cell.Suffix,
+			Bold:      cell.Bold,
+			Wrap:      cell.Wrap,
+			FitTo:     cell.FitTo,
+			OffsetX:   cell.OffsetX,
+			OffsetY:   cell.OffsetY,
+		}
+	}
+	return res
+}
@@ -0,0 +1,642 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/xuri/excelize/v2"
+
+	"krstenica/internal/dto"
+	"krstenica/internal/errorx"
+	"krstenica/internal/model"
+	"krstenica/internal/repository"
+	"krstenica/internal/requestctx"
+)
+
+// Delovi verzije obrasca koji se mogu preuzeti.
+const (
+	CertificateTemplatePartTemplate   = "template"
+	CertificateTemplatePartPreview    = "preview"
+	CertificateTemplatePartBackground = "background"
+)
+
+const certificateTemplateXLSXContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// certificateTemplateImageExtensions su tipovi pozadinskih slika koje podrzavaju
+// i XLSX i PDF izlaz.
+var certificateTemplateImageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+}
+
+func (s *service) ListCertificateTemplates(ctx context.Context) ([]*dto.CertificateTemplate, error) {
+	templates, err := s.repo.ListCertificateTemplates(ctx, model.CertificateTemplateKindKrstenica)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	res := make([]*dto.CertificateTemplate, len(templates))
+	for i := range templates {
+		res[i], err = s.makeCertificateTemplateResponse(ctx, s.repo, &templates[i], false)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// GetCertificateTemplateByID vraca obrazac sa svim verzijama.
+func (s *service) GetCertificateTemplateByID(ctx context.Context, id int64) (*dto.CertificateTemplate, error) {
+	res, err := s.getCertificateTemplate(ctx, s.repo, id)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return res, nil
+}
+
+// getCertificateTemplate cita obrazac sa svim verzijama kroz zadati repo
+// (i unutar transakcije izmene).
+func (s *service) getCertificateTemplate(ctx context.Context, repo repository.Repo, id int64) (*dto.CertificateTemplate, error) {
+	template, err := repo.GetCertificateTemplateByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.makeCertificateTemplateResponse(ctx, repo, template, true)
+}
+
+// CreateCertificateTemplate pravi obrazac sa prvom verzijom; XLSX obrazac je obavezan.
+func (s *service) CreateCertificateTemplate(ctx context.Context, req *dto.CertificateTemplateCreateReq) (*dto.CertificateTemplate, error) {
+	name, err := validateCertificateTemplateName(req.Name)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if req.Template == nil {
+		return nil, errorx.GetValidationError("CertificateTemplate", "validation", "XLSX template is required")
+	}
+
+	var res *dto.CertificateTemplate
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		now := time.Now()
+		created, err := txRepo.CreateCertificateTemplate(ctx, &model.CertificateTemplate{
+			Kind:      model.CertificateTemplateKindKrstenica,
+			Name:      name,
+			Status:    model.CertificateTemplateStatusActive,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+		if err != nil {
+			return err
+		}
+		id := created.ID
+		if _, err := s.createCertificateTemplateVersion(ctx, txRepo, id, nil, &req.CertificateTemplateVersionReq); err != nil {
+			return err
+		}
+		updated, err := s.getCertificateTemplate(ctx, txRepo, id)
+		if err != nil {
+			return err
+		}
+		res = updated
+		return s.recordAudit(ctx, txRepo, model.AuditEntityTemplate, id, model.AuditActionCreate, nil, res)
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// AddCertificateTemplateVersion dodaje novu verziju obrasca. Ranije verzije
+// ostaju sacuvane za ponovnu stampu vec izdatih uverenja.
+func (s *service) AddCertificateTemplateVersion(ctx context.Context, id int64, req *dto.CertificateTemplateVersionReq) (*dto.CertificateTemplate, error) {
+	current, err := s.GetCertificateTemplateByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Template == nil && req.PreviewTemplate == nil && req.Background == nil && !req.RemoveBackground &&
+		current.CurrentVersion != nil && req.BackgroundFullBleed == current.CurrentVersion.BackgroundFullBleed {
+		return nil, errorx.GetValidationError("CertificateTemplate", "validation", "new version has no changes")
+	}
+
+	previous, err := s.repo.GetLatestCertificateTemplateVersion(ctx, id)
+	if err != nil && !errors.Is(err, errorx.ErrCertificateTemplateNotFound) {
+		log.Println(err)
+		return nil, err
+	}
+	var res *dto.CertificateTemplate
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if _, err := s.createCertificateTemplateVersion(ctx, txRepo, id, previous, req); err != nil {
+			return err
+		}
+		updated, err := s.getCertificateTemplate(ctx, txRepo, id)
+		if err != nil {
+			return err
+		}
+		res = updated
+		return s.recordAudit(ctx, txRepo, model.AuditEntityTemplate, id, model.AuditActionUpdate, current, res)
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// UpdateCertificateTemplate menja naziv obrasca i eparhije i hramove kojima je dodeljen.
+func (s *service) UpdateCertificateTemplate(ctx context.Context, id int64, req *dto.CertificateTemplateUpdateReq) (*dto.CertificateTemplate, error) {
+	current, err := s.GetCertificateTemplateByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := validateCertificateTemplateName(req.Name)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	eparhijaIDs := uniquePositiveIDs(req.EparhijaIds)
+	for _, eparhijaID := range eparhijaIDs {
+		if _, err := s.repo.GetEparhijeByID(ctx, eparhijaID); err != nil {
+			log.Println(err)
+			return nil, err
+		}
+	}
+	tampleIDs := uniquePositiveIDs(req.TampleIds)
+	for _, tampleID := range tampleIDs {
+		if _, err := s.repo.GetTampleByID(ctx, tampleID); err != nil {
+			log.Println(err)
+			return nil, err
+		}
+	}
+
+	var res *dto.CertificateTemplate
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if err := txRepo.UpdateCertificateTemplate(ctx, id, map[string]interface{}{"name": name}); err != nil {
+			return err
+		}
+		if err := txRepo.ReplaceCertificateTemplateAssignments(ctx, id, eparhijaIDs, tampleIDs); err != nil {
+			return err
+		}
+		updated, err := s.getCertificateTemplate(ctx, txRepo, id)
+		if err != nil {
+			return err
+		}
+		res = updated
+		return s.recordAudit(ctx, txRepo, model.AuditEntityTemplate, id, model.AuditActionUpdate, current, res)
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// SetDefaultCertificateTemplate odredjuje obrazac za stampu krstenica kojima
+// hram ni eparhija nemaju dodeljen obrazac.
+func (s *service) SetDefaultCertificateTemplate(ctx context.Context, id int64) (*dto.CertificateTemplate, error) {
+	current, err := s.GetCertificateTemplateByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if current.IsDefault {
+		return current, nil
+	}
+
+	var res *dto.CertificateTemplate
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if err := txRepo.SetDefaultCertificateTemplate(ctx, current.Kind, id); err != nil {
+			return err
+		}
+		updated, err := s.getCertificateTemplate(ctx, txRepo, id)
+		if err != nil {
+			return err
+		}
+		res = updated
+		return s.recordAudit(ctx, txRepo, model.AuditEntityTemplate, id, model.AuditActionUpdate, current, res)
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ClearDefaultCertificateTemplate vraca stampu bez dodeljenog obrasca na
+// obrazac ugradjen u aplikaciju.
+func (s *service) ClearDefaultCertificateTemplate(ctx context.Context) error {
+	current, err := s.repo.GetDefaultCertificateTemplate(ctx, model.CertificateTemplateKindKrstenica)
+	if err != nil {
+		if errors.Is(err, errorx.ErrCertificateTemplateNotFound) {
+			return nil
+		}
+		log.Println(err)
+		return err
+	}
+
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if err := txRepo.UpdateCertificateTemplate(ctx, current.ID, map[string]interface{}{"is_default": false}); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, txRepo, model.AuditEntityTemplate, current.ID, model.AuditActionUpdate,
+			map[string]interface{}{"is_default": true}, map[string]interface{}{"is_default": false})
+	})
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteCertificateTemplate sklanja obrazac iz izbora i uklanja njegove
+// dodele; verzije i fajlovi ostaju zbog ponovne stampe izdatih uverenja.
+func (s *service) DeleteCertificateTemplate(ctx context.Context, id int64) error {
+	current, err := s.GetCertificateTemplateByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if current.IsDefault {
+		return errorx.ErrCertificateTemplateDefault
+	}
+
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if err := txRepo.DeleteCertificateTemplateAssignments(ctx, id); err != nil {
+			return err
+		}
+		if err := txRepo.UpdateCertificateTemplate(ctx, id, map[string]interface{}{"status": model.CertificateTemplateStatusDeleted}); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, txRepo, model.AuditEntityTemplate, id, model.AuditActionDelete, current, nil)
+	})
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	return nil
+}
+
+// ResolveKrstenicaTemplate bira verziju obrasca za stampu krstenice: zadatu
+// verziju (ponovna stampa), tekucu verziju zadatog obrasca, obrazac hrama,
+// obrazac eparhije ili podrazumevani obrazac. Vraca nil kada se stampa po
+// obrascu ugradjenom u aplikaciju.
+func (s *service) ResolveKrstenicaTemplate(ctx context.Context, krstenica *dto.Krstenica, templateID, versionID int64) (*dto.CertificateTemplateVersion, error) {
+	if versionID > 0 {
+		version, err := s.repo.GetCertificateTemplateVersionByID(ctx, versionID)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+		template, err := s.getCertificateTemplateIncludingDeleted(ctx, version.TemplateId)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+		return s.makeCertificateTemplateVersionResponse(version, template.Name), nil
+	}
+
+	var (
+		template *model.CertificateTemplate
+		err      error
+	)
+	if templateID > 0 {
+		template, err = s.repo.GetCertificateTemplateByID(ctx, templateID)
+	} else {
+		var tampleID, eparhijaID int64
+		if krstenica.TampleId != nil {
+			tampleID = *krstenica.TampleId
+		}
+		if krstenica.EparhijaId != nil {
+			eparhijaID = *krstenica.EparhijaId
+		}
+		template, err = s.repo.GetAssignedCertificateTemplate(ctx, model.CertificateTemplateKindKrstenica, tampleID, eparhijaID)
+		if errors.Is(err, errorx.ErrCertificateTemplateNotFound) {
+			template, err = s.repo.GetDefaultCertificateTemplate(ctx, model.CertificateTemplateKindKrstenica)
+			if errors.Is(err, errorx.ErrCertificateTemplateNotFound) {
+				return nil, nil
+			}
+		}
+	}
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	version, err := s.repo.GetLatestCertificateTemplateVersion(ctx, template.ID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return s.makeCertificateTemplateVersionResponse(version, template.Name), nil
+}
+
+// OpenCertificateTemplateFile otvara jedan fajl verzije obrasca za preuzimanje;
+// vraca i ime pod kojim je fajl otpremljen i njegov tip.
+func (s *service) OpenCertificateTemplateFile(ctx context.Context, templateID, versionID int64, part string) (string, string, *os.File, error) {
+	version, err := s.repo.GetCertificateTemplateVersionByID(ctx, versionID)
+	if err != nil {
+		log.Println(err)
+		return "", "", nil, err
+	}
+	if version.TemplateId != templateID {
+		return "", "", nil, errorx.ErrCertificateTemplateNotFound
+	}
+
+	var key, fileName string
+	contentType := certificateTemplateXLSXContentType
+	switch part {
+	case CertificateTemplatePartTemplate:
+		key, fileName = version.StorageKey, version.FileName
+	case CertificateTemplatePartPreview:
+		key, fileName = version.PreviewStorageKey, version.PreviewFileName
+	case CertificateTemplatePartBackground:
+		key, fileName = version.BackgroundStorageKey, version.BackgroundFileName
+		if strings.HasSuffix(key, ".png") {
+			contentType = "image/png"
+		} else {
+			contentType = "image/jpeg"
+		}
+	}
+	if key == "" {
+		return "", "", nil, errorx.ErrCertificateTemplateNotFound
+	}
+
+	file, err := os.Open(s.certificateTemplatePath(key))
+	if err != nil {
+		log.Println(err)
+		if os.IsNotExist(err) {
+			return "", "", nil, errorx.ErrCertificateTemplateNotFound
+		}
+		return "", "", nil, err
+	}
+
+	return fileName, contentType, file, nil
+}
+
+// CertificateTemplateMaxSizeBytes je najveca dozvoljena velicina jednog fajla obrasca.
+func (s *service) CertificateTemplateMaxSizeBytes() int64 {
+	return s.conf.Templates.MaxSizeMB * 1024 * 1024
+}
+
+// createCertificateTemplateVersion proverava i cuva otpremljene fajlove i
+// upisuje novu verziju; fajlovi koji nisu otpremljeni preuzimaju se iz
+// prethodne verzije. Sacuvani fajlovi se nikad ne menjaju, pa ih verzije mogu deliti.
+func (s *service) createCertificateTemplateVersion(ctx context.Context, repo repository.Repo, templateID int64, previous *model.CertificateTemplateVersion, req *dto.CertificateTemplateVersionReq) (*model.CertificateTemplateVersion, error) {
+	note := strings.TrimSpace(req.Note)
+	if utf8.RuneCountInString(note) > 500 {
+		return nil, errorx.GetValidationError("CertificateTemplate", "validation", "note can not be longer than 500 characters")
+	}
+
+	version := &model.CertificateTemplateVersion{
+		TemplateId:          templateID,
+		BackgroundFullBleed: req.BackgroundFullBleed,
+		Note:                note,
+		CreatedAt:           time.Now(),
+	}
+	if previous != nil {
+		version.FileName, version.StorageKey, version.Checksum = previous.FileName, previous.StorageKey, previous.Checksum
+		version.PreviewFileName, version.PreviewStorageKey = previous.PreviewFileName, previous.PreviewStorageKey
+		version.BackgroundFileName, version.BackgroundStorageKey = previous.BackgroundFileName, previous.BackgroundStorageKey
+	}
+	if req.RemoveBackground {
+		version.BackgroundFileName, version.BackgroundStorageKey = "", ""
+	}
+
+	var err error
+	if req.Template != nil {
+		if version.FileName, version.StorageKey, err = s.storeCertificateTemplateXLSX(templateID, req.Template, "obrazac.xlsx"); err != nil {
+			return nil, err
+		}
+		checksum := sha256.Sum256(req.Template.Content)
+		version.Checksum = hex.EncodeToString(checksum[:])
+	}
+	if req.PreviewTemplate != nil {
+		if version.PreviewFileName, version.PreviewStorageKey, err = s.storeCertificateTemplateXLSX(templateID, req.PreviewTemplate, "pregled.xlsx"); err != nil {
+			return nil, err
+		}
+	}
+	if req.Background != nil {
+		if version.BackgroundFileName, version.BackgroundStorageKey, err = s.storeCertificateTemplateImage(templateID, req.Background); err != nil {
+			return nil, err
+		}
+	}
+	if version.StorageKey == "" {
+		return nil, errorx.GetValidationError("CertificateTemplate", "validation", "XLSX template is required")
+	}
+
+	if user, ok := requestctx.UserFromContext(ctx); ok {
+		if user.ID > 0 {
+			version.UploadedById = sql.NullInt64{Valid: true, Int64: user.ID}
+		}
+		version.UploadedByUsername = user.Username
+	}
+
+	return repo.CreateCertificateTemplateVersion(ctx, version)
+}
+
+// storeCertificateTemplateXLSX proverava da se XLSX obrazac moze otvoriti i cuva ga.
+func (s *service) storeCertificateTemplateXLSX(templateID int64, file *dto.CertificateTemplateFile, defaultName string) (string, string, error) {
+	fileName, err := s.validateCertificateTemplateFile(file, defaultName)
+	if err != nil {
+		return "", "", err
+	}
+
+	workbook, err := excelize.OpenReader(bytes.NewReader(file.Content))
+	if err != nil {
+		log.Println(err)
+		return "", "", errorx.GetValidationError("CertificateTemplate", "validation", fmt.Sprintf("%s is not a valid XLSX file", fileName))
+	}
+	sheets := workbook.GetSheetList()
+	workbook.Close()
+	if len(sheets) == 0 {
+		return "", "", errorx.GetValidationError("CertificateTemplate", "validation", fmt.Sprintf("%s has no sheets", fileName))
+	}
+
+	key, err := s.writeCertificateTemplateFile(templateID, ".xlsx", file.Content)
+	if err != nil {
+		return "", "", err
+	}
+	return fileName, key, nil
+}
+
+// storeCertificateTemplateImage proverava pozadinsku sliku (JPEG ili PNG) i cuva je.
+func (s *service) storeCertificateTemplateImage(templateID int64, file *dto.CertificateTemplateFile) (string, string, error) {
+	fileName, err := s.validateCertificateTemplateFile(file, "pozadina.jpg")
+	if err != nil {
+		return "", "", err
+	}
+
+	extension, ok := certificateTemplateImageExtensions[detectAttachmentContentType(file.Content)]
+	if !ok {
+		return "", "", errorx.GetValidationError("CertificateTemplate", "validation", "background must be a JPEG or PNG image")
+	}
+	if _, _, err := image.DecodeConfig(bytes.NewReader(file.Content)); err != nil {
+		log.Println(err)
+		return "", "", errorx.GetValidationError("CertificateTemplate", "validation", "background image can not be read")
+	}
+
+	key, err := s.writeCertificateTemplateFile(templateID, extension, file.Content)
+	if err != nil {
+		return "", "", err
+	}
+	return fileName, key, nil
+}
+
+func (s *service) validateCertificateTemplateFile(file *dto.CertificateTemplateFile, defaultName string) (string, error) {
+	if len(file.Content) == 0 {
+		return "", errorx.GetValidationError("CertificateTemplate", "validation", "file is empty")
+	}
+	if int64(len(file.Content)) > s.CertificateTemplateMaxSizeBytes() {
+		return "", errorx.GetValidationError("CertificateTemplate", "validation", fmt.Sprintf("file can not be larger than %d MB", s.conf.Templates.MaxSizeMB))
+	}
+
+	fileName := strings.TrimSpace(filepath.Base(strings.ReplaceAll(file.FileName, "\\", "/")))
+	if fileName == "" || fileName == "." || fileName == "/" {
+		fileName = defaultName
+	}
+	if utf8.RuneCountInString(fileName) > 255 {
+		return "", errorx.GetValidationError("CertificateTemplate", "validation", "file name can not be longer than 255 characters")
+	}
+	return fileName, nil
+}
+
+func (s *service) writeCertificateTemplateFile(templateID int64, extension string, content []byte) (string, error) {
+	name, err := randomAttachmentName()
+	if err != nil {
+		return "", err
+	}
+	key := filepath.ToSlash(filepath.Join(model.CertificateTemplateKindKrstenica, strconv.FormatInt(templateID, 10), name+extension))
+
+	path := s.certificateTemplatePath(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, content, 0640); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+func (s *service) certificateTemplatePath(key string) string {
+	return filepath.Join(s.conf.Templates.Dir, filepath.FromSlash(key))
+}
+
+// getCertificateTemplateIncludingDeleted vraca obrazac verzije za ponovnu
+// stampu; za obrisan obrazac vraca se obrazac bez naziva jer se verzija i
+// dalje moze stampati.
+func (s *service) getCertificateTemplateIncludingDeleted(ctx context.Context, id int64) (*model.CertificateTemplate, error) {
+	template, err := s.repo.GetCertificateTemplateByID(ctx, id)
+	if errors.Is(err, errorx.ErrCertificateTemplateNotFound) {
+		return &model.CertificateTemplate{ID: id}, nil
+	}
+	return template, err
+}
+
+func validateCertificateTemplateName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errorx.GetValidationError("CertificateTemplate", "validation", "name is required")
+	}
+	if utf8.RuneCountInString(name) > 255 {
+		return "", errorx.GetValidationError("CertificateTemplate", "validation", "name is too long")
+	}
+	return name, nil
+}
+
+func uniquePositiveIDs(ids []int64) []int64 {
+	seen := map[int64]bool{}
+	var res []int64
+	for _, id := range ids {
+		if id > 0 && !seen[id] {
+			seen[id] = true
+			res = append(res, id)
+		}
+	}
+	return res
+}
+
+func (s *service) makeCertificateTemplateResponse(ctx context.Context, repo repository.Repo, template *model.CertificateTemplate, withVersions bool) (*dto.CertificateTemplate, error) {
+	res := &dto.CertificateTemplate{
+		ID:          template.ID,
+		Kind:        template.Kind,
+		Name:        template.Name,
+		IsDefault:   template.IsDefault,
+		Assignments: []dto.CertificateTemplateAssignment{},
+		CreatedAt:   template.CreatedAt,
+		UpdatedAt:   template.UpdatedAt,
+	}
+
+	versions, err := repo.ListCertificateTemplateVersions(ctx, template.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) > 0 {
+		res.CurrentVersion = s.makeCertificateTemplateVersionResponse(&versions[0], template.Name)
+	}
+	if withVersions {
+		res.Versions = make([]*dto.CertificateTemplateVersion, len(versions))
+		for i := range versions {
+			res.Versions[i] = s.makeCertificateTemplateVersionResponse(&versions[i], template.Name)
+		}
+	}
+
+	assignments, err := repo.ListCertificateTemplateAssignments(ctx, template.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, assignment := range assignments {
+		res.Assignments = append(res.Assignments, dto.CertificateTemplateAssignment{
+			EparhijaId:   int64Ptr(assignment.EparhijaId),
+			EparhijaName: assignment.EparhijaName,
+			TampleId:     int64Ptr(assignment.TampleId),
+			TampleName:   assignment.TampleName,
+		})
+	}
+
+	return res, nil
+}
+
+func (s *service) makeCertificateTemplateVersionResponse(version *model.CertificateTemplateVersion, templateName string) *dto.CertificateTemplateVersion {
+	res := &dto.CertificateTemplateVersion{
+		ID:                  version.ID,
+		TemplateId:          version.TemplateId,
+		TemplateName:        templateName,
+		Version:             version.Version,
+		FileName:            version.FileName,
+		PreviewFileName:     version.PreviewFileName,
+		BackgroundFileName:  version.BackgroundFileName,
+		BackgroundFullBleed: version.BackgroundFullBleed,
+		Checksum:            version.Checksum,
+		Note:                version.Note,
+		UploadedByUsername:  version.UploadedByUsername,
+		CreatedAt:           version.CreatedAt,
+		TemplatePath:        s.certificateTemplatePath(version.StorageKey),
+	}
+	if version.PreviewStorageKey != "" {
+		res.PreviewTemplatePath = s.certificateTemplatePath(version.PreviewStorageKey)
+	}
+	if version.BackgroundStorageKey != "" {
+		res.BackgroundPath = s.certificateTemplatePath(version.BackgroundStorageKey)
+	}
+	return res
+}
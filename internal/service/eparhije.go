@@ -6,17 +6,29 @@ import (
 	"krstenica/internal/dto"
 	"krstenica/internal/errorx"
 	"krstenica/internal/model"
+	"krstenica/internal/repository"
 	"krstenica/pkg"
 	"log"
 	"time"
 )
 
 func (s *service) DeleteEparhije(ctx context.Context, id int64) error {
+	current, err := s.repo.GetEparhijeByID(ctx, id)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
 
 	updates := map[string]interface{}{}
 	updates["status"] = model.EparhijeStatusDeleted
+	markDeleted(ctx, updates)
 
-	err := s.repo.UpdateEparhije(ctx, id, updates)
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if err := txRepo.UpdateEparhije(ctx, id, updates); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, txRepo, model.AuditEntityEparhija, id, model.AuditActionDelete, makeEparhijeResponse(current), nil)
+	})
 	if err != nil {
 		log.Println(err)
 		return err
@@ -26,26 +38,36 @@ func (s *service) DeleteEparhije(ctx context.Context, id int64) error {
 }
 
 func (s *service) UpdateEparhije(ctx context.Context, id int64, eparhijaReq *dto.EparhijeUpdateReq) (*dto.Eparhije, error) {
-	updates, err := validateEparhijeUpdateRequest(eparhijaReq)
+	current, err := s.repo.GetEparhijeByID(ctx, id)
 	if err != nil {
 		log.Println(err)
 		return nil, err
 	}
 
-	err = s.repo.UpdateEparhije(ctx, id, updates)
+	updates, err := validateEparhijeUpdateRequest(eparhijaReq)
 	if err != nil {
 		log.Println(err)
 		return nil, err
 	}
 
-	eparhija, err := s.repo.GetEparhijeByID(ctx, id)
+	var res *dto.Eparhije
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if err := txRepo.UpdateEparhije(ctx, id, updates); err != nil {
+			return err
+		}
+		eparhija, err := txRepo.GetEparhijeByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		res = makeEparhijeResponse(eparhija)
+		return s.recordAudit(ctx, txRepo, model.AuditEntityEparhija, id, model.AuditActionUpdate, makeEparhijeResponse(current), res)
+	})
 	if err != nil {
 		log.Println(err)
 		return nil, err
 	}
 
-	return makeEparhijeResponse(eparhija), nil
-
+	return res, nil
 }
 
 func (s *service) CreateEparhije(ctx context.Context, eparhijeReq *dto.EparhijeCreateReq) (*dto.Eparhije, error) {
@@ -62,13 +84,21 @@ func (s *service) CreateEparhije(ctx context.Context, eparhijeReq *dto.EparhijeC
 		CreatedAt: sql.NullTime{Valid: true, Time: time.Now()},
 	}
 
-	newEparhija, err := s.repo.CreateEparhije(ctx, eparhija)
+	var res *dto.Eparhije
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		newEparhija, err := txRepo.CreateEparhije(ctx, eparhija)
+		if err != nil {
+			return err
+		}
+		res = makeEparhijeResponse(newEparhija)
+		return s.recordAudit(ctx, txRepo, model.AuditEntityEparhija, res.ID, model.AuditActionCreate, nil, res)
+	})
 	if err != nil {
 		log.Println(err)
 		return nil, err
 	}
 
-	return makeEparhijeResponse(newEparhija), nil
+	return res, nil
 }
 
 func (s *service) GetEparhijeByID(ctx context.Context, id int64) (*dto.Eparhije, error) {
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"krstenica/internal/dto"
+	"krstenica/internal/errorx"
+	"krstenica/internal/model"
+	"krstenica/internal/repository"
+	"krstenica/internal/requestctx"
+	"krstenica/pkg"
+)
+
+// IssueKrstenicaCertificate beleži izdavanje uverenja o krštenju i dodeljuje mu
+// sledeći redni broj u tekućoj godini. render pravi dokument sa dodeljenim
+// brojem posle upisa, van zaključavanja brojeva; ako render ne uspe, upis se
+// briše.
+func (s *service) IssueKrstenicaCertificate(ctx context.Context, krstenicaID int64, req *dto.IssuedCertificateCreateReq, render func(*dto.IssuedCertificate) error) (*dto.IssuedCertificate, error) {
+	issued, err := s.issueKrstenicaCertificate(ctx, s.repo, krstenicaID, req)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if err := render(issued); err != nil {
+		log.Println(err)
+		s.discardIssuedCertificates(ctx, issued)
+		return nil, err
+	}
+
+	return issued, nil
+}
+
+// discardIssuedCertificates briše uverenja za koja dokument nije napravljen,
+// da ih provera ne bi prikazala kao važeća.
+func (s *service) discardIssuedCertificates(ctx context.Context, certificates ...*dto.IssuedCertificate) {
+	ids := make([]int64, len(certificates))
+	for i, certificate := range certificates {
+		ids[i] = certificate.ID
+	}
+	// brisanje mora da prođe i kada je zahtev u međuvremenu otkazan
+	if err := s.repo.DeleteIssuedCertificates(context.WithoutCancel(ctx), ids); err != nil {
+		log.Println("Error discarding issued certificates:", ids, err)
+	}
+}
+
+// IssueKrstenicaCertificates izdaje po jedno uverenje za svaku krštenicu
+// (reqs[i] pripada krstenicaIDs[i]). Brojevi se dodeljuju u jednoj kratkoj
+// transakciji, a zbirni dokument se pravi posle nje; ako render ne uspe, sva
+// izdata uverenja se brišu.
+func (s *service) IssueKrstenicaCertificates(ctx context.Context, krstenicaIDs []int64, reqs []*dto.IssuedCertificateCreateReq, render func([]*dto.IssuedCertificate) error) ([]*dto.IssuedCertificate, error) {
+	if len(krstenicaIDs) != len(reqs) {
+		return nil, errorx.GetValidationError("IssuedCertificate", "validation", "Each krstenica needs its own issue request")
+	}
+
+	issued := make([]*dto.IssuedCertificate, len(krstenicaIDs))
+	err := s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		for i, krstenicaID := range krstenicaIDs {
+			certificate, err := s.issueKrstenicaCertificate(ctx, txRepo, krstenicaID, reqs[i])
+			if err != nil {
+				return err
+			}
+			issued[i] = certificate
+		}
+		return nil
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if err := render(issued); err != nil {
+		log.Println(err)
+		s.discardIssuedCertificates(ctx, issued...)
+		return nil, err
+	}
+
+	return issued, nil
+}
+
+func (s *service) issueKrstenicaCertificate(ctx context.Context, repo repository.Repo, krstenicaID int64, req *dto.IssuedCertificateCreateReq) (*dto.IssuedCertificate, error) {
+	krstenica, err := repo.GetKrstenicaByID(ctx, krstenicaID)
+	if err != nil {
+		return nil, err
+	}
+	if err := enforceCityPermission(ctx, krstenica.City); err != nil {
+		return nil, err
+	}
+	if err := validateIssuedCertificateCreateRequest(req); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	certificate := &model.IssuedCertificatePost{
+		KrstenicaId:     krstenicaID,
+		TampleId:        krstenica.TampleId,
+		Year:            int64(now.Year()),
+		Format:          req.Format,
+		TemplateVersion: req.TemplateVersion,
+		Purpose:         req.Purpose,
+		CreatedAt:       now,
+	}
+	if req.TemplateVersionId != nil {
+		certificate.TemplateVersionId = sql.NullInt64{Valid: true, Int64: *req.TemplateVersionId}
+	}
+	if user, ok := requestctx.UserFromContext(ctx); ok {
+		if user.ID > 0 {
+			certificate.IssuedById = sql.NullInt64{Valid: true, Int64: user.ID}
+		}
+		certificate.IssuedByUsername = user.Username
+	}
+
+	issued, err := repo.CreateIssuedCertificate(ctx, certificate)
+	if err != nil {
+		return nil, err
+	}
+
+	return makeIssuedCertificateResponse(issued), nil
+}
+
+func (s *service) GetIssuedCertificateByID(ctx context.Context, id int64) (*dto.IssuedCertificate, error) {
+	certificate, err := s.repo.GetIssuedCertificateByID(ctx, id)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if err := enforceCityPermission(ctx, certificate.City); err != nil {
+		return nil, err
+	}
+
+	return makeIssuedCertificateResponse(certificate), nil
+}
+
+func (s *service) ListIssuedCertificates(ctx context.Context, filterAndSort *pkg.FilterAndSort) ([]*dto.IssuedCertificate, int64, error) {
+	if user, ok := requestctx.UserFromContext(ctx); ok && !user.IsAdmin() {
+		city := strings.TrimSpace(user.City)
+		if city == "" {
+			return nil, 0, errors.New("корисник нема додељен град")
+		}
+		filterAndSort = ensureFilterAndSort(filterAndSort)
+		applyCityFilter(filterAndSort, city)
+	}
+	certificates, totalCount, err := s.repo.ListIssuedCertificates(ctx, filterAndSort)
+	if err != nil {
+		log.Println(err)
+		return nil, 0, err
+	}
+
+	res := make([]*dto.IssuedCertificate, len(certificates))
+	for i := range certificates {
+		res[i] = makeIssuedCertificateResponse(&certificates[i])
+	}
+	return res, totalCount, nil
+}
+
+// RevokeIssuedCertificate poništava izdato uverenje; QR provera ga posle toga
+// prikazuje kao poništeno.
+func (s *service) RevokeIssuedCertificate(ctx context.Context, id int64, req *dto.IssuedCertificateRevokeReq) (*dto.IssuedCertificate, error) {
+	certificate, err := s.repo.GetIssuedCertificateByID(ctx, id)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if err := enforceCityPermission(ctx, certificate.City); err != nil {
+		return nil, err
+	}
+	if certificate.RevokedAt.Valid {
+		return nil, errorx.GetValidationError("IssuedCertificate", "validation", "Certificate is already revoked")
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if utf8.RuneCountInString(reason) > 500 {
+		return nil, errorx.GetValidationError("IssuedCertificate", "validation", "Reason can not be longer than 500 characters")
+	}
+
+	updates := map[string]interface{}{}
+	updates["revoked_at"] = time.Now()
+	updates["revoke_reason"] = reason
+	if user, ok := requestctx.UserFromContext(ctx); ok {
+		updates["revoked_by_username"] = user.Username
+	}
+
+	if err := s.repo.UpdateIssuedCertificate(ctx, id, updates); err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return s.GetIssuedCertificateByID(ctx, id)
+}
+
+// VerifyIssuedCertificate vraća podatke za javnu proveru uverenja. Uverenje je
+// poništeno ako je ručno povučeno ili je krštenica obrisana, a zamenjeno ako je
+// za istu krštenicu kasnije izdato novo uverenje.
+func (s *service) VerifyIssuedCertificate(ctx context.Context, id int64) (*dto.CertificateVerification, error) {
+	certificate, err := s.repo.GetIssuedCertificateByID(ctx, id)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	res := &dto.CertificateVerification{
+		Serial:     fmt.Sprintf("%d/%d", certificate.SerialNumber, certificate.Year),
+		FirstName:  certificate.FirstName,
+		LastName:   certificate.LastName,
+		Baptism:    certificate.Baptism.Time,
+		TampleName: certificate.TampleName,
+		IssuedAt:   certificate.CreatedAt,
+		Status:     string(model.VerificationStatusValid),
+	}
+
+	switch {
+	case certificate.RevokedAt.Valid:
+		res.Status = string(model.VerificationStatusRevoked)
+		res.RevokedAt = &certificate.RevokedAt.Time
+	case certificate.KrstenicaStatus == "deleted":
+		res.Status = string(model.VerificationStatusRevoked)
+	default:
+		newer, err := s.repo.HasNewerIssuedCertificate(ctx, certificate.KrstenicaId, certificate.ID)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+		if newer {
+			res.Status = string(model.VerificationStatusSuperseded)
+		}
+	}
+
+	return res, nil
+}
+
+func makeIssuedCertificateResponse(certificate *model.IssuedCertificate) *dto.IssuedCertificate {
+	res := &dto.IssuedCertificate{
+		ID:                certificate.ID,
+		KrstenicaId:       certificate.KrstenicaId,
+		TampleId:          int64Ptr(certificate.TampleId),
+		TampleName:        certificate.TampleName,
+		FirstName:         certificate.FirstName,
+		LastName:          certificate.LastName,
+		City:              certificate.City,
+		Baptism:           certificate.Baptism.Time,
+		Year:              certificate.Year,
+		SerialNumber:      certificate.SerialNumber,
+		Serial:            fmt.Sprintf("%d/%d", certificate.SerialNumber, certificate.Year),
+		Format:            certificate.Format,
+		TemplateVersion:   certificate.TemplateVersion,
+		TemplateVersionId: int64Ptr(certificate.TemplateVersionId),
+		TemplateName:      certificate.TemplateName,
+		TemplateRevision:  int64Ptr(certificate.TemplateRevision),
+		Purpose:           certificate.Purpose,
+		IssuedById:        int64Ptr(certificate.IssuedById),
+		IssuedByUsername:  certificate.IssuedByUsername,
+		CreatedAt:         certificate.CreatedAt,
+		RevokedBy:         certificate.RevokedBy,
+		RevokeReason:      certificate.RevokeReason,
+	}
+	if certificate.RevokedAt.Valid {
+		res.RevokedAt = &certificate.RevokedAt.Time
+	}
+	return res
+}
+
+func validateIssuedCertificateCreateRequest(req *dto.IssuedCertificateCreateReq) error {
+	req.Format = strings.ToLower(strings.TrimSpace(req.Format))
+	if req.Format == "" {
+		return errorx.GetValidationError("IssuedCertificate", "validation", "Format is required")
+	}
+	req.TemplateVersion = strings.TrimSpace(req.TemplateVersion)
+	if req.TemplateVersion == "" {
+		req.TemplateVersion = "1"
+	}
+	req.Purpose = strings.TrimSpace(req.Purpose)
+	if utf8.RuneCountInString(req.Purpose) > 500 {
+		return errorx.GetValidationError("IssuedCertificate", "validation", "Purpose can not be longer than 500 characters")
+	}
+
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 	"krstenica/internal/dto"
 	"krstenica/internal/errorx"
 	"krstenica/internal/model"
+	"krstenica/internal/repository"
 	"krstenica/internal/requestctx"
 	"krstenica/pkg"
 )
@@ -26,8 +27,14 @@ func (s *service) DeleteKrstenica(ctx context.Context, id int64) error {
 
 	updates := map[string]interface{}{}
 	updates["status"] = model.PersonStatusDeleted
+	markDeleted(ctx, updates)
 
-	err = s.repo.UpdateKrstenica(ctx, id, updates)
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if err := txRepo.UpdateKrstenica(ctx, id, updates); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, txRepo, model.AuditEntityKrstenica, id, model.AuditActionDelete, makeKrstenicaResponse(current), nil)
+	})
 	if err != nil {
 		log.Println(err)
 		return err
@@ -37,6 +44,12 @@ func (s *service) DeleteKrstenica(ctx context.Context, id int64) error {
 }
 
 func (s *service) UpdateKrstenica(ctx context.Context, id int64, krstenicaReq *dto.KrstenicaUpdateReq) (*dto.Krstenica, error) {
+	return s.updateKrstenica(ctx, id, krstenicaReq, 0)
+}
+
+// updateKrstenica čuva izmenu i beleži novu verziju; restoredFrom je broj
+// verzije iz koje je izmena vraćena (0 za običnu izmenu).
+func (s *service) updateKrstenica(ctx context.Context, id int64, krstenicaReq *dto.KrstenicaUpdateReq, restoredFrom int64) (*dto.Krstenica, error) {
 	current, err := s.repo.GetKrstenicaByID(ctx, id)
 	if err != nil {
 		log.Println(err)
@@ -51,6 +64,32 @@ func (s *service) UpdateKrstenica(ctx context.Context, id int64, krstenicaReq *d
 		log.Println(err)
 		return nil, err
 	}
+	if krstenicaPlacementChanged(current, krstenicaReq) {
+		if err := s.resolveKrstenicaBookUpdate(ctx, current, krstenicaReq, updates); err != nil {
+			log.Println(err)
+			return nil, err
+		}
+	}
+	hasFather := current.FatherId.Valid
+	if v, ok := updates["father_id"]; ok {
+		hasFather = v != nil
+	}
+	hasMother := current.MotherId.Valid
+	if v, ok := updates["mother_id"]; ok {
+		hasMother = v != nil
+	}
+	if !hasFather && !hasMother {
+		return nil, errorx.GetValidationError("Krstenica", "validation", "At least one parent (father or mother) is required")
+	}
+	if krstenicaReq.GodparentIds != nil || krstenicaReq.GodfatherId != nil {
+		godparentIds, err := s.resolveGodparentsForUpdate(ctx, current, krstenicaReq)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+		delete(updates, "godfather_id")
+		updates["godparent_ids"] = godparentIds
+	}
 	if user, ok := requestctx.UserFromContext(ctx); ok && !user.IsAdmin() {
 		city := strings.TrimSpace(user.City)
 		if city == "" {
@@ -59,20 +98,28 @@ func (s *service) UpdateKrstenica(ctx context.Context, id int64, krstenicaReq *d
 		updates["city"] = city
 	}
 
-	err = s.repo.UpdateKrstenica(ctx, id, updates)
+	var krstenica *model.Krstenica
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if err := txRepo.UpdateKrstenica(ctx, id, updates); err != nil {
+			return err
+		}
+		updated, err := txRepo.GetKrstenicaByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		krstenica = updated
+		return s.recordAudit(ctx, txRepo, model.AuditEntityKrstenica, id, model.AuditActionUpdate, makeKrstenicaResponse(current), makeKrstenicaResponse(krstenica))
+	})
 	if err != nil {
 		log.Println(err)
 		return nil, err
 	}
 
-	krstenica, err := s.repo.GetKrstenicaByID(ctx, id)
-	if err != nil {
-		log.Println(err)
-		return nil, err
-	}
+	s.recordKrstenicaVersion(ctx, current, krstenica, restoredFrom)
 
-	return makeKrstenicaResponse(krstenica), nil
+	res := makeKrstenicaResponse(krstenica)
 
+	return res, nil
 }
 
 func (s *service) CreateKrstenica(ctx context.Context, krstenicaReq *dto.KrstenicaCreateReq) (*dto.Krstenica, error) {
@@ -88,6 +135,18 @@ func (s *service) CreateKrstenica(ctx context.Context, krstenicaReq *dto.Krsteni
 		log.Println(err)
 		return nil, err
 	}
+	book, err := s.resolveKrstenicaBook(ctx, krstenicaReq)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if book == nil && (strings.TrimSpace(krstenicaReq.Book) == "" || krstenicaReq.Page <= 0 || krstenicaReq.CurrentNumber <= 0) {
+		return nil, errorx.GetValidationError("Krstenica", "validation", "Book, page and current number are required when no registry book is selected")
+	}
+	if err := s.checkGodparentsExist(ctx, krstenicaReq.GodparentIds); err != nil {
+		log.Println(err)
+		return nil, err
+	}
 
 	isChurchMarried := strings.TrimSpace(krstenicaReq.IsChurchMarried)
 	isTwin := strings.TrimSpace(krstenicaReq.IsTwin)
@@ -108,12 +167,15 @@ func (s *service) CreateKrstenica(ctx context.Context, krstenicaReq *dto.Krsteni
 
 	krstenica := &model.KrstenicaPost{
 		Book:                   krstenicaReq.Book,
+		BookId:                 krstenicaReq.BookId,
 		Page:                   krstenicaReq.Page,
 		CurrentNumber:          krstenicaReq.CurrentNumber,
 		EparhijaId:             krstenicaReq.EparhijaId,
 		TampleId:               krstenicaReq.TampleId,
-		ParentId:               krstenicaReq.ParentId,
+		FatherId:               krstenicaReq.FatherId,
+		MotherId:               krstenicaReq.MotherId,
 		GodfatherId:            krstenicaReq.GodfatherId,
+		GodparentIds:           krstenicaReq.GodparentIds,
 		ParohId:                krstenicaReq.ParohId,
 		PriestId:               krstenicaReq.PriestId,
 		FirstName:              krstenicaReq.FirstName,
@@ -138,13 +200,34 @@ func (s *service) CreateKrstenica(ctx context.Context, krstenicaReq *dto.Krsteni
 		CreatedAt:              sql.NullTime{Valid: true, Time: time.Now()},
 	}
 
-	newKrstenica, err := s.repo.CreateKrstenica(ctx, krstenica)
+	var newKrstenica *model.Krstenica
+	if book != nil {
+		bookID := book.ID
+		krstenica.BookId = &bookID
+	}
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		var err error
+		if book != nil && krstenica.Page <= 0 && krstenica.CurrentNumber <= 0 {
+			// strana i tekući broj se dodeljuju automatski iz knjige
+			newKrstenica, err = txRepo.CreateKrstenicaInBook(ctx, krstenica)
+		} else {
+			newKrstenica, err = txRepo.CreateKrstenica(ctx, krstenica)
+		}
+		if err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, txRepo, model.AuditEntityKrstenica, newKrstenica.ID, model.AuditActionCreate, nil, makeKrstenicaResponse(newKrstenica))
+	})
 	if err != nil {
 		log.Println(err)
 		return nil, err
 	}
 
-	return makeKrstenicaResponse(newKrstenica), nil
+	s.recordKrstenicaVersion(ctx, nil, newKrstenica, 0)
+
+	res := makeKrstenicaResponse(newKrstenica)
+
+	return res, nil
 }
 
 func (s *service) GetKrstenicaByID(ctx context.Context, id int64) (*dto.Krstenica, error) {
@@ -157,7 +240,18 @@ func (s *service) GetKrstenicaByID(ctx context.Context, id int64) (*dto.Krstenic
 		return nil, err
 	}
 
-	return makeKrstenicaResponse(krstenica), nil
+	annotations, err := s.repo.ListKrstenicaAnnotations(ctx, id)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	res := makeKrstenicaResponse(krstenica)
+	res.Annotations = make([]dto.KrstenicaAnnotation, len(annotations))
+	for i := range annotations {
+		res.Annotations[i] = *makeKrstenicaAnnotationResponse(&annotations[i])
+	}
+	return res, nil
 }
 
 func (s *service) ListKrstenice(ctx context.Context, filterAndSort *pkg.FilterAndSort) ([]*dto.Krstenica, int64, error) {
@@ -182,6 +276,32 @@ func (s *service) ListKrstenice(ctx context.Context, filterAndSort *pkg.FilterAn
 	return res, totalCount, nil
 }
 
+// ExportKrstenice predaje krstenice po filterima u serijama, uz ista
+// ogranicenja po gradu kao ListKrstenice.
+func (s *service) ExportKrstenice(ctx context.Context, filterAndSort *pkg.FilterAndSort, fn func([]*dto.Krstenica) error) error {
+	if user, ok := requestctx.UserFromContext(ctx); ok && !user.IsAdmin() {
+		city := strings.TrimSpace(user.City)
+		if city == "" {
+			return errors.New("корисник нема додељен град")
+		}
+		filterAndSort = ensureFilterAndSort(filterAndSort)
+		applyCityFilter(filterAndSort, city)
+	}
+	err := s.repo.ExportKrstenice(ctx, filterAndSort, func(krstenica []model.Krstenica) error {
+		res := make([]*dto.Krstenica, len(krstenica))
+		for i := range krstenica {
+			res[i] = makeKrstenicaResponse(&krstenica[i])
+		}
+		return fn(res)
+	})
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	return nil
+}
+
 // func makeKrstenicaPostResponse(krstenica *model.KrstenicaPost) *dto.Krstenica {
 // 	return &dto.Krstenica{
 // 		ID:            krstenica.ID,
@@ -238,6 +358,7 @@ func makeKrstenicaResponse(krstenica *model.Krstenica) *dto.Krstenica {
 	return &dto.Krstenica{
 		ID:                     krstenica.ID,
 		Book:                   krstenica.Book,
+		BookId:                 int64Ptr(krstenica.BookId),
 		Page:                   krstenica.Page,
 		CurrentNumber:          krstenica.CurrentNumber,
 		EparhijaId:             int64Ptr(krstenica.EparhijaId),
@@ -245,18 +366,25 @@ func makeKrstenicaResponse(krstenica *model.Krstenica) *dto.Krstenica {
 		TampleId:               int64Ptr(krstenica.TampleId),
 		TampleName:             krstenica.TampleName,
 		TampleCity:             krstenica.TampleCity,
-		ParentId:               int64Ptr(krstenica.ParentId),
-		ParentFirstName:        krstenica.ParentFirstName,
-		ParentLastName:         krstenica.ParentLastName,
-		ParentOccupation:       krstenica.ParentOccupation,
-		ParentCity:             krstenica.ParentCity,
-		ParentReligion:         krstenica.ParentReligion,
+		FatherId:               int64Ptr(krstenica.FatherId),
+		FatherFirstName:        krstenica.FatherFirstName,
+		FatherLastName:         krstenica.FatherLastName,
+		FatherOccupation:       krstenica.FatherOccupation,
+		FatherCity:             krstenica.FatherCity,
+		FatherReligion:         krstenica.FatherReligion,
+		MotherId:               int64Ptr(krstenica.MotherId),
+		MotherFirstName:        krstenica.MotherFirstName,
+		MotherLastName:         krstenica.MotherLastName,
+		MotherOccupation:       krstenica.MotherOccupation,
+		MotherCity:             krstenica.MotherCity,
+		MotherReligion:         krstenica.MotherReligion,
 		GodfatherId:            int64Ptr(krstenica.GodfatherId),
 		GodfatherFirstName:     krstenica.GodfatherFirstName,
 		GodfatherLastName:      krstenica.GodfatherLastName,
 		GodfatherOccupation:    krstenica.GodfatherOccupation,
 		GodfatherCity:          krstenica.GodfatherCity,
 		GodfatherReligion:      krstenica.GodfatherReligion,
+		Godparents:             makeKrstenicaGodparentsResponse(krstenica.Godparents),
 		ParohId:                int64Ptr(krstenica.ParohId),
 		ParohFirstName:         krstenica.ParohFirstName,
 		ParohLastName:          krstenica.ParohLastName,
@@ -284,7 +412,79 @@ func makeKrstenicaResponse(krstenica *model.Krstenica) *dto.Krstenica {
 		Comment:                krstenica.Comment,
 		Status:                 string(krstenica.Status),
 		CreatedAt:              krstenica.CreatedAt.Time,
+		Score:                  float64Ptr(krstenica.Score),
+	}
+}
+
+func makeKrstenicaGodparentsResponse(godparents []model.KrstenicaGodparent) []dto.KrstenicaGodparent {
+	res := make([]dto.KrstenicaGodparent, len(godparents))
+	for i, g := range godparents {
+		res[i] = dto.KrstenicaGodparent{
+			PersonId:   g.PersonId,
+			Position:   g.Position,
+			FirstName:  g.FirstName,
+			LastName:   g.LastName,
+			Occupation: g.Occupation,
+			City:       g.City,
+			Religion:   g.Religion,
+		}
+	}
+	return res
+}
+
+// normalizeGodparentIds odbacuje neispravne i ponovljene ID-jeve, a redosled čuva.
+func normalizeGodparentIds(ids []int64) []int64 {
+	res := make([]int64, 0, len(ids))
+	seen := map[int64]bool{}
+	for _, id := range ids {
+		if id <= 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		res = append(res, id)
+	}
+	return res
+}
+
+// resolveGodparentsForUpdate vraća novi spisak kumova. Ako je poslat samo
+// godfather_id, menja se prvi kum, a ostali ostaju.
+func (s *service) resolveGodparentsForUpdate(ctx context.Context, current *model.Krstenica, krstenicaReq *dto.KrstenicaUpdateReq) ([]int64, error) {
+	var ids []int64
+	if krstenicaReq.GodparentIds != nil {
+		ids = normalizeGodparentIds(krstenicaReq.GodparentIds)
+	} else {
+		ids = []int64{*krstenicaReq.GodfatherId}
+		for _, g := range current.Godparents {
+			ids = append(ids, g.PersonId)
+		}
+		if len(current.Godparents) > 0 {
+			ids = append(ids[:1], ids[2:]...)
+		}
+		ids = normalizeGodparentIds(ids)
+	}
+	if len(ids) == 0 {
+		return nil, errorx.GetValidationError("Krstenica", "validation", "At least one godparent is required")
+	}
+	if err := s.checkGodparentsExist(ctx, ids); err != nil {
+		return nil, err
 	}
+	return ids, nil
+}
+
+func (s *service) checkGodparentsExist(ctx context.Context, ids []int64) error {
+	for _, id := range ids {
+		person, err := s.repo.GetPersonByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, errorx.ErrPersonNotFound) {
+				return errorx.GetValidationError("Krstenica", "validation", "Selected godparent does not exist")
+			}
+			return err
+		}
+		if person.Status == string(model.PersonStatusDeleted) {
+			return errorx.GetValidationError("Krstenica", "validation", "Selected godparent does not exist")
+		}
+	}
+	return nil
 }
 
 func int64Ptr(value sql.NullInt64) *int64 {
@@ -295,7 +495,32 @@ func int64Ptr(value sql.NullInt64) *int64 {
 	return &v
 }
 
+func float64Ptr(value sql.NullFloat64) *float64 {
+	if !value.Valid {
+		return nil
+	}
+	v := value.Float64
+	return &v
+}
+
 func validateKrstenicaCreaterequest(krstenicaReq *dto.KrstenicaCreateReq) error {
+	if krstenicaReq.FatherId != nil && *krstenicaReq.FatherId <= 0 {
+		krstenicaReq.FatherId = nil
+	}
+	if krstenicaReq.MotherId != nil && *krstenicaReq.MotherId <= 0 {
+		krstenicaReq.MotherId = nil
+	}
+	if krstenicaReq.FatherId == nil && krstenicaReq.MotherId == nil {
+		return errorx.GetValidationError("Krstenica", "validation", "At least one parent (father or mother) is required")
+	}
+	if len(krstenicaReq.GodparentIds) == 0 && krstenicaReq.GodfatherId > 0 {
+		krstenicaReq.GodparentIds = []int64{krstenicaReq.GodfatherId}
+	}
+	krstenicaReq.GodparentIds = normalizeGodparentIds(krstenicaReq.GodparentIds)
+	if len(krstenicaReq.GodparentIds) == 0 {
+		return errorx.GetValidationError("Krstenica", "validation", "At least one godparent is required")
+	}
+	krstenicaReq.GodfatherId = krstenicaReq.GodparentIds[0]
 	if len(krstenicaReq.FirstName) > 255 {
 		return errorx.GetValidationError("Krstenica", "validation", "First name of krstenica can not be longer than 255 characters")
 	}
@@ -341,6 +566,13 @@ func validateKrstenicaUpdateRequest(krstenicaReq *dto.KrstenicaUpdateReq) (map[s
 	if krstenicaReq.Book != nil {
 		updates["book"] = *krstenicaReq.Book
 	}
+	if krstenicaReq.BookId != nil {
+		if *krstenicaReq.BookId <= 0 {
+			updates["book_id"] = nil
+		} else {
+			updates["book_id"] = *krstenicaReq.BookId
+		}
+	}
 	if krstenicaReq.Page != nil {
 		updates["page"] = *krstenicaReq.Page
 	}
@@ -353,8 +585,19 @@ func validateKrstenicaUpdateRequest(krstenicaReq *dto.KrstenicaUpdateReq) (map[s
 	if krstenicaReq.TampleId != nil {
 		updates["tample_id"] = *krstenicaReq.TampleId
 	}
-	if krstenicaReq.ParentId != nil {
-		updates["parent_id"] = *krstenicaReq.ParentId
+	if krstenicaReq.FatherId != nil {
+		if *krstenicaReq.FatherId <= 0 {
+			updates["father_id"] = nil
+		} else {
+			updates["father_id"] = *krstenicaReq.FatherId
+		}
+	}
+	if krstenicaReq.MotherId != nil {
+		if *krstenicaReq.MotherId <= 0 {
+			updates["mother_id"] = nil
+		} else {
+			updates["mother_id"] = *krstenicaReq.MotherId
+		}
 	}
 	if krstenicaReq.GodfatherId != nil {
 		updates["godfather_id"] = *krstenicaReq.GodfatherId
@@ -497,5 +740,5 @@ func enforceCityPermission(ctx context.Context, recordCity string) error {
 	if strings.EqualFold(strings.TrimSpace(user.City), strings.TrimSpace(recordCity)) {
 		return nil
 	}
-	return errors.New("немате дозволу за овај град")
+	return errorx.ErrCityForbidden
 }
@@ -0,0 +1,644 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+
+	"krstenica/internal/dto"
+	"krstenica/internal/errorx"
+	"krstenica/internal/repository"
+	"krstenica/internal/requestctx"
+	"krstenica/pkg"
+)
+
+// Vrste stavki u izvestaju uvoza.
+const (
+	importIssueError     = "error"
+	importIssueDuplicate = "duplicate"
+	importIssueWarning   = "warning"
+)
+
+// errImportRollback ponistava transakciju uvoza kada se nista ne upisuje.
+var errImportRollback = errors.New("import rolled back")
+
+// errImportRowRejected vraca savepoint reda na pocetak kada red ima greske.
+var errImportRowRejected = errors.New("import row rejected")
+
+type krstenicaImportField struct {
+	Key     string
+	Headers []string
+}
+
+// krstenicaImportFields su polja koja se mogu mapirati na kolone uvoza, sa
+// podrazumevanim naslovima kolona (isti kao u izvozu i na formi za unos).
+var krstenicaImportFields = []krstenicaImportField{
+	{Key: "book", Headers: []string{"Књига"}},
+	{Key: "page", Headers: []string{"Страна", "Страна књиге"}},
+	{Key: "current_number", Headers: []string{"Текући број"}},
+	{Key: "eparhija", Headers: []string{"Епархија"}},
+	{Key: "tample", Headers: []string{"Храм"}},
+	{Key: "tample_city", Headers: []string{"Место храма"}},
+	{Key: "first_name", Headers: []string{"Име", "Име детета"}},
+	{Key: "last_name", Headers: []string{"Презиме"}},
+	{Key: "gender", Headers: []string{"Пол", "Пол детета"}},
+	{Key: "city", Headers: []string{"Град"}},
+	{Key: "country", Headers: []string{"Држава"}},
+	{Key: "birth_date", Headers: []string{"Датум рођења", "Датум и време рођења"}},
+	{Key: "birth_order", Headers: []string{"Рођење - редослед"}},
+	{Key: "place_of_birthday", Headers: []string{"Место рођења"}},
+	{Key: "municipality_of_birthday", Headers: []string{"Општина рођења"}},
+	{Key: "baptism", Headers: []string{"Датум крштења"}},
+	{Key: "father", Headers: []string{"Отац"}},
+	{Key: "mother", Headers: []string{"Мајка"}},
+	{Key: "godparents", Headers: []string{"Кумови", "Кум"}},
+	{Key: "priest", Headers: []string{"Свештеник"}},
+	{Key: "paroh", Headers: []string{"Парох"}},
+	{Key: "is_church_married", Headers: []string{"Је ли дете црквено брачно?"}},
+	{Key: "is_twin", Headers: []string{"Дете је близанац?"}},
+	{Key: "has_physical_disability", Headers: []string{"Физички недостатак?"}},
+	{Key: "anagrafa", Headers: []string{"Страна домовника / анаграф"}},
+	{Key: "number_of_certificate", Headers: []string{"Број сертификата"}},
+	{Key: "town_of_certificate", Headers: []string{"Место сертификата"}},
+	{Key: "certificate", Headers: []string{"Датум издавања сертификата"}},
+	{Key: "comment", Headers: []string{"Напомена"}},
+}
+
+var importDateLayouts = []string{
+	"02.01.2006.", "02.01.2006", "2.1.2006.", "2.1.2006",
+	"02.01.2006. 15:04", "02.01.2006 15:04",
+	"2006-01-02", "2006-01-02 15:04", "2006-01-02T15:04:05",
+	"02/01/2006",
+}
+
+// KrstenicaImportMappingTemplate vraca podrazumevano mapiranje kolona u obliku
+// koji ImportKrstenice prima, da bi se moglo menjati.
+func KrstenicaImportMappingTemplate() string {
+	var b strings.Builder
+	for _, field := range krstenicaImportFields {
+		fmt.Fprintf(&b, "%s=%s\n", field.Key, field.Headers[0])
+	}
+	return b.String()
+}
+
+type krstenicaImportRow struct {
+	number  int
+	values  []string
+	columns map[string]int
+	issues  []dto.KrstenicaImportIssue
+	created []dto.KrstenicaImportCreated
+}
+
+func (r *krstenicaImportRow) value(field string) string {
+	idx, ok := r.columns[field]
+	if !ok || idx >= len(r.values) {
+		return ""
+	}
+	return strings.TrimSpace(r.values[idx])
+}
+
+func (r *krstenicaImportRow) addIssue(kind, field, message string) {
+	r.issues = append(r.issues, dto.KrstenicaImportIssue{Row: r.number, Kind: kind, Field: field, Message: message})
+}
+
+func (r *krstenicaImportRow) hasIssue(kind string) bool {
+	for _, issue := range r.issues {
+		if issue.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// ImportKrstenice uvozi krstenice iz XLSX ili CSV datoteke. Svi redovi se
+// upisuju u jednoj transakciji, svaki red u svom savepoint-u; osobe, svestenici,
+// hramovi i eparhije se pronalaze po imenu ili se prave. Transakcija se
+// potvrdjuje samo ako nije probni uvoz i ako nijedan red nema gresku ili
+// duplikat (osim kada se duplikati preskacu); u suprotnom izvestaj opisuje
+// sta bi bilo upisano.
+func (s *service) ImportKrstenice(ctx context.Context, req *dto.KrstenicaImportReq, fileName string, content []byte) (*dto.KrstenicaImportReport, error) {
+	if user, ok := requestctx.UserFromContext(ctx); ok && !user.IsAdmin() {
+		return nil, errors.New("увоз крштеница је дозвољен само администратору")
+	}
+	if req == nil {
+		req = &dto.KrstenicaImportReq{DryRun: true}
+	}
+
+	mapping, err := parseKrstenicaImportMapping(req.Mapping)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := readKrstenicaImportRows(fileName, content)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if len(rows) < 2 {
+		return nil, errorx.ErrImportEmpty
+	}
+
+	report := &dto.KrstenicaImportReport{
+		DryRun:  req.DryRun,
+		Columns: map[string]string{},
+		Issues:  []dto.KrstenicaImportIssue{},
+		Created: []dto.KrstenicaImportCreated{},
+	}
+	columns, issues := resolveKrstenicaImportColumns(rows[0], mapping, report.Columns)
+	if len(issues) > 0 {
+		report.Issues = issues
+		report.Errors = len(issues)
+		return report, nil
+	}
+
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		for i, values := range rows[1:] {
+			if isBlankImportRow(values) {
+				continue
+			}
+			report.Rows++
+
+			row := &krstenicaImportRow{number: i + 2, values: values, columns: columns}
+			rowErr := txRepo.Transaction(ctx, func(rowRepo repository.Repo) error {
+				rowService := &service{conf: s.conf, repo: rowRepo}
+				return rowService.importKrstenicaRow(ctx, row)
+			})
+			if rowErr != nil && !errors.Is(rowErr, errImportRowRejected) {
+				log.Println(rowErr)
+				row.addIssue(importIssueError, "", rowErr.Error())
+			}
+
+			for _, issue := range row.issues {
+				switch issue.Kind {
+				case importIssueError:
+					report.Errors++
+				case importIssueDuplicate:
+					report.Duplicates++
+				case importIssueWarning:
+					report.Warnings++
+				}
+			}
+			report.Issues = append(report.Issues, row.issues...)
+
+			switch {
+			case rowErr == nil:
+				report.Imported++
+				report.Created = append(report.Created, row.created...)
+			case req.SkipDuplicates && row.hasIssue(importIssueDuplicate) && !row.hasIssue(importIssueError):
+				report.Skipped++
+			}
+		}
+
+		if req.DryRun || report.Errors > 0 || (report.Duplicates > 0 && !req.SkipDuplicates) {
+			return errImportRollback
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errImportRollback) {
+		log.Println(err)
+		return nil, err
+	}
+	report.Committed = err == nil
+
+	return report, nil
+}
+
+// importKrstenicaRow upisuje jedan red; vraca errImportRowRejected kada red ima
+// gresku ili duplikat, da bi se ponistile i osobe napravljene za taj red.
+func (s *service) importKrstenicaRow(ctx context.Context, row *krstenicaImportRow) error {
+	req := &dto.KrstenicaCreateReq{
+		Book:                   row.value("book"),
+		FirstName:              row.value("first_name"),
+		LastName:               row.value("last_name"),
+		Gender:                 row.value("gender"),
+		City:                   row.value("city"),
+		Country:                row.value("country"),
+		BirthOrder:             row.value("birth_order"),
+		PlaceOfBirthday:        row.value("place_of_birthday"),
+		MunicipalityOfBirthday: row.value("municipality_of_birthday"),
+		IsChurchMarried:        row.value("is_church_married"),
+		IsTwin:                 row.value("is_twin"),
+		HasPhysicalDisability:  row.value("has_physical_disability"),
+		Anagrafa:               row.value("anagrafa"),
+		NumberOfCertificate:    row.value("number_of_certificate"),
+		TownOfCertificate:      row.value("town_of_certificate"),
+		Comment:                row.value("comment"),
+	}
+	req.Page = row.parseInt("page")
+	req.CurrentNumber = row.parseInt("current_number")
+	req.BirthDate = row.parseDate("birth_date")
+	req.Baptism = row.parseDate("baptism")
+	req.Certificate = row.parseDate("certificate")
+	if row.hasIssue(importIssueError) {
+		return errImportRowRejected
+	}
+
+	var err error
+	tampleCity := row.value("tample_city")
+	if req.EparhijaId, err = s.resolveImportEparhija(ctx, row, row.value("eparhija"), tampleCity); err != nil {
+		return err
+	}
+	if req.TampleId, err = s.resolveImportTample(ctx, row, row.value("tample"), tampleCity); err != nil {
+		return err
+	}
+	if req.PriestId, err = s.resolveImportPriest(ctx, row, row.value("priest"), tampleCity); err != nil {
+		return err
+	}
+	if req.FatherId, err = s.resolveImportPersonPtr(ctx, row, "father", req.City); err != nil {
+		return err
+	}
+	if req.MotherId, err = s.resolveImportPersonPtr(ctx, row, "mother", req.City); err != nil {
+		return err
+	}
+	if req.ParohId, err = s.resolveImportPersonPtr(ctx, row, "paroh", req.City); err != nil {
+		return err
+	}
+	for _, name := range splitImportList(row.value("godparents")) {
+		id, err := s.resolveImportPerson(ctx, row, "godparents", name, req.City)
+		if err != nil {
+			return err
+		}
+		req.GodparentIds = append(req.GodparentIds, id)
+	}
+
+	if err := validateKrstenicaCreaterequest(req); err != nil {
+		row.addIssue(importIssueError, "", err.Error())
+		return errImportRowRejected
+	}
+
+	if err := s.checkImportDuplicates(ctx, row, req); err != nil {
+		return err
+	}
+	if row.hasIssue(importIssueDuplicate) {
+		return errImportRowRejected
+	}
+
+	if _, err := s.CreateKrstenica(ctx, req); err != nil {
+		if errors.Is(err, errorx.ErrBookNumberTaken) {
+			row.addIssue(importIssueDuplicate, "current_number", err.Error())
+		} else {
+			row.addIssue(importIssueError, "", err.Error())
+		}
+		return errImportRowRejected
+	}
+
+	return nil
+}
+
+// checkImportDuplicates prijavljuje krstenice koje vec postoje sa istom
+// knjigom, stranom i tekucim brojem, ili sa istim imenom i datumom rodjenja.
+// Redovi upisani ranije u istom uvozu se takodje vide.
+func (s *service) checkImportDuplicates(ctx context.Context, row *krstenicaImportRow, req *dto.KrstenicaCreateReq) error {
+	if req.TampleId > 0 && strings.TrimSpace(req.Book) != "" && req.Page > 0 && req.CurrentNumber > 0 {
+		existing, _, err := s.repo.ListKrstenice(ctx, importLookup(map[string]string{
+			"tample_id":      strconv.FormatInt(req.TampleId, 10),
+			"book":           strings.TrimSpace(req.Book),
+			"page":           strconv.FormatInt(req.Page, 10),
+			"current_number": strconv.FormatInt(req.CurrentNumber, 10),
+		}))
+		if err != nil {
+			return err
+		}
+		if len(existing) > 0 {
+			row.addIssue(importIssueDuplicate, "current_number", fmt.Sprintf("%s (ID %d)", errorx.ErrBookNumberTaken.Error(), existing[0].ID))
+		}
+	}
+
+	if req.BirthDate.IsZero() || req.FirstName == "" || req.LastName == "" {
+		return nil
+	}
+	filters := map[string]string{"first_name": req.FirstName, "last_name": req.LastName}
+	if req.TampleId > 0 {
+		filters["tample_id"] = strconv.FormatInt(req.TampleId, 10)
+	}
+	existing, _, err := s.repo.ListKrstenice(ctx, importLookup(filters))
+	if err != nil {
+		return err
+	}
+	birthDate := req.BirthDate.Format("2006-01-02")
+	for _, krstenica := range existing {
+		if krstenica.BirthDate.Valid && krstenica.BirthDate.Time.In(time.Local).Format("2006-01-02") == birthDate {
+			row.addIssue(importIssueDuplicate, "birth_date", fmt.Sprintf("већ постоји крштеница истог имена и датума рођења (ID %d)", krstenica.ID))
+			break
+		}
+	}
+
+	return nil
+}
+
+func (s *service) resolveImportEparhija(ctx context.Context, row *krstenicaImportRow, name, city string) (int64, error) {
+	if name == "" {
+		return 0, nil
+	}
+	list, _, err := s.repo.ListEparhije(ctx, importLookup(map[string]string{"name": name}))
+	if err != nil {
+		return 0, err
+	}
+	if len(list) > 0 {
+		warnAmbiguousImport(row, "eparhija", name, len(list), list[0].ID)
+		return list[0].ID, nil
+	}
+
+	created, err := s.CreateEparhije(ctx, &dto.EparhijeCreateReq{Name: name, City: city})
+	if err != nil {
+		return 0, err
+	}
+	row.created = append(row.created, dto.KrstenicaImportCreated{Row: row.number, Entity: "eparhija", Name: name})
+	return created.ID, nil
+}
+
+func (s *service) resolveImportTample(ctx context.Context, row *krstenicaImportRow, name, city string) (int64, error) {
+	if name == "" {
+		return 0, nil
+	}
+	filters := map[string]string{"name": name}
+	if city != "" {
+		filters["city"] = city
+	}
+	list, _, err := s.repo.ListTamples(ctx, importLookup(filters))
+	if err != nil {
+		return 0, err
+	}
+	if len(list) > 0 {
+		warnAmbiguousImport(row, "tample", name, len(list), list[0].ID)
+		return list[0].ID, nil
+	}
+
+	created, err := s.CreateTample(ctx, &dto.TampleCreateReq{Name: name, City: city})
+	if err != nil {
+		return 0, err
+	}
+	row.created = append(row.created, dto.KrstenicaImportCreated{Row: row.number, Entity: "tample", Name: name})
+	return created.ID, nil
+}
+
+func (s *service) resolveImportPriest(ctx context.Context, row *krstenicaImportRow, name, city string) (int64, error) {
+	title, firstName, lastName := splitImportPriestName(name)
+	if firstName == "" && lastName == "" {
+		return 0, nil
+	}
+	list, _, err := s.repo.ListPriests(ctx, importLookup(map[string]string{"first_name": firstName, "last_name": lastName}))
+	if err != nil {
+		return 0, err
+	}
+	if len(list) > 0 {
+		warnAmbiguousImport(row, "priest", name, len(list), list[0].ID)
+		return list[0].ID, nil
+	}
+
+	created, err := s.CreatePriest(ctx, &dto.PriestCreateReq{Title: title, FirstName: firstName, LastName: lastName, City: city})
+	if err != nil {
+		return 0, err
+	}
+	row.created = append(row.created, dto.KrstenicaImportCreated{Row: row.number, Entity: "priest", Name: name})
+	return created.ID, nil
+}
+
+func (s *service) resolveImportPersonPtr(ctx context.Context, row *krstenicaImportRow, field, city string) (*int64, error) {
+	name := row.value(field)
+	if name == "" {
+		return nil, nil
+	}
+	id, err := s.resolveImportPerson(ctx, row, field, name, city)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// resolveImportPerson trazi osobu po imenu i prezimenu (bez obzira na pismo i
+// dijakritike) i gradu krstenice; ako je nema, pravi novu.
+func (s *service) resolveImportPerson(ctx context.Context, row *krstenicaImportRow, field, name, city string) (int64, error) {
+	firstName, lastName := splitImportName(name)
+	filters := map[string]string{"first_name": firstName, "last_name": lastName}
+	if city != "" {
+		filters["city"] = city
+	}
+	list, _, err := s.repo.ListPersons(ctx, importLookup(filters))
+	if err != nil {
+		return 0, err
+	}
+	if len(list) > 0 {
+		warnAmbiguousImport(row, field, name, len(list), list[0].ID)
+		return list[0].ID, nil
+	}
+
+	created, err := s.CreatePerson(ctx, &dto.PersonCreateReq{FirstName: firstName, LastName: lastName, City: city})
+	if err != nil {
+		return 0, err
+	}
+	row.created = append(row.created, dto.KrstenicaImportCreated{Row: row.number, Entity: "person", Name: name})
+	return created.ID, nil
+}
+
+func warnAmbiguousImport(row *krstenicaImportRow, field, name string, matches int, id int64) {
+	if matches < 2 {
+		return
+	}
+	row.addIssue(importIssueWarning, field, fmt.Sprintf("више записа одговара имену %q, узет је најстарији (ID %d)", name, id))
+}
+
+// importLookup pravi upit za trazenje po jednakosti, najstariji zapis prvi.
+// Dva reda su dovoljna da se prepozna visesmisleno ime.
+func importLookup(filters map[string]string) *pkg.FilterAndSort {
+	filterAndSort := ensureFilterAndSort(nil)
+	for property, value := range filters {
+		filterAndSort.Filters[pkg.FilterKey{Property: property, Operator: "eq"}] = []string{value}
+	}
+	filterAndSort.Sort = append(filterAndSort.Sort, &pkg.SortOptions{Property: "id", Direction: "ASC"})
+	filterAndSort.Paging.PageSize = "2"
+
+	return filterAndSort
+}
+
+func (r *krstenicaImportRow) parseInt(field string) int64 {
+	value := r.value(field)
+	if value == "" {
+		return 0
+	}
+	number, err := strconv.ParseFloat(strings.ReplaceAll(value, " ", ""), 64)
+	if err != nil || number != float64(int64(number)) {
+		r.addIssue(importIssueError, field, fmt.Sprintf("неисправан број %q", value))
+		return 0
+	}
+	return int64(number)
+}
+
+// parseDate prihvata uobicajene zapise datuma i Excel serijske brojeve.
+func (r *krstenicaImportRow) parseDate(field string) time.Time {
+	value := r.value(field)
+	if value == "" {
+		return time.Time{}
+	}
+	for _, layout := range importDateLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t
+		}
+	}
+	// manji brojevi su verovatno godine, a ne Excel datumi
+	if serial, err := strconv.ParseFloat(value, 64); err == nil && serial > 2100 {
+		if t, err := excelize.ExcelDateToTime(serial, false); err == nil {
+			return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.Local)
+		}
+	}
+
+	r.addIssue(importIssueError, field, fmt.Sprintf("неисправан датум %q, очекује се нпр. 02.01.2006", value))
+	return time.Time{}
+}
+
+// splitImportName deli "Ime Prezime": poslednja rec je prezime, ostalo ime.
+func splitImportName(name string) (string, string) {
+	words := strings.Fields(name)
+	switch len(words) {
+	case 0:
+		return "", ""
+	case 1:
+		return words[0], ""
+	}
+	return strings.Join(words[:len(words)-1], " "), words[len(words)-1]
+}
+
+// splitImportPriestName deli "zvanje Ime Prezime" kako ga pise izvoz krstenica.
+func splitImportPriestName(name string) (string, string, string) {
+	words := strings.Fields(name)
+	if len(words) < 3 {
+		firstName, lastName := splitImportName(name)
+		return "", firstName, lastName
+	}
+	n := len(words)
+	return strings.Join(words[:n-2], " "), words[n-2], words[n-1]
+}
+
+func splitImportList(value string) []string {
+	parts := strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' })
+	res := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			res = append(res, part)
+		}
+	}
+	return res
+}
+
+func isBlankImportRow(values []string) bool {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// parseKrstenicaImportMapping cita redove oblika polje=Naslov kolone.
+func parseKrstenicaImportMapping(text string) (map[string]string, error) {
+	mapping := map[string]string{}
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, header, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		header = strings.TrimSpace(header)
+		if !ok || key == "" {
+			return nil, errorx.GetValidationError("Import", "validation", fmt.Sprintf("mapping line %d must look like field=Column header", i+1))
+		}
+		if findKrstenicaImportField(key) == nil {
+			return nil, errorx.GetValidationError("Import", "validation", fmt.Sprintf("unknown field %q in mapping line %d", key, i+1))
+		}
+		mapping[key] = header
+	}
+	return mapping, nil
+}
+
+func findKrstenicaImportField(key string) *krstenicaImportField {
+	for i := range krstenicaImportFields {
+		if krstenicaImportFields[i].Key == key {
+			return &krstenicaImportFields[i]
+		}
+	}
+	return nil
+}
+
+// resolveKrstenicaImportColumns pronalazi kolonu za svako polje. Polje iz
+// mapiranja mora da postoji u zaglavlju (prazan naslov iskljucuje polje);
+// ostala polja se traze po podrazumevanim naslovima i po kljucu polja.
+// Naslovi se porede bez obzira na pismo, dijakritike i velika slova.
+func resolveKrstenicaImportColumns(header []string, mapping map[string]string, found map[string]string) (map[string]int, []dto.KrstenicaImportIssue) {
+	index := map[string]int{}
+	for i, title := range header {
+		key := pkg.FoldSearchText(strings.TrimSpace(title))
+		if _, ok := index[key]; !ok && key != "" {
+			index[key] = i
+		}
+	}
+
+	columns := map[string]int{}
+	issues := []dto.KrstenicaImportIssue{}
+	for _, field := range krstenicaImportFields {
+		titles := append([]string{field.Key}, field.Headers...)
+		if mapped, ok := mapping[field.Key]; ok {
+			if mapped == "" {
+				continue
+			}
+			titles = []string{mapped}
+		}
+
+		matched := false
+		for _, title := range titles {
+			if i, ok := index[pkg.FoldSearchText(title)]; ok {
+				columns[field.Key] = i
+				found[field.Key] = strings.TrimSpace(header[i])
+				matched = true
+				break
+			}
+		}
+		if !matched && mapping[field.Key] != "" {
+			issues = append(issues, dto.KrstenicaImportIssue{Row: 1, Kind: importIssueError, Field: field.Key, Message: fmt.Sprintf("колона %q није пронађена у заглављу", mapping[field.Key])})
+		}
+	}
+	if _, ok := columns["first_name"]; !ok && len(issues) == 0 {
+		issues = append(issues, dto.KrstenicaImportIssue{Row: 1, Kind: importIssueError, Field: "first_name", Message: "заглавље нема колону са именом детета"})
+	}
+
+	return columns, issues
+}
+
+// readKrstenicaImportRows cita prvi list XLSX datoteke ili CSV (zarez ili
+// tacka-zarez kao separator). Prvi red je zaglavlje.
+func readKrstenicaImportRows(fileName string, content []byte) ([][]string, error) {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".xlsx":
+		xlsx, err := excelize.OpenReader(bytes.NewReader(content))
+		if err != nil {
+			return nil, err
+		}
+		defer xlsx.Close()
+
+		sheets := xlsx.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, errorx.ErrImportEmpty
+		}
+		return xlsx.GetRows(sheets[0], excelize.Options{RawCellValue: true})
+	case ".csv":
+		content = bytes.TrimPrefix(content, []byte("\xEF\xBB\xBF"))
+		reader := csv.NewReader(bytes.NewReader(content))
+		reader.FieldsPerRecord = -1
+		reader.LazyQuotes = true
+		firstLine, _, _ := bytes.Cut(content, []byte("\n"))
+		if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+			reader.Comma = ';'
+		}
+		return reader.ReadAll()
+	}
+
+	return nil, errorx.ErrImportFormat
+}
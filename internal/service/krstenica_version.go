@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"sort"
+	"time"
+
+	"krstenica/internal/dto"
+	"krstenica/internal/errorx"
+	"krstenica/internal/model"
+	"krstenica/internal/requestctx"
+)
+
+// recordKrstenicaVersion čuva snimak krštenice posle izmene. before je stanje
+// pre izmene i upisuje se kao prva verzija ako krštenica još nema istoriju.
+// Greška se samo loguje jer je sama izmena već sačuvana.
+func (s *service) recordKrstenicaVersion(ctx context.Context, before, after *model.Krstenica, restoredFrom int64) {
+	snapshot, err := json.Marshal(after)
+	if err != nil {
+		log.Println("krstenica version:", err)
+		return
+	}
+
+	version := &model.KrstenicaVersion{
+		Snapshot:  string(snapshot),
+		CreatedAt: time.Now(),
+	}
+	if restoredFrom > 0 {
+		version.RestoredFromVersion = sql.NullInt64{Valid: true, Int64: restoredFrom}
+	}
+	if user, ok := requestctx.UserFromContext(ctx); ok {
+		if user.ID > 0 {
+			version.CreatedById = sql.NullInt64{Valid: true, Int64: user.ID}
+		}
+		version.CreatedByUsername = user.Username
+	}
+
+	var baseline *model.KrstenicaVersion
+	if before != nil {
+		previous, err := json.Marshal(before)
+		if err != nil {
+			log.Println("krstenica version:", err)
+			return
+		}
+		if string(previous) == string(snapshot) {
+			return
+		}
+		baseline = &model.KrstenicaVersion{Snapshot: string(previous), CreatedAt: time.Now()}
+		if before.CreatedAt.Valid {
+			baseline.CreatedAt = before.CreatedAt.Time
+		}
+	}
+
+	if err := s.repo.CreateKrstenicaVersion(ctx, after.ID, baseline, version); err != nil {
+		log.Println("krstenica version:", err)
+	}
+}
+
+// ListKrstenicaVersions vraća verzije od najnovije; prva u listi je trenutno stanje.
+func (s *service) ListKrstenicaVersions(ctx context.Context, krstenicaID int64) ([]*dto.KrstenicaVersion, error) {
+	if _, err := s.getPermittedKrstenica(ctx, krstenicaID); err != nil {
+		return nil, err
+	}
+
+	versions, err := s.repo.ListKrstenicaVersions(ctx, krstenicaID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	snapshots := make([]*model.Krstenica, len(versions))
+	for i := range versions {
+		snapshots[i], err = decodeKrstenicaSnapshot(&versions[i])
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+	}
+
+	res := make([]*dto.KrstenicaVersion, len(versions))
+	for i := range versions {
+		var previous *model.Krstenica
+		if i+1 < len(versions) {
+			previous = snapshots[i+1]
+		}
+		res[i] = makeKrstenicaVersionResponse(&versions[i], snapshots[i], previous)
+		res[i].Current = i == 0
+	}
+	return res, nil
+}
+
+func (s *service) GetKrstenicaVersion(ctx context.Context, krstenicaID, version int64) (*dto.KrstenicaVersion, error) {
+	versions, err := s.ListKrstenicaVersions(ctx, krstenicaID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range versions {
+		if v.Version == version {
+			return v, nil
+		}
+	}
+	return nil, errorx.ErrKrstenicaVersionNotFound
+}
+
+// RestoreKrstenicaVersion vraća podatke iz ranije verzije kao običnu izmenu:
+// prolazi istu validaciju i pravi novu verziju. Status krštenice se ne menja.
+func (s *service) RestoreKrstenicaVersion(ctx context.Context, krstenicaID, version int64) (*dto.Krstenica, error) {
+	if _, err := s.getPermittedKrstenica(ctx, krstenicaID); err != nil {
+		return nil, err
+	}
+
+	versions, err := s.repo.ListKrstenicaVersions(ctx, krstenicaID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if len(versions) > 0 && versions[0].Version == version {
+		return nil, errorx.GetValidationError("Krstenica", "validation", "Version is already the current one")
+	}
+
+	target, err := s.repo.GetKrstenicaVersion(ctx, krstenicaID, version)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	snapshot, err := decodeKrstenicaSnapshot(target)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return s.updateKrstenica(ctx, krstenicaID, makeKrstenicaRestoreRequest(snapshot), version)
+}
+
+func decodeKrstenicaSnapshot(version *model.KrstenicaVersion) (*model.Krstenica, error) {
+	var krstenica model.Krstenica
+	if err := json.Unmarshal([]byte(version.Snapshot), &krstenica); err != nil {
+		return nil, err
+	}
+	return &krstenica, nil
+}
+
+// makeKrstenicaRestoreRequest pravi zahtev za izmenu koji postavlja sva polja
+// snimka. Prazni datumi i obavezne veze ostaju kakvi su trenutno.
+func makeKrstenicaRestoreRequest(k *model.Krstenica) *dto.KrstenicaUpdateReq {
+	req := &dto.KrstenicaUpdateReq{
+		Book:                   &k.Book,
+		Page:                   &k.Page,
+		CurrentNumber:          &k.CurrentNumber,
+		FirstName:              &k.FirstName,
+		LastName:               &k.LastName,
+		Gender:                 &k.Gender,
+		City:                   &k.City,
+		Country:                &k.Country,
+		BirthOrder:             &k.BirthOrder,
+		PlaceOfBirthday:        &k.PlaceOfBirthday,
+		MunicipalityOfBirthday: &k.MunicipalityOfBirthday,
+		IsChurchMarried:        &k.IsChurchMarried,
+		IsTwin:                 &k.IsTwin,
+		HasPhysicalDisability:  &k.HasPhysicalDisability,
+		Anagrafa:               &k.Anagrafa,
+		NumberOfCertificate:    &k.NumberOfCertificate,
+		TownOfCertificate:      &k.TownOfCertificate,
+		Comment:                &k.Comment,
+		BookId:                 restoreOptionalID(k.BookId),
+		FatherId:               restoreOptionalID(k.FatherId),
+		MotherId:               restoreOptionalID(k.MotherId),
+		EparhijaId:             int64Ptr(k.EparhijaId),
+		TampleId:               int64Ptr(k.TampleId),
+		ParohId:                int64Ptr(k.ParohId),
+		PriestId:               int64Ptr(k.PriestId),
+	}
+
+	godparents := append([]model.KrstenicaGodparent(nil), k.Godparents...)
+	sort.Slice(godparents, func(i, j int) bool { return godparents[i].Position < godparents[j].Position })
+	for _, g := range godparents {
+		req.GodparentIds = append(req.GodparentIds, g.PersonId)
+	}
+	if len(req.GodparentIds) == 0 && k.GodfatherId.Valid {
+		req.GodparentIds = []int64{k.GodfatherId.Int64}
+	}
+
+	if k.BirthDate.Valid {
+		req.BirthDate = &k.BirthDate.Time
+	}
+	if k.Baptism.Valid {
+		req.Baptism = &k.Baptism.Time
+	}
+	if k.Certificate.Valid {
+		req.Certificate = &k.Certificate.Time
+	}
+	return req
+}
+
+// restoreOptionalID vraća 0 za vezu koje nije bilo, što u izmeni briše vezu.
+func restoreOptionalID(value sql.NullInt64) *int64 {
+	id := int64(0)
+	if value.Valid {
+		id = value.Int64
+	}
+	return &id
+}
+
+func makeKrstenicaVersionResponse(version *model.KrstenicaVersion, snapshot, previous *model.Krstenica) *dto.KrstenicaVersion {
+	res := &dto.KrstenicaVersion{
+		ID:                  version.ID,
+		KrstenicaId:         version.KrstenicaId,
+		Version:             version.Version,
+		RestoredFromVersion: int64Ptr(version.RestoredFromVersion),
+		CreatedById:         int64Ptr(version.CreatedById),
+		CreatedByUsername:   version.CreatedByUsername,
+		CreatedAt:           version.CreatedAt,
+		Changes:             []dto.AuditFieldChange{},
+		Krstenica:           makeKrstenicaResponse(snapshot),
+	}
+	if previous != nil {
+		res.Changes = makeAuditFieldChanges(diffAuditFields(auditFields(makeKrstenicaResponse(previous)), auditFields(res.Krstenica)))
+	}
+	return res
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"krstenica/internal/dto"
+	"krstenica/internal/model"
+	"krstenica/internal/requestctx"
+	"krstenica/pkg"
+)
+
+// CheckKrstenicaNumbering prolazi kroz krštenice po knjizi i godini (hram +
+// naziv knjige + godina krštenja) redom tekućih brojeva i prijavljuje rupe,
+// duplikate, strane koje idu unazad i datume krštenja koji se ne slažu sa
+// redosledom brojeva.
+func (s *service) CheckKrstenicaNumbering(ctx context.Context, req *dto.NumberingCheckReq) (*dto.NumberingReport, error) {
+	if req == nil {
+		req = &dto.NumberingCheckReq{}
+	}
+
+	filterAndSort := ensureFilterAndSort(nil)
+	if req.BookId > 0 {
+		filterAndSort.Filters[pkg.FilterKey{Property: "book_id", Operator: "eq"}] = []string{strconv.FormatInt(req.BookId, 10)}
+	}
+	if req.TampleId > 0 {
+		filterAndSort.Filters[pkg.FilterKey{Property: "tample_id", Operator: "eq"}] = []string{strconv.FormatInt(req.TampleId, 10)}
+	}
+	if book := strings.TrimSpace(req.Book); book != "" {
+		filterAndSort.Filters[pkg.FilterKey{Property: "book", Operator: "eq"}] = []string{book}
+	}
+	if user, ok := requestctx.UserFromContext(ctx); ok && !user.IsAdmin() {
+		city := strings.TrimSpace(user.City)
+		if city == "" {
+			return nil, errors.New("корисник нема додељен град")
+		}
+		applyCityFilter(filterAndSort, city)
+	}
+
+	entries, err := s.repo.ListKrsteniceNumbering(ctx, filterAndSort, req.Year)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	report := &dto.NumberingReport{Year: req.Year, Books: []*dto.NumberingBookReport{}}
+	for start := 0; start < len(entries); {
+		end := start + 1
+		for end < len(entries) && sameNumberingGroup(&entries[start], &entries[end]) {
+			end++
+		}
+		group := entries[start:end]
+		start = end
+
+		bookReport := &dto.NumberingBookReport{
+			TampleId:    int64Ptr(group[0].TampleId),
+			TampleName:  group[0].TampleName,
+			Book:        group[0].Book,
+			BookId:      int64Ptr(group[0].BookId),
+			Year:        group[0].Year,
+			Entries:     len(group),
+			FirstNumber: group[0].CurrentNumber,
+			LastNumber:  group[len(group)-1].CurrentNumber,
+			Issues:      checkNumberingGroup(group),
+		}
+		report.TotalIssues += len(bookReport.Issues)
+		report.Books = append(report.Books, bookReport)
+	}
+
+	return report, nil
+}
+
+func checkNumberingGroup(group []model.KrstenicaNumberingEntry) []dto.NumberingIssue {
+	issues := []dto.NumberingIssue{}
+	if len(group) == 0 {
+		return issues
+	}
+
+	if first := group[0]; first.CurrentNumber > 1 {
+		issues = append(issues, dto.NumberingIssue{
+			Kind:          string(model.NumberingIssueGap),
+			Page:          first.Page,
+			CurrentNumber: first.CurrentNumber,
+			KrstenicaIds:  []int64{first.ID},
+			Message:       missingNumbersMessage(1, first.CurrentNumber-1),
+		})
+	}
+
+	for i := 0; i < len(group); {
+		j := i + 1
+		for j < len(group) && group[j].CurrentNumber == group[i].CurrentNumber {
+			j++
+		}
+		if j-i > 1 {
+			ids := make([]int64, 0, j-i)
+			pages := make([]string, 0, j-i)
+			for _, k := range group[i:j] {
+				ids = append(ids, k.ID)
+				pages = append(pages, strconv.FormatInt(k.Page, 10))
+			}
+			issues = append(issues, dto.NumberingIssue{
+				Kind:          string(model.NumberingIssueDuplicate),
+				Page:          group[i].Page,
+				CurrentNumber: group[i].CurrentNumber,
+				KrstenicaIds:  ids,
+				Message:       fmt.Sprintf("Број %d је уписан %d пута (стр. %s)", group[i].CurrentNumber, j-i, strings.Join(pages, ", ")),
+			})
+		}
+		i = j
+	}
+
+	for i := 1; i < len(group); i++ {
+		prev, cur := group[i-1], group[i]
+		if cur.CurrentNumber-prev.CurrentNumber > 1 {
+			issues = append(issues, dto.NumberingIssue{
+				Kind:          string(model.NumberingIssueGap),
+				Page:          cur.Page,
+				CurrentNumber: cur.CurrentNumber,
+				KrstenicaIds:  []int64{prev.ID, cur.ID},
+				Message:       missingNumbersMessage(prev.CurrentNumber+1, cur.CurrentNumber-1),
+			})
+		}
+		if cur.CurrentNumber != prev.CurrentNumber && cur.Page < prev.Page {
+			issues = append(issues, dto.NumberingIssue{
+				Kind:          string(model.NumberingIssuePageOrder),
+				Page:          cur.Page,
+				CurrentNumber: cur.CurrentNumber,
+				KrstenicaIds:  []int64{prev.ID, cur.ID},
+				Message: fmt.Sprintf("Број %d је на страни %d, а претходни број %d на страни %d",
+					cur.CurrentNumber, cur.Page, prev.CurrentNumber, prev.Page),
+			})
+		}
+	}
+
+	var lastDated *model.KrstenicaNumberingEntry
+	for i := range group {
+		cur := &group[i]
+		if !cur.Baptism.Valid {
+			continue
+		}
+		if lastDated != nil && lastDated.CurrentNumber != cur.CurrentNumber && cur.Baptism.Time.Before(lastDated.Baptism.Time) {
+			issues = append(issues, dto.NumberingIssue{
+				Kind:          string(model.NumberingIssueDateOrder),
+				Page:          cur.Page,
+				CurrentNumber: cur.CurrentNumber,
+				KrstenicaIds:  []int64{lastDated.ID, cur.ID},
+				Message: fmt.Sprintf("Крштење под бројем %d (%s) је пре крштења под бројем %d (%s)",
+					cur.CurrentNumber, cur.Baptism.Time.Format("02.01.2006."),
+					lastDated.CurrentNumber, lastDated.Baptism.Time.Format("02.01.2006.")),
+			})
+		}
+		lastDated = cur
+	}
+
+	sort.SliceStable(issues, func(a, b int) bool {
+		return issues[a].CurrentNumber < issues[b].CurrentNumber
+	})
+	return issues
+}
+
+// sameNumberingGroup javlja da li su upisi iz iste knjige istog hrama i iz
+// iste godine; numeracija u knjizi počinje iznova svake godine.
+func sameNumberingGroup(a, b *model.KrstenicaNumberingEntry) bool {
+	return a.TampleId == b.TampleId && a.Book == b.Book && a.Year == b.Year
+}
+
+func missingNumbersMessage(from, to int64) string {
+	if from == to {
+		return fmt.Sprintf("Недостаје број %d", from)
+	}
+	return fmt.Sprintf("Недостају бројеви %d–%d", from, to)
+}
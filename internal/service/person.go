@@ -6,17 +6,29 @@ import (
 	"krstenica/internal/dto"
 	"krstenica/internal/errorx"
 	"krstenica/internal/model"
+	"krstenica/internal/repository"
 	"krstenica/pkg"
 	"log"
 	"time"
 )
 
 func (s *service) DeletePerson(ctx context.Context, id int64) error {
+	current, err := s.repo.GetPersonByID(ctx, id)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
 
 	updates := map[string]interface{}{}
 	updates["status"] = model.PersonStatusDeleted
+	markDeleted(ctx, updates)
 
-	err := s.repo.UpdatePerson(ctx, id, updates)
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if err := txRepo.UpdatePerson(ctx, id, updates); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, txRepo, model.AuditEntityPerson, id, model.AuditActionDelete, makePersonResponse(current), nil)
+	})
 	if err != nil {
 		log.Println(err)
 		return err
@@ -26,26 +38,36 @@ func (s *service) DeletePerson(ctx context.Context, id int64) error {
 }
 
 func (s *service) UpdatePerson(ctx context.Context, id int64, personReq *dto.PersonUpdateReq) (*dto.Person, error) {
-	updates, err := validatePersonUpdateRequest(personReq)
+	current, err := s.repo.GetPersonByID(ctx, id)
 	if err != nil {
 		log.Println(err)
 		return nil, err
 	}
 
-	err = s.repo.UpdatePerson(ctx, id, updates)
+	updates, err := validatePersonUpdateRequest(personReq)
 	if err != nil {
 		log.Println(err)
 		return nil, err
 	}
 
-	person, err := s.repo.GetPersonByID(ctx, id)
+	var res *dto.Person
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if err := txRepo.UpdatePerson(ctx, id, updates); err != nil {
+			return err
+		}
+		person, err := txRepo.GetPersonByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		res = makePersonResponse(person)
+		return s.recordAudit(ctx, txRepo, model.AuditEntityPerson, id, model.AuditActionUpdate, makePersonResponse(current), res)
+	})
 	if err != nil {
 		log.Println(err)
 		return nil, err
 	}
 
-	return makePersonResponse(person), nil
-
+	return res, nil
 }
 
 func (s *service) CreatePerson(ctx context.Context, personReq *dto.PersonCreateReq) (*dto.Person, error) {
@@ -69,13 +91,21 @@ func (s *service) CreatePerson(ctx context.Context, personReq *dto.PersonCreateR
 		CreatedAt:  sql.NullTime{Valid: true, Time: time.Now()},
 	}
 
-	newPerson, err := s.repo.CreatePerson(ctx, person)
+	var res *dto.Person
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		newPerson, err := txRepo.CreatePerson(ctx, person)
+		if err != nil {
+			return err
+		}
+		res = makePersonResponse(newPerson)
+		return s.recordAudit(ctx, txRepo, model.AuditEntityPerson, res.ID, model.AuditActionCreate, nil, res)
+	})
 	if err != nil {
 		log.Println(err)
 		return nil, err
 	}
 
-	return makePersonResponse(newPerson), nil
+	return res, nil
 }
 
 func (s *service) GetPersonByID(ctx context.Context, id int64) (*dto.Person, error) {
@@ -6,17 +6,29 @@ import (
 	"krstenica/internal/dto"
 	"krstenica/internal/errorx"
 	"krstenica/internal/model"
+	"krstenica/internal/repository"
 	"krstenica/pkg"
 	"log"
 	"time"
 )
 
 func (s *service) DeletePriest(ctx context.Context, id int64) error {
+	current, err := s.repo.GetPriestByID(ctx, id)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
 
 	updates := map[string]interface{}{}
 	updates["status"] = model.PriestStatusDeleted
+	markDeleted(ctx, updates)
 
-	err := s.repo.UpdatePriest(ctx, id, updates)
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if err := txRepo.UpdatePriest(ctx, id, updates); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, txRepo, model.AuditEntityPriest, id, model.AuditActionDelete, makePriestResponse(current), nil)
+	})
 	if err != nil {
 		log.Println(err)
 		return err
@@ -26,26 +38,36 @@ func (s *service) DeletePriest(ctx context.Context, id int64) error {
 }
 
 func (s *service) UpdatePriest(ctx context.Context, id int64, priestReq *dto.PriestUpdateReq) (*dto.Priest, error) {
-	updates, err := validatePriestUpdateRequest(priestReq)
+	current, err := s.repo.GetPriestByID(ctx, id)
 	if err != nil {
 		log.Println(err)
 		return nil, err
 	}
 
-	err = s.repo.UpdatePriest(ctx, id, updates)
+	updates, err := validatePriestUpdateRequest(priestReq)
 	if err != nil {
 		log.Println(err)
 		return nil, err
 	}
 
-	priest, err := s.repo.GetPriestByID(ctx, id)
+	var res *dto.Priest
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if err := txRepo.UpdatePriest(ctx, id, updates); err != nil {
+			return err
+		}
+		priest, err := txRepo.GetPriestByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		res = makePriestResponse(priest)
+		return s.recordAudit(ctx, txRepo, model.AuditEntityPriest, id, model.AuditActionUpdate, makePriestResponse(current), res)
+	})
 	if err != nil {
 		log.Println(err)
 		return nil, err
 	}
 
-	return makePriestResponse(priest), nil
-
+	return res, nil
 }
 
 func (s *service) CreatePriest(ctx context.Context, priestReq *dto.PriestCreateReq) (*dto.Priest, error) {
@@ -64,13 +86,21 @@ func (s *service) CreatePriest(ctx context.Context, priestReq *dto.PriestCreateR
 		CreatedAt: sql.NullTime{Valid: true, Time: time.Now()},
 	}
 
-	newPriest, err := s.repo.CreatePriest(ctx, priest)
+	var res *dto.Priest
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		newPriest, err := txRepo.CreatePriest(ctx, priest)
+		if err != nil {
+			return err
+		}
+		res = makePriestResponse(newPriest)
+		return s.recordAudit(ctx, txRepo, model.AuditEntityPriest, res.ID, model.AuditActionCreate, nil, res)
+	})
 	if err != nil {
 		log.Println(err)
 		return nil, err
 	}
 
-	return makePriestResponse(newPriest), nil
+	return res, nil
 }
 
 func (s *service) GetPriestByID(ctx context.Context, id int64) (*dto.Priest, error) {
@@ -0,0 +1,317 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/xuri/excelize/v2"
+
+	"krstenica/internal/dto"
+	"krstenica/internal/errorx"
+	"krstenica/internal/model"
+	"krstenica/internal/repository"
+	"krstenica/internal/requestctx"
+)
+
+const (
+	// printCalibrationMaxOffsetMM ogranicava pomeraj stampaca; veci pomeraj
+	// je pogresno umetnut papir, a ne odstupanje stampaca.
+	printCalibrationMaxOffsetMM = 30.0
+	printCalibrationMinScale    = 0.9
+	printCalibrationMaxScale    = 1.1
+)
+
+func (s *service) ListPrintCalibrations(ctx context.Context) ([]*dto.PrintCalibration, error) {
+	calibrations, err := s.repo.ListPrintCalibrations(ctx)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	res := make([]*dto.PrintCalibration, 0, len(calibrations))
+	for i := range calibrations {
+		res = append(res, makePrintCalibrationResponse(&calibrations[i]))
+	}
+	return res, nil
+}
+
+func (s *service) GetPrintCalibrationByID(ctx context.Context, id int64) (*dto.PrintCalibration, error) {
+	calibration, err := s.repo.GetPrintCalibrationByID(ctx, id)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return makePrintCalibrationResponse(calibration), nil
+}
+
+// ResolvePrintCalibration vraca profil koji vazi za stampu bez zadatog
+// profila: profil radne stanice, pa licni profil korisnika. Vraca nil kada
+// nijedan ne postoji.
+func (s *service) ResolvePrintCalibration(ctx context.Context, workstation string) (*dto.PrintCalibration, error) {
+	if workstation = strings.TrimSpace(workstation); workstation != "" {
+		calibration, err := s.repo.GetWorkstationPrintCalibration(ctx, workstation)
+		if err == nil {
+			return makePrintCalibrationResponse(calibration), nil
+		}
+		if !errors.Is(err, errorx.ErrPrintCalibrationNotFound) {
+			log.Println(err)
+			return nil, err
+		}
+	}
+
+	if user, ok := requestctx.UserFromContext(ctx); ok && user.ID > 0 {
+		calibration, err := s.repo.GetUserPrintCalibration(ctx, user.ID)
+		if err == nil {
+			return makePrintCalibrationResponse(calibration), nil
+		}
+		if !errors.Is(err, errorx.ErrPrintCalibrationNotFound) {
+			log.Println(err)
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+func (s *service) CreatePrintCalibration(ctx context.Context, req *dto.PrintCalibrationReq) (*dto.PrintCalibration, error) {
+	calibration, err := s.validatePrintCalibration(ctx, 0, nil, req)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	now := time.Now()
+	calibration.Status = model.PrintCalibrationStatusActive
+	calibration.CreatedAt = now
+	calibration.UpdatedAt = now
+
+	var res *dto.PrintCalibration
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		created, err := txRepo.CreatePrintCalibration(ctx, calibration)
+		if err != nil {
+			return err
+		}
+		res = makePrintCalibrationResponse(created)
+		return s.recordAudit(ctx, txRepo, model.AuditEntityCalibration, res.ID, model.AuditActionCreate, nil, res)
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *service) UpdatePrintCalibration(ctx context.Context, id int64, req *dto.PrintCalibrationReq) (*dto.PrintCalibration, error) {
+	current, err := s.repo.GetPrintCalibrationByID(ctx, id)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if err := checkPrintCalibrationOwner(ctx, current); err != nil {
+		return nil, err
+	}
+
+	calibration, err := s.validatePrintCalibration(ctx, id, current, req)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	var res *dto.PrintCalibration
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		err := txRepo.UpdatePrintCalibration(ctx, id, map[string]interface{}{
+			"name":        calibration.Name,
+			"offset_x":    calibration.OffsetX,
+			"offset_y":    calibration.OffsetY,
+			"scale":       calibration.Scale,
+			"user_id":     calibration.UserId,
+			"workstation": calibration.Workstation,
+		})
+		if err != nil {
+			return err
+		}
+		if err := txRepo.ReplacePrintCalibrationCells(ctx, id, calibration.Cells); err != nil {
+			return err
+		}
+		updated, err := txRepo.GetPrintCalibrationByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		res = makePrintCalibrationResponse(updated)
+		return s.recordAudit(ctx, txRepo, model.AuditEntityCalibration, id, model.AuditActionUpdate, makePrintCalibrationResponse(current), res)
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *service) DeletePrintCalibration(ctx context.Context, id int64) error {
+	current, err := s.repo.GetPrintCalibrationByID(ctx, id)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	if err := checkPrintCalibrationOwner(ctx, current); err != nil {
+		return err
+	}
+
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if err := txRepo.UpdatePrintCalibration(ctx, id, map[string]interface{}{"status": model.PrintCalibrationStatusDeleted}); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, txRepo, model.AuditEntityCalibration, id, model.AuditActionDelete, makePrintCalibrationResponse(current), nil)
+	})
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	return nil
+}
+
+// checkPrintCalibrationOwner dozvoljava izmenu licnog profila samo vlasniku
+// i administratoru; profile radnih stanica menjaju svi korisnici.
+func checkPrintCalibrationOwner(ctx context.Context, calibration *model.PrintCalibration) error {
+	if !calibration.UserId.Valid {
+		return nil
+	}
+	user, ok := requestctx.UserFromContext(ctx)
+	if ok && (user.ID == calibration.UserId.Int64 || user.IsAdmin()) {
+		return nil
+	}
+	return errorx.ErrPrintCalibrationForbidden
+}
+
+// validatePrintCalibration proverava zahtev i vraca profil spreman za upis.
+// Korisnik i radna stanica imaju najvise po jedan profil.
+func (s *service) validatePrintCalibration(ctx context.Context, id int64, current *model.PrintCalibration, req *dto.PrintCalibrationReq) (*model.PrintCalibration, error) {
+	fail := func(message string) error {
+		return errorx.GetValidationError("PrintCalibration", "validation", message)
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fail("name is required")
+	}
+	if utf8.RuneCountInString(name) > 255 {
+		return nil, fail("name is too long")
+	}
+	if !validPrintCalibrationOffset(req.OffsetX) || !validPrintCalibrationOffset(req.OffsetY) {
+		return nil, fail(fmt.Sprintf("offsets must be between -%.0f and %.0f mm", printCalibrationMaxOffsetMM, printCalibrationMaxOffsetMM))
+	}
+	scale := req.Scale
+	if scale == 0 {
+		scale = 1
+	}
+	if math.IsNaN(scale) || scale < printCalibrationMinScale || scale > printCalibrationMaxScale {
+		return nil, fail(fmt.Sprintf("scale must be between %.2f and %.2f", printCalibrationMinScale, printCalibrationMaxScale))
+	}
+
+	calibration := &model.PrintCalibration{
+		Name:    name,
+		OffsetX: req.OffsetX,
+		OffsetY: req.OffsetY,
+		Scale:   scale,
+	}
+
+	switch strings.TrimSpace(req.Scope) {
+	case dto.PrintCalibrationScopeNone:
+	case dto.PrintCalibrationScopeUser:
+		// licni profil ostaje vlasniku i kada ga menja administrator
+		if current != nil && current.UserId.Valid {
+			calibration.UserId = current.UserId
+		} else if user, ok := requestctx.UserFromContext(ctx); ok && user.ID > 0 {
+			calibration.UserId = sql.NullInt64{Valid: true, Int64: user.ID}
+		} else {
+			return nil, fail("personal profile requires a signed in user")
+		}
+		existing, err := s.repo.GetUserPrintCalibration(ctx, calibration.UserId.Int64)
+		if err == nil && existing.ID != id {
+			return nil, fail(fmt.Sprintf("user already has personal profile %q", existing.Name))
+		}
+		if err != nil && !errors.Is(err, errorx.ErrPrintCalibrationNotFound) {
+			return nil, err
+		}
+	case dto.PrintCalibrationScopeWorkstation:
+		workstation := strings.TrimSpace(req.Workstation)
+		if workstation == "" {
+			return nil, fail("workstation is required")
+		}
+		if utf8.RuneCountInString(workstation) > 100 {
+			return nil, fail("workstation name is too long")
+		}
+		existing, err := s.repo.GetWorkstationPrintCalibration(ctx, workstation)
+		if err == nil && existing.ID != id {
+			return nil, fail(fmt.Sprintf("workstation %s already has profile %q", workstation, existing.Name))
+		}
+		if err != nil && !errors.Is(err, errorx.ErrPrintCalibrationNotFound) {
+			return nil, err
+		}
+		calibration.Workstation = workstation
+	default:
+		return nil, fail(fmt.Sprintf("invalid scope %q", req.Scope))
+	}
+
+	used := map[string]bool{}
+	for i, cell := range req.Cells {
+		ref := strings.ToUpper(strings.TrimSpace(cell.Cell))
+		if _, _, err := excelize.CellNameToCoordinates(ref); err != nil {
+			return nil, fail(fmt.Sprintf("row %d: invalid cell %q", i+1, cell.Cell))
+		}
+		if used[ref] {
+			return nil, fail(fmt.Sprintf("row %d: cell %s is already listed", i+1, ref))
+		}
+		used[ref] = true
+		if !validPrintCalibrationOffset(cell.OffsetX) || !validPrintCalibrationOffset(cell.OffsetY) {
+			return nil, fail(fmt.Sprintf("row %d: offsets must be between -%.0f and %.0f mm", i+1, printCalibrationMaxOffsetMM, printCalibrationMaxOffsetMM))
+		}
+		calibration.Cells = append(calibration.Cells, model.PrintCalibrationCell{
+			Cell:    ref,
+			OffsetX: cell.OffsetX,
+			OffsetY: cell.OffsetY,
+		})
+	}
+
+	return calibration, nil
+}
+
+func validPrintCalibrationOffset(v float64) bool {
+	return !math.IsNaN(v) && math.Abs(v) <= printCalibrationMaxOffsetMM
+}
+
+func makePrintCalibrationResponse(calibration *model.PrintCalibration) *dto.PrintCalibration {
+	res := &dto.PrintCalibration{
+		ID:          calibration.ID,
+		Name:        calibration.Name,
+		OffsetX:     calibration.OffsetX,
+		OffsetY:     calibration.OffsetY,
+		Scale:       calibration.Scale,
+		Username:    calibration.Username,
+		Workstation: calibration.Workstation,
+		Cells:       make([]dto.PrintCalibrationCell, len(calibration.Cells)),
+		CreatedAt:   calibration.CreatedAt,
+		UpdatedAt:   calibration.UpdatedAt,
+	}
+	if calibration.UserId.Valid {
+		res.UserId = &calibration.UserId.Int64
+	}
+	for i, cell := range calibration.Cells {
+		res.Cells[i] = dto.PrintCalibrationCell{
+			Cell:    cell.Cell,
+			OffsetX: cell.OffsetX,
+			OffsetY: cell.OffsetY,
+		}
+	}
+	return res
+}
@@ -6,6 +6,7 @@ import (
 	"krstenica/internal/dto"
 	"krstenica/internal/repository"
 	"krstenica/pkg"
+	"os"
 )
 
 type Service interface {
@@ -35,9 +36,79 @@ type Service interface {
 
 	GetKrstenicaByID(ctx context.Context, id int64) (*dto.Krstenica, error)
 	ListKrstenice(ctx context.Context, filterAndSort *pkg.FilterAndSort) ([]*dto.Krstenica, int64, error)
+	ExportKrstenice(ctx context.Context, filterAndSort *pkg.FilterAndSort, fn func([]*dto.Krstenica) error) error
+	ImportKrstenice(ctx context.Context, req *dto.KrstenicaImportReq, fileName string, content []byte) (*dto.KrstenicaImportReport, error)
 	CreateKrstenica(ctx context.Context, personReq *dto.KrstenicaCreateReq) (*dto.Krstenica, error)
 	UpdateKrstenica(ctx context.Context, id int64, personReq *dto.KrstenicaUpdateReq) (*dto.Krstenica, error)
 	DeleteKrstenica(ctx context.Context, id int64) error
+	ListKrstenicaAnnotations(ctx context.Context, krstenicaID int64) ([]*dto.KrstenicaAnnotation, error)
+	CreateKrstenicaAnnotation(ctx context.Context, krstenicaID int64, req *dto.KrstenicaAnnotationCreateReq) (*dto.KrstenicaAnnotation, error)
+	UpdateKrstenicaAnnotation(ctx context.Context, krstenicaID, id int64, req *dto.KrstenicaAnnotationUpdateReq) (*dto.KrstenicaAnnotation, error)
+	DeleteKrstenicaAnnotation(ctx context.Context, krstenicaID, id int64) error
+	ListKrstenicaAttachments(ctx context.Context, krstenicaID int64) ([]*dto.KrstenicaAttachment, error)
+	CreateKrstenicaAttachment(ctx context.Context, krstenicaID int64, fileName string, content []byte) (*dto.KrstenicaAttachment, error)
+	OpenKrstenicaAttachment(ctx context.Context, krstenicaID, id int64, thumbnail bool) (*dto.KrstenicaAttachment, *os.File, error)
+	DeleteKrstenicaAttachment(ctx context.Context, krstenicaID, id int64) error
+	AttachmentMaxSizeBytes() int64
+	ListKrstenicaVersions(ctx context.Context, krstenicaID int64) ([]*dto.KrstenicaVersion, error)
+	GetKrstenicaVersion(ctx context.Context, krstenicaID, version int64) (*dto.KrstenicaVersion, error)
+	RestoreKrstenicaVersion(ctx context.Context, krstenicaID, version int64) (*dto.Krstenica, error)
+	ListAuditLogs(ctx context.Context, filterAndSort *pkg.FilterAndSort) ([]*dto.AuditLog, int64, error)
+	ListTrash(ctx context.Context, filterAndSort *pkg.FilterAndSort) ([]*dto.TrashItem, int64, error)
+	RestoreTrashItem(ctx context.Context, entity string, id int64) error
+	PurgeTrashItem(ctx context.Context, entity string, id int64) error
+	IssueKrstenicaCertificate(ctx context.Context, krstenicaID int64, req *dto.IssuedCertificateCreateReq, render func(*dto.IssuedCertificate) error) (*dto.IssuedCertificate, error)
+	IssueKrstenicaCertificates(ctx context.Context, krstenicaIDs []int64, reqs []*dto.IssuedCertificateCreateReq, render func([]*dto.IssuedCertificate) error) ([]*dto.IssuedCertificate, error)
+	GetIssuedCertificateByID(ctx context.Context, id int64) (*dto.IssuedCertificate, error)
+	ListIssuedCertificates(ctx context.Context, filterAndSort *pkg.FilterAndSort) ([]*dto.IssuedCertificate, int64, error)
+	RevokeIssuedCertificate(ctx context.Context, id int64, req *dto.IssuedCertificateRevokeReq) (*dto.IssuedCertificate, error)
+	VerifyIssuedCertificate(ctx context.Context, id int64) (*dto.CertificateVerification, error)
+
+	ListCertificateLayouts(ctx context.Context) ([]*dto.CertificateLayout, error)
+	GetCertificateLayoutByID(ctx context.Context, id int64) (*dto.CertificateLayout, error)
+	GetDefaultCertificateLayout(ctx context.Context) (*dto.CertificateLayout, error)
+	CreateCertificateLayout(ctx context.Context, req *dto.CertificateLayoutCreateReq) (*dto.CertificateLayout, error)
+	UpdateCertificateLayout(ctx context.Context, id int64, req *dto.CertificateLayoutUpdateReq) (*dto.CertificateLayout, error)
+	SetDefaultCertificateLayout(ctx context.Context, id int64) (*dto.CertificateLayout, error)
+	DeleteCertificateLayout(ctx context.Context, id int64) error
+
+	ListCertificateTemplates(ctx context.Context) ([]*dto.CertificateTemplate, error)
+	GetCertificateTemplateByID(ctx context.Context, id int64) (*dto.CertificateTemplate, error)
+	CreateCertificateTemplate(ctx context.Context, req *dto.CertificateTemplateCreateReq) (*dto.CertificateTemplate, error)
+	AddCertificateTemplateVersion(ctx context.Context, id int64, req *dto.CertificateTemplateVersionReq) (*dto.CertificateTemplate, error)
+	UpdateCertificateTemplate(ctx context.Context, id int64, req *dto.CertificateTemplateUpdateReq) (*dto.CertificateTemplate, error)
+	SetDefaultCertificateTemplate(ctx context.Context, id int64) (*dto.CertificateTemplate, error)
+	ClearDefaultCertificateTemplate(ctx context.Context) error
+	DeleteCertificateTemplate(ctx context.Context, id int64) error
+	ResolveKrstenicaTemplate(ctx context.Context, krstenica *dto.Krstenica, templateID, versionID int64) (*dto.CertificateTemplateVersion, error)
+	OpenCertificateTemplateFile(ctx context.Context, templateID, versionID int64, part string) (string, string, *os.File, error)
+	CertificateTemplateMaxSizeBytes() int64
+
+	ListPrintCalibrations(ctx context.Context) ([]*dto.PrintCalibration, error)
+	GetPrintCalibrationByID(ctx context.Context, id int64) (*dto.PrintCalibration, error)
+	ResolvePrintCalibration(ctx context.Context, workstation string) (*dto.PrintCalibration, error)
+	CreatePrintCalibration(ctx context.Context, req *dto.PrintCalibrationReq) (*dto.PrintCalibration, error)
+	UpdatePrintCalibration(ctx context.Context, id int64, req *dto.PrintCalibrationReq) (*dto.PrintCalibration, error)
+	DeletePrintCalibration(ctx context.Context, id int64) error
+
+	GetVencanicaByID(ctx context.Context, id int64) (*dto.Vencanica, error)
+	ListVencanice(ctx context.Context, filterAndSort *pkg.FilterAndSort) ([]*dto.Vencanica, int64, error)
+	CreateVencanica(ctx context.Context, vencanicaReq *dto.VencanicaCreateReq) (*dto.Vencanica, error)
+	UpdateVencanica(ctx context.Context, id int64, vencanicaReq *dto.VencanicaUpdateReq) (*dto.Vencanica, error)
+	DeleteVencanica(ctx context.Context, id int64) error
+
+	GetUmrlicaByID(ctx context.Context, id int64) (*dto.Umrlica, error)
+	ListUmrlice(ctx context.Context, filterAndSort *pkg.FilterAndSort) ([]*dto.Umrlica, int64, error)
+	CreateUmrlica(ctx context.Context, umrlicaReq *dto.UmrlicaCreateReq) (*dto.Umrlica, error)
+	UpdateUmrlica(ctx context.Context, id int64, umrlicaReq *dto.UmrlicaUpdateReq) (*dto.Umrlica, error)
+	DeleteUmrlica(ctx context.Context, id int64) error
+
+	GetBookByID(ctx context.Context, id int64) (*dto.Book, error)
+	ListBooks(ctx context.Context, filterAndSort *pkg.FilterAndSort) ([]*dto.Book, int64, error)
+	CreateBook(ctx context.Context, bookReq *dto.BookCreateReq) (*dto.Book, error)
+	UpdateBook(ctx context.Context, id int64, bookReq *dto.BookUpdateReq) (*dto.Book, error)
+	DeleteBook(ctx context.Context, id int64) error
+	CheckKrstenicaNumbering(ctx context.Context, req *dto.NumberingCheckReq) (*dto.NumberingReport, error)
 
 	AuthenticateUser(ctx context.Context, username, password string) (bool, error)
 	EnsureDefaultUser(ctx context.Context) error
@@ -6,17 +6,29 @@ import (
 	"krstenica/internal/dto"
 	"krstenica/internal/errorx"
 	"krstenica/internal/model"
+	"krstenica/internal/repository"
 	"krstenica/pkg"
 	"log"
 	"time"
 )
 
 func (s *service) DeleteTample(ctx context.Context, id int64) error {
+	current, err := s.repo.GetTampleByID(ctx, id)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
 
 	updates := map[string]interface{}{}
 	updates["status"] = model.TampleStatusDeleted
+	markDeleted(ctx, updates)
 
-	err := s.repo.UpdateTample(ctx, id, updates)
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if err := txRepo.UpdateTample(ctx, id, updates); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, txRepo, model.AuditEntityTample, id, model.AuditActionDelete, makeTampleResponse(current), nil)
+	})
 	if err != nil {
 		log.Println(err)
 		return err
@@ -26,26 +38,36 @@ func (s *service) DeleteTample(ctx context.Context, id int64) error {
 }
 
 func (s *service) UpdateTample(ctx context.Context, id int64, tampleReq *dto.TampleUpdateReq) (*dto.Tample, error) {
-	updates, err := validateTampleUpdateRequest(tampleReq)
+	current, err := s.repo.GetTampleByID(ctx, id)
 	if err != nil {
 		log.Println(err)
 		return nil, err
 	}
 
-	err = s.repo.UpdateTample(ctx, id, updates)
+	updates, err := validateTampleUpdateRequest(tampleReq)
 	if err != nil {
 		log.Println(err)
 		return nil, err
 	}
 
-	tample, err := s.repo.GetTampleByID(ctx, id)
+	var res *dto.Tample
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if err := txRepo.UpdateTample(ctx, id, updates); err != nil {
+			return err
+		}
+		tample, err := txRepo.GetTampleByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		res = makeTampleResponse(tample)
+		return s.recordAudit(ctx, txRepo, model.AuditEntityTample, id, model.AuditActionUpdate, makeTampleResponse(current), res)
+	})
 	if err != nil {
 		log.Println(err)
 		return nil, err
 	}
 
-	return makeTampleResponse(tample), nil
-
+	return res, nil
 }
 
 func (s *service) CreateTample(ctx context.Context, tampleReq *dto.TampleCreateReq) (*dto.Tample, error) {
@@ -62,13 +84,21 @@ func (s *service) CreateTample(ctx context.Context, tampleReq *dto.TampleCreateR
 		CreatedAt: sql.NullTime{Valid: true, Time: time.Now()},
 	}
 
-	newTample, err := s.repo.CreateTample(ctx, tample)
+	var res *dto.Tample
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		newTample, err := txRepo.CreateTample(ctx, tample)
+		if err != nil {
+			return err
+		}
+		res = makeTampleResponse(newTample)
+		return s.recordAudit(ctx, txRepo, model.AuditEntityTample, res.ID, model.AuditActionCreate, nil, res)
+	})
 	if err != nil {
 		log.Println(err)
 		return nil, err
 	}
 
-	return makeTampleResponse(newTample), nil
+	return res, nil
 }
 
 func (s *service) GetTampleByID(ctx context.Context, id int64) (*dto.Tample, error) {
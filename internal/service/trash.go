@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"krstenica/internal/dto"
+	"krstenica/internal/errorx"
+	"krstenica/internal/model"
+	"krstenica/internal/repository"
+	"krstenica/internal/requestctx"
+	"krstenica/pkg"
+)
+
+// markDeleted dopunjuje izmenu za meko brisanje podacima o tome ko je i kada obrisao zapis.
+func markDeleted(ctx context.Context, updates map[string]interface{}) {
+	updates["deleted_at"] = time.Now()
+	if user, ok := requestctx.UserFromContext(ctx); ok {
+		if user.ID > 0 {
+			updates["deleted_by_id"] = user.ID
+		}
+		updates["deleted_by_username"] = user.Username
+	}
+}
+
+func (s *service) ListTrash(ctx context.Context, filterAndSort *pkg.FilterAndSort) ([]*dto.TrashItem, int64, error) {
+	items, totalCount, err := s.repo.ListTrash(ctx, filterAndSort)
+	if err != nil {
+		log.Println(err)
+		return nil, 0, err
+	}
+
+	res := make([]*dto.TrashItem, len(items))
+	for i := range items {
+		res[i] = s.makeTrashItemResponse(&items[i])
+	}
+	return res, totalCount, nil
+}
+
+func (s *service) RestoreTrashItem(ctx context.Context, entity string, id int64) error {
+	if _, err := s.repo.GetTrashItem(ctx, entity, id); err != nil {
+		log.Println(err)
+		return err
+	}
+
+	err := s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if err := txRepo.RestoreTrashItem(ctx, entity, id); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, txRepo, entity, id, model.AuditActionRestore,
+			map[string]interface{}{"status": "deleted"},
+			map[string]interface{}{"status": "active"})
+	})
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	return nil
+}
+
+// PurgeTrashItem trajno briše zapis iz korpe. Odbija brisanje pre isteka roka
+// čuvanja i dok na zapis pokazuje bilo koji drugi zapis.
+func (s *service) PurgeTrashItem(ctx context.Context, entity string, id int64) error {
+	item, err := s.repo.GetTrashItem(ctx, entity, id)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	res := s.makeTrashItemResponse(item)
+	if !res.CanPurge {
+		return fmt.Errorf("%w (до %s)", errorx.ErrTrashRetentionNotExpired, res.PurgeAllowedAt.Format("02.01.2006."))
+	}
+
+	live, deleted, err := s.repo.CountTrashReferences(ctx, entity, id)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	if live > 0 {
+		return fmt.Errorf("%w: активних записа %d", errorx.ErrTrashItemInUse, live)
+	}
+	if deleted > 0 {
+		return fmt.Errorf("%w: обрисаних записа %d, њих прво трајно обришите", errorx.ErrTrashItemInUse, deleted)
+	}
+
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if err := txRepo.PurgeTrashItem(ctx, entity, id); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, txRepo, entity, id, model.AuditActionPurge, res, nil)
+	})
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	if entity == model.AuditEntityKrstenica {
+		// fajlovi priloga ostaju na disku posle kaskadnog brisanja redova
+		dir := s.attachmentPath(filepath.Join("krstenice", strconv.FormatInt(id, 10)))
+		if err := os.RemoveAll(dir); err != nil {
+			log.Println(err)
+		}
+	}
+
+	return nil
+}
+
+func (s *service) makeTrashItemResponse(item *model.TrashItem) *dto.TrashItem {
+	res := &dto.TrashItem{
+		Entity:            item.Entity,
+		ID:                item.ID,
+		Name:              item.Name,
+		Details:           item.Details,
+		City:              item.City,
+		DeletedAt:         timePtr(item.DeletedAt),
+		DeletedById:       int64Ptr(item.DeletedById),
+		DeletedByUsername: item.DeletedByUsername,
+		CanPurge:          true,
+	}
+	if item.DeletedAt.Valid {
+		purgeAllowedAt := item.DeletedAt.Time.AddDate(0, 0, s.conf.Trash.RetentionDays)
+		res.PurgeAllowedAt = &purgeAllowedAt
+		res.CanPurge = !time.Now().Before(purgeAllowedAt)
+	}
+	return res
+}
+
+func timePtr(value sql.NullTime) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	t := value.Time
+	return &t
+}
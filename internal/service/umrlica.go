@@ -0,0 +1,417 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"krstenica/internal/dto"
+	"krstenica/internal/errorx"
+	"krstenica/internal/model"
+	"krstenica/internal/repository"
+	"krstenica/internal/requestctx"
+	"krstenica/pkg"
+)
+
+func (s *service) DeleteUmrlica(ctx context.Context, id int64) error {
+	current, err := s.repo.GetUmrlicaByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := enforceCityPermission(ctx, current.City); err != nil {
+		return err
+	}
+
+	updates := map[string]interface{}{}
+	updates["status"] = model.UmrlicaStatusDeleted
+
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if err := txRepo.UpdateUmrlica(ctx, id, updates); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, txRepo, model.AuditEntityUmrlica, id, model.AuditActionDelete, makeUmrlicaResponse(current), nil)
+	})
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) UpdateUmrlica(ctx context.Context, id int64, umrlicaReq *dto.UmrlicaUpdateReq) (*dto.Umrlica, error) {
+	current, err := s.repo.GetUmrlicaByID(ctx, id)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if err := enforceCityPermission(ctx, current.City); err != nil {
+		return nil, err
+	}
+
+	updates, err := validateUmrlicaUpdateRequest(current, umrlicaReq)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if krstenicaID, ok := updates["krstenica_id"].(int64); ok {
+		if err := s.ensureUmrlicaKrstenica(ctx, krstenicaID); err != nil {
+			return nil, err
+		}
+	}
+	if user, ok := requestctx.UserFromContext(ctx); ok && !user.IsAdmin() {
+		city := strings.TrimSpace(user.City)
+		if city == "" {
+			return nil, errors.New("корисник нема додељен град")
+		}
+		updates["city"] = city
+	}
+
+	var res *dto.Umrlica
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if err := txRepo.UpdateUmrlica(ctx, id, updates); err != nil {
+			return err
+		}
+		umrlica, err := txRepo.GetUmrlicaByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		res = makeUmrlicaResponse(umrlica)
+		return s.recordAudit(ctx, txRepo, model.AuditEntityUmrlica, id, model.AuditActionUpdate, makeUmrlicaResponse(current), res)
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *service) CreateUmrlica(ctx context.Context, umrlicaReq *dto.UmrlicaCreateReq) (*dto.Umrlica, error) {
+	if user, ok := requestctx.UserFromContext(ctx); ok && !user.IsAdmin() {
+		city := strings.TrimSpace(user.City)
+		if city == "" {
+			return nil, errors.New("корисник нема додељен град")
+		}
+		umrlicaReq.City = city
+	}
+	err := validateUmrlicaCreateRequest(umrlicaReq)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if umrlicaReq.KrstenicaId != nil {
+		if err := s.ensureUmrlicaKrstenica(ctx, *umrlicaReq.KrstenicaId); err != nil {
+			return nil, err
+		}
+	}
+
+	umrlica := &model.UmrlicaPost{
+		Book:                umrlicaReq.Book,
+		Page:                umrlicaReq.Page,
+		CurrentNumber:       umrlicaReq.CurrentNumber,
+		EparhijaId:          umrlicaReq.EparhijaId,
+		TampleId:            umrlicaReq.TampleId,
+		DeceasedId:          umrlicaReq.DeceasedId,
+		KrstenicaId:         umrlicaReq.KrstenicaId,
+		PriestId:            umrlicaReq.PriestId,
+		DeathDate:           nullTime(umrlicaReq.DeathDate),
+		BurialDate:          nullTime(umrlicaReq.BurialDate),
+		City:                umrlicaReq.City,
+		Country:             umrlicaReq.Country,
+		Cemetery:            umrlicaReq.Cemetery,
+		CauseOfDeath:        umrlicaReq.CauseOfDeath,
+		MaritalStatus:       umrlicaReq.MaritalStatus,
+		Sacraments:          umrlicaReq.Sacraments,
+		NumberOfCertificate: umrlicaReq.NumberOfCertificate,
+		TownOfCertificate:   umrlicaReq.TownOfCertificate,
+		Certificate:         nullTime(umrlicaReq.Certificate),
+		Comment:             umrlicaReq.Comment,
+		Status:              string(model.UmrlicaStatusActive),
+		CreatedAt:           sql.NullTime{Valid: true, Time: time.Now()},
+	}
+
+	var res *dto.Umrlica
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		newUmrlica, err := txRepo.CreateUmrlica(ctx, umrlica)
+		if err != nil {
+			return err
+		}
+		res = makeUmrlicaResponse(newUmrlica)
+		return s.recordAudit(ctx, txRepo, model.AuditEntityUmrlica, res.ID, model.AuditActionCreate, nil, res)
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *service) GetUmrlicaByID(ctx context.Context, id int64) (*dto.Umrlica, error) {
+	umrlica, err := s.repo.GetUmrlicaByID(ctx, id)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if err := enforceCityPermission(ctx, umrlica.City); err != nil {
+		return nil, err
+	}
+
+	return makeUmrlicaResponse(umrlica), nil
+}
+
+func (s *service) ListUmrlice(ctx context.Context, filterAndSort *pkg.FilterAndSort) ([]*dto.Umrlica, int64, error) {
+	if user, ok := requestctx.UserFromContext(ctx); ok && !user.IsAdmin() {
+		city := strings.TrimSpace(user.City)
+		if city == "" {
+			return nil, 0, errors.New("корисник нема додељен град")
+		}
+		filterAndSort = ensureFilterAndSort(filterAndSort)
+		applyCityFilter(filterAndSort, city)
+	}
+	umrlice, totalCount, err := s.repo.ListUmrlice(ctx, filterAndSort)
+	if err != nil {
+		log.Println(err)
+		return nil, 0, err
+	}
+
+	res := make([]*dto.Umrlica, len(umrlice))
+	for i := range umrlice {
+		res[i] = makeUmrlicaResponse(&umrlice[i])
+	}
+	return res, totalCount, nil
+}
+
+// ensureUmrlicaKrstenica proverava da povezana krštenica postoji i nije obrisana.
+func (s *service) ensureUmrlicaKrstenica(ctx context.Context, krstenicaID int64) error {
+	krstenica, err := s.repo.GetKrstenicaByID(ctx, krstenicaID)
+	if err != nil {
+		if errors.Is(err, errorx.ErrKrstenicaNotFound) {
+			return errorx.GetValidationError("Umrlica", "validation", "Linked krstenica does not exist")
+		}
+		return err
+	}
+	if krstenica.Status == string(model.KrstenicaStatusDeleted) {
+		return errorx.GetValidationError("Umrlica", "validation", "Linked krstenica does not exist")
+	}
+	return nil
+}
+
+func makeUmrlicaResponse(umrlica *model.Umrlica) *dto.Umrlica {
+	return &dto.Umrlica{
+		ID:                     umrlica.ID,
+		Book:                   umrlica.Book,
+		Page:                   umrlica.Page,
+		CurrentNumber:          umrlica.CurrentNumber,
+		EparhijaId:             int64Ptr(umrlica.EparhijaId),
+		EparhijaName:           umrlica.EparhijaName,
+		TampleId:               int64Ptr(umrlica.TampleId),
+		TampleName:             umrlica.TampleName,
+		TampleCity:             umrlica.TampleCity,
+		DeceasedId:             int64Ptr(umrlica.DeceasedId),
+		DeceasedFirstName:      umrlica.DeceasedFirstName,
+		DeceasedLastName:       umrlica.DeceasedLastName,
+		DeceasedOccupation:     umrlica.DeceasedOccupation,
+		DeceasedCity:           umrlica.DeceasedCity,
+		DeceasedAddress:        umrlica.DeceasedAddress,
+		DeceasedReligion:       umrlica.DeceasedReligion,
+		DeceasedBirthDate:      umrlica.DeceasedBirthDate.Time,
+		KrstenicaId:            int64Ptr(umrlica.KrstenicaId),
+		KrstenicaBook:          umrlica.KrstenicaBook,
+		KrstenicaPage:          umrlica.KrstenicaPage.Int64,
+		KrstenicaCurrentNumber: umrlica.KrstenicaCurrentNumber.Int64,
+		KrstenicaFirstName:     umrlica.KrstenicaFirstName,
+		KrstenicaLastName:      umrlica.KrstenicaLastName,
+		KrstenicaBirthDate:     umrlica.KrstenicaBirthDate.Time,
+		KrstenicaBaptism:       umrlica.KrstenicaBaptism.Time,
+		KrstenicaTampleName:    umrlica.KrstenicaTampleName,
+		PriestId:               int64Ptr(umrlica.PriestId),
+		PriestFirstName:        umrlica.PriestFirstName,
+		PriestLastName:         umrlica.PriestLastName,
+		PriestTitle:            umrlica.PriestTitle,
+		DeathDate:              umrlica.DeathDate.Time,
+		BurialDate:             umrlica.BurialDate.Time,
+		City:                   umrlica.City,
+		Country:                umrlica.Country,
+		Cemetery:               umrlica.Cemetery,
+		CauseOfDeath:           umrlica.CauseOfDeath,
+		MaritalStatus:          umrlica.MaritalStatus,
+		Sacraments:             umrlica.Sacraments,
+		NumberOfCertificate:    umrlica.NumberOfCertificate,
+		TownOfCertificate:      umrlica.TownOfCertificate,
+		Certificate:            umrlica.Certificate.Time,
+		Comment:                umrlica.Comment,
+		Status:                 umrlica.Status,
+		CreatedAt:              umrlica.CreatedAt.Time,
+	}
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	if t.IsZero() {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Valid: true, Time: t}
+}
+
+func validateUmrlicaCreateRequest(umrlicaReq *dto.UmrlicaCreateReq) error {
+	umrlicaReq.Book = strings.TrimSpace(umrlicaReq.Book)
+	if umrlicaReq.Book == "" {
+		return errorx.GetValidationError("Umrlica", "validation", "Book is required")
+	}
+	if umrlicaReq.DeceasedId <= 0 {
+		return errorx.GetValidationError("Umrlica", "validation", "Deceased person is required")
+	}
+	if umrlicaReq.KrstenicaId != nil && *umrlicaReq.KrstenicaId <= 0 {
+		umrlicaReq.KrstenicaId = nil
+	}
+	if umrlicaReq.PriestId <= 0 {
+		return errorx.GetValidationError("Umrlica", "validation", "Priest is required")
+	}
+	if umrlicaReq.DeathDate.IsZero() {
+		return errorx.GetValidationError("Umrlica", "validation", "Date of death is required")
+	}
+	if !umrlicaReq.BurialDate.IsZero() && umrlicaReq.BurialDate.Before(umrlicaReq.DeathDate) {
+		return errorx.GetValidationError("Umrlica", "validation", "Burial date can not be before date of death")
+	}
+	if len(umrlicaReq.City) > 100 {
+		return errorx.GetValidationError("Umrlica", "validation", "city of umrlica can not be longer than 100 characters")
+	}
+	if len(umrlicaReq.Country) > 100 {
+		return errorx.GetValidationError("Umrlica", "validation", "Country of umrlica can not be longer than 100 characters")
+	}
+	umrlicaReq.Cemetery = strings.TrimSpace(umrlicaReq.Cemetery)
+	if len(umrlicaReq.Cemetery) > 255 {
+		return errorx.GetValidationError("Umrlica", "validation", "Cemetery can not be longer than 255 characters")
+	}
+
+	umrlicaReq.CauseOfDeath = strings.TrimSpace(umrlicaReq.CauseOfDeath)
+	umrlicaReq.MaritalStatus = strings.TrimSpace(umrlicaReq.MaritalStatus)
+	umrlicaReq.Sacraments = strings.TrimSpace(umrlicaReq.Sacraments)
+
+	umrlicaReq.NumberOfCertificate = strings.TrimSpace(umrlicaReq.NumberOfCertificate)
+	if len(umrlicaReq.NumberOfCertificate) > 255 {
+		return errorx.GetValidationError("Umrlica", "validation", "Number of certificate can not be longer than 255 characters")
+	}
+	if len(umrlicaReq.TownOfCertificate) > 100 {
+		return errorx.GetValidationError("Umrlica", "validation", "Town of certificate can not be longer than 100 characters")
+	}
+	if len(umrlicaReq.Comment) > 255 {
+		return errorx.GetValidationError("Umrlica", "validation", "Comment can not be longer than 255 characters")
+	}
+
+	return nil
+}
+
+func validateUmrlicaUpdateRequest(current *model.Umrlica, umrlicaReq *dto.UmrlicaUpdateReq) (map[string]interface{}, error) {
+	updates := map[string]interface{}{}
+
+	if umrlicaReq.Book != nil {
+		trimmed := strings.TrimSpace(*umrlicaReq.Book)
+		if trimmed == "" {
+			return nil, errorx.GetValidationError("Umrlica", "validation", "Book is required")
+		}
+		updates["book"] = trimmed
+	}
+	if umrlicaReq.Page != nil {
+		updates["page"] = *umrlicaReq.Page
+	}
+	if umrlicaReq.CurrentNumber != nil {
+		updates["current_number"] = *umrlicaReq.CurrentNumber
+	}
+	if umrlicaReq.EparhijaId != nil {
+		updates["eparhija_id"] = *umrlicaReq.EparhijaId
+	}
+	if umrlicaReq.TampleId != nil {
+		updates["tample_id"] = *umrlicaReq.TampleId
+	}
+	if umrlicaReq.DeceasedId != nil {
+		if *umrlicaReq.DeceasedId <= 0 {
+			return nil, errorx.GetValidationError("Umrlica", "validation", "Deceased person is required")
+		}
+		updates["deceased_id"] = *umrlicaReq.DeceasedId
+	}
+	if umrlicaReq.KrstenicaId != nil {
+		if *umrlicaReq.KrstenicaId <= 0 {
+			updates["krstenica_id"] = nil
+		} else {
+			updates["krstenica_id"] = *umrlicaReq.KrstenicaId
+		}
+	}
+	if umrlicaReq.PriestId != nil {
+		updates["priest_id"] = *umrlicaReq.PriestId
+	}
+
+	deathDate := current.DeathDate.Time
+	if umrlicaReq.DeathDate != nil {
+		deathDate = *umrlicaReq.DeathDate
+		updates["death_date"] = deathDate
+	}
+	burialDate := current.BurialDate.Time
+	if umrlicaReq.BurialDate != nil {
+		burialDate = *umrlicaReq.BurialDate
+		updates["burial_date"] = burialDate
+	}
+	if !burialDate.IsZero() && !deathDate.IsZero() && burialDate.Before(deathDate) {
+		return nil, errorx.GetValidationError("Umrlica", "validation", "Burial date can not be before date of death")
+	}
+
+	if umrlicaReq.City != nil {
+		if len(*umrlicaReq.City) > 100 {
+			return nil, errorx.GetValidationError("Umrlica", "validation", "city of umrlica can not be longer than 100 characters")
+		}
+		updates["city"] = *umrlicaReq.City
+	}
+	if umrlicaReq.Country != nil {
+		if len(*umrlicaReq.Country) > 100 {
+			return nil, errorx.GetValidationError("Umrlica", "validation", "Country of umrlica can not be longer than 100 characters")
+		}
+		updates["country"] = *umrlicaReq.Country
+	}
+	if umrlicaReq.Cemetery != nil {
+		trimmed := strings.TrimSpace(*umrlicaReq.Cemetery)
+		if len(trimmed) > 255 {
+			return nil, errorx.GetValidationError("Umrlica", "validation", "Cemetery can not be longer than 255 characters")
+		}
+		updates["cemetery"] = trimmed
+	}
+	if umrlicaReq.CauseOfDeath != nil {
+		updates["cause_of_death"] = strings.TrimSpace(*umrlicaReq.CauseOfDeath)
+	}
+	if umrlicaReq.MaritalStatus != nil {
+		updates["marital_status"] = strings.TrimSpace(*umrlicaReq.MaritalStatus)
+	}
+	if umrlicaReq.Sacraments != nil {
+		updates["sacraments"] = strings.TrimSpace(*umrlicaReq.Sacraments)
+	}
+	if umrlicaReq.NumberOfCertificate != nil {
+		trimmed := strings.TrimSpace(*umrlicaReq.NumberOfCertificate)
+		if len(trimmed) > 255 {
+			return nil, errorx.GetValidationError("Umrlica", "validation", "Number of certificate can not be longer than 255 characters")
+		}
+		updates["number_of_certificate"] = trimmed
+	}
+	if umrlicaReq.TownOfCertificate != nil {
+		if len(*umrlicaReq.TownOfCertificate) > 100 {
+			return nil, errorx.GetValidationError("Umrlica", "validation", "Town of certificate can not be longer than 100 characters")
+		}
+		updates["town_of_certificate"] = *umrlicaReq.TownOfCertificate
+	}
+	if umrlicaReq.Certificate != nil {
+		updates["certificate"] = *umrlicaReq.Certificate
+	}
+	if umrlicaReq.Comment != nil {
+		if len(*umrlicaReq.Comment) > 255 {
+			return nil, errorx.GetValidationError("Umrlica", "validation", "Comment can not be longer than 255 characters")
+		}
+		updates["comment"] = *umrlicaReq.Comment
+	}
+	if umrlicaReq.Status != nil {
+		updates["status"] = *umrlicaReq.Status
+	}
+
+	return updates, nil
+}
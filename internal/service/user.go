@@ -7,6 +7,7 @@ import (
 
 	"krstenica/internal/dto"
 	"krstenica/internal/model"
+	"krstenica/internal/repository"
 
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
@@ -51,7 +52,7 @@ func (s *service) EnsureDefaultUser(ctx context.Context) error {
 		}
 		return s.repo.UpdateUser(ctx, user.ID, updates)
 	case errors.Is(err, gorm.ErrRecordNotFound):
-		return s.createUserInternal(ctx, username, password, userRoleAdmin, "")
+		return s.createUserInternal(ctx, s.repo, username, password, userRoleAdmin, "")
 	default:
 		return err
 	}
@@ -127,22 +128,23 @@ func (s *service) CreateUser(ctx context.Context, req *dto.UserCreateReq) (*dto.
 		return nil, errors.New("city is required for non-admin users")
 	}
 
-	if err := s.createUserInternal(ctx, username, req.Password, role, city); err != nil {
-		return nil, err
-	}
-
-	created, err := s.repo.GetUserByUsername(ctx, username)
+	var res *dto.User
+	err := s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if err := s.createUserInternal(ctx, txRepo, username, req.Password, role, city); err != nil {
+			return err
+		}
+		created, err := txRepo.GetUserByUsername(ctx, username)
+		if err != nil {
+			return err
+		}
+		res = makeUserResponse(created)
+		return s.recordAudit(ctx, txRepo, model.AuditEntityUser, res.ID, model.AuditActionCreate, nil, res)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &dto.User{
-		ID:        created.ID,
-		Username:  created.Username,
-		Role:      created.Role,
-		City:      created.City,
-		CreatedAt: created.CreatedAt,
-	}, nil
+	return res, nil
 }
 
 func (s *service) GetUser(ctx context.Context, id int64) (*dto.User, error) {
@@ -215,15 +217,34 @@ func (s *service) UpdateUser(ctx context.Context, id int64, req *dto.UserUpdateR
 		}, nil
 	}
 
-	if err := s.repo.UpdateUser(ctx, id, updates); err != nil {
+	var res *dto.User
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if err := txRepo.UpdateUser(ctx, id, updates); err != nil {
+			return err
+		}
+		updated, err := txRepo.GetUserByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		res = makeUserResponse(updated)
+		before, after := auditFields(makeUserResponse(current)), auditFields(res)
+		if _, ok := updates["password_hash"]; ok && before != nil && after != nil {
+			// lozinka se ne upisuje, beleži se samo da je promenjena
+			before["password"] = auditPasswordMask
+			after["password"] = auditPasswordMask + " (нова)"
+		}
+		return s.recordAudit(ctx, txRepo, model.AuditEntityUser, id, model.AuditActionUpdate, before, after)
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return s.GetUser(ctx, id)
+	return res, nil
 }
 
 func (s *service) DeleteUser(ctx context.Context, id int64) error {
-	if _, err := s.repo.GetUserByID(ctx, id); err != nil {
+	current, err := s.repo.GetUserByID(ctx, id)
+	if err != nil {
 		return err
 	}
 
@@ -235,10 +256,15 @@ func (s *service) DeleteUser(ctx context.Context, id int64) error {
 		return errors.New("не може се обрисати последњи корисник")
 	}
 
-	return s.repo.DeleteUser(ctx, id)
+	return s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if err := txRepo.DeleteUser(ctx, id); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, txRepo, model.AuditEntityUser, id, model.AuditActionDelete, makeUserResponse(current), nil)
+	})
 }
 
-func (s *service) createUserInternal(ctx context.Context, username, password, role, city string) error {
+func (s *service) createUserInternal(ctx context.Context, repo repository.Repo, username, password, role, city string) error {
 	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
 		return err
@@ -249,7 +275,7 @@ func (s *service) createUserInternal(ctx context.Context, username, password, ro
 		Role:         role,
 		City:         city,
 	}
-	_, err = s.repo.CreateUser(ctx, user)
+	_, err = repo.CreateUser(ctx, user)
 	return err
 }
 
@@ -260,3 +286,13 @@ func normalizeRole(role string) string {
 	}
 	return role
 }
+
+func makeUserResponse(user *model.User) *dto.User {
+	return &dto.User{
+		ID:        user.ID,
+		Username:  user.Username,
+		Role:      user.Role,
+		City:      user.City,
+		CreatedAt: user.CreatedAt,
+	}
+}
@@ -0,0 +1,386 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"krstenica/internal/dto"
+	"krstenica/internal/errorx"
+	"krstenica/internal/model"
+	"krstenica/internal/repository"
+	"krstenica/internal/requestctx"
+	"krstenica/pkg"
+)
+
+func (s *service) DeleteVencanica(ctx context.Context, id int64) error {
+	current, err := s.repo.GetVencanicaByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := enforceCityPermission(ctx, current.City); err != nil {
+		return err
+	}
+
+	updates := map[string]interface{}{}
+	updates["status"] = model.VencanicaStatusDeleted
+
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if err := txRepo.UpdateVencanica(ctx, id, updates); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, txRepo, model.AuditEntityVencanica, id, model.AuditActionDelete, makeVencanicaResponse(current), nil)
+	})
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) UpdateVencanica(ctx context.Context, id int64, vencanicaReq *dto.VencanicaUpdateReq) (*dto.Vencanica, error) {
+	current, err := s.repo.GetVencanicaByID(ctx, id)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if err := enforceCityPermission(ctx, current.City); err != nil {
+		return nil, err
+	}
+
+	updates, err := validateVencanicaUpdateRequest(current, vencanicaReq)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if user, ok := requestctx.UserFromContext(ctx); ok && !user.IsAdmin() {
+		city := strings.TrimSpace(user.City)
+		if city == "" {
+			return nil, errors.New("корисник нема додељен град")
+		}
+		updates["city"] = city
+	}
+
+	var res *dto.Vencanica
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		if err := txRepo.UpdateVencanica(ctx, id, updates); err != nil {
+			return err
+		}
+		vencanica, err := txRepo.GetVencanicaByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		res = makeVencanicaResponse(vencanica)
+		return s.recordAudit(ctx, txRepo, model.AuditEntityVencanica, id, model.AuditActionUpdate, makeVencanicaResponse(current), res)
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *service) CreateVencanica(ctx context.Context, vencanicaReq *dto.VencanicaCreateReq) (*dto.Vencanica, error) {
+	if user, ok := requestctx.UserFromContext(ctx); ok && !user.IsAdmin() {
+		city := strings.TrimSpace(user.City)
+		if city == "" {
+			return nil, errors.New("корисник нема додељен град")
+		}
+		vencanicaReq.City = city
+	}
+	err := validateVencanicaCreateRequest(vencanicaReq)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	marriageDate := sql.NullTime{}
+	if !vencanicaReq.MarriageDate.IsZero() {
+		marriageDate = sql.NullTime{Valid: true, Time: vencanicaReq.MarriageDate}
+	}
+	certificateDate := sql.NullTime{}
+	if !vencanicaReq.Certificate.IsZero() {
+		certificateDate = sql.NullTime{Valid: true, Time: vencanicaReq.Certificate}
+	}
+
+	vencanica := &model.VencanicaPost{
+		Book:                vencanicaReq.Book,
+		Page:                vencanicaReq.Page,
+		CurrentNumber:       vencanicaReq.CurrentNumber,
+		EparhijaId:          vencanicaReq.EparhijaId,
+		TampleId:            vencanicaReq.TampleId,
+		GroomId:             vencanicaReq.GroomId,
+		BrideId:             vencanicaReq.BrideId,
+		WitnessId:           vencanicaReq.WitnessId,
+		SecondWitnessId:     vencanicaReq.SecondWitnessId,
+		PriestId:            vencanicaReq.PriestId,
+		MarriageDate:        marriageDate,
+		City:                vencanicaReq.City,
+		Country:             vencanicaReq.Country,
+		GroomMarriageOrder:  vencanicaReq.GroomMarriageOrder,
+		BrideMarriageOrder:  vencanicaReq.BrideMarriageOrder,
+		CivilMarriage:       vencanicaReq.CivilMarriage,
+		NumberOfCertificate: vencanicaReq.NumberOfCertificate,
+		TownOfCertificate:   vencanicaReq.TownOfCertificate,
+		Certificate:         certificateDate,
+		Comment:             vencanicaReq.Comment,
+		Status:              string(model.VencanicaStatusActive),
+		CreatedAt:           sql.NullTime{Valid: true, Time: time.Now()},
+	}
+
+	var res *dto.Vencanica
+	err = s.repo.Transaction(ctx, func(txRepo repository.Repo) error {
+		newVencanica, err := txRepo.CreateVencanica(ctx, vencanica)
+		if err != nil {
+			return err
+		}
+		res = makeVencanicaResponse(newVencanica)
+		return s.recordAudit(ctx, txRepo, model.AuditEntityVencanica, res.ID, model.AuditActionCreate, nil, res)
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *service) GetVencanicaByID(ctx context.Context, id int64) (*dto.Vencanica, error) {
+	vencanica, err := s.repo.GetVencanicaByID(ctx, id)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if err := enforceCityPermission(ctx, vencanica.City); err != nil {
+		return nil, err
+	}
+
+	return makeVencanicaResponse(vencanica), nil
+}
+
+func (s *service) ListVencanice(ctx context.Context, filterAndSort *pkg.FilterAndSort) ([]*dto.Vencanica, int64, error) {
+	if user, ok := requestctx.UserFromContext(ctx); ok && !user.IsAdmin() {
+		city := strings.TrimSpace(user.City)
+		if city == "" {
+			return nil, 0, errors.New("корисник нема додељен град")
+		}
+		filterAndSort = ensureFilterAndSort(filterAndSort)
+		applyCityFilter(filterAndSort, city)
+	}
+	vencanice, totalCount, err := s.repo.ListVencanice(ctx, filterAndSort)
+	if err != nil {
+		log.Println(err)
+		return nil, 0, err
+	}
+
+	res := make([]*dto.Vencanica, len(vencanice))
+	for i := range vencanice {
+		res[i] = makeVencanicaResponse(&vencanice[i])
+	}
+	return res, totalCount, nil
+}
+
+func makeVencanicaResponse(vencanica *model.Vencanica) *dto.Vencanica {
+	return &dto.Vencanica{
+		ID:                     vencanica.ID,
+		Book:                   vencanica.Book,
+		Page:                   vencanica.Page,
+		CurrentNumber:          vencanica.CurrentNumber,
+		EparhijaId:             int64Ptr(vencanica.EparhijaId),
+		EparhijaName:           vencanica.EparhijaName,
+		TampleId:               int64Ptr(vencanica.TampleId),
+		TampleName:             vencanica.TampleName,
+		TampleCity:             vencanica.TampleCity,
+		GroomId:                int64Ptr(vencanica.GroomId),
+		GroomFirstName:         vencanica.GroomFirstName,
+		GroomLastName:          vencanica.GroomLastName,
+		GroomOccupation:        vencanica.GroomOccupation,
+		GroomCity:              vencanica.GroomCity,
+		GroomReligion:          vencanica.GroomReligion,
+		GroomBirthDate:         vencanica.GroomBirthDate.Time,
+		BrideId:                int64Ptr(vencanica.BrideId),
+		BrideFirstName:         vencanica.BrideFirstName,
+		BrideLastName:          vencanica.BrideLastName,
+		BrideOccupation:        vencanica.BrideOccupation,
+		BrideCity:              vencanica.BrideCity,
+		BrideReligion:          vencanica.BrideReligion,
+		BrideBirthDate:         vencanica.BrideBirthDate.Time,
+		WitnessId:              int64Ptr(vencanica.WitnessId),
+		WitnessFirstName:       vencanica.WitnessFirstName,
+		WitnessLastName:        vencanica.WitnessLastName,
+		WitnessCity:            vencanica.WitnessCity,
+		SecondWitnessId:        int64Ptr(vencanica.SecondWitnessId),
+		SecondWitnessFirstName: vencanica.SecondWitnessFirstName,
+		SecondWitnessLastName:  vencanica.SecondWitnessLastName,
+		SecondWitnessCity:      vencanica.SecondWitnessCity,
+		PriestId:               int64Ptr(vencanica.PriestId),
+		PriestFirstName:        vencanica.PriestFirstName,
+		PriestLastName:         vencanica.PriestLastName,
+		PriestTitle:            vencanica.PriestTitle,
+		MarriageDate:           vencanica.MarriageDate.Time,
+		City:                   vencanica.City,
+		Country:                vencanica.Country,
+		GroomMarriageOrder:     vencanica.GroomMarriageOrder,
+		BrideMarriageOrder:     vencanica.BrideMarriageOrder,
+		CivilMarriage:          vencanica.CivilMarriage,
+		NumberOfCertificate:    vencanica.NumberOfCertificate,
+		TownOfCertificate:      vencanica.TownOfCertificate,
+		Certificate:            vencanica.Certificate.Time,
+		Comment:                vencanica.Comment,
+		Status:                 vencanica.Status,
+		CreatedAt:              vencanica.CreatedAt.Time,
+	}
+}
+
+func validateVencanicaCreateRequest(vencanicaReq *dto.VencanicaCreateReq) error {
+	vencanicaReq.Book = strings.TrimSpace(vencanicaReq.Book)
+	if vencanicaReq.Book == "" {
+		return errorx.GetValidationError("Vencanica", "validation", "Book is required")
+	}
+	if vencanicaReq.GroomId <= 0 || vencanicaReq.BrideId <= 0 {
+		return errorx.GetValidationError("Vencanica", "validation", "Groom and bride are required")
+	}
+	if vencanicaReq.GroomId == vencanicaReq.BrideId {
+		return errorx.GetValidationError("Vencanica", "validation", "Groom and bride must be different persons")
+	}
+	if vencanicaReq.WitnessId <= 0 {
+		return errorx.GetValidationError("Vencanica", "validation", "Witness is required")
+	}
+	if vencanicaReq.SecondWitnessId != nil && *vencanicaReq.SecondWitnessId <= 0 {
+		vencanicaReq.SecondWitnessId = nil
+	}
+	if vencanicaReq.PriestId <= 0 {
+		return errorx.GetValidationError("Vencanica", "validation", "Priest is required")
+	}
+	if len(vencanicaReq.City) > 100 {
+		return errorx.GetValidationError("Vencanica", "validation", "city of vencanica can not be longer than 100 characters")
+	}
+	if len(vencanicaReq.Country) > 100 {
+		return errorx.GetValidationError("Vencanica", "validation", "Country of vencanica can not be longer than 100 characters")
+	}
+
+	vencanicaReq.GroomMarriageOrder = strings.TrimSpace(vencanicaReq.GroomMarriageOrder)
+	vencanicaReq.BrideMarriageOrder = strings.TrimSpace(vencanicaReq.BrideMarriageOrder)
+	vencanicaReq.CivilMarriage = strings.TrimSpace(vencanicaReq.CivilMarriage)
+
+	vencanicaReq.NumberOfCertificate = strings.TrimSpace(vencanicaReq.NumberOfCertificate)
+	if len(vencanicaReq.NumberOfCertificate) > 255 {
+		return errorx.GetValidationError("Vencanica", "validation", "Number of certificate can not be longer than 255 characters")
+	}
+	if len(vencanicaReq.TownOfCertificate) > 100 {
+		return errorx.GetValidationError("Vencanica", "validation", "Town of certificate can not be longer than 100 characters")
+	}
+	if len(vencanicaReq.Comment) > 255 {
+		return errorx.GetValidationError("Vencanica", "validation", "Comment can not be longer than 255 characters")
+	}
+
+	return nil
+}
+
+func validateVencanicaUpdateRequest(current *model.Vencanica, vencanicaReq *dto.VencanicaUpdateReq) (map[string]interface{}, error) {
+	updates := map[string]interface{}{}
+
+	if vencanicaReq.Book != nil {
+		trimmed := strings.TrimSpace(*vencanicaReq.Book)
+		if trimmed == "" {
+			return nil, errorx.GetValidationError("Vencanica", "validation", "Book is required")
+		}
+		updates["book"] = trimmed
+	}
+	if vencanicaReq.Page != nil {
+		updates["page"] = *vencanicaReq.Page
+	}
+	if vencanicaReq.CurrentNumber != nil {
+		updates["current_number"] = *vencanicaReq.CurrentNumber
+	}
+	if vencanicaReq.EparhijaId != nil {
+		updates["eparhija_id"] = *vencanicaReq.EparhijaId
+	}
+	if vencanicaReq.TampleId != nil {
+		updates["tample_id"] = *vencanicaReq.TampleId
+	}
+
+	groomID := current.GroomId.Int64
+	if vencanicaReq.GroomId != nil {
+		groomID = *vencanicaReq.GroomId
+		updates["groom_id"] = groomID
+	}
+	brideID := current.BrideId.Int64
+	if vencanicaReq.BrideId != nil {
+		brideID = *vencanicaReq.BrideId
+		updates["bride_id"] = brideID
+	}
+	if groomID == brideID {
+		return nil, errorx.GetValidationError("Vencanica", "validation", "Groom and bride must be different persons")
+	}
+
+	if vencanicaReq.WitnessId != nil {
+		updates["witness_id"] = *vencanicaReq.WitnessId
+	}
+	if vencanicaReq.SecondWitnessId != nil {
+		if *vencanicaReq.SecondWitnessId <= 0 {
+			updates["second_witness_id"] = nil
+		} else {
+			updates["second_witness_id"] = *vencanicaReq.SecondWitnessId
+		}
+	}
+	if vencanicaReq.PriestId != nil {
+		updates["priest_id"] = *vencanicaReq.PriestId
+	}
+	if vencanicaReq.MarriageDate != nil {
+		updates["marriage_date"] = *vencanicaReq.MarriageDate
+	}
+	if vencanicaReq.City != nil {
+		if len(*vencanicaReq.City) > 100 {
+			return nil, errorx.GetValidationError("Vencanica", "validation", "city of vencanica can not be longer than 100 characters")
+		}
+		updates["city"] = *vencanicaReq.City
+	}
+	if vencanicaReq.Country != nil {
+		if len(*vencanicaReq.Country) > 100 {
+			return nil, errorx.GetValidationError("Vencanica", "validation", "Country of vencanica can not be longer than 100 characters")
+		}
+		updates["country"] = *vencanicaReq.Country
+	}
+	if vencanicaReq.GroomMarriageOrder != nil {
+		updates["groom_marriage_order"] = strings.TrimSpace(*vencanicaReq.GroomMarriageOrder)
+	}
+	if vencanicaReq.BrideMarriageOrder != nil {
+		updates["bride_marriage_order"] = strings.TrimSpace(*vencanicaReq.BrideMarriageOrder)
+	}
+	if vencanicaReq.CivilMarriage != nil {
+		updates["civil_marriage"] = strings.TrimSpace(*vencanicaReq.CivilMarriage)
+	}
+	if vencanicaReq.NumberOfCertificate != nil {
+		trimmed := strings.TrimSpace(*vencanicaReq.NumberOfCertificate)
+		if len(trimmed) > 255 {
+			return nil, errorx.GetValidationError("Vencanica", "validation", "Number of certificate can not be longer than 255 characters")
+		}
+		updates["number_of_certificate"] = trimmed
+	}
+	if vencanicaReq.TownOfCertificate != nil {
+		if len(*vencanicaReq.TownOfCertificate) > 100 {
+			return nil, errorx.GetValidationError("Vencanica", "validation", "Town of certificate can not be longer than 100 characters")
+		}
+		updates["town_of_certificate"] = *vencanicaReq.TownOfCertificate
+	}
+	if vencanicaReq.Certificate != nil {
+		updates["certificate"] = *vencanicaReq.Certificate
+	}
+	if vencanicaReq.Comment != nil {
+		if len(*vencanicaReq.Comment) > 255 {
+			return nil, errorx.GetValidationError("Vencanica", "validation", "Comment can not be longer than 255 characters")
+		}
+		updates["comment"] = *vencanicaReq.Comment
+	}
+	if vencanicaReq.Status != nil {
+		updates["status"] = *vencanicaReq.Status
+	}
+
+	return updates, nil
+}
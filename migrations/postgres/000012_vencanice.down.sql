@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP TABLE IF EXISTS vencanice;
+
+UPDATE persons SET role = NULL WHERE role IN ('groom', 'bride');
+ALTER TABLE persons DROP CONSTRAINT IF EXISTS persons_role_check;
+ALTER TABLE persons ADD CONSTRAINT persons_role_check
+    CHECK (role IN ('mother', 'father', 'godfather', 'paroh'));
+
+COMMIT;
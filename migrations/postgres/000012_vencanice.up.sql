@@ -0,0 +1,33 @@
+BEGIN;
+
+ALTER TABLE persons DROP CONSTRAINT IF EXISTS persons_role_check;
+ALTER TABLE persons ADD CONSTRAINT persons_role_check
+    CHECK (role IN ('mother', 'father', 'godfather', 'paroh', 'groom', 'bride'));
+
+CREATE TABLE IF NOT EXISTS vencanice (
+    id SERIAL PRIMARY KEY,
+    book VARCHAR(255) NOT NULL,
+    page INTEGER NOT NULL,
+    current_number INTEGER NOT NULL,
+    eparhija_id INTEGER NOT NULL REFERENCES eparhije(id),
+    tample_id INTEGER NOT NULL REFERENCES tamples(id),
+    groom_id INTEGER NOT NULL REFERENCES persons(id),
+    bride_id INTEGER NOT NULL REFERENCES persons(id),
+    witness_id INTEGER NOT NULL REFERENCES persons(id),
+    second_witness_id INTEGER REFERENCES persons(id),
+    priest_id INTEGER NOT NULL REFERENCES priests(id),
+    marriage_date TIMESTAMP,
+    city VARCHAR(100),
+    country VARCHAR(100),
+    groom_marriage_order TEXT,
+    bride_marriage_order TEXT,
+    civil_marriage TEXT,
+    number_of_certificate TEXT,
+    town_of_certificate VARCHAR(100),
+    certificate DATE,
+    comment VARCHAR(255),
+    status VARCHAR(255),
+    created_at TIMESTAMP
+);
+
+COMMIT;
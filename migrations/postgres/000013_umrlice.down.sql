@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP TABLE IF EXISTS umrlice;
+
+UPDATE persons SET role = NULL WHERE role = 'deceased';
+ALTER TABLE persons DROP CONSTRAINT IF EXISTS persons_role_check;
+ALTER TABLE persons ADD CONSTRAINT persons_role_check
+    CHECK (role IN ('mother', 'father', 'godfather', 'paroh', 'groom', 'bride'));
+
+COMMIT;
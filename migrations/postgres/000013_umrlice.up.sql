@@ -0,0 +1,35 @@
+BEGIN;
+
+ALTER TABLE persons DROP CONSTRAINT IF EXISTS persons_role_check;
+ALTER TABLE persons ADD CONSTRAINT persons_role_check
+    CHECK (role IN ('mother', 'father', 'godfather', 'paroh', 'groom', 'bride', 'deceased'));
+
+CREATE TABLE IF NOT EXISTS umrlice (
+    id SERIAL PRIMARY KEY,
+    book VARCHAR(255) NOT NULL,
+    page INTEGER NOT NULL,
+    current_number INTEGER NOT NULL,
+    eparhija_id INTEGER NOT NULL REFERENCES eparhije(id),
+    tample_id INTEGER NOT NULL REFERENCES tamples(id),
+    deceased_id INTEGER NOT NULL REFERENCES persons(id),
+    krstenica_id INTEGER REFERENCES krstenice(id),
+    priest_id INTEGER NOT NULL REFERENCES priests(id),
+    death_date TIMESTAMP,
+    burial_date TIMESTAMP,
+    city VARCHAR(100),
+    country VARCHAR(100),
+    cemetery VARCHAR(255),
+    cause_of_death TEXT,
+    marital_status TEXT,
+    sacraments TEXT,
+    number_of_certificate TEXT,
+    town_of_certificate VARCHAR(100),
+    certificate DATE,
+    comment VARCHAR(255),
+    status VARCHAR(255),
+    created_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_umrlice_krstenica_id ON umrlice (krstenica_id);
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE krstenice DROP CONSTRAINT IF EXISTS krstenice_book_page_number_key;
+ALTER TABLE krstenice DROP COLUMN IF EXISTS book_id;
+
+DROP TABLE IF EXISTS books;
+
+COMMIT;
@@ -0,0 +1,55 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS books (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    tample_id INTEGER NOT NULL REFERENCES tamples(id),
+    year_from INTEGER NOT NULL,
+    year_to INTEGER,
+    page_capacity INTEGER NOT NULL DEFAULT 200 CHECK (page_capacity > 0),
+    entries_per_page INTEGER NOT NULL DEFAULT 10 CHECK (entries_per_page > 0),
+    state VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (state IN ('open', 'closed')),
+    status VARCHAR(255),
+    created_at TIMESTAMP,
+    CHECK (year_to IS NULL OR year_to >= year_from)
+);
+
+ALTER TABLE krstenice ADD COLUMN IF NOT EXISTS book_id INTEGER REFERENCES books(id);
+
+-- Postojeće knjige (tample + naziv) prenose se kao otvorene knjige bez gornje granice godine
+INSERT INTO books (name, tample_id, year_from, page_capacity, entries_per_page, state, status, created_at)
+SELECT k.book,
+       k.tample_id,
+       COALESCE(MIN(EXTRACT(YEAR FROM COALESCE(k.baptism, k.created_at)))::INTEGER, EXTRACT(YEAR FROM NOW())::INTEGER),
+       GREATEST(MAX(k.page), 200),
+       10,
+       'open',
+       'active',
+       NOW()
+FROM krstenice k
+WHERE k.tample_id IS NOT NULL
+  AND COALESCE(TRIM(k.book), '') <> ''
+  AND LENGTH(k.book) <= 100
+GROUP BY k.tample_id, k.book;
+
+-- Duplikati (ista knjiga, strana i broj) ostaju bez book_id da bi ograničenje moglo da se doda
+UPDATE krstenice k
+SET book_id = b.id
+FROM books b
+WHERE b.tample_id = k.tample_id
+  AND b.name = k.book
+  AND NOT EXISTS (
+      SELECT 1 FROM krstenice d
+      WHERE d.tample_id = k.tample_id
+        AND d.book = k.book
+        AND d.page = k.page
+        AND d.current_number = k.current_number
+        AND d.id < k.id
+  );
+
+ALTER TABLE krstenice
+    ADD CONSTRAINT krstenice_book_page_number_key UNIQUE (book_id, page, current_number);
+
+CREATE INDEX IF NOT EXISTS idx_books_tample_id ON books (tample_id);
+
+COMMIT;
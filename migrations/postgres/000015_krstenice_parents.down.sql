@@ -0,0 +1,15 @@
+BEGIN;
+
+ALTER TABLE krstenice ADD COLUMN IF NOT EXISTS parent_id INTEGER REFERENCES persons(id);
+
+UPDATE krstenice SET parent_id = COALESCE(father_id, mother_id);
+
+ALTER TABLE krstenice ALTER COLUMN parent_id SET NOT NULL;
+
+ALTER TABLE krstenice DROP CONSTRAINT IF EXISTS krstenice_parents_check;
+DROP INDEX IF EXISTS idx_krstenice_father_id;
+DROP INDEX IF EXISTS idx_krstenice_mother_id;
+ALTER TABLE krstenice DROP COLUMN IF EXISTS father_id;
+ALTER TABLE krstenice DROP COLUMN IF EXISTS mother_id;
+
+COMMIT;
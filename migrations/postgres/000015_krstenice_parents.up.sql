@@ -0,0 +1,26 @@
+BEGIN;
+
+ALTER TABLE krstenice ADD COLUMN IF NOT EXISTS father_id INTEGER REFERENCES persons(id);
+ALTER TABLE krstenice ADD COLUMN IF NOT EXISTS mother_id INTEGER REFERENCES persons(id);
+
+-- Postojeći roditelj prelazi u odgovarajuće polje prema ulozi osobe; sve osim majke ide kao otac
+UPDATE krstenice k
+SET mother_id = k.parent_id
+FROM persons p
+WHERE p.id = k.parent_id
+  AND p.role = 'mother';
+
+UPDATE krstenice k
+SET father_id = k.parent_id
+WHERE k.parent_id IS NOT NULL
+  AND k.mother_id IS NULL;
+
+ALTER TABLE krstenice DROP COLUMN IF EXISTS parent_id;
+
+ALTER TABLE krstenice
+    ADD CONSTRAINT krstenice_parents_check CHECK (father_id IS NOT NULL OR mother_id IS NOT NULL);
+
+CREATE INDEX IF NOT EXISTS idx_krstenice_father_id ON krstenice (father_id);
+CREATE INDEX IF NOT EXISTS idx_krstenice_mother_id ON krstenice (mother_id);
+
+COMMIT;
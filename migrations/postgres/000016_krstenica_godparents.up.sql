@@ -0,0 +1,20 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS krstenica_godparents (
+    krstenica_id INTEGER NOT NULL REFERENCES krstenice(id) ON DELETE CASCADE,
+    person_id INTEGER NOT NULL REFERENCES persons(id),
+    position INTEGER NOT NULL CHECK (position > 0),
+    PRIMARY KEY (krstenica_id, position),
+    CONSTRAINT krstenica_godparents_person_unique UNIQUE (krstenica_id, person_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_krstenica_godparents_person_id ON krstenica_godparents (person_id);
+
+-- Postojeći kum postaje prvi kum; krstenice.godfather_id i dalje čuva prvog kuma
+INSERT INTO krstenica_godparents (krstenica_id, person_id, position)
+SELECT id, godfather_id, 1
+FROM krstenice
+WHERE godfather_id IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+COMMIT;
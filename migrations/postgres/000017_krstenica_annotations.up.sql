@@ -0,0 +1,17 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS krstenica_annotations (
+    id SERIAL PRIMARY KEY,
+    krstenica_id INTEGER NOT NULL REFERENCES krstenice(id) ON DELETE CASCADE,
+    kind VARCHAR(30) NOT NULL CHECK (kind IN ('marriage', 'monastic', 'name_change', 'death', 'other')),
+    note_date DATE NOT NULL,
+    text TEXT NOT NULL DEFAULT '',
+    created_by_id BIGINT REFERENCES app_users(id) ON DELETE SET NULL,
+    created_by_username VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_krstenica_annotations_krstenica_id ON krstenica_annotations (krstenica_id);
+
+COMMIT;
@@ -0,0 +1,21 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS issued_certificates (
+    id SERIAL PRIMARY KEY,
+    krstenica_id INTEGER NOT NULL REFERENCES krstenice(id),
+    tample_id INTEGER REFERENCES tamples(id),
+    year INTEGER NOT NULL,
+    serial_number INTEGER NOT NULL CHECK (serial_number > 0),
+    format VARCHAR(20) NOT NULL,
+    template_version VARCHAR(20) NOT NULL DEFAULT '1',
+    purpose TEXT NOT NULL DEFAULT '',
+    issued_by_id BIGINT REFERENCES app_users(id) ON DELETE SET NULL,
+    issued_by_username VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT issued_certificates_year_serial_key UNIQUE (year, serial_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_issued_certificates_krstenica_id ON issued_certificates (krstenica_id);
+CREATE INDEX IF NOT EXISTS idx_issued_certificates_created_at ON issued_certificates (created_at);
+
+COMMIT;
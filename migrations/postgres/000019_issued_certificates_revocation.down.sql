@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE issued_certificates
+    DROP COLUMN IF EXISTS revoke_reason,
+    DROP COLUMN IF EXISTS revoked_by_username,
+    DROP COLUMN IF EXISTS revoked_at;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE issued_certificates
+    ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS revoked_by_username VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS revoke_reason TEXT NOT NULL DEFAULT '';
+
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS krstenica_attachments (
+    id SERIAL PRIMARY KEY,
+    krstenica_id INTEGER NOT NULL REFERENCES krstenice(id) ON DELETE CASCADE,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    storage_key VARCHAR(255) NOT NULL,
+    thumbnail_key VARCHAR(255) NOT NULL DEFAULT '',
+    checksum CHAR(64) NOT NULL,
+    uploaded_by_id BIGINT REFERENCES app_users(id) ON DELETE SET NULL,
+    uploaded_by_username VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_krstenica_attachments_krstenica_id ON krstenica_attachments (krstenica_id);
+
+COMMIT;
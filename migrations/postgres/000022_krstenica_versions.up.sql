@@ -0,0 +1,15 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS krstenica_versions (
+    id BIGSERIAL PRIMARY KEY,
+    krstenica_id INTEGER NOT NULL REFERENCES krstenice(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    snapshot JSONB NOT NULL,
+    restored_from_version INTEGER,
+    created_by_id BIGINT REFERENCES app_users(id) ON DELETE SET NULL,
+    created_by_username VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_krstenica_versions_version UNIQUE (krstenica_id, version)
+);
+
+COMMIT;
@@ -0,0 +1,28 @@
+BEGIN;
+
+DELETE FROM audit_log WHERE action IN ('restore', 'purge');
+ALTER TABLE audit_log DROP CONSTRAINT IF EXISTS audit_log_action_check;
+ALTER TABLE audit_log
+    ADD CONSTRAINT audit_log_action_check CHECK (action IN ('create', 'update', 'delete'));
+
+ALTER TABLE eparhije DROP COLUMN IF EXISTS deleted_by_username;
+ALTER TABLE eparhije DROP COLUMN IF EXISTS deleted_by_id;
+ALTER TABLE eparhije DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE tamples DROP COLUMN IF EXISTS deleted_by_username;
+ALTER TABLE tamples DROP COLUMN IF EXISTS deleted_by_id;
+ALTER TABLE tamples DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE priests DROP COLUMN IF EXISTS deleted_by_username;
+ALTER TABLE priests DROP COLUMN IF EXISTS deleted_by_id;
+ALTER TABLE priests DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE persons DROP COLUMN IF EXISTS deleted_by_username;
+ALTER TABLE persons DROP COLUMN IF EXISTS deleted_by_id;
+ALTER TABLE persons DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE krstenice DROP COLUMN IF EXISTS deleted_by_username;
+ALTER TABLE krstenice DROP COLUMN IF EXISTS deleted_by_id;
+ALTER TABLE krstenice DROP COLUMN IF EXISTS deleted_at;
+
+COMMIT;
@@ -0,0 +1,34 @@
+BEGIN;
+
+ALTER TABLE krstenice ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE krstenice ADD COLUMN IF NOT EXISTS deleted_by_id BIGINT REFERENCES app_users(id) ON DELETE SET NULL;
+ALTER TABLE krstenice ADD COLUMN IF NOT EXISTS deleted_by_username VARCHAR(255) NOT NULL DEFAULT '';
+
+ALTER TABLE persons ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE persons ADD COLUMN IF NOT EXISTS deleted_by_id BIGINT REFERENCES app_users(id) ON DELETE SET NULL;
+ALTER TABLE persons ADD COLUMN IF NOT EXISTS deleted_by_username VARCHAR(255) NOT NULL DEFAULT '';
+
+ALTER TABLE priests ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE priests ADD COLUMN IF NOT EXISTS deleted_by_id BIGINT REFERENCES app_users(id) ON DELETE SET NULL;
+ALTER TABLE priests ADD COLUMN IF NOT EXISTS deleted_by_username VARCHAR(255) NOT NULL DEFAULT '';
+
+ALTER TABLE tamples ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE tamples ADD COLUMN IF NOT EXISTS deleted_by_id BIGINT REFERENCES app_users(id) ON DELETE SET NULL;
+ALTER TABLE tamples ADD COLUMN IF NOT EXISTS deleted_by_username VARCHAR(255) NOT NULL DEFAULT '';
+
+ALTER TABLE eparhije ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE eparhije ADD COLUMN IF NOT EXISTS deleted_by_id BIGINT REFERENCES app_users(id) ON DELETE SET NULL;
+ALTER TABLE eparhije ADD COLUMN IF NOT EXISTS deleted_by_username VARCHAR(255) NOT NULL DEFAULT '';
+
+-- Ranije obrisani zapisi nemaju vreme brisanja; rok čuvanja im teče od ove migracije
+UPDATE krstenice SET deleted_at = NOW() WHERE status = 'deleted' AND deleted_at IS NULL;
+UPDATE persons SET deleted_at = NOW() WHERE status = 'deleted' AND deleted_at IS NULL;
+UPDATE priests SET deleted_at = NOW() WHERE status = 'deleted' AND deleted_at IS NULL;
+UPDATE tamples SET deleted_at = NOW() WHERE status = 'deleted' AND deleted_at IS NULL;
+UPDATE eparhije SET deleted_at = NOW() WHERE status = 'deleted' AND deleted_at IS NULL;
+
+ALTER TABLE audit_log DROP CONSTRAINT IF EXISTS audit_log_action_check;
+ALTER TABLE audit_log
+    ADD CONSTRAINT audit_log_action_check CHECK (action IN ('create', 'update', 'delete', 'restore', 'purge'));
+
+COMMIT;
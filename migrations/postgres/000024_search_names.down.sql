@@ -0,0 +1,14 @@
+BEGIN;
+
+ALTER TABLE krstenice DROP COLUMN IF EXISTS search_last_name;
+ALTER TABLE krstenice DROP COLUMN IF EXISTS search_first_name;
+
+ALTER TABLE priests DROP COLUMN IF EXISTS search_last_name;
+ALTER TABLE priests DROP COLUMN IF EXISTS search_first_name;
+
+ALTER TABLE persons DROP COLUMN IF EXISTS search_last_name;
+ALTER TABLE persons DROP COLUMN IF EXISTS search_first_name;
+
+DROP FUNCTION IF EXISTS fold_search_text(TEXT);
+
+COMMIT;
@@ -0,0 +1,39 @@
+BEGIN;
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Normalizuje tekst za pretragu: cirilica u latinicu, bez dijakritika, mala slova.
+-- Mora ostati uskladjena sa pkg.FoldSearchText.
+CREATE OR REPLACE FUNCTION fold_search_text(value TEXT) RETURNS TEXT
+    LANGUAGE SQL IMMUTABLE STRICT PARALLEL SAFE AS $$
+    SELECT lower(translate(
+        replace(replace(replace(replace(replace(replace(replace(replace(replace(replace(
+            value,
+            'Ђ', 'Dj'), 'ђ', 'dj'), 'Đ', 'Dj'), 'đ', 'dj'),
+            'Љ', 'Lj'), 'љ', 'lj'), 'Њ', 'Nj'), 'њ', 'nj'),
+            'Џ', 'Dz'), 'џ', 'dz'),
+        'АБВГДЕЖЗИЈКЛМНОПРСТЋУФХЦЧШабвгдежзијклмнопрстћуфхцчшĆČŠŽćčšž',
+        'ABVGDEZZIJKLMNOPRSTCUFHCCSabvgdezzijklmnoprstcufhccsCCSZccsz'))
+$$;
+
+ALTER TABLE persons
+    ADD COLUMN IF NOT EXISTS search_first_name TEXT GENERATED ALWAYS AS (fold_search_text(first_name)) STORED,
+    ADD COLUMN IF NOT EXISTS search_last_name TEXT GENERATED ALWAYS AS (fold_search_text(last_name)) STORED;
+
+ALTER TABLE priests
+    ADD COLUMN IF NOT EXISTS search_first_name TEXT GENERATED ALWAYS AS (fold_search_text(first_name)) STORED,
+    ADD COLUMN IF NOT EXISTS search_last_name TEXT GENERATED ALWAYS AS (fold_search_text(last_name)) STORED;
+
+ALTER TABLE krstenice
+    ADD COLUMN IF NOT EXISTS search_first_name TEXT GENERATED ALWAYS AS (fold_search_text(first_name)) STORED,
+    ADD COLUMN IF NOT EXISTS search_last_name TEXT GENERATED ALWAYS AS (fold_search_text(last_name)) STORED;
+
+-- icontains filter poredi LOWER(kolona) LIKE '%...%', pa je indeks nad istim izrazom.
+CREATE INDEX IF NOT EXISTS idx_persons_search_first_name ON persons USING GIN (LOWER(search_first_name) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_persons_search_last_name ON persons USING GIN (LOWER(search_last_name) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_priests_search_first_name ON priests USING GIN (LOWER(search_first_name) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_priests_search_last_name ON priests USING GIN (LOWER(search_last_name) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_krstenice_search_first_name ON krstenice USING GIN (LOWER(search_first_name) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_krstenice_search_last_name ON krstenice USING GIN (LOWER(search_last_name) gin_trgm_ops);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_krstenice_search_full_name_trgm;
+ALTER TABLE krstenice DROP COLUMN IF EXISTS search_full_name;
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE krstenice
+    ADD COLUMN IF NOT EXISTS search_full_name TEXT GENERATED ALWAYS AS (
+        fold_search_text(COALESCE(first_name, '') || ' ' || COALESCE(last_name, ''))
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_krstenice_search_full_name_trgm
+    ON krstenice USING GIN (search_full_name gin_trgm_ops);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS certificate_layout_cells;
+DROP TABLE IF EXISTS certificate_layouts;
+
+COMMIT;
@@ -0,0 +1,88 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS certificate_layouts (
+    id SERIAL PRIMARY KEY,
+    kind VARCHAR(30) NOT NULL DEFAULT 'krstenica' CHECK (kind IN ('krstenica')),
+    name VARCHAR(255) NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_certificate_layouts_default
+    ON certificate_layouts (kind) WHERE is_default AND status = 'active';
+
+CREATE TABLE IF NOT EXISTS certificate_layout_cells (
+    id SERIAL PRIMARY KEY,
+    layout_id INTEGER NOT NULL REFERENCES certificate_layouts(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    cell VARCHAR(10) NOT NULL,
+    field VARCHAR(50) NOT NULL,
+    target VARCHAR(10) NOT NULL DEFAULT 'all' CHECK (target IN ('all', 'xlsx', 'pdf')),
+    date_style VARCHAR(30) NOT NULL DEFAULT '',
+    prefix VARCHAR(50) NOT NULL DEFAULT '',
+    suffix VARCHAR(50) NOT NULL DEFAULT '',
+    bold BOOLEAN NOT NULL DEFAULT FALSE,
+    wrap BOOLEAN NOT NULL DEFAULT FALSE,
+    fit_to VARCHAR(10) NOT NULL DEFAULT '',
+    offset_x DOUBLE PRECISION NOT NULL DEFAULT 0,
+    offset_y DOUBLE PRECISION NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_certificate_layout_cells_layout_id ON certificate_layout_cells (layout_id, position);
+
+-- Podrazumevani raspored je dosadašnje mapiranje krštenice iz koda.
+WITH layout AS (
+    INSERT INTO certificate_layouts (kind, name, is_default)
+    VALUES ('krstenica', 'Стандардни образац', TRUE)
+    RETURNING id
+)
+INSERT INTO certificate_layout_cells (layout_id, position, cell, field, target, date_style, prefix, bold, fit_to, offset_x, offset_y)
+SELECT layout.id, v.position, v.cell, v.field, v.target, v.date_style, v.prefix, v.bold, v.fit_to, v.offset_x, v.offset_y
+FROM layout, (VALUES
+    (1, 'C1', 'book', 'all', '', '', FALSE, '', 0.0, -8.0),
+    (2, 'C2', 'page', 'all', '', '', FALSE, '', 0.0, -7.0),
+    (3, 'C3', 'current_number', 'all', '', '', FALSE, '', 0.0, -3.5),
+    (4, 'F8', 'eparhija_name', 'all', '', '', FALSE, '', 12.0, -5.0),
+    (5, 'C10', 'tample_name', 'all', '', '', FALSE, '', 0.0, -6.0),
+    (6, 'I10', 'tample_city', 'all', '', '', FALSE, '', 10.6, -6.0),
+    (7, 'N10', 'baptism', 'all', 'year', '', FALSE, '', 2.0, -6.0),
+    (8, 'F13', 'birth_date', 'all', 'date_time', '', FALSE, '', 12.0, -5.0),
+    (9, 'E16', 'place_of_birth', 'all', '', '', FALSE, '', 12.0, -3.0),
+    (10, 'F16', 'place_and_municipality_of_birth', 'all', '', '', FALSE, '', 12.0, -3.0),
+    (11, 'G16', 'municipality_of_birth', 'all', '', '', FALSE, '', 12.0, -3.0),
+    (12, 'F19', 'baptism', 'all', 'date', '', FALSE, '', 12.0, 3.0),
+    (13, 'G24', 'tample_city', 'xlsx', '', '', FALSE, '', 0.0, 0.0),
+    (14, 'I24', 'tample_name', 'xlsx', '', '', FALSE, '', 0.0, 0.0),
+    (15, 'G24', 'tample', 'pdf', '', '', FALSE, '', 12.0, -6.0),
+    (16, 'F27', 'first_name', 'all', '', '', TRUE, '', 12.0, -8.0),
+    (17, 'I27', 'gender', 'all', '', '', FALSE, '', 0.0, -8.0),
+    (18, 'F30', 'parents', 'all', '', '', FALSE, '', 12.0, -8.0),
+    (19, 'F31', 'parents_city', 'xlsx', '', '', FALSE, '', 0.0, 0.0),
+    (20, 'F31', 'parents_city', 'pdf', '', 'из ', FALSE, '', 12.0, -7.0),
+    (21, 'I31', 'parents_religion', 'all', '', '', FALSE, '', 0.0, -7.0),
+    (22, 'I32', 'birth_order', 'all', '', '', FALSE, '', -10.0, 7.0),
+    (23, 'I36', 'is_church_married', 'all', '', '', FALSE, '', -10.0, -3.0),
+    (24, 'I38', 'is_twin', 'all', '', '', FALSE, '', -10.0, 3.0),
+    (25, 'I41', 'has_physical_disability', 'all', '', '', FALSE, '', 5.0, 1.0),
+    (26, 'F43', 'priest_first_name', 'xlsx', '', '', FALSE, '', 0.0, 0.0),
+    (27, 'H43', 'priest_last_name', 'xlsx', '', '', FALSE, '', 0.0, 0.0),
+    (28, 'K43', 'priest_title', 'xlsx', '', '', FALSE, '', 0.0, 0.0),
+    (29, 'F43', 'priest', 'pdf', '', '', FALSE, '', 12.0, 9.0),
+    (30, 'E48', 'godparent_first_name', 'xlsx', '', '', FALSE, 'M48', 0.0, 0.0),
+    (31, 'G48', 'godparent_last_name', 'xlsx', '', '', FALSE, '', 0.0, 0.0),
+    (32, 'K48', 'godparent_occupation', 'xlsx', '', '', FALSE, '', 0.0, 0.0),
+    (33, 'E48', 'godparents', 'pdf', '', '', FALSE, 'M48', 10.0, -2.0),
+    (34, 'E49', 'godparents_city', 'xlsx', '', '', FALSE, 'M49', 0.0, 0.0),
+    (35, 'E49', 'godparents_city', 'pdf', '', 'из ', FALSE, 'M49', 10.0, 0.0),
+    (36, 'G49', 'godparents_religion', 'all', '', '', FALSE, '', 10.0, 0.0),
+    (37, 'E51', 'anagrafa', 'all', '', '', FALSE, '', 30.0, 7.0),
+    (38, 'C54', 'remarks', 'all', '', '', FALSE, 'M54', 20.0, 1.0),
+    (39, 'B62', 'number_of_certificate', 'all', '', '', FALSE, '', 10.0, -2.0),
+    (40, 'B63', 'certificate', 'all', 'day_month', '', FALSE, '', 0.0, 4.0),
+    (41, 'C63', 'certificate', 'all', 'year_short', '', FALSE, '', 8.0, 4.0),
+    (42, 'B65', 'town_of_certificate', 'all', '', '', FALSE, '', 2.0, 2.0)
+) AS v (position, cell, field, target, date_style, prefix, bold, fit_to, offset_x, offset_y);
+
+COMMIT;
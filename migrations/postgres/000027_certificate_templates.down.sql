@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE issued_certificates DROP COLUMN IF EXISTS template_version_id;
+
+DROP TABLE IF EXISTS certificate_template_assignments;
+DROP TABLE IF EXISTS certificate_template_versions;
+DROP TABLE IF EXISTS certificate_templates;
+
+COMMIT;
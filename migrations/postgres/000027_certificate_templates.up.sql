@@ -0,0 +1,56 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS certificate_templates (
+    id SERIAL PRIMARY KEY,
+    kind VARCHAR(30) NOT NULL DEFAULT 'krstenica' CHECK (kind IN ('krstenica')),
+    name VARCHAR(255) NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_certificate_templates_default
+    ON certificate_templates (kind) WHERE is_default AND status = 'active';
+
+-- Verzije se nikad ne menjaju ni brisu; izdato uverenje pamti verziju po kojoj je odstampano.
+CREATE TABLE IF NOT EXISTS certificate_template_versions (
+    id SERIAL PRIMARY KEY,
+    template_id INTEGER NOT NULL REFERENCES certificate_templates(id),
+    version INTEGER NOT NULL CHECK (version > 0),
+    file_name VARCHAR(255) NOT NULL,
+    storage_key VARCHAR(255) NOT NULL,
+    preview_file_name VARCHAR(255) NOT NULL DEFAULT '',
+    preview_storage_key VARCHAR(255) NOT NULL DEFAULT '',
+    background_file_name VARCHAR(255) NOT NULL DEFAULT '',
+    background_storage_key VARCHAR(255) NOT NULL DEFAULT '',
+    background_full_bleed BOOLEAN NOT NULL DEFAULT TRUE,
+    checksum CHAR(64) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    uploaded_by_id BIGINT REFERENCES app_users(id) ON DELETE SET NULL,
+    uploaded_by_username VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT certificate_template_versions_template_version_key UNIQUE (template_id, version)
+);
+
+-- Podrazumevani obrazac za eparhiju ili hram; hram ima prednost pri stampi.
+CREATE TABLE IF NOT EXISTS certificate_template_assignments (
+    id SERIAL PRIMARY KEY,
+    template_id INTEGER NOT NULL REFERENCES certificate_templates(id) ON DELETE CASCADE,
+    eparhija_id INTEGER REFERENCES eparhije(id) ON DELETE CASCADE,
+    tample_id INTEGER REFERENCES tamples(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK ((eparhija_id IS NULL) <> (tample_id IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_certificate_template_assignments_eparhija
+    ON certificate_template_assignments (eparhija_id) WHERE eparhija_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_certificate_template_assignments_tample
+    ON certificate_template_assignments (tample_id) WHERE tample_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_certificate_template_assignments_template_id
+    ON certificate_template_assignments (template_id);
+
+ALTER TABLE issued_certificates
+    ADD COLUMN IF NOT EXISTS template_version_id INTEGER REFERENCES certificate_template_versions(id);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS print_calibration_cells;
+DROP TABLE IF EXISTS print_calibrations;
+
+COMMIT;
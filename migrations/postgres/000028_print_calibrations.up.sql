@@ -0,0 +1,33 @@
+BEGIN;
+
+-- Profil kalibracije ispravlja pomeranje teksta jednog stampaca. Profil moze
+-- pripadati korisniku ili radnoj stanici; profil bez vlasnika se bira rucno.
+CREATE TABLE IF NOT EXISTS print_calibrations (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    offset_x DOUBLE PRECISION NOT NULL DEFAULT 0,
+    offset_y DOUBLE PRECISION NOT NULL DEFAULT 0,
+    scale DOUBLE PRECISION NOT NULL DEFAULT 1 CHECK (scale > 0),
+    user_id INTEGER REFERENCES app_users(id) ON DELETE SET NULL,
+    workstation VARCHAR(100) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (user_id IS NULL OR workstation = '')
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_print_calibrations_user
+    ON print_calibrations (user_id) WHERE user_id IS NOT NULL AND status = 'active';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_print_calibrations_workstation
+    ON print_calibrations (workstation) WHERE workstation != '' AND status = 'active';
+
+CREATE TABLE IF NOT EXISTS print_calibration_cells (
+    id SERIAL PRIMARY KEY,
+    calibration_id INTEGER NOT NULL REFERENCES print_calibrations(id) ON DELETE CASCADE,
+    cell VARCHAR(10) NOT NULL,
+    offset_x DOUBLE PRECISION NOT NULL DEFAULT 0,
+    offset_y DOUBLE PRECISION NOT NULL DEFAULT 0,
+    UNIQUE (calibration_id, cell)
+);
+
+COMMIT;
@@ -1,6 +1,9 @@
 package pkg
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -25,12 +28,46 @@ type Paging struct {
 	PageNumber string `query:"page_number" default:"1"`
 	PageSize   string `query:"page_size"`
 	Paging     string `query:"paging"`
+	// Cursor je kursor sledece strane kod paging=cursor.
+	Cursor string `query:"cursor"`
+	// NextCursor popunjava repozitorijum kada postoji sledeca strana.
+	NextCursor string
+}
+
+// PagingCursor ukljucuje paginaciju kursorom umesto page_number.
+const PagingCursor = "cursor"
+
+// IsCursorPaging vraca true kada je trazena paginacija kursorom.
+func (m *FilterAndSort) IsCursorPaging() bool {
+	return m != nil && m.Paging != nil && strings.EqualFold(strings.TrimSpace(m.Paging.Paging), PagingCursor)
+}
+
+// EncodeCursor pravi neprovidan kursor od vrednosti kljuca poslednjeg reda.
+func EncodeCursor(values []string) string {
+	payload, _ := json.Marshal(values)
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+// DecodeCursor cita vrednosti kljuca iz kursora.
+func DecodeCursor(cursor string) ([]string, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("BAD_CURSOR")
+	}
+	var values []string
+	if err := json.Unmarshal(payload, &values); err != nil {
+		return nil, fmt.Errorf("BAD_CURSOR")
+	}
+
+	return values, nil
 }
 
 type FilterAndSort struct {
 	Filters map[FilterKey][]string
 	Sort    []*SortOptions
 	Paging  *Paging
+	// Fuzzy je tekst za pretragu po slicnosti imena (parametar fuzzy).
+	Fuzzy string
 }
 
 func ParseUrlQuery(ctx *gin.Context) *FilterAndSort {
@@ -61,11 +98,18 @@ func ParseUrlQuery(ctx *gin.Context) *FilterAndSort {
 		m.Paging.Paging = paging
 	}
 
+	cursor, exist := ctx.GetQuery("cursor")
+	if exist {
+		m.Paging.Cursor = strings.TrimSpace(cursor)
+	}
+
 	_, existAll := ctx.GetQuery("all")
 	if existAll {
 		m.Paging.All = "yes"
 	}
 
+	m.Fuzzy = strings.TrimSpace(ctx.Query("fuzzy"))
+
 	sort, exist := ctx.GetQuery("sort")
 
 	if exist {
@@ -106,7 +150,7 @@ func ParseUrlQuery(ctx *gin.Context) *FilterAndSort {
 	// }
 
 	// create maps
-	keysWords := []string{"sort", "page_number", "page_size", "paging", "all"}
+	keysWords := []string{"sort", "page_number", "page_size", "paging", "all", "fuzzy", "cursor"}
 
 	queryParams := ctx.Request.URL.Query()
 
@@ -114,6 +158,14 @@ func ParseUrlQuery(ctx *gin.Context) *FilterAndSort {
 		if InList(key, keysWords) {
 			continue
 		}
+		// filter nosi izraz sa and/or/not i zagradama, vidi FilterExpression
+		if key == "filter" {
+			m.Filters[FilterKey{
+				Property: key,
+				Operator: FilterExpressionOperator,
+			}] = val
+			continue
+		}
 		//filters
 		if !strings.Contains(key, "(") {
 			m.Filters[FilterKey{
@@ -17,6 +17,21 @@ func FilterToSQL(filters map[FilterKey][]string, fn FilterPropertyValidator) (st
 
 	for k, val := range filters {
 		log.Printf("val  %v %v", k, val)
+		if k.Operator == FilterExpressionOperator {
+			for _, v := range val {
+				expr, err := ParseFilterExpression(v)
+				if err != nil {
+					return "", nil, err
+				}
+				w, p, err := filterExpressionToSQL(expr, fn)
+				if err != nil {
+					return "", nil, err
+				}
+				where = append(where, w)
+				params = append(params, p...)
+			}
+			continue
+		}
 		w, p, err := filterToSQL(k.Property, k.Operator, val, fn)
 		if err != nil {
 			return "", nil, err
@@ -0,0 +1,293 @@
+package pkg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"krstenica/internal/errorx"
+)
+
+// FilterExpressionOperator oznacava kljuc filtera koji nosi izraz iz parametra filter.
+const FilterExpressionOperator = "expr"
+
+// Vrste cvorova u izrazu filtera. Prazan Kind je uslov (list stabla).
+const (
+	FilterExpressionAnd = "and"
+	FilterExpressionOr  = "or"
+	FilterExpressionNot = "not"
+)
+
+const maxFilterExpressionDepth = 32
+
+// FilterExpression je cvor stabla izraza filtera, npr.
+//
+//	(gte(baptism)=1995-01-01 or tample_id=3) and not priest_id=7
+//
+// Uslov ima oblik operator(polje)=vrednost ili polje=vrednost (eq). Vrednost sa
+// razmacima ili zagradama se navodi pod navodnicima: icontains(city)="Novi Sad".
+// Prioritet je not, pa and, pa or; zagrade grupisu.
+type FilterExpression struct {
+	Kind     string
+	Key      FilterKey
+	Values   []string
+	Children []*FilterExpression
+}
+
+// ParseFilterExpression parsira izraz iz parametra filter.
+func ParseFilterExpression(input string) (*FilterExpression, error) {
+	p := &filterExpressionParser{input: input}
+	expr, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return nil, p.errorf("unexpected %q", p.input[p.pos:p.pos+1])
+	}
+
+	return expr, nil
+}
+
+// Walk poziva fn za svaki cvor izraza, pocevsi od korena.
+func (e *FilterExpression) Walk(fn func(*FilterExpression)) {
+	fn(e)
+	for _, child := range e.Children {
+		child.Walk(fn)
+	}
+}
+
+// String vraca izraz u obliku koji ParseFilterExpression ponovo cita.
+func (e *FilterExpression) String() string {
+	switch e.Kind {
+	case FilterExpressionNot:
+		return "not (" + e.Children[0].String() + ")"
+	case FilterExpressionAnd, FilterExpressionOr:
+		parts := make([]string, len(e.Children))
+		for i, child := range e.Children {
+			parts[i] = child.String()
+		}
+		return "(" + strings.Join(parts, " "+e.Kind+" ") + ")"
+	}
+
+	condition := e.Key.Operator + "(" + e.Key.Property + ")"
+	if len(e.Values) == 0 {
+		return condition
+	}
+	return condition + "=" + strconv.Quote(e.Values[0])
+}
+
+func filterExpressionToSQL(e *FilterExpression, fn FilterPropertyValidator) (string, []interface{}, error) {
+	switch e.Kind {
+	case FilterExpressionNot:
+		w, p, err := filterExpressionToSQL(e.Children[0], fn)
+		if err != nil {
+			return "", nil, err
+		}
+		return "NOT (" + w + ")", p, nil
+	case FilterExpressionAnd, FilterExpressionOr:
+		parts := make([]string, 0, len(e.Children))
+		params := []interface{}{}
+		for _, child := range e.Children {
+			w, p, err := filterExpressionToSQL(child, fn)
+			if err != nil {
+				return "", nil, err
+			}
+			parts = append(parts, w)
+			params = append(params, p...)
+		}
+		return "(" + strings.Join(parts, " "+strings.ToUpper(e.Kind)+" ") + ")", params, nil
+	}
+
+	// za razliku od pojedinacnih filtera, nepoznat uslov u izrazu se ne
+	// preskace jer bi promenio smisao or/not grupe
+	w, p, err := filterToSQL(e.Key.Property, e.Key.Operator, e.Values, fn)
+	if err != nil || w == "" {
+		return "", nil, filterExpressionError("unsupported condition %s(%s)", e.Key.Operator, e.Key.Property)
+	}
+
+	return w, p, nil
+}
+
+type filterExpressionParser struct {
+	input string
+	pos   int
+}
+
+func (p *filterExpressionParser) errorf(format string, args ...interface{}) error {
+	return filterExpressionError(format+" at position %d", append(args, p.pos)...)
+}
+
+// filterExpressionError vraca gresku neispravnog izraza kao gresku validacije
+// da bi je handler vratio kao 400.
+func filterExpressionError(format string, args ...interface{}) error {
+	return errorx.GetValidationError("Filter", "validation", "BAD_FILTER_EXPRESSION: "+fmt.Sprintf(format, args...))
+}
+
+func (p *filterExpressionParser) parseOr(depth int) (*FilterExpression, error) {
+	return p.parseList(depth, FilterExpressionOr, p.parseAnd)
+}
+
+func (p *filterExpressionParser) parseAnd(depth int) (*FilterExpression, error) {
+	return p.parseList(depth, FilterExpressionAnd, p.parseUnary)
+}
+
+func (p *filterExpressionParser) parseList(depth int, kind string, next func(int) (*FilterExpression, error)) (*FilterExpression, error) {
+	first, err := next(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	children := []*FilterExpression{first}
+	for p.acceptKeyword(kind) {
+		child, err := next(depth)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+	if len(children) == 1 {
+		return first, nil
+	}
+
+	return &FilterExpression{Kind: kind, Children: children}, nil
+}
+
+func (p *filterExpressionParser) parseUnary(depth int) (*FilterExpression, error) {
+	if depth > maxFilterExpressionDepth {
+		return nil, p.errorf("expression nested too deep")
+	}
+
+	if p.acceptKeyword(FilterExpressionNot) {
+		child, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return &FilterExpression{Kind: FilterExpressionNot, Children: []*FilterExpression{child}}, nil
+	}
+
+	p.skipSpaces()
+	if p.accept('(') {
+		expr, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+		if !p.accept(')') {
+			return nil, p.errorf("expected ')'")
+		}
+		return expr, nil
+	}
+
+	return p.parseCondition()
+}
+
+func (p *filterExpressionParser) parseCondition() (*FilterExpression, error) {
+	name := p.readIdent()
+	if name == "" {
+		if p.pos >= len(p.input) {
+			return nil, p.errorf("unexpected end of expression")
+		}
+		return nil, p.errorf("expected condition")
+	}
+
+	key := FilterKey{Property: name, Operator: "eq"}
+	if p.accept('(') {
+		key.Operator = name
+		key.Property = p.readIdent()
+		if key.Property == "" {
+			return nil, p.errorf("expected property name")
+		}
+		if !p.accept(')') {
+			return nil, p.errorf("expected ')'")
+		}
+	}
+
+	expr := &FilterExpression{Key: key}
+	if !p.accept('=') {
+		return expr, nil
+	}
+
+	value, err := p.readValue()
+	if err != nil {
+		return nil, err
+	}
+	expr.Values = []string{value}
+
+	return expr, nil
+}
+
+func (p *filterExpressionParser) readValue() (string, error) {
+	if p.pos < len(p.input) && p.input[p.pos] == '"' {
+		start := p.pos
+		for i := start + 1; i < len(p.input); i++ {
+			switch p.input[i] {
+			case '\\':
+				i++
+			case '"':
+				value, err := strconv.Unquote(p.input[start : i+1])
+				if err != nil {
+					return "", p.errorf("invalid quoted value")
+				}
+				p.pos = i + 1
+				return value, nil
+			}
+		}
+		return "", p.errorf("unterminated quoted value")
+	}
+
+	start := p.pos
+	for p.pos < len(p.input) && !isFilterExpressionSpace(p.input[p.pos]) && p.input[p.pos] != '(' && p.input[p.pos] != ')' {
+		p.pos++
+	}
+
+	return p.input[start:p.pos], nil
+}
+
+func (p *filterExpressionParser) readIdent() string {
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.input) && isFilterExpressionIdent(p.input[p.pos]) {
+		p.pos++
+	}
+
+	return p.input[start:p.pos]
+}
+
+// acceptKeyword preuzima rec and/or/not (bez obzira na velika slova) ako sledi.
+func (p *filterExpressionParser) acceptKeyword(keyword string) bool {
+	p.skipSpaces()
+	end := p.pos + len(keyword)
+	if end > len(p.input) || !strings.EqualFold(p.input[p.pos:end], keyword) {
+		return false
+	}
+	if end < len(p.input) && isFilterExpressionIdent(p.input[end]) {
+		return false
+	}
+	p.pos = end
+
+	return true
+}
+
+func (p *filterExpressionParser) accept(c byte) bool {
+	if p.pos < len(p.input) && p.input[p.pos] == c {
+		p.pos++
+		return true
+	}
+
+	return false
+}
+
+func (p *filterExpressionParser) skipSpaces() {
+	for p.pos < len(p.input) && isFilterExpressionSpace(p.input[p.pos]) {
+		p.pos++
+	}
+}
+
+func isFilterExpressionSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isFilterExpressionIdent(c byte) bool {
+	return c == '_' || c == '.' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
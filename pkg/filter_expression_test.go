@@ -0,0 +1,211 @@
+package pkg
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"krstenica/internal/errorx"
+)
+
+var testFilterColumns = map[string]string{
+	"baptism":   "t.baptism",
+	"tample_id": "t.tample_id",
+	"priest_id": "t.priest_id",
+	"city":      "tm.city",
+	"name":      "t.name",
+}
+
+func validateTestFilterAttr(p string, v []string) (string, error) {
+	column, ok := testFilterColumns[p]
+	if !ok {
+		return "", fmt.Errorf("UNSUPPORTED_FILTER_PROPERTY")
+	}
+	return column, nil
+}
+
+func TestParseFilterExpression(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"uslov bez operatora", "tample_id=3", `eq(tample_id)="3"`},
+		{"uslov sa operatorom", "gte(baptism)=1995-01-01", `gte(baptism)="1995-01-01"`},
+		{"uslov bez vrednosti", "isnull(priest_id)", "isnull(priest_id)"},
+		{"and pre or", "a=1 or b=2 and c=3", `(eq(a)="1" or (eq(b)="2" and eq(c)="3"))`},
+		{"not pre and", "not a=1 and b=2", `(not (eq(a)="1") and eq(b)="2")`},
+		{"zagrade menjaju prioritet", "(a=1 or b=2) and c=3", `((eq(a)="1" or eq(b)="2") and eq(c)="3")`},
+		{"not nad grupom", "not (a=1 or b=2)", `not ((eq(a)="1" or eq(b)="2"))`},
+		{"ugnjezdene zagrade", "((a=1))", `eq(a)="1"`},
+		{"kljucne reci bez obzira na velika slova", "a=1 OR b=2 AnD NOT c=3", `(eq(a)="1" or (eq(b)="2" and not (eq(c)="3")))`},
+		{"rec koja pocinje kljucnom reci", "order=1 or notes=2", `(eq(order)="1" or eq(notes)="2")`},
+		{"vrednost pod navodnicima", `icontains(city)="Novi Sad (grad)"`, `icontains(city)="Novi Sad (grad)"`},
+		{"escape u vrednosti", `name="a\"b\\c"`, `eq(name)="a\"b\\c"`},
+		{"zagrada zavrsava vrednost", "(a=1)", `eq(a)="1"`},
+		{"razmaci oko uslova", "  a=1  ", `eq(a)="1"`},
+		{"lista za in", "in(tample_id)=1,2,3", `in(tample_id)="1,2,3"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := ParseFilterExpression(tt.input)
+			if err != nil {
+				t.Fatalf("ParseFilterExpression(%q) error: %v", tt.input, err)
+			}
+			if got := expr.String(); got != tt.want {
+				t.Fatalf("ParseFilterExpression(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+			again, err := ParseFilterExpression(expr.String())
+			if err != nil {
+				t.Fatalf("ParseFilterExpression(%q) error: %v", expr.String(), err)
+			}
+			if again.String() != expr.String() {
+				t.Fatalf("round trip %s != %s", again, expr)
+			}
+		})
+	}
+}
+
+func TestParseFilterExpressionErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"prazan izraz", ""},
+		{"samo razmaci", "   "},
+		{"nezatvorena zagrada", "(a=1 or b=2"},
+		{"visak zagrada", "a=1)"},
+		{"operator bez vrednosti", "a=1 or"},
+		{"nezatvoreni navodnici", `name="abc`},
+		{"neispravan escape", `name="a\qb"`},
+		{"operator bez polja", "eq()=1"},
+		{"nezatvoren operator", "eq(a=1"},
+		{"dva uslova bez veznika", "a=1 b=2"},
+		{"duboko ugnjezdavanje", strings.Repeat("(", maxFilterExpressionDepth+2) + "a=1" + strings.Repeat(")", maxFilterExpressionDepth+2)},
+		{"duboko ugnjezden not", strings.Repeat("not ", maxFilterExpressionDepth+2) + "a=1"},
+		{"sql u imenu polja", "name;DROP=1"},
+		{"razmak pre znaka jednakosti", "a =1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := ParseFilterExpression(tt.input)
+			if err == nil {
+				t.Fatalf("ParseFilterExpression(%q) = %s, want error", tt.input, expr)
+			}
+			if !errorx.IsValidationError(err) {
+				t.Fatalf("ParseFilterExpression(%q) error %v is not a validation error", tt.input, err)
+			}
+		})
+	}
+}
+
+func TestFilterToSQL(t *testing.T) {
+	tests := []struct {
+		name       string
+		filters    map[FilterKey][]string
+		wantWhere  string
+		wantParams []interface{}
+	}{
+		{
+			name:       "pojedinacni filter",
+			filters:    map[FilterKey][]string{{Property: "tample_id", Operator: "eq"}: {"3"}},
+			wantWhere:  "t.tample_id = ?",
+			wantParams: []interface{}{"3"},
+		},
+		{
+			name: "prioritet i zagrade",
+			filters: map[FilterKey][]string{{Operator: FilterExpressionOperator}: {
+				"(between(baptism)=1995-01-01,1996-12-31 or gte(baptism)=2000-01-01) and tample_id=3 and not priest_id=7",
+			}},
+			wantWhere:  "((t.baptism BETWEEN ? AND ? OR t.baptism >= ?) AND t.tample_id = ? AND NOT (t.priest_id = ?))",
+			wantParams: []interface{}{"1995-01-01", "1996-12-31", "2000-01-01", "3", "7"},
+		},
+		{
+			name: "and pre or",
+			filters: map[FilterKey][]string{{Operator: FilterExpressionOperator}: {
+				"tample_id=1 or tample_id=2 and isnull(priest_id)",
+			}},
+			wantWhere:  "(t.tample_id = ? OR (t.tample_id = ? AND t.priest_id IS NULL))",
+			wantParams: []interface{}{"1", "2"},
+		},
+		{
+			name: "lista vrednosti",
+			filters: map[FilterKey][]string{{Operator: FilterExpressionOperator}: {
+				"not in(tample_id)=1,2",
+			}},
+			wantWhere:  "NOT (t.tample_id IN  (?,?))",
+			wantParams: []interface{}{"1", "2"},
+		},
+		{
+			name: "vise izraza se spaja sa and",
+			filters: map[FilterKey][]string{{Operator: FilterExpressionOperator}: {
+				"tample_id=1 or tample_id=2",
+				"icontains(city)=\"Novi Sad\"",
+			}},
+			wantWhere:  "(t.tample_id = ? OR t.tample_id = ?) AND LOWER(tm.city) LIKE ?",
+			wantParams: []interface{}{"1", "2", "%novi sad%"},
+		},
+		{
+			name: "sql u vrednosti ostaje parametar",
+			filters: map[FilterKey][]string{{Operator: FilterExpressionOperator}: {
+				`name="x'; DROP TABLE krstenice;--" or name="' OR '1'='1"`,
+			}},
+			wantWhere:  "(t.name = ? OR t.name = ?)",
+			wantParams: []interface{}{"x'; DROP TABLE krstenice;--", "' OR '1'='1"},
+		},
+		{
+			name: "escape navodnika u vrednosti",
+			filters: map[FilterKey][]string{{Operator: FilterExpressionOperator}: {
+				`contains(name)="\") or TRUE or (\""`,
+			}},
+			wantWhere:  "t.name LIKE ?",
+			wantParams: []interface{}{`%") or TRUE or ("%`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, params, err := FilterToSQL(tt.filters, validateTestFilterAttr)
+			if err != nil {
+				t.Fatalf("FilterToSQL error: %v", err)
+			}
+			if where != tt.wantWhere {
+				t.Fatalf("where = %q, want %q", where, tt.wantWhere)
+			}
+			if !reflect.DeepEqual(params, tt.wantParams) {
+				t.Fatalf("params = %#v, want %#v", params, tt.wantParams)
+			}
+		})
+	}
+}
+
+func TestFilterToSQLExpressionErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+	}{
+		{"nepoznato polje", "tample_id=3 or password_hash=x"},
+		{"nepoznato polje pod not", "not unknown=1"},
+		{"nepoznat operator", "like(name)=abc"},
+		{"sql umesto operatora", "tample_id=1 or sleep(name)=1"},
+		{"sql umesto polja", `eq(t.name)="x" or eq(pg_sleep)=10`},
+		{"between bez dve vrednosti", "between(baptism)=1995-01-01"},
+		{"neispravan izraz", "tample_id=1 or (priest_id=2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters := map[FilterKey][]string{{Operator: FilterExpressionOperator}: {tt.expr}}
+			where, _, err := FilterToSQL(filters, validateTestFilterAttr)
+			if err == nil {
+				t.Fatalf("FilterToSQL(%q) = %q, want error", tt.expr, where)
+			}
+			if !errorx.IsValidationError(err) {
+				t.Fatalf("FilterToSQL(%q) error %v is not a validation error", tt.expr, err)
+			}
+		})
+	}
+}
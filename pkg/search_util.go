@@ -0,0 +1,26 @@
+package pkg
+
+import "strings"
+
+// searchTextReplacer prevodi cirilicu u latinicu i uklanja dijakritike,
+// isto kao SQL funkcija fold_search_text.
+var searchTextReplacer = strings.NewReplacer(
+	"Ђ", "Dj", "ђ", "dj", "Đ", "Dj", "đ", "dj",
+	"Љ", "Lj", "љ", "lj", "Њ", "Nj", "њ", "nj",
+	"Џ", "Dz", "џ", "dz",
+	"А", "A", "Б", "B", "В", "V", "Г", "G", "Д", "D", "Е", "E", "Ж", "Z", "З", "Z",
+	"И", "I", "Ј", "J", "К", "K", "Л", "L", "М", "M", "Н", "N", "О", "O", "П", "P",
+	"Р", "R", "С", "S", "Т", "T", "Ћ", "C", "У", "U", "Ф", "F", "Х", "H", "Ц", "C",
+	"Ч", "C", "Ш", "S",
+	"а", "a", "б", "b", "в", "v", "г", "g", "д", "d", "е", "e", "ж", "z", "з", "z",
+	"и", "i", "ј", "j", "к", "k", "л", "l", "м", "m", "н", "n", "о", "o", "п", "p",
+	"р", "r", "с", "s", "т", "t", "ћ", "c", "у", "u", "ф", "f", "х", "h", "ц", "c",
+	"ч", "c", "ш", "s",
+	"Ć", "C", "Č", "C", "Š", "S", "Ž", "Z",
+	"ć", "c", "č", "c", "š", "s", "ž", "z",
+)
+
+// FoldSearchText vraca tekst normalizovan za pretragu nezavisno od pisma i dijakritika.
+func FoldSearchText(value string) string {
+	return strings.ToLower(searchTextReplacer.Replace(value))
+}